@@ -1,25 +1,45 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
 	"net"
 	"net/http"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/golang-jwt/jwt/v5"
+	openapi_types "github.com/oapi-codegen/runtime/types"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 
 	"github.com/itisalisas/avito-backend/internal/generated/dto"
 	"github.com/itisalisas/avito-backend/internal/handlers"
+	"github.com/itisalisas/avito-backend/internal/jwtkeys"
 	middleware2 "github.com/itisalisas/avito-backend/internal/middleware"
+	"github.com/itisalisas/avito-backend/internal/models"
+	"github.com/itisalisas/avito-backend/internal/notify"
+	"github.com/itisalisas/avito-backend/internal/oidc"
+	"github.com/itisalisas/avito-backend/internal/passwordhash"
+	"github.com/itisalisas/avito-backend/internal/service/assignment"
 	"github.com/itisalisas/avito-backend/internal/service/auth"
+	"github.com/itisalisas/avito-backend/internal/service/city"
 	"github.com/itisalisas/avito-backend/internal/service/product"
+	"github.com/itisalisas/avito-backend/internal/service/producttype"
 	"github.com/itisalisas/avito-backend/internal/service/pvz"
+	"github.com/itisalisas/avito-backend/internal/service/rbac"
 	"github.com/itisalisas/avito-backend/internal/service/reception"
+	"github.com/itisalisas/avito-backend/internal/service/serviceaccount"
+	"github.com/itisalisas/avito-backend/internal/service/user"
 	"github.com/itisalisas/avito-backend/internal/storage"
+	"github.com/itisalisas/avito-backend/internal/storage/memory"
 	my_grpc "github.com/itisalisas/avito-backend/internal/transport/grpc"
 	"github.com/itisalisas/avito-backend/pkg/metrics"
 	middleware3 "github.com/itisalisas/avito-backend/pkg/middleware"
@@ -38,22 +58,179 @@ func initializeDatabase() (*storage.DB, error) {
 	return db, nil
 }
 
+// initializeStorage selects the storage backend from STORAGE: "memory" keeps
+// everything in process, anything else connects to Postgres.
+func initializeStorage() (*storage.Repositories, func() error, error) {
+	if os.Getenv("STORAGE") == "memory" {
+		log.Println("using in-memory storage")
+		return memory.NewRepositories(), func() error { return nil }, nil
+	}
+
+	db, err := initializeDatabase()
+	if err != nil {
+		return nil, nil, err
+	}
+	return storage.NewRepositories(db.DB), db.Close, nil
+}
+
+// authConfigFromEnv reads REQUIRE_EMPLOYEE_INVITE (moderators always need an
+// invite to register), REQUIRE_MODERATOR_2FA, TOTP_ISSUER, DUMMY_LOGIN (off
+// unless set to true) and the PASSWORD_* strength policy, which defaults to at
+// least 8 characters including a digit.
+func authConfigFromEnv() auth.Config {
+	requireEmployeeInvite, _ := strconv.ParseBool(os.Getenv("REQUIRE_EMPLOYEE_INVITE"))
+	requireModeratorTwoFactor, _ := strconv.ParseBool(os.Getenv("REQUIRE_MODERATOR_2FA"))
+	dummyLogin, _ := strconv.ParseBool(os.Getenv("DUMMY_LOGIN"))
+
+	policy := auth.PasswordPolicy{MinLength: 8, RequireDigit: true}
+	if minLength, err := strconv.Atoi(os.Getenv("PASSWORD_MIN_LENGTH")); err == nil {
+		policy.MinLength = minLength
+	}
+	if requireDigit, err := strconv.ParseBool(os.Getenv("PASSWORD_REQUIRE_DIGIT")); err == nil {
+		policy.RequireDigit = requireDigit
+	}
+	policy.RequireMixedCase, _ = strconv.ParseBool(os.Getenv("PASSWORD_REQUIRE_MIXED_CASE"))
+	policy.RequireSymbol, _ = strconv.ParseBool(os.Getenv("PASSWORD_REQUIRE_SYMBOL"))
+
+	return auth.Config{
+		RequireEmployeeInvite:     requireEmployeeInvite,
+		PasswordPolicy:            policy,
+		RequireModeratorTwoFactor: requireModeratorTwoFactor,
+		TOTPIssuer:                os.Getenv("TOTP_ISSUER"),
+		DummyLogin:                dummyLogin,
+	}
+}
+
+// ssoConfigFromEnv discovers the OpenID Connect provider at OIDC_ISSUER_URL.
+// Single sign-on stays disabled if it is not set. OIDC_ROLE_MAPPING has the
+// form "group=role,other-group=role".
+func ssoConfigFromEnv(ctx context.Context) (auth.SSOConfig, error) {
+	issuer := os.Getenv("OIDC_ISSUER_URL")
+	if issuer == "" {
+		return auth.SSOConfig{}, nil
+	}
+
+	config := auth.SSOConfig{
+		RoleClaim:   os.Getenv("OIDC_ROLE_CLAIM"),
+		RoleMapping: make(map[string]dto.UserRole),
+		DefaultRole: dto.UserRole(os.Getenv("OIDC_DEFAULT_ROLE")),
+	}
+	if config.DefaultRole != "" && !slices.Contains(models.Roles, config.DefaultRole) {
+		return auth.SSOConfig{}, fmt.Errorf("OIDC_DEFAULT_ROLE: unknown role %q", config.DefaultRole)
+	}
+	for _, pair := range strings.Split(os.Getenv("OIDC_ROLE_MAPPING"), ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		value, role, ok := strings.Cut(pair, "=")
+		if !ok || !slices.Contains(models.Roles, dto.UserRole(strings.TrimSpace(role))) {
+			return auth.SSOConfig{}, fmt.Errorf("OIDC_ROLE_MAPPING: invalid entry %q", pair)
+		}
+		config.RoleMapping[strings.TrimSpace(value)] = dto.UserRole(strings.TrimSpace(role))
+	}
+
+	provider, err := oidc.Discover(ctx, oidc.Config{
+		IssuerURL:    issuer,
+		ClientID:     os.Getenv("OIDC_CLIENT_ID"),
+		ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+		RedirectURL:  os.Getenv("OIDC_REDIRECT_URL"),
+	})
+	if err != nil {
+		return auth.SSOConfig{}, err
+	}
+	config.Provider = provider
+	return config, nil
+}
+
+// initializeNotifier writes notifications to NOTIFY_FILE. Notifications carry
+// password reset tokens, so they go to stdout, where any log reader sees them,
+// only if NOTIFY_FILE is "-", and the service does not start without either.
+func initializeNotifier() (*notify.LogNotifier, func() error, error) {
+	path := os.Getenv("NOTIFY_FILE")
+	switch path {
+	case "":
+		return nil, nil, errors.New(`NOTIFY_FILE is not set: give a file for notifications, or "-" for stdout in development`)
+	case "-":
+		return notify.NewLogNotifier(os.Stdout), func() error { return nil }, nil
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, nil, err
+	}
+	return notify.NewLogNotifier(f), f.Close, nil
+}
+
 func setupRouter(authHandler *handlers.AuthHandler, pvzHandler *handlers.PvzHandler,
-	productHandler *handlers.ProductHandler, receptionHandler *handlers.ReceptionHandler) http.Handler {
+	productHandler *handlers.ProductHandler, receptionHandler *handlers.ReceptionHandler,
+	productTypeHandler *handlers.ProductTypeHandler, cityHandler *handlers.CityHandler,
+	assignmentHandler *handlers.AssignmentHandler, userHandler *handlers.UserHandler,
+	serviceAccountHandler *handlers.ServiceAccountHandler, roleHandler *handlers.RoleHandler,
+	jwksHandler *handlers.JWKSHandler, pvzAccess middleware2.PvzAccess, keyfunc jwt.Keyfunc,
+	revocations middleware2.TokenRevocations, users middleware2.ActiveUsers, apiKeys middleware2.APIKeys,
+	permissions middleware2.RolePermissions) http.Handler {
 
 	m := chi.NewRouter()
 	m.Use(middleware3.MetricsMiddleware)
 	m.Use(middleware.Logger)
 
+	checkAuth := middleware2.CheckAuth(keyfunc, revocations, users, apiKeys, permissions)
+
+	m.HandleFunc("GET /.well-known/jwks.json", jwksHandler.GetJWKS)
 	m.HandleFunc("POST /dummyLogin", authHandler.DummyLogin)
 	m.HandleFunc("POST /register", authHandler.Register)
 	m.HandleFunc("POST /login", authHandler.Login)
-	m.With(middleware2.CheckAuth(), middleware2.CheckRole(dto.Moderator)).HandleFunc("POST /pvz", pvzHandler.AddPvz)
-	m.With(middleware2.CheckAuth(), middleware2.CheckRole(dto.Moderator, dto.Employee)).HandleFunc("GET /pvz", pvzHandler.GetPvz)
-	m.With(middleware2.CheckAuth(), middleware2.CheckRole(dto.Employee)).HandleFunc("POST /pvz/{pvzId}/close_last_reception", receptionHandler.CloseLastReception)
-	m.With(middleware2.CheckAuth(), middleware2.CheckRole(dto.Employee)).HandleFunc("POST /pvz/{pvzId}/delete_last_product", productHandler.DeleteLastProduct)
-	m.With(middleware2.CheckAuth(), middleware2.CheckRole(dto.Employee)).HandleFunc("POST /receptions", receptionHandler.AddReception)
-	m.With(middleware2.CheckAuth(), middleware2.CheckRole(dto.Employee)).HandleFunc("POST /products", productHandler.AddProduct)
+	m.HandleFunc("POST /login/2fa", authHandler.CompleteLogin)
+	m.HandleFunc("POST /login/2fa/enroll", authHandler.StartLoginEnrollment)
+	m.HandleFunc("GET /oidc/login", authHandler.SSOLogin)
+	m.HandleFunc("GET /oidc/callback", authHandler.SSOCallback)
+	m.HandleFunc("POST /token/refresh", authHandler.Refresh)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermInviteCreate)).HandleFunc("POST /invites", authHandler.CreateInvite)
+	m.With(checkAuth).HandleFunc("POST /logout", authHandler.Logout)
+	m.With(checkAuth).HandleFunc("GET /me", authHandler.Me)
+	m.With(checkAuth).HandleFunc("POST /me/password", authHandler.ChangePassword)
+	m.With(checkAuth).HandleFunc("POST /me/2fa", authHandler.StartTOTPEnrollment)
+	m.With(checkAuth).HandleFunc("POST /me/2fa/confirm", authHandler.ConfirmTOTP)
+	m.With(checkAuth).HandleFunc("POST /me/2fa/disable", authHandler.DisableTOTP)
+	m.HandleFunc("POST /password/reset", authHandler.RequestPasswordReset)
+	m.HandleFunc("POST /password/reset/confirm", authHandler.ResetPassword)
+	m.HandleFunc("GET /cities", cityHandler.GetCities)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermCityManage)).HandleFunc("POST /cities", cityHandler.AddCity)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermCityManage)).HandleFunc("POST /cities/{cityId}/disable", cityHandler.DisableCity)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermPvzCreate)).HandleFunc("POST /pvz", pvzHandler.AddPvz)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermPvzRead)).HandleFunc("GET /pvz", pvzHandler.GetPvz)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermPvzRead), middleware2.CheckPvzAccess(pvzAccess, middleware2.PvzFromPath)).HandleFunc("GET /pvz/{pvzId}", pvzHandler.GetPvzById)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermPvzUpdate), middleware2.CheckPvzAccess(pvzAccess, middleware2.PvzFromPath)).HandleFunc("PATCH /pvz/{pvzId}", pvzHandler.UpdatePvz)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermPvzDecommission), middleware2.CheckPvzAccess(pvzAccess, middleware2.PvzFromPath)).HandleFunc("POST /pvz/{pvzId}/decommission", pvzHandler.DecommissionPvz)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermReceptionClose), middleware2.CheckPvzAccess(pvzAccess, middleware2.PvzFromPath)).HandleFunc("POST /pvz/{pvzId}/close_last_reception", receptionHandler.CloseLastReception)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermProductDelete), middleware2.CheckPvzAccess(pvzAccess, middleware2.PvzFromPath)).HandleFunc("POST /pvz/{pvzId}/delete_last_product", productHandler.DeleteLastProduct)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermReceptionPause), middleware2.CheckPvzAccess(pvzAccess, middleware2.PvzFromReception)).HandleFunc("POST /receptions/{receptionId}/pause", receptionHandler.PauseReception)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermReceptionResume), middleware2.CheckPvzAccess(pvzAccess, middleware2.PvzFromReception)).HandleFunc("POST /receptions/{receptionId}/resume", receptionHandler.ResumeReception)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermReceptionClose), middleware2.CheckPvzAccess(pvzAccess, middleware2.PvzFromReception)).HandleFunc("POST /receptions/{receptionId}/close", receptionHandler.CloseReception)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermReceptionCancel), middleware2.CheckPvzAccess(pvzAccess, middleware2.PvzFromReception)).HandleFunc("POST /receptions/{receptionId}/cancel", receptionHandler.CancelReception)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermReceptionReopen)).HandleFunc("POST /receptions/{receptionId}/reopen", receptionHandler.ReopenReception)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermReceptionCreate), middleware2.CheckPvzAccess(pvzAccess, middleware2.PvzFromBody)).HandleFunc("POST /receptions", receptionHandler.AddReception)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermProductCreate), middleware2.CheckPvzAccess(pvzAccess, middleware2.PvzFromBody)).HandleFunc("POST /products", productHandler.AddProduct)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermUserRead)).HandleFunc("GET /users", userHandler.GetUsers)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermUserManage)).HandleFunc("PATCH /users/{userId}", userHandler.UpdateUser)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermUserManage)).HandleFunc("POST /users/{userId}/deactivate", userHandler.DeactivateUser)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermUserManage)).HandleFunc("POST /users/{userId}/reactivate", userHandler.ReactivateUser)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermAssignmentManage)).HandleFunc("GET /users/{userId}/pvz", assignmentHandler.GetAssignedPvzs)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermAssignmentManage)).HandleFunc("POST /users/{userId}/pvz", assignmentHandler.AssignPvz)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermAssignmentManage)).HandleFunc("DELETE /users/{userId}/pvz/{pvzId}", assignmentHandler.UnassignPvz)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermUserManage)).HandleFunc("POST /users/{userId}/unlock", authHandler.UnlockUser)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermServiceAccountManage)).HandleFunc("POST /service-accounts", serviceAccountHandler.CreateServiceAccount)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermServiceAccountManage)).HandleFunc("GET /service-accounts", serviceAccountHandler.GetServiceAccounts)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermServiceAccountManage)).HandleFunc("POST /service-accounts/{serviceAccountId}/api-keys", serviceAccountHandler.CreateAPIKey)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermServiceAccountManage)).HandleFunc("GET /service-accounts/{serviceAccountId}/api-keys", serviceAccountHandler.GetAPIKeys)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermServiceAccountManage)).HandleFunc("POST /api-keys/{keyId}/revoke", serviceAccountHandler.RevokeAPIKey)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermProductTypeRead)).HandleFunc("GET /product_types", productTypeHandler.GetProductTypes)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermProductTypeManage)).HandleFunc("POST /product_types", productTypeHandler.AddProductType)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermProductTypeManage)).HandleFunc("PATCH /product_types/{productTypeId}", productTypeHandler.RenameProductType)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermProductTypeManage)).HandleFunc("POST /product_types/{productTypeId}/deactivate", productTypeHandler.DeactivateProductType)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermRoleManage)).HandleFunc("GET /permissions", roleHandler.GetPermissions)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermRoleManage)).HandleFunc("GET /roles", roleHandler.GetRoles)
+	m.With(checkAuth, middleware2.RequirePermission(models.PermRoleManage)).HandleFunc("PUT /roles/{role}/permissions", roleHandler.SetRolePermissions)
 
 	return m
 }
@@ -74,33 +251,75 @@ func startPrometheusServer() {
 }
 
 func RunServer() error {
-	db, err := initializeDatabase()
+	keys, err := jwtkeys.LoadFromEnv()
 	if err != nil {
 		return err
 	}
-	defer func(db *storage.DB) {
-		err := db.Close()
+
+	notifier, closeNotifier, err := initializeNotifier()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := closeNotifier(); err != nil {
+			log.Printf("failed to close notification file: %v", err)
+		}
+	}()
+
+	hasher, err := passwordhash.LoadFromEnv()
+	if err != nil {
+		return err
+	}
+
+	repos, closeStorage, err := initializeStorage()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err := closeStorage()
 		if err != nil {
 			log.Fatalf("failed to close database connection: %v", err)
 		}
-	}(db)
+	}()
 
-	userRepo := storage.NewUserRepository(db.DB)
-	pvzRepo := storage.NewPvzRepository(db.DB)
-	productRepo := storage.NewProductRepository(db.DB)
-	receptionRepo := storage.NewReceptionRepository(db.DB)
+	authConfig := authConfigFromEnv()
+	authConfig.SSO, err = ssoConfigFromEnv(context.Background())
+	if err != nil {
+		return err
+	}
 
-	authService := auth.NewAuthService(userRepo)
-	pvzService := pvz.NewPvzService(pvzRepo)
-	productService := product.NewProductService(productRepo, receptionRepo)
-	receptionService := reception.NewReceptionService(receptionRepo)
+	authService := auth.NewAuthService(repos.TxManager, repos.User, repos.Token, repos.Invite, repos.Pvz,
+		repos.Assignment, repos.LoginAttempt, repos.TwoFactor, repos.Identity, keys, notifier, hasher, authConfig)
+	if email := os.Getenv("ADMIN_EMAIL"); email != "" {
+		if err := authService.BootstrapAdmin(context.Background(), openapi_types.Email(email), os.Getenv("ADMIN_PASSWORD")); err != nil {
+			return fmt.Errorf("failed to bootstrap admin: %w", err)
+		}
+	}
+	pvzService := pvz.NewPvzService(repos.TxManager, repos.Pvz, repos.City, repos.Assignment)
+	productService := product.NewProductService(repos.TxManager, repos.Product, repos.Reception, repos.ProductType)
+	receptionService := reception.NewReceptionService(repos.TxManager, repos.Reception, repos.Pvz)
+	productTypeService := producttype.NewProductTypeService(repos.TxManager, repos.ProductType)
+	cityService := city.NewCityService(repos.TxManager, repos.City)
+	assignmentService := assignment.NewAssignmentService(repos.TxManager, repos.Assignment, repos.User, repos.Pvz, repos.Reception)
+	userService := user.NewUserService(repos.TxManager, repos.User, repos.Token)
+	serviceAccountService := serviceaccount.NewServiceAccountService(repos.TxManager, repos.ServiceAccount)
+	rbacService := rbac.NewRBACService(repos.TxManager, repos.Role)
 
 	authHandler := handlers.NewAuthHandler(authService)
 	pvzHandler := handlers.NewPvzHandler(pvzService)
 	productHandler := handlers.NewProductHandler(productService)
 	receptionHandler := handlers.NewReceptionHandler(receptionService)
+	productTypeHandler := handlers.NewProductTypeHandler(productTypeService)
+	cityHandler := handlers.NewCityHandler(cityService)
+	assignmentHandler := handlers.NewAssignmentHandler(assignmentService)
+	userHandler := handlers.NewUserHandler(userService)
+	serviceAccountHandler := handlers.NewServiceAccountHandler(serviceAccountService)
+	roleHandler := handlers.NewRoleHandler(rbacService)
+	jwksHandler := handlers.NewJWKSHandler(keys)
 
-	m := setupRouter(authHandler, pvzHandler, productHandler, receptionHandler)
+	m := setupRouter(authHandler, pvzHandler, productHandler, receptionHandler, productTypeHandler, cityHandler,
+		assignmentHandler, userHandler, serviceAccountHandler, roleHandler, jwksHandler, assignmentService, keys.Keyfunc,
+		authService, userService, serviceAccountService, rbacService)
 
 	go func() {
 		lis, err := net.Listen("tcp", ":3000")
@@ -108,8 +327,18 @@ func RunServer() error {
 			log.Fatalf("failed to listen: %v", err)
 		}
 
-		s := grpc.NewServer()
-		my_grpc.RegisterGRPCServer(s, pvzService)
+		authenticator := middleware2.NewAuthenticator(keys.Keyfunc, authService, userService, serviceAccountService, rbacService)
+		s := grpc.NewServer(
+			grpc.ChainUnaryInterceptor(
+				my_grpc.UnaryErrorInterceptor(),
+				my_grpc.UnaryAuthInterceptor(authenticator, assignmentService),
+			),
+			grpc.ChainStreamInterceptor(
+				my_grpc.StreamErrorInterceptor(),
+				my_grpc.StreamAuthInterceptor(authenticator, assignmentService),
+			),
+		)
+		my_grpc.RegisterGRPCServer(s, pvzService, receptionService, productService)
 		reflection.Register(s)
 
 		log.Printf("gRPC server listening at %v", lis.Addr())
@@ -10,31 +10,27 @@ import (
 )
 
 const (
+	ApiKeyAuthScopes = "apiKeyAuth.Scopes"
 	BearerAuthScopes = "bearerAuth.Scopes"
 )
 
-// Defines values for PVZCity.
+// Defines values for InviteRole.
 const (
-	Казань         PVZCity = "Казань"
-	Москва         PVZCity = "Москва"
-	СанктПетербург PVZCity = "Санкт-Петербург"
-)
-
-// Defines values for ProductType.
-const (
-	ProductTypeОбувь       ProductType = "обувь"
-	ProductTypeОдежда      ProductType = "одежда"
-	ProductTypeЭлектроника ProductType = "электроника"
+	InviteRoleEmployee  InviteRole = "employee"
+	InviteRoleModerator InviteRole = "moderator"
 )
 
 // Defines values for ReceptionStatus.
 const (
+	Cancelled  ReceptionStatus = "cancelled"
 	Close      ReceptionStatus = "close"
 	InProgress ReceptionStatus = "in_progress"
+	Paused     ReceptionStatus = "paused"
 )
 
 // Defines values for UserRole.
 const (
+	UserRoleAdmin     UserRole = "admin"
 	UserRoleEmployee  UserRole = "employee"
 	UserRoleModerator UserRole = "moderator"
 )
@@ -45,11 +41,10 @@ const (
 	PostDummyLoginJSONBodyRoleModerator PostDummyLoginJSONBodyRole = "moderator"
 )
 
-// Defines values for PostProductsJSONBodyType.
+// Defines values for PostInvitesJSONBodyRole.
 const (
-	PostProductsJSONBodyTypeОбувь       PostProductsJSONBodyType = "обувь"
-	PostProductsJSONBodyTypeОдежда      PostProductsJSONBodyType = "одежда"
-	PostProductsJSONBodyTypeЭлектроника PostProductsJSONBodyType = "электроника"
+	PostInvitesJSONBodyRoleEmployee  PostInvitesJSONBodyRole = "employee"
+	PostInvitesJSONBodyRoleModerator PostInvitesJSONBodyRole = "moderator"
 )
 
 // Defines values for PostRegisterJSONBodyRole.
@@ -58,56 +53,225 @@ const (
 	Moderator PostRegisterJSONBodyRole = "moderator"
 )
 
+// ApiKey API-ключ сервисной учетной записи, передается в заголовке X-API-Key
+type ApiKey struct {
+	CreatedAt time.Time          `json:"createdAt"`
+	ExpiresAt *time.Time         `json:"expiresAt,omitempty"`
+	Id        openapi_types.UUID `json:"id"`
+
+	// Key Ключ целиком, возвращается только при создании
+	Key        *string    `json:"key,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	Name       string     `json:"name"`
+
+	// Prefix Начало ключа, по которому его можно узнать
+	Prefix           string             `json:"prefix"`
+	RevokedAt        *time.Time         `json:"revokedAt,omitempty"`
+	ServiceAccountId openapi_types.UUID `json:"serviceAccountId"`
+}
+
+// City defines model for City.
+type City struct {
+	Active bool               `json:"active"`
+	Id     openapi_types.UUID `json:"id"`
+	Name   string             `json:"name"`
+}
+
 // Error defines model for Error.
 type Error struct {
 	Message string `json:"message"`
 }
 
+// Invite Одноразовое приглашение для регистрации пользователя с заданной ролью
+type Invite struct {
+	// Code Код приглашения, возвращается только при создании
+	Code      *string             `json:"code,omitempty"`
+	CreatedBy *openapi_types.UUID `json:"createdBy,omitempty"`
+	ExpiresAt time.Time           `json:"expiresAt"`
+	Id        openapi_types.UUID  `json:"id"`
+
+	// PvzIds ПВЗ, на которые сотрудник будет назначен при регистрации
+	PvzIds []openapi_types.UUID `json:"pvzIds"`
+	Role   InviteRole           `json:"role"`
+}
+
+// InviteRole defines model for Invite.Role.
+type InviteRole string
+
+// JWK Открытый ключ проверки подписи токенов (RFC 7517)
+type JWK struct {
+	// Alg RS256 или EdDSA
+	Alg string  `json:"alg"`
+	Crv *string `json:"crv,omitempty"`
+	E   *string `json:"e,omitempty"`
+	Kid string  `json:"kid"`
+
+	// Kty RSA или OKP
+	Kty string  `json:"kty"`
+	N   *string `json:"n,omitempty"`
+	Use string  `json:"use"`
+	X   *string `json:"x,omitempty"`
+}
+
+// JWKSet defines model for JWKSet.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// LoginChallenge Пароль верен, но для входа нужен код двухфакторной аутентификации
+type LoginChallenge struct {
+	// ChallengeToken Одноразовый токен для POST /login/2fa и POST /login/2fa/enroll
+	ChallengeToken string `json:"challengeToken"`
+
+	// EnrollmentRequired Для роли пользователя 2FA обязательна, но еще не настроена. Ее нужно настроить через POST /login/2fa/enroll
+	EnrollmentRequired bool      `json:"enrollmentRequired"`
+	ExpiresAt          time.Time `json:"expiresAt"`
+}
+
 // PVZ defines model for PVZ.
 type PVZ struct {
-	City             PVZCity             `json:"city"`
+	Address *string `json:"address,omitempty"`
+
+	// City Название активного города из справочника
+	City string `json:"city"`
+
+	// CreatedBy Пользователь, создавший ПВЗ
+	CreatedBy *openapi_types.UUID `json:"createdBy,omitempty"`
+
+	// DecommissionedAt Время вывода ПВЗ из эксплуатации, после него новые приемки не открываются
+	DecommissionedAt *time.Time          `json:"decommissionedAt,omitempty"`
 	Id               *openapi_types.UUID `json:"id,omitempty"`
-	RegistrationDate *time.Time          `json:"registrationDate,omitempty"`
+	Latitude         *float64            `json:"latitude,omitempty"`
+	Longitude        *float64            `json:"longitude,omitempty"`
+
+	// Name Отображаемое название ПВЗ
+	Name             *string    `json:"name,omitempty"`
+	OpeningHours     *string    `json:"openingHours,omitempty"`
+	Phone            *string    `json:"phone,omitempty"`
+	RegistrationDate *time.Time `json:"registrationDate,omitempty"`
 }
 
-// PVZCity defines model for PVZ.City.
-type PVZCity string
+// PVZUpdate Изменяемые поля профиля ПВЗ, отсутствующие поля не меняются
+type PVZUpdate struct {
+	Address      *string  `json:"address,omitempty"`
+	Latitude     *float64 `json:"latitude,omitempty"`
+	Longitude    *float64 `json:"longitude,omitempty"`
+	Name         *string  `json:"name,omitempty"`
+	OpeningHours *string  `json:"openingHours,omitempty"`
+	Phone        *string  `json:"phone,omitempty"`
+}
 
 // Product defines model for Product.
 type Product struct {
+	// CreatedBy Пользователь, создавший товар
+	CreatedBy   *openapi_types.UUID `json:"createdBy,omitempty"`
 	DateTime    *time.Time          `json:"dateTime,omitempty"`
 	Id          *openapi_types.UUID `json:"id,omitempty"`
 	ReceptionId openapi_types.UUID  `json:"receptionId"`
-	Type        ProductType         `json:"type"`
+
+	// Type Название типа товара из справочника
+	Type string `json:"type"`
+}
+
+// ProductType defines model for ProductType.
+type ProductType struct {
+	Active bool               `json:"active"`
+	Id     openapi_types.UUID `json:"id"`
+	Name   string             `json:"name"`
 }
 
-// ProductType defines model for Product.Type.
-type ProductType string
+// PvzAssignment Назначение сотрудника на ПВЗ
+type PvzAssignment struct {
+	PvzId  openapi_types.UUID `json:"pvzId"`
+	UserId openapi_types.UUID `json:"userId"`
+}
 
 // Reception defines model for Reception.
 type Reception struct {
-	DateTime time.Time           `json:"dateTime"`
-	Id       *openapi_types.UUID `json:"id,omitempty"`
-	PvzId    openapi_types.UUID  `json:"pvzId"`
-	Status   ReceptionStatus     `json:"status"`
+	// CreatedBy Пользователь, создавший приемку
+	CreatedBy *openapi_types.UUID `json:"createdBy,omitempty"`
+	DateTime  time.Time           `json:"dateTime"`
+	Id        *openapi_types.UUID `json:"id,omitempty"`
+	PvzId     openapi_types.UUID  `json:"pvzId"`
+
+	// Status Состояние приемки. Переходы: in_progress -> paused (пауза), paused -> in_progress (возобновление), in_progress/paused -> close (закрытие), in_progress/paused -> cancelled (отмена), close -> in_progress (повторное открытие, только модератор)
+	Status ReceptionStatus `json:"status"`
 }
 
-// ReceptionStatus defines model for Reception.Status.
+// ReceptionStatus Состояние приемки. Переходы: in_progress -> paused (пауза), paused -> in_progress (возобновление), in_progress/paused -> close (закрытие), in_progress/paused -> cancelled (отмена), close -> in_progress (повторное открытие, только модератор)
 type ReceptionStatus string
 
+// Role Роль и разрешения, которые она дает
+type Role struct {
+	Permissions []string `json:"permissions"`
+
+	// Role Роль (employee, moderator, admin)
+	Role string `json:"role"`
+}
+
+// ServiceAccount Учетная запись для интеграций, которая входит по API-ключам
+type ServiceAccount struct {
+	CreatedAt time.Time           `json:"createdAt"`
+	CreatedBy *openapi_types.UUID `json:"createdBy,omitempty"`
+	Id        openapi_types.UUID  `json:"id"`
+	Name      string              `json:"name"`
+
+	// PvzIds ПВЗ, которыми ограничены ключи; пустой список — без ограничений
+	PvzIds []openapi_types.UUID `json:"pvzIds"`
+
+	// Role Роль, с которой выполняются запросы по ключам (employee, moderator)
+	Role string `json:"role"`
+}
+
 // Token defines model for Token.
 type Token = string
 
+// TokenPair defines model for TokenPair.
+type TokenPair struct {
+	// AccessToken Короткоживущий JWT для заголовка Authorization
+	AccessToken string `json:"accessToken"`
+
+	// RefreshToken Одноразовый токен для POST /token/refresh, при обновлении заменяется новым
+	RefreshToken string `json:"refreshToken"`
+}
+
+// TotpEnrollment defines model for TotpEnrollment.
+type TotpEnrollment struct {
+	// ProvisioningUri otpauth:// URI для QR-кода
+	ProvisioningUri string `json:"provisioningUri"`
+
+	// RecoveryCodes Одноразовые коды восстановления, показываются только один раз и действуют после подтверждения
+	RecoveryCodes []string `json:"recoveryCodes"`
+
+	// Secret Секрет в base32 для ручного ввода в приложение-аутентификатор
+	Secret string `json:"secret"`
+}
+
 // User defines model for User.
 type User struct {
-	Email openapi_types.Email `json:"email"`
-	Id    *openapi_types.UUID `json:"id,omitempty"`
-	Role  UserRole            `json:"role"`
+	// Active Деактивированный пользователь не может войти и пользоваться выданными токенами
+	Active      *bool               `json:"active,omitempty"`
+	CreatedAt   *time.Time          `json:"createdAt,omitempty"`
+	Email       openapi_types.Email `json:"email"`
+	Id          *openapi_types.UUID `json:"id,omitempty"`
+	LastLoginAt *time.Time          `json:"lastLoginAt,omitempty"`
+	Role        UserRole            `json:"role"`
 }
 
 // UserRole defines model for User.Role.
 type UserRole string
 
+// UserUpdate Изменяемые поля пользователя
+type UserUpdate struct {
+	// Role Новая роль (employee, moderator)
+	Role string `json:"role"`
+}
+
+// PostCitiesJSONBody defines parameters for PostCities.
+type PostCitiesJSONBody struct {
+	Name string `json:"name"`
+}
+
 // PostDummyLoginJSONBody defines parameters for PostDummyLogin.
 type PostDummyLoginJSONBody struct {
 	Role PostDummyLoginJSONBodyRole `json:"role"`
@@ -116,20 +280,97 @@ type PostDummyLoginJSONBody struct {
 // PostDummyLoginJSONBodyRole defines parameters for PostDummyLogin.
 type PostDummyLoginJSONBodyRole string
 
+// PostInvitesJSONBody defines parameters for PostInvites.
+type PostInvitesJSONBody struct {
+	// PvzIds ПВЗ для назначения сотрудника, только для роли employee
+	PvzIds *[]openapi_types.UUID   `json:"pvzIds,omitempty"`
+	Role   PostInvitesJSONBodyRole `json:"role"`
+
+	// TtlHours Срок действия в часах, по умолчанию 72
+	TtlHours *int `json:"ttlHours,omitempty"`
+}
+
+// PostInvitesJSONBodyRole defines parameters for PostInvites.
+type PostInvitesJSONBodyRole string
+
 // PostLoginJSONBody defines parameters for PostLogin.
 type PostLoginJSONBody struct {
 	Email    openapi_types.Email `json:"email"`
 	Password string              `json:"password"`
 }
 
+// PostLogin2faJSONBody defines parameters for PostLogin2fa.
+type PostLogin2faJSONBody struct {
+	ChallengeToken string `json:"challengeToken"`
+	Code           string `json:"code"`
+}
+
+// PostLogin2faEnrollJSONBody defines parameters for PostLogin2faEnroll.
+type PostLogin2faEnrollJSONBody struct {
+	ChallengeToken string `json:"challengeToken"`
+}
+
+// PostLogoutJSONBody defines parameters for PostLogout.
+type PostLogoutJSONBody struct {
+	RefreshToken *string `json:"refreshToken,omitempty"`
+}
+
+// PostMe2faConfirmJSONBody defines parameters for PostMe2faConfirm.
+type PostMe2faConfirmJSONBody struct {
+	Code string `json:"code"`
+}
+
+// PostMe2faDisableJSONBody defines parameters for PostMe2faDisable.
+type PostMe2faDisableJSONBody struct {
+	Code string `json:"code"`
+}
+
+// PostMePasswordJSONBody defines parameters for PostMePassword.
+type PostMePasswordJSONBody struct {
+	CurrentPassword string `json:"currentPassword"`
+	NewPassword     string `json:"newPassword"`
+}
+
+// GetOidcCallbackParams defines parameters for GetOidcCallback.
+type GetOidcCallbackParams struct {
+	// Code Код авторизации
+	Code *string `form:"code,omitempty" json:"code,omitempty"`
+
+	// State Значение state, выданное при перенаправлении к провайдеру
+	State *string `form:"state,omitempty" json:"state,omitempty"`
+
+	// Error Ошибка, которую вернул провайдер
+	Error *string `form:"error,omitempty" json:"error,omitempty"`
+}
+
+// PostPasswordResetJSONBody defines parameters for PostPasswordReset.
+type PostPasswordResetJSONBody struct {
+	Email openapi_types.Email `json:"email"`
+}
+
+// PostPasswordResetConfirmJSONBody defines parameters for PostPasswordResetConfirm.
+type PostPasswordResetConfirmJSONBody struct {
+	NewPassword string `json:"newPassword"`
+	Token       string `json:"token"`
+}
+
+// PostProductTypesJSONBody defines parameters for PostProductTypes.
+type PostProductTypesJSONBody struct {
+	Name string `json:"name"`
+}
+
+// PatchProductTypesProductTypeIdJSONBody defines parameters for PatchProductTypesProductTypeId.
+type PatchProductTypesProductTypeIdJSONBody struct {
+	Name string `json:"name"`
+}
+
 // PostProductsJSONBody defines parameters for PostProducts.
 type PostProductsJSONBody struct {
-	PvzId openapi_types.UUID       `json:"pvzId"`
-	Type  PostProductsJSONBodyType `json:"type"`
-}
+	PvzId openapi_types.UUID `json:"pvzId"`
 
-// PostProductsJSONBodyType defines parameters for PostProducts.
-type PostProductsJSONBodyType string
+	// Type Название активного типа товара из справочника
+	Type string `json:"type"`
+}
 
 // GetPvzParams defines parameters for GetPvz.
 type GetPvzParams struct {
@@ -139,11 +380,32 @@ type GetPvzParams struct {
 	// EndDate Конечная дата диапазона
 	EndDate *time.Time `form:"endDate,omitempty" json:"endDate,omitempty"`
 
+	// City Город ПВЗ, можно указать несколько раз
+	City *[]string `form:"city,omitempty" json:"city,omitempty"`
+
+	// ReceptionStatus Статус приёмки (in_progress, paused, close, cancelled)
+	ReceptionStatus *string `form:"receptionStatus,omitempty" json:"receptionStatus,omitempty"`
+
+	// ProductType Тип товара в приёмке
+	ProductType *string `form:"productType,omitempty" json:"productType,omitempty"`
+
+	// ProductStartDate Начальная дата добавления товара
+	ProductStartDate *time.Time `form:"productStartDate,omitempty" json:"productStartDate,omitempty"`
+
+	// ProductEndDate Конечная дата добавления товара
+	ProductEndDate *time.Time `form:"productEndDate,omitempty" json:"productEndDate,omitempty"`
+
+	// HasOpenReception Только ПВЗ с открытой приёмкой
+	HasOpenReception *bool `form:"hasOpenReception,omitempty" json:"hasOpenReception,omitempty"`
+
 	// Page Номер страницы
 	Page *int `form:"page,omitempty" json:"page,omitempty"`
 
 	// Limit Количество элементов на странице
 	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Курсор следующей страницы из заголовка X-Next-Cursor (взаимоисключающий с page)
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
 }
 
 // PostReceptionsJSONBody defines parameters for PostReceptions.
@@ -153,28 +415,140 @@ type PostReceptionsJSONBody struct {
 
 // PostRegisterJSONBody defines parameters for PostRegister.
 type PostRegisterJSONBody struct {
-	Email    openapi_types.Email      `json:"email"`
-	Password string                   `json:"password"`
-	Role     PostRegisterJSONBodyRole `json:"role"`
+	Email openapi_types.Email `json:"email"`
+
+	// InviteCode Код приглашения, обязателен для модераторов (и для сотрудников, если так настроен сервис)
+	InviteCode *string                  `json:"inviteCode,omitempty"`
+	Password   string                   `json:"password"`
+	Role       PostRegisterJSONBodyRole `json:"role"`
 }
 
 // PostRegisterJSONBodyRole defines parameters for PostRegister.
 type PostRegisterJSONBodyRole string
 
+// PutRolesRolePermissionsJSONBody defines parameters for PutRolesRolePermissions.
+type PutRolesRolePermissionsJSONBody struct {
+	Permissions []string `json:"permissions"`
+}
+
+// PostServiceAccountsJSONBody defines parameters for PostServiceAccounts.
+type PostServiceAccountsJSONBody struct {
+	Name string `json:"name"`
+
+	// PvzIds ПВЗ, которыми ограничить ключи
+	PvzIds *[]openapi_types.UUID `json:"pvzIds,omitempty"`
+
+	// Role Роль запросов по ключам (employee, moderator)
+	Role string `json:"role"`
+}
+
+// PostServiceAccountsServiceAccountIdApiKeysJSONBody defines parameters for PostServiceAccountsServiceAccountIdApiKeys.
+type PostServiceAccountsServiceAccountIdApiKeysJSONBody struct {
+	Name string `json:"name"`
+
+	// TtlDays Срок действия в днях, по умолчанию бессрочно
+	TtlDays *int `json:"ttlDays,omitempty"`
+}
+
+// PostTokenRefreshJSONBody defines parameters for PostTokenRefresh.
+type PostTokenRefreshJSONBody struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+// GetUsersParams defines parameters for GetUsers.
+type GetUsersParams struct {
+	// Email Часть email без учета регистра
+	Email *string `form:"email,omitempty" json:"email,omitempty"`
+
+	// Role Роль пользователя (employee, moderator)
+	Role *string `form:"role,omitempty" json:"role,omitempty"`
+
+	// Active Только активные или только деактивированные пользователи
+	Active *bool `form:"active,omitempty" json:"active,omitempty"`
+
+	// Page Номер страницы
+	Page *int `form:"page,omitempty" json:"page,omitempty"`
+
+	// Limit Количество элементов на странице
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// PostUsersUserIdPvzJSONBody defines parameters for PostUsersUserIdPvz.
+type PostUsersUserIdPvzJSONBody struct {
+	PvzId openapi_types.UUID `json:"pvzId"`
+}
+
+// PostCitiesJSONRequestBody defines body for PostCities for application/json ContentType.
+type PostCitiesJSONRequestBody PostCitiesJSONBody
+
 // PostDummyLoginJSONRequestBody defines body for PostDummyLogin for application/json ContentType.
 type PostDummyLoginJSONRequestBody PostDummyLoginJSONBody
 
+// PostInvitesJSONRequestBody defines body for PostInvites for application/json ContentType.
+type PostInvitesJSONRequestBody PostInvitesJSONBody
+
 // PostLoginJSONRequestBody defines body for PostLogin for application/json ContentType.
 type PostLoginJSONRequestBody PostLoginJSONBody
 
+// PostLogin2faJSONRequestBody defines body for PostLogin2fa for application/json ContentType.
+type PostLogin2faJSONRequestBody PostLogin2faJSONBody
+
+// PostLogin2faEnrollJSONRequestBody defines body for PostLogin2faEnroll for application/json ContentType.
+type PostLogin2faEnrollJSONRequestBody PostLogin2faEnrollJSONBody
+
+// PostLogoutJSONRequestBody defines body for PostLogout for application/json ContentType.
+type PostLogoutJSONRequestBody PostLogoutJSONBody
+
+// PostMe2faConfirmJSONRequestBody defines body for PostMe2faConfirm for application/json ContentType.
+type PostMe2faConfirmJSONRequestBody PostMe2faConfirmJSONBody
+
+// PostMe2faDisableJSONRequestBody defines body for PostMe2faDisable for application/json ContentType.
+type PostMe2faDisableJSONRequestBody PostMe2faDisableJSONBody
+
+// PostMePasswordJSONRequestBody defines body for PostMePassword for application/json ContentType.
+type PostMePasswordJSONRequestBody PostMePasswordJSONBody
+
+// PostPasswordResetJSONRequestBody defines body for PostPasswordReset for application/json ContentType.
+type PostPasswordResetJSONRequestBody PostPasswordResetJSONBody
+
+// PostPasswordResetConfirmJSONRequestBody defines body for PostPasswordResetConfirm for application/json ContentType.
+type PostPasswordResetConfirmJSONRequestBody PostPasswordResetConfirmJSONBody
+
+// PostProductTypesJSONRequestBody defines body for PostProductTypes for application/json ContentType.
+type PostProductTypesJSONRequestBody PostProductTypesJSONBody
+
+// PatchProductTypesProductTypeIdJSONRequestBody defines body for PatchProductTypesProductTypeId for application/json ContentType.
+type PatchProductTypesProductTypeIdJSONRequestBody PatchProductTypesProductTypeIdJSONBody
+
 // PostProductsJSONRequestBody defines body for PostProducts for application/json ContentType.
 type PostProductsJSONRequestBody PostProductsJSONBody
 
 // PostPvzJSONRequestBody defines body for PostPvz for application/json ContentType.
 type PostPvzJSONRequestBody = PVZ
 
+// PatchPvzPvzIdJSONRequestBody defines body for PatchPvzPvzId for application/json ContentType.
+type PatchPvzPvzIdJSONRequestBody = PVZUpdate
+
 // PostReceptionsJSONRequestBody defines body for PostReceptions for application/json ContentType.
 type PostReceptionsJSONRequestBody PostReceptionsJSONBody
 
 // PostRegisterJSONRequestBody defines body for PostRegister for application/json ContentType.
 type PostRegisterJSONRequestBody PostRegisterJSONBody
+
+// PutRolesRolePermissionsJSONRequestBody defines body for PutRolesRolePermissions for application/json ContentType.
+type PutRolesRolePermissionsJSONRequestBody PutRolesRolePermissionsJSONBody
+
+// PostServiceAccountsJSONRequestBody defines body for PostServiceAccounts for application/json ContentType.
+type PostServiceAccountsJSONRequestBody PostServiceAccountsJSONBody
+
+// PostServiceAccountsServiceAccountIdApiKeysJSONRequestBody defines body for PostServiceAccountsServiceAccountIdApiKeys for application/json ContentType.
+type PostServiceAccountsServiceAccountIdApiKeysJSONRequestBody PostServiceAccountsServiceAccountIdApiKeysJSONBody
+
+// PostTokenRefreshJSONRequestBody defines body for PostTokenRefresh for application/json ContentType.
+type PostTokenRefreshJSONRequestBody PostTokenRefreshJSONBody
+
+// PatchUsersUserIdJSONRequestBody defines body for PatchUsersUserId for application/json ContentType.
+type PatchUsersUserIdJSONRequestBody = UserUpdate
+
+// PostUsersUserIdPvzJSONRequestBody defines body for PostUsersUserIdPvz for application/json ContentType.
+type PostUsersUserIdPvzJSONRequestBody PostUsersUserIdPvzJSONBody
@@ -11,7 +11,6 @@ package mocks
 
 import (
 	context "context"
-	sql "database/sql"
 	reflect "reflect"
 	time "time"
 
@@ -60,35 +59,6 @@ func (mr *MockProductRepositoryInterfaceMockRecorder) AddProduct(ctx, product an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddProduct", reflect.TypeOf((*MockProductRepositoryInterface)(nil).AddProduct), ctx, product)
 }
 
-// BeginTx mocks base method.
-func (m *MockProductRepositoryInterface) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "BeginTx", ctx, opts)
-	ret0, _ := ret[0].(*sql.Tx)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// BeginTx indicates an expected call of BeginTx.
-func (mr *MockProductRepositoryInterfaceMockRecorder) BeginTx(ctx, opts any) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginTx", reflect.TypeOf((*MockProductRepositoryInterface)(nil).BeginTx), ctx, opts)
-}
-
-// Commit mocks base method.
-func (m *MockProductRepositoryInterface) Commit() error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Commit")
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// Commit indicates an expected call of Commit.
-func (mr *MockProductRepositoryInterfaceMockRecorder) Commit() *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Commit", reflect.TypeOf((*MockProductRepositoryInterface)(nil).Commit))
-}
-
 // DeleteProductById mocks base method.
 func (m *MockProductRepositoryInterface) DeleteProductById(ctx context.Context, productId types.UUID) error {
 	m.ctrl.T.Helper()
@@ -118,18 +88,102 @@ func (mr *MockProductRepositoryInterfaceMockRecorder) GetLastProduct(ctx, recept
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastProduct", reflect.TypeOf((*MockProductRepositoryInterface)(nil).GetLastProduct), ctx, receptionId)
 }
 
-// Rollback mocks base method.
-func (m *MockProductRepositoryInterface) Rollback() error {
+// MockProductTypeRepositoryInterface is a mock of ProductTypeRepositoryInterface interface.
+type MockProductTypeRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductTypeRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockProductTypeRepositoryInterfaceMockRecorder is the mock recorder for MockProductTypeRepositoryInterface.
+type MockProductTypeRepositoryInterfaceMockRecorder struct {
+	mock *MockProductTypeRepositoryInterface
+}
+
+// NewMockProductTypeRepositoryInterface creates a new mock instance.
+func NewMockProductTypeRepositoryInterface(ctrl *gomock.Controller) *MockProductTypeRepositoryInterface {
+	mock := &MockProductTypeRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockProductTypeRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductTypeRepositoryInterface) EXPECT() *MockProductTypeRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateProductType mocks base method.
+func (m *MockProductTypeRepositoryInterface) CreateProductType(ctx context.Context, productType *dto.ProductType) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Rollback")
+	ret := m.ctrl.Call(m, "CreateProductType", ctx, productType)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Rollback indicates an expected call of Rollback.
-func (mr *MockProductRepositoryInterfaceMockRecorder) Rollback() *gomock.Call {
+// CreateProductType indicates an expected call of CreateProductType.
+func (mr *MockProductTypeRepositoryInterfaceMockRecorder) CreateProductType(ctx, productType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProductType", reflect.TypeOf((*MockProductTypeRepositoryInterface)(nil).CreateProductType), ctx, productType)
+}
+
+// DeactivateProductType mocks base method.
+func (m *MockProductTypeRepositoryInterface) DeactivateProductType(ctx context.Context, id types.UUID) (*dto.ProductType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeactivateProductType", ctx, id)
+	ret0, _ := ret[0].(*dto.ProductType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeactivateProductType indicates an expected call of DeactivateProductType.
+func (mr *MockProductTypeRepositoryInterfaceMockRecorder) DeactivateProductType(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateProductType", reflect.TypeOf((*MockProductTypeRepositoryInterface)(nil).DeactivateProductType), ctx, id)
+}
+
+// GetProductTypeByName mocks base method.
+func (m *MockProductTypeRepositoryInterface) GetProductTypeByName(ctx context.Context, name string) (*dto.ProductType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProductTypeByName", ctx, name)
+	ret0, _ := ret[0].(*dto.ProductType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProductTypeByName indicates an expected call of GetProductTypeByName.
+func (mr *MockProductTypeRepositoryInterfaceMockRecorder) GetProductTypeByName(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductTypeByName", reflect.TypeOf((*MockProductTypeRepositoryInterface)(nil).GetProductTypeByName), ctx, name)
+}
+
+// GetProductTypes mocks base method.
+func (m *MockProductTypeRepositoryInterface) GetProductTypes(ctx context.Context) ([]dto.ProductType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProductTypes", ctx)
+	ret0, _ := ret[0].([]dto.ProductType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProductTypes indicates an expected call of GetProductTypes.
+func (mr *MockProductTypeRepositoryInterfaceMockRecorder) GetProductTypes(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductTypes", reflect.TypeOf((*MockProductTypeRepositoryInterface)(nil).GetProductTypes), ctx)
+}
+
+// RenameProductType mocks base method.
+func (m *MockProductTypeRepositoryInterface) RenameProductType(ctx context.Context, id types.UUID, name string) (*dto.ProductType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameProductType", ctx, id, name)
+	ret0, _ := ret[0].(*dto.ProductType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameProductType indicates an expected call of RenameProductType.
+func (mr *MockProductTypeRepositoryInterfaceMockRecorder) RenameProductType(ctx, id, name any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rollback", reflect.TypeOf((*MockProductRepositoryInterface)(nil).Rollback))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameProductType", reflect.TypeOf((*MockProductTypeRepositoryInterface)(nil).RenameProductType), ctx, id, name)
 }
 
 // MockReceptionRepositoryInterface is a mock of ReceptionRepositoryInterface interface.
@@ -170,21 +224,6 @@ func (mr *MockReceptionRepositoryInterfaceMockRecorder) AddReception(ctx, recept
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReception", reflect.TypeOf((*MockReceptionRepositoryInterface)(nil).AddReception), ctx, reception)
 }
 
-// BeginTx mocks base method.
-func (m *MockReceptionRepositoryInterface) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "BeginTx", ctx, opts)
-	ret0, _ := ret[0].(*sql.Tx)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// BeginTx indicates an expected call of BeginTx.
-func (mr *MockReceptionRepositoryInterfaceMockRecorder) BeginTx(ctx, opts any) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginTx", reflect.TypeOf((*MockReceptionRepositoryInterface)(nil).BeginTx), ctx, opts)
-}
-
 // CloseLastReception mocks base method.
 func (m *MockReceptionRepositoryInterface) CloseLastReception(ctx context.Context, receptionId types.UUID) (*dto.Reception, error) {
 	m.ctrl.T.Helper()
@@ -200,20 +239,6 @@ func (mr *MockReceptionRepositoryInterfaceMockRecorder) CloseLastReception(ctx,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseLastReception", reflect.TypeOf((*MockReceptionRepositoryInterface)(nil).CloseLastReception), ctx, receptionId)
 }
 
-// Commit mocks base method.
-func (m *MockReceptionRepositoryInterface) Commit() error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Commit")
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// Commit indicates an expected call of Commit.
-func (mr *MockReceptionRepositoryInterfaceMockRecorder) Commit() *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Commit", reflect.TypeOf((*MockReceptionRepositoryInterface)(nil).Commit))
-}
-
 // GetLastReceptionByPvzId mocks base method.
 func (m *MockReceptionRepositoryInterface) GetLastReceptionByPvzId(ctx context.Context, pvzId types.UUID) (*dto.Reception, error) {
 	m.ctrl.T.Helper()
@@ -229,18 +254,34 @@ func (mr *MockReceptionRepositoryInterfaceMockRecorder) GetLastReceptionByPvzId(
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastReceptionByPvzId", reflect.TypeOf((*MockReceptionRepositoryInterface)(nil).GetLastReceptionByPvzId), ctx, pvzId)
 }
 
-// Rollback mocks base method.
-func (m *MockReceptionRepositoryInterface) Rollback() error {
+// GetReceptionById mocks base method.
+func (m *MockReceptionRepositoryInterface) GetReceptionById(ctx context.Context, receptionId types.UUID) (*dto.Reception, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Rollback")
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "GetReceptionById", ctx, receptionId)
+	ret0, _ := ret[0].(*dto.Reception)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReceptionById indicates an expected call of GetReceptionById.
+func (mr *MockReceptionRepositoryInterfaceMockRecorder) GetReceptionById(ctx, receptionId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReceptionById", reflect.TypeOf((*MockReceptionRepositoryInterface)(nil).GetReceptionById), ctx, receptionId)
+}
+
+// UpdateReceptionStatus mocks base method.
+func (m *MockReceptionRepositoryInterface) UpdateReceptionStatus(ctx context.Context, receptionId types.UUID, status dto.ReceptionStatus) (*dto.Reception, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateReceptionStatus", ctx, receptionId, status)
+	ret0, _ := ret[0].(*dto.Reception)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Rollback indicates an expected call of Rollback.
-func (mr *MockReceptionRepositoryInterfaceMockRecorder) Rollback() *gomock.Call {
+// UpdateReceptionStatus indicates an expected call of UpdateReceptionStatus.
+func (mr *MockReceptionRepositoryInterfaceMockRecorder) UpdateReceptionStatus(ctx, receptionId, status any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rollback", reflect.TypeOf((*MockReceptionRepositoryInterface)(nil).Rollback))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReceptionStatus", reflect.TypeOf((*MockReceptionRepositoryInterface)(nil).UpdateReceptionStatus), ctx, receptionId, status)
 }
 
 // MockPvzRepositoryInterface is a mock of PvzRepositoryInterface interface.
@@ -267,35 +308,6 @@ func (m *MockPvzRepositoryInterface) EXPECT() *MockPvzRepositoryInterfaceMockRec
 	return m.recorder
 }
 
-// BeginTx mocks base method.
-func (m *MockPvzRepositoryInterface) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "BeginTx", ctx, opts)
-	ret0, _ := ret[0].(*sql.Tx)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// BeginTx indicates an expected call of BeginTx.
-func (mr *MockPvzRepositoryInterfaceMockRecorder) BeginTx(ctx, opts any) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginTx", reflect.TypeOf((*MockPvzRepositoryInterface)(nil).BeginTx), ctx, opts)
-}
-
-// Commit mocks base method.
-func (m *MockPvzRepositoryInterface) Commit() error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Commit")
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// Commit indicates an expected call of Commit.
-func (mr *MockPvzRepositoryInterfaceMockRecorder) Commit() *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Commit", reflect.TypeOf((*MockPvzRepositoryInterface)(nil).Commit))
-}
-
 // CreatePvz mocks base method.
 func (m *MockPvzRepositoryInterface) CreatePvz(ctx context.Context, pvz *dto.PVZ) error {
 	m.ctrl.T.Helper()
@@ -310,6 +322,21 @@ func (mr *MockPvzRepositoryInterfaceMockRecorder) CreatePvz(ctx, pvz any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePvz", reflect.TypeOf((*MockPvzRepositoryInterface)(nil).CreatePvz), ctx, pvz)
 }
 
+// DecommissionPvz mocks base method.
+func (m *MockPvzRepositoryInterface) DecommissionPvz(ctx context.Context, id types.UUID) (*dto.PVZ, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecommissionPvz", ctx, id)
+	ret0, _ := ret[0].(*dto.PVZ)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecommissionPvz indicates an expected call of DecommissionPvz.
+func (mr *MockPvzRepositoryInterfaceMockRecorder) DecommissionPvz(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecommissionPvz", reflect.TypeOf((*MockPvzRepositoryInterface)(nil).DecommissionPvz), ctx, id)
+}
+
 // GetAllPVZs mocks base method.
 func (m *MockPvzRepositoryInterface) GetAllPVZs(ctx context.Context) ([]dto.PVZ, error) {
 	m.ctrl.T.Helper()
@@ -325,86 +352,156 @@ func (mr *MockPvzRepositoryInterfaceMockRecorder) GetAllPVZs(ctx any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllPVZs", reflect.TypeOf((*MockPvzRepositoryInterface)(nil).GetAllPVZs), ctx)
 }
 
+// GetPvzById mocks base method.
+func (m *MockPvzRepositoryInterface) GetPvzById(ctx context.Context, id types.UUID) (*dto.PVZ, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPvzById", ctx, id)
+	ret0, _ := ret[0].(*dto.PVZ)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPvzById indicates an expected call of GetPvzById.
+func (mr *MockPvzRepositoryInterfaceMockRecorder) GetPvzById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPvzById", reflect.TypeOf((*MockPvzRepositoryInterface)(nil).GetPvzById), ctx, id)
+}
+
 // GetPvzList mocks base method.
-func (m *MockPvzRepositoryInterface) GetPvzList(ctx context.Context, startTime, endTime *time.Time, page, limit uint64) ([]*models.ExtendedPvz, error) {
+func (m *MockPvzRepositoryInterface) GetPvzList(ctx context.Context, params models.PvzListParams) (*models.PvzPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPvzList", ctx, startTime, endTime, page, limit)
-	ret0, _ := ret[0].([]*models.ExtendedPvz)
+	ret := m.ctrl.Call(m, "GetPvzList", ctx, params)
+	ret0, _ := ret[0].(*models.PvzPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetPvzList indicates an expected call of GetPvzList.
-func (mr *MockPvzRepositoryInterfaceMockRecorder) GetPvzList(ctx, startTime, endTime, page, limit any) *gomock.Call {
+func (mr *MockPvzRepositoryInterfaceMockRecorder) GetPvzList(ctx, params any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPvzList", reflect.TypeOf((*MockPvzRepositoryInterface)(nil).GetPvzList), ctx, startTime, endTime, page, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPvzList", reflect.TypeOf((*MockPvzRepositoryInterface)(nil).GetPvzList), ctx, params)
 }
 
-// Rollback mocks base method.
-func (m *MockPvzRepositoryInterface) Rollback() error {
+// UpdatePvz mocks base method.
+func (m *MockPvzRepositoryInterface) UpdatePvz(ctx context.Context, id types.UUID, update *dto.PVZUpdate) (*dto.PVZ, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Rollback")
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "UpdatePvz", ctx, id, update)
+	ret0, _ := ret[0].(*dto.PVZ)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Rollback indicates an expected call of Rollback.
-func (mr *MockPvzRepositoryInterfaceMockRecorder) Rollback() *gomock.Call {
+// UpdatePvz indicates an expected call of UpdatePvz.
+func (mr *MockPvzRepositoryInterfaceMockRecorder) UpdatePvz(ctx, id, update any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rollback", reflect.TypeOf((*MockPvzRepositoryInterface)(nil).Rollback))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePvz", reflect.TypeOf((*MockPvzRepositoryInterface)(nil).UpdatePvz), ctx, id, update)
 }
 
-// MockUserRepositoryInterface is a mock of UserRepositoryInterface interface.
-type MockUserRepositoryInterface struct {
+// MockCityRepositoryInterface is a mock of CityRepositoryInterface interface.
+type MockCityRepositoryInterface struct {
 	ctrl     *gomock.Controller
-	recorder *MockUserRepositoryInterfaceMockRecorder
+	recorder *MockCityRepositoryInterfaceMockRecorder
 	isgomock struct{}
 }
 
-// MockUserRepositoryInterfaceMockRecorder is the mock recorder for MockUserRepositoryInterface.
-type MockUserRepositoryInterfaceMockRecorder struct {
-	mock *MockUserRepositoryInterface
+// MockCityRepositoryInterfaceMockRecorder is the mock recorder for MockCityRepositoryInterface.
+type MockCityRepositoryInterfaceMockRecorder struct {
+	mock *MockCityRepositoryInterface
 }
 
-// NewMockUserRepositoryInterface creates a new mock instance.
-func NewMockUserRepositoryInterface(ctrl *gomock.Controller) *MockUserRepositoryInterface {
-	mock := &MockUserRepositoryInterface{ctrl: ctrl}
-	mock.recorder = &MockUserRepositoryInterfaceMockRecorder{mock}
+// NewMockCityRepositoryInterface creates a new mock instance.
+func NewMockCityRepositoryInterface(ctrl *gomock.Controller) *MockCityRepositoryInterface {
+	mock := &MockCityRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockCityRepositoryInterfaceMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockUserRepositoryInterface) EXPECT() *MockUserRepositoryInterfaceMockRecorder {
+func (m *MockCityRepositoryInterface) EXPECT() *MockCityRepositoryInterfaceMockRecorder {
 	return m.recorder
 }
 
-// BeginTx mocks base method.
-func (m *MockUserRepositoryInterface) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+// CreateCity mocks base method.
+func (m *MockCityRepositoryInterface) CreateCity(ctx context.Context, city *dto.City) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCity", ctx, city)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCity indicates an expected call of CreateCity.
+func (mr *MockCityRepositoryInterfaceMockRecorder) CreateCity(ctx, city any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCity", reflect.TypeOf((*MockCityRepositoryInterface)(nil).CreateCity), ctx, city)
+}
+
+// DisableCity mocks base method.
+func (m *MockCityRepositoryInterface) DisableCity(ctx context.Context, id types.UUID) (*dto.City, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "BeginTx", ctx, opts)
-	ret0, _ := ret[0].(*sql.Tx)
+	ret := m.ctrl.Call(m, "DisableCity", ctx, id)
+	ret0, _ := ret[0].(*dto.City)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// BeginTx indicates an expected call of BeginTx.
-func (mr *MockUserRepositoryInterfaceMockRecorder) BeginTx(ctx, opts any) *gomock.Call {
+// DisableCity indicates an expected call of DisableCity.
+func (mr *MockCityRepositoryInterfaceMockRecorder) DisableCity(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginTx", reflect.TypeOf((*MockUserRepositoryInterface)(nil).BeginTx), ctx, opts)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableCity", reflect.TypeOf((*MockCityRepositoryInterface)(nil).DisableCity), ctx, id)
 }
 
-// Commit mocks base method.
-func (m *MockUserRepositoryInterface) Commit() error {
+// GetCities mocks base method.
+func (m *MockCityRepositoryInterface) GetCities(ctx context.Context) ([]dto.City, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Commit")
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "GetCities", ctx)
+	ret0, _ := ret[0].([]dto.City)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCities indicates an expected call of GetCities.
+func (mr *MockCityRepositoryInterfaceMockRecorder) GetCities(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCities", reflect.TypeOf((*MockCityRepositoryInterface)(nil).GetCities), ctx)
+}
+
+// GetCityByName mocks base method.
+func (m *MockCityRepositoryInterface) GetCityByName(ctx context.Context, name string) (*dto.City, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCityByName", ctx, name)
+	ret0, _ := ret[0].(*dto.City)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Commit indicates an expected call of Commit.
-func (mr *MockUserRepositoryInterfaceMockRecorder) Commit() *gomock.Call {
+// GetCityByName indicates an expected call of GetCityByName.
+func (mr *MockCityRepositoryInterfaceMockRecorder) GetCityByName(ctx, name any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Commit", reflect.TypeOf((*MockUserRepositoryInterface)(nil).Commit))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCityByName", reflect.TypeOf((*MockCityRepositoryInterface)(nil).GetCityByName), ctx, name)
+}
+
+// MockUserRepositoryInterface is a mock of UserRepositoryInterface interface.
+type MockUserRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockUserRepositoryInterfaceMockRecorder is the mock recorder for MockUserRepositoryInterface.
+type MockUserRepositoryInterfaceMockRecorder struct {
+	mock *MockUserRepositoryInterface
+}
+
+// NewMockUserRepositoryInterface creates a new mock instance.
+func NewMockUserRepositoryInterface(ctrl *gomock.Controller) *MockUserRepositoryInterface {
+	mock := &MockUserRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockUserRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserRepositoryInterface) EXPECT() *MockUserRepositoryInterfaceMockRecorder {
+	return m.recorder
 }
 
 // CreateUser mocks base method.
@@ -436,83 +533,1010 @@ func (mr *MockUserRepositoryInterfaceMockRecorder) GetUserByEmail(ctx, email any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetUserByEmail), ctx, email)
 }
 
-// Rollback mocks base method.
-func (m *MockUserRepositoryInterface) Rollback() error {
+// GetUserById mocks base method.
+func (m *MockUserRepositoryInterface) GetUserById(ctx context.Context, id types.UUID) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserById", ctx, id)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserById indicates an expected call of GetUserById.
+func (mr *MockUserRepositoryInterfaceMockRecorder) GetUserById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserById", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetUserById), ctx, id)
+}
+
+// ListUsers mocks base method.
+func (m *MockUserRepositoryInterface) ListUsers(ctx context.Context, params models.UserListParams) (*models.UserPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsers", ctx, params)
+	ret0, _ := ret[0].(*models.UserPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockUserRepositoryInterfaceMockRecorder) ListUsers(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockUserRepositoryInterface)(nil).ListUsers), ctx, params)
+}
+
+// RehashPassword mocks base method.
+func (m *MockUserRepositoryInterface) RehashPassword(ctx context.Context, id types.UUID, oldHash, newHash string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RehashPassword", ctx, id, oldHash, newHash)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RehashPassword indicates an expected call of RehashPassword.
+func (mr *MockUserRepositoryInterfaceMockRecorder) RehashPassword(ctx, id, oldHash, newHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RehashPassword", reflect.TypeOf((*MockUserRepositoryInterface)(nil).RehashPassword), ctx, id, oldHash, newHash)
+}
+
+// SetActive mocks base method.
+func (m *MockUserRepositoryInterface) SetActive(ctx context.Context, id types.UUID, active bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetActive", ctx, id, active)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetActive indicates an expected call of SetActive.
+func (mr *MockUserRepositoryInterfaceMockRecorder) SetActive(ctx, id, active any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetActive", reflect.TypeOf((*MockUserRepositoryInterface)(nil).SetActive), ctx, id, active)
+}
+
+// UpdateLastLogin mocks base method.
+func (m *MockUserRepositoryInterface) UpdateLastLogin(ctx context.Context, id types.UUID, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLastLogin", ctx, id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLastLogin indicates an expected call of UpdateLastLogin.
+func (mr *MockUserRepositoryInterfaceMockRecorder) UpdateLastLogin(ctx, id, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLastLogin", reflect.TypeOf((*MockUserRepositoryInterface)(nil).UpdateLastLogin), ctx, id, at)
+}
+
+// UpdatePassword mocks base method.
+func (m *MockUserRepositoryInterface) UpdatePassword(ctx context.Context, id types.UUID, passwordHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassword", ctx, id, passwordHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *MockUserRepositoryInterfaceMockRecorder) UpdatePassword(ctx, id, passwordHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockUserRepositoryInterface)(nil).UpdatePassword), ctx, id, passwordHash)
+}
+
+// UpdateRole mocks base method.
+func (m *MockUserRepositoryInterface) UpdateRole(ctx context.Context, id types.UUID, role dto.UserRole) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Rollback")
+	ret := m.ctrl.Call(m, "UpdateRole", ctx, id, role)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Rollback indicates an expected call of Rollback.
-func (mr *MockUserRepositoryInterfaceMockRecorder) Rollback() *gomock.Call {
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *MockUserRepositoryInterfaceMockRecorder) UpdateRole(ctx, id, role any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rollback", reflect.TypeOf((*MockUserRepositoryInterface)(nil).Rollback))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockUserRepositoryInterface)(nil).UpdateRole), ctx, id, role)
 }
 
-// MockTransactionStorage is a mock of TransactionStorage interface.
-type MockTransactionStorage struct {
+// MockAssignmentRepositoryInterface is a mock of AssignmentRepositoryInterface interface.
+type MockAssignmentRepositoryInterface struct {
 	ctrl     *gomock.Controller
-	recorder *MockTransactionStorageMockRecorder
+	recorder *MockAssignmentRepositoryInterfaceMockRecorder
 	isgomock struct{}
 }
 
-// MockTransactionStorageMockRecorder is the mock recorder for MockTransactionStorage.
-type MockTransactionStorageMockRecorder struct {
-	mock *MockTransactionStorage
+// MockAssignmentRepositoryInterfaceMockRecorder is the mock recorder for MockAssignmentRepositoryInterface.
+type MockAssignmentRepositoryInterfaceMockRecorder struct {
+	mock *MockAssignmentRepositoryInterface
 }
 
-// NewMockTransactionStorage creates a new mock instance.
-func NewMockTransactionStorage(ctrl *gomock.Controller) *MockTransactionStorage {
-	mock := &MockTransactionStorage{ctrl: ctrl}
-	mock.recorder = &MockTransactionStorageMockRecorder{mock}
+// NewMockAssignmentRepositoryInterface creates a new mock instance.
+func NewMockAssignmentRepositoryInterface(ctrl *gomock.Controller) *MockAssignmentRepositoryInterface {
+	mock := &MockAssignmentRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockAssignmentRepositoryInterfaceMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockTransactionStorage) EXPECT() *MockTransactionStorageMockRecorder {
+func (m *MockAssignmentRepositoryInterface) EXPECT() *MockAssignmentRepositoryInterfaceMockRecorder {
 	return m.recorder
 }
 
-// BeginTx mocks base method.
-func (m *MockTransactionStorage) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+// AssignPvz mocks base method.
+func (m *MockAssignmentRepositoryInterface) AssignPvz(ctx context.Context, userId, pvzId types.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignPvz", ctx, userId, pvzId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignPvz indicates an expected call of AssignPvz.
+func (mr *MockAssignmentRepositoryInterfaceMockRecorder) AssignPvz(ctx, userId, pvzId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignPvz", reflect.TypeOf((*MockAssignmentRepositoryInterface)(nil).AssignPvz), ctx, userId, pvzId)
+}
+
+// GetAssignedPvzIds mocks base method.
+func (m *MockAssignmentRepositoryInterface) GetAssignedPvzIds(ctx context.Context, userId types.UUID) ([]types.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAssignedPvzIds", ctx, userId)
+	ret0, _ := ret[0].([]types.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAssignedPvzIds indicates an expected call of GetAssignedPvzIds.
+func (mr *MockAssignmentRepositoryInterfaceMockRecorder) GetAssignedPvzIds(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAssignedPvzIds", reflect.TypeOf((*MockAssignmentRepositoryInterface)(nil).GetAssignedPvzIds), ctx, userId)
+}
+
+// IsAssigned mocks base method.
+func (m *MockAssignmentRepositoryInterface) IsAssigned(ctx context.Context, userId, pvzId types.UUID) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "BeginTx", ctx, opts)
-	ret0, _ := ret[0].(*sql.Tx)
+	ret := m.ctrl.Call(m, "IsAssigned", ctx, userId, pvzId)
+	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// BeginTx indicates an expected call of BeginTx.
-func (mr *MockTransactionStorageMockRecorder) BeginTx(ctx, opts any) *gomock.Call {
+// IsAssigned indicates an expected call of IsAssigned.
+func (mr *MockAssignmentRepositoryInterfaceMockRecorder) IsAssigned(ctx, userId, pvzId any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginTx", reflect.TypeOf((*MockTransactionStorage)(nil).BeginTx), ctx, opts)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAssigned", reflect.TypeOf((*MockAssignmentRepositoryInterface)(nil).IsAssigned), ctx, userId, pvzId)
 }
 
-// Commit mocks base method.
-func (m *MockTransactionStorage) Commit() error {
+// UnassignPvz mocks base method.
+func (m *MockAssignmentRepositoryInterface) UnassignPvz(ctx context.Context, userId, pvzId types.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Commit")
+	ret := m.ctrl.Call(m, "UnassignPvz", ctx, userId, pvzId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Commit indicates an expected call of Commit.
-func (mr *MockTransactionStorageMockRecorder) Commit() *gomock.Call {
+// UnassignPvz indicates an expected call of UnassignPvz.
+func (mr *MockAssignmentRepositoryInterfaceMockRecorder) UnassignPvz(ctx, userId, pvzId any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Commit", reflect.TypeOf((*MockTransactionStorage)(nil).Commit))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnassignPvz", reflect.TypeOf((*MockAssignmentRepositoryInterface)(nil).UnassignPvz), ctx, userId, pvzId)
+}
+
+// MockTokenRepositoryInterface is a mock of TokenRepositoryInterface interface.
+type MockTokenRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockTokenRepositoryInterfaceMockRecorder is the mock recorder for MockTokenRepositoryInterface.
+type MockTokenRepositoryInterfaceMockRecorder struct {
+	mock *MockTokenRepositoryInterface
+}
+
+// NewMockTokenRepositoryInterface creates a new mock instance.
+func NewMockTokenRepositoryInterface(ctrl *gomock.Controller) *MockTokenRepositoryInterface {
+	mock := &MockTokenRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockTokenRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokenRepositoryInterface) EXPECT() *MockTokenRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreatePasswordResetToken mocks base method.
+func (m *MockTokenRepositoryInterface) CreatePasswordResetToken(ctx context.Context, token *models.PasswordResetToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePasswordResetToken", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePasswordResetToken indicates an expected call of CreatePasswordResetToken.
+func (mr *MockTokenRepositoryInterfaceMockRecorder) CreatePasswordResetToken(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePasswordResetToken", reflect.TypeOf((*MockTokenRepositoryInterface)(nil).CreatePasswordResetToken), ctx, token)
+}
+
+// CreateRefreshToken mocks base method.
+func (m *MockTokenRepositoryInterface) CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRefreshToken", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRefreshToken indicates an expected call of CreateRefreshToken.
+func (mr *MockTokenRepositoryInterfaceMockRecorder) CreateRefreshToken(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRefreshToken", reflect.TypeOf((*MockTokenRepositoryInterface)(nil).CreateRefreshToken), ctx, token)
+}
+
+// GetPasswordResetTokenByHash mocks base method.
+func (m *MockTokenRepositoryInterface) GetPasswordResetTokenByHash(ctx context.Context, hash string) (*models.PasswordResetToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPasswordResetTokenByHash", ctx, hash)
+	ret0, _ := ret[0].(*models.PasswordResetToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPasswordResetTokenByHash indicates an expected call of GetPasswordResetTokenByHash.
+func (mr *MockTokenRepositoryInterfaceMockRecorder) GetPasswordResetTokenByHash(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPasswordResetTokenByHash", reflect.TypeOf((*MockTokenRepositoryInterface)(nil).GetPasswordResetTokenByHash), ctx, hash)
+}
+
+// GetRefreshTokenByHash mocks base method.
+func (m *MockTokenRepositoryInterface) GetRefreshTokenByHash(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefreshTokenByHash", ctx, hash)
+	ret0, _ := ret[0].(*models.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefreshTokenByHash indicates an expected call of GetRefreshTokenByHash.
+func (mr *MockTokenRepositoryInterfaceMockRecorder) GetRefreshTokenByHash(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefreshTokenByHash", reflect.TypeOf((*MockTokenRepositoryInterface)(nil).GetRefreshTokenByHash), ctx, hash)
+}
+
+// IsAccessTokenRevoked mocks base method.
+func (m *MockTokenRepositoryInterface) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsAccessTokenRevoked", ctx, jti)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsAccessTokenRevoked indicates an expected call of IsAccessTokenRevoked.
+func (mr *MockTokenRepositoryInterfaceMockRecorder) IsAccessTokenRevoked(ctx, jti any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAccessTokenRevoked", reflect.TypeOf((*MockTokenRepositoryInterface)(nil).IsAccessTokenRevoked), ctx, jti)
+}
+
+// RevokeAccessToken mocks base method.
+func (m *MockTokenRepositoryInterface) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAccessToken", ctx, jti, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAccessToken indicates an expected call of RevokeAccessToken.
+func (mr *MockTokenRepositoryInterfaceMockRecorder) RevokeAccessToken(ctx, jti, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAccessToken", reflect.TypeOf((*MockTokenRepositoryInterface)(nil).RevokeAccessToken), ctx, jti, expiresAt)
+}
+
+// RevokeRefreshToken mocks base method.
+func (m *MockTokenRepositoryInterface) RevokeRefreshToken(ctx context.Context, id types.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeRefreshToken", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeRefreshToken indicates an expected call of RevokeRefreshToken.
+func (mr *MockTokenRepositoryInterfaceMockRecorder) RevokeRefreshToken(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRefreshToken", reflect.TypeOf((*MockTokenRepositoryInterface)(nil).RevokeRefreshToken), ctx, id)
+}
+
+// RevokeUserRefreshTokens mocks base method.
+func (m *MockTokenRepositoryInterface) RevokeUserRefreshTokens(ctx context.Context, userId types.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserRefreshTokens", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUserRefreshTokens indicates an expected call of RevokeUserRefreshTokens.
+func (mr *MockTokenRepositoryInterfaceMockRecorder) RevokeUserRefreshTokens(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserRefreshTokens", reflect.TypeOf((*MockTokenRepositoryInterface)(nil).RevokeUserRefreshTokens), ctx, userId)
+}
+
+// UsePasswordResetToken mocks base method.
+func (m *MockTokenRepositoryInterface) UsePasswordResetToken(ctx context.Context, id types.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UsePasswordResetToken", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UsePasswordResetToken indicates an expected call of UsePasswordResetToken.
+func (mr *MockTokenRepositoryInterfaceMockRecorder) UsePasswordResetToken(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UsePasswordResetToken", reflect.TypeOf((*MockTokenRepositoryInterface)(nil).UsePasswordResetToken), ctx, id)
+}
+
+// UseUserPasswordResetTokens mocks base method.
+func (m *MockTokenRepositoryInterface) UseUserPasswordResetTokens(ctx context.Context, userId types.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseUserPasswordResetTokens", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseUserPasswordResetTokens indicates an expected call of UseUserPasswordResetTokens.
+func (mr *MockTokenRepositoryInterfaceMockRecorder) UseUserPasswordResetTokens(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseUserPasswordResetTokens", reflect.TypeOf((*MockTokenRepositoryInterface)(nil).UseUserPasswordResetTokens), ctx, userId)
+}
+
+// MockInviteRepositoryInterface is a mock of InviteRepositoryInterface interface.
+type MockInviteRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockInviteRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockInviteRepositoryInterfaceMockRecorder is the mock recorder for MockInviteRepositoryInterface.
+type MockInviteRepositoryInterfaceMockRecorder struct {
+	mock *MockInviteRepositoryInterface
+}
+
+// NewMockInviteRepositoryInterface creates a new mock instance.
+func NewMockInviteRepositoryInterface(ctrl *gomock.Controller) *MockInviteRepositoryInterface {
+	mock := &MockInviteRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockInviteRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInviteRepositoryInterface) EXPECT() *MockInviteRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateInvite mocks base method.
+func (m *MockInviteRepositoryInterface) CreateInvite(ctx context.Context, invite *models.Invite) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInvite", ctx, invite)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateInvite indicates an expected call of CreateInvite.
+func (mr *MockInviteRepositoryInterfaceMockRecorder) CreateInvite(ctx, invite any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInvite", reflect.TypeOf((*MockInviteRepositoryInterface)(nil).CreateInvite), ctx, invite)
+}
+
+// GetInviteByHash mocks base method.
+func (m *MockInviteRepositoryInterface) GetInviteByHash(ctx context.Context, hash string) (*models.Invite, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInviteByHash", ctx, hash)
+	ret0, _ := ret[0].(*models.Invite)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInviteByHash indicates an expected call of GetInviteByHash.
+func (mr *MockInviteRepositoryInterfaceMockRecorder) GetInviteByHash(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInviteByHash", reflect.TypeOf((*MockInviteRepositoryInterface)(nil).GetInviteByHash), ctx, hash)
+}
+
+// UseInvite mocks base method.
+func (m *MockInviteRepositoryInterface) UseInvite(ctx context.Context, id, userId types.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseInvite", ctx, id, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseInvite indicates an expected call of UseInvite.
+func (mr *MockInviteRepositoryInterfaceMockRecorder) UseInvite(ctx, id, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseInvite", reflect.TypeOf((*MockInviteRepositoryInterface)(nil).UseInvite), ctx, id, userId)
+}
+
+// MockLoginAttemptRepositoryInterface is a mock of LoginAttemptRepositoryInterface interface.
+type MockLoginAttemptRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginAttemptRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockLoginAttemptRepositoryInterfaceMockRecorder is the mock recorder for MockLoginAttemptRepositoryInterface.
+type MockLoginAttemptRepositoryInterfaceMockRecorder struct {
+	mock *MockLoginAttemptRepositoryInterface
+}
+
+// NewMockLoginAttemptRepositoryInterface creates a new mock instance.
+func NewMockLoginAttemptRepositoryInterface(ctrl *gomock.Controller) *MockLoginAttemptRepositoryInterface {
+	mock := &MockLoginAttemptRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockLoginAttemptRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginAttemptRepositoryInterface) EXPECT() *MockLoginAttemptRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// BlockLogin mocks base method.
+func (m *MockLoginAttemptRepositoryInterface) BlockLogin(ctx context.Context, subject string, until time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockLogin", ctx, subject, until)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BlockLogin indicates an expected call of BlockLogin.
+func (mr *MockLoginAttemptRepositoryInterfaceMockRecorder) BlockLogin(ctx, subject, until any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockLogin", reflect.TypeOf((*MockLoginAttemptRepositoryInterface)(nil).BlockLogin), ctx, subject, until)
+}
+
+// GetLoginFailures mocks base method.
+func (m *MockLoginAttemptRepositoryInterface) GetLoginFailures(ctx context.Context, subject string) (*models.LoginFailures, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoginFailures", ctx, subject)
+	ret0, _ := ret[0].(*models.LoginFailures)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoginFailures indicates an expected call of GetLoginFailures.
+func (mr *MockLoginAttemptRepositoryInterfaceMockRecorder) GetLoginFailures(ctx, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoginFailures", reflect.TypeOf((*MockLoginAttemptRepositoryInterface)(nil).GetLoginFailures), ctx, subject)
+}
+
+// RecordLoginFailure mocks base method.
+func (m *MockLoginAttemptRepositoryInterface) RecordLoginFailure(ctx context.Context, subject string, at, windowStart time.Time) (*models.LoginFailures, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordLoginFailure", ctx, subject, at, windowStart)
+	ret0, _ := ret[0].(*models.LoginFailures)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordLoginFailure indicates an expected call of RecordLoginFailure.
+func (mr *MockLoginAttemptRepositoryInterfaceMockRecorder) RecordLoginFailure(ctx, subject, at, windowStart any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordLoginFailure", reflect.TypeOf((*MockLoginAttemptRepositoryInterface)(nil).RecordLoginFailure), ctx, subject, at, windowStart)
+}
+
+// ResetLoginFailures mocks base method.
+func (m *MockLoginAttemptRepositoryInterface) ResetLoginFailures(ctx context.Context, subject string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetLoginFailures", ctx, subject)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetLoginFailures indicates an expected call of ResetLoginFailures.
+func (mr *MockLoginAttemptRepositoryInterfaceMockRecorder) ResetLoginFailures(ctx, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetLoginFailures", reflect.TypeOf((*MockLoginAttemptRepositoryInterface)(nil).ResetLoginFailures), ctx, subject)
+}
+
+// MockServiceAccountRepositoryInterface is a mock of ServiceAccountRepositoryInterface interface.
+type MockServiceAccountRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceAccountRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockServiceAccountRepositoryInterfaceMockRecorder is the mock recorder for MockServiceAccountRepositoryInterface.
+type MockServiceAccountRepositoryInterfaceMockRecorder struct {
+	mock *MockServiceAccountRepositoryInterface
+}
+
+// NewMockServiceAccountRepositoryInterface creates a new mock instance.
+func NewMockServiceAccountRepositoryInterface(ctrl *gomock.Controller) *MockServiceAccountRepositoryInterface {
+	mock := &MockServiceAccountRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockServiceAccountRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockServiceAccountRepositoryInterface) EXPECT() *MockServiceAccountRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockServiceAccountRepositoryInterface) CreateAPIKey(ctx context.Context, key *models.APIKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockServiceAccountRepositoryInterfaceMockRecorder) CreateAPIKey(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockServiceAccountRepositoryInterface)(nil).CreateAPIKey), ctx, key)
+}
+
+// CreateServiceAccount mocks base method.
+func (m *MockServiceAccountRepositoryInterface) CreateServiceAccount(ctx context.Context, account *models.ServiceAccount) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateServiceAccount", ctx, account)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateServiceAccount indicates an expected call of CreateServiceAccount.
+func (mr *MockServiceAccountRepositoryInterfaceMockRecorder) CreateServiceAccount(ctx, account any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateServiceAccount", reflect.TypeOf((*MockServiceAccountRepositoryInterface)(nil).CreateServiceAccount), ctx, account)
+}
+
+// GetAPIKeyByHash mocks base method.
+func (m *MockServiceAccountRepositoryInterface) GetAPIKeyByHash(ctx context.Context, hash string) (*models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeyByHash", ctx, hash)
+	ret0, _ := ret[0].(*models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeyByHash indicates an expected call of GetAPIKeyByHash.
+func (mr *MockServiceAccountRepositoryInterfaceMockRecorder) GetAPIKeyByHash(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeyByHash", reflect.TypeOf((*MockServiceAccountRepositoryInterface)(nil).GetAPIKeyByHash), ctx, hash)
+}
+
+// GetServiceAccountById mocks base method.
+func (m *MockServiceAccountRepositoryInterface) GetServiceAccountById(ctx context.Context, id types.UUID) (*models.ServiceAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceAccountById", ctx, id)
+	ret0, _ := ret[0].(*models.ServiceAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServiceAccountById indicates an expected call of GetServiceAccountById.
+func (mr *MockServiceAccountRepositoryInterfaceMockRecorder) GetServiceAccountById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceAccountById", reflect.TypeOf((*MockServiceAccountRepositoryInterface)(nil).GetServiceAccountById), ctx, id)
+}
+
+// ListAPIKeys mocks base method.
+func (m *MockServiceAccountRepositoryInterface) ListAPIKeys(ctx context.Context, accountId types.UUID) ([]*models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAPIKeys", ctx, accountId)
+	ret0, _ := ret[0].([]*models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAPIKeys indicates an expected call of ListAPIKeys.
+func (mr *MockServiceAccountRepositoryInterfaceMockRecorder) ListAPIKeys(ctx, accountId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIKeys", reflect.TypeOf((*MockServiceAccountRepositoryInterface)(nil).ListAPIKeys), ctx, accountId)
+}
+
+// ListServiceAccounts mocks base method.
+func (m *MockServiceAccountRepositoryInterface) ListServiceAccounts(ctx context.Context) ([]*models.ServiceAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListServiceAccounts", ctx)
+	ret0, _ := ret[0].([]*models.ServiceAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListServiceAccounts indicates an expected call of ListServiceAccounts.
+func (mr *MockServiceAccountRepositoryInterfaceMockRecorder) ListServiceAccounts(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServiceAccounts", reflect.TypeOf((*MockServiceAccountRepositoryInterface)(nil).ListServiceAccounts), ctx)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockServiceAccountRepositoryInterface) RevokeAPIKey(ctx context.Context, id types.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockServiceAccountRepositoryInterfaceMockRecorder) RevokeAPIKey(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockServiceAccountRepositoryInterface)(nil).RevokeAPIKey), ctx, id)
+}
+
+// UpdateAPIKeyLastUsed mocks base method.
+func (m *MockServiceAccountRepositoryInterface) UpdateAPIKeyLastUsed(ctx context.Context, id types.UUID, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAPIKeyLastUsed", ctx, id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAPIKeyLastUsed indicates an expected call of UpdateAPIKeyLastUsed.
+func (mr *MockServiceAccountRepositoryInterfaceMockRecorder) UpdateAPIKeyLastUsed(ctx, id, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAPIKeyLastUsed", reflect.TypeOf((*MockServiceAccountRepositoryInterface)(nil).UpdateAPIKeyLastUsed), ctx, id, at)
+}
+
+// MockRoleRepositoryInterface is a mock of RoleRepositoryInterface interface.
+type MockRoleRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoleRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockRoleRepositoryInterfaceMockRecorder is the mock recorder for MockRoleRepositoryInterface.
+type MockRoleRepositoryInterfaceMockRecorder struct {
+	mock *MockRoleRepositoryInterface
+}
+
+// NewMockRoleRepositoryInterface creates a new mock instance.
+func NewMockRoleRepositoryInterface(ctrl *gomock.Controller) *MockRoleRepositoryInterface {
+	mock := &MockRoleRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockRoleRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoleRepositoryInterface) EXPECT() *MockRoleRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GetRolePermissions mocks base method.
+func (m *MockRoleRepositoryInterface) GetRolePermissions(ctx context.Context, role dto.UserRole) ([]models.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRolePermissions", ctx, role)
+	ret0, _ := ret[0].([]models.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRolePermissions indicates an expected call of GetRolePermissions.
+func (mr *MockRoleRepositoryInterfaceMockRecorder) GetRolePermissions(ctx, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRolePermissions", reflect.TypeOf((*MockRoleRepositoryInterface)(nil).GetRolePermissions), ctx, role)
+}
+
+// ListRolePermissions mocks base method.
+func (m *MockRoleRepositoryInterface) ListRolePermissions(ctx context.Context) (map[dto.UserRole][]models.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRolePermissions", ctx)
+	ret0, _ := ret[0].(map[dto.UserRole][]models.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRolePermissions indicates an expected call of ListRolePermissions.
+func (mr *MockRoleRepositoryInterfaceMockRecorder) ListRolePermissions(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRolePermissions", reflect.TypeOf((*MockRoleRepositoryInterface)(nil).ListRolePermissions), ctx)
+}
+
+// SetRolePermissions mocks base method.
+func (m *MockRoleRepositoryInterface) SetRolePermissions(ctx context.Context, role dto.UserRole, permissions []models.Permission) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRolePermissions", ctx, role, permissions)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRolePermissions indicates an expected call of SetRolePermissions.
+func (mr *MockRoleRepositoryInterfaceMockRecorder) SetRolePermissions(ctx, role, permissions any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRolePermissions", reflect.TypeOf((*MockRoleRepositoryInterface)(nil).SetRolePermissions), ctx, role, permissions)
+}
+
+// MockTwoFactorRepositoryInterface is a mock of TwoFactorRepositoryInterface interface.
+type MockTwoFactorRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockTwoFactorRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockTwoFactorRepositoryInterfaceMockRecorder is the mock recorder for MockTwoFactorRepositoryInterface.
+type MockTwoFactorRepositoryInterfaceMockRecorder struct {
+	mock *MockTwoFactorRepositoryInterface
+}
+
+// NewMockTwoFactorRepositoryInterface creates a new mock instance.
+func NewMockTwoFactorRepositoryInterface(ctrl *gomock.Controller) *MockTwoFactorRepositoryInterface {
+	mock := &MockTwoFactorRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockTwoFactorRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTwoFactorRepositoryInterface) EXPECT() *MockTwoFactorRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ConfirmTOTP mocks base method.
+func (m *MockTwoFactorRepositoryInterface) ConfirmTOTP(ctx context.Context, userId types.UUID, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmTOTP", ctx, userId, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfirmTOTP indicates an expected call of ConfirmTOTP.
+func (mr *MockTwoFactorRepositoryInterfaceMockRecorder) ConfirmTOTP(ctx, userId, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmTOTP", reflect.TypeOf((*MockTwoFactorRepositoryInterface)(nil).ConfirmTOTP), ctx, userId, at)
+}
+
+// CreateLoginChallenge mocks base method.
+func (m *MockTwoFactorRepositoryInterface) CreateLoginChallenge(ctx context.Context, challenge *models.LoginChallenge) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLoginChallenge", ctx, challenge)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateLoginChallenge indicates an expected call of CreateLoginChallenge.
+func (mr *MockTwoFactorRepositoryInterfaceMockRecorder) CreateLoginChallenge(ctx, challenge any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoginChallenge", reflect.TypeOf((*MockTwoFactorRepositoryInterface)(nil).CreateLoginChallenge), ctx, challenge)
+}
+
+// DeleteTOTP mocks base method.
+func (m *MockTwoFactorRepositoryInterface) DeleteTOTP(ctx context.Context, userId types.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTOTP", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTOTP indicates an expected call of DeleteTOTP.
+func (mr *MockTwoFactorRepositoryInterfaceMockRecorder) DeleteTOTP(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTOTP", reflect.TypeOf((*MockTwoFactorRepositoryInterface)(nil).DeleteTOTP), ctx, userId)
+}
+
+// GetLoginChallengeByHash mocks base method.
+func (m *MockTwoFactorRepositoryInterface) GetLoginChallengeByHash(ctx context.Context, hash string) (*models.LoginChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoginChallengeByHash", ctx, hash)
+	ret0, _ := ret[0].(*models.LoginChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoginChallengeByHash indicates an expected call of GetLoginChallengeByHash.
+func (mr *MockTwoFactorRepositoryInterfaceMockRecorder) GetLoginChallengeByHash(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoginChallengeByHash", reflect.TypeOf((*MockTwoFactorRepositoryInterface)(nil).GetLoginChallengeByHash), ctx, hash)
+}
+
+// GetTOTP mocks base method.
+func (m *MockTwoFactorRepositoryInterface) GetTOTP(ctx context.Context, userId types.UUID) (*models.TOTP, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTOTP", ctx, userId)
+	ret0, _ := ret[0].(*models.TOTP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTOTP indicates an expected call of GetTOTP.
+func (mr *MockTwoFactorRepositoryInterfaceMockRecorder) GetTOTP(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTOTP", reflect.TypeOf((*MockTwoFactorRepositoryInterface)(nil).GetTOTP), ctx, userId)
+}
+
+// RecordLoginChallengeAttempt mocks base method.
+func (m *MockTwoFactorRepositoryInterface) RecordLoginChallengeAttempt(ctx context.Context, id types.UUID) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordLoginChallengeAttempt", ctx, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordLoginChallengeAttempt indicates an expected call of RecordLoginChallengeAttempt.
+func (mr *MockTwoFactorRepositoryInterfaceMockRecorder) RecordLoginChallengeAttempt(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordLoginChallengeAttempt", reflect.TypeOf((*MockTwoFactorRepositoryInterface)(nil).RecordLoginChallengeAttempt), ctx, id)
+}
+
+// ReplaceRecoveryCodes mocks base method.
+func (m *MockTwoFactorRepositoryInterface) ReplaceRecoveryCodes(ctx context.Context, userId types.UUID, hashes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceRecoveryCodes", ctx, userId, hashes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceRecoveryCodes indicates an expected call of ReplaceRecoveryCodes.
+func (mr *MockTwoFactorRepositoryInterfaceMockRecorder) ReplaceRecoveryCodes(ctx, userId, hashes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceRecoveryCodes", reflect.TypeOf((*MockTwoFactorRepositoryInterface)(nil).ReplaceRecoveryCodes), ctx, userId, hashes)
+}
+
+// SaveTOTP mocks base method.
+func (m *MockTwoFactorRepositoryInterface) SaveTOTP(ctx context.Context, totp *models.TOTP) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveTOTP", ctx, totp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveTOTP indicates an expected call of SaveTOTP.
+func (mr *MockTwoFactorRepositoryInterfaceMockRecorder) SaveTOTP(ctx, totp any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTOTP", reflect.TypeOf((*MockTwoFactorRepositoryInterface)(nil).SaveTOTP), ctx, totp)
+}
+
+// UseLoginChallenge mocks base method.
+func (m *MockTwoFactorRepositoryInterface) UseLoginChallenge(ctx context.Context, id types.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseLoginChallenge", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseLoginChallenge indicates an expected call of UseLoginChallenge.
+func (mr *MockTwoFactorRepositoryInterfaceMockRecorder) UseLoginChallenge(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseLoginChallenge", reflect.TypeOf((*MockTwoFactorRepositoryInterface)(nil).UseLoginChallenge), ctx, id)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockTwoFactorRepositoryInterface) UseRecoveryCode(ctx context.Context, userId types.UUID, hash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", ctx, userId, hash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockTwoFactorRepositoryInterfaceMockRecorder) UseRecoveryCode(ctx, userId, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockTwoFactorRepositoryInterface)(nil).UseRecoveryCode), ctx, userId, hash)
+}
+
+// UseTOTPStep mocks base method.
+func (m *MockTwoFactorRepositoryInterface) UseTOTPStep(ctx context.Context, userId types.UUID, step int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseTOTPStep", ctx, userId, step)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseTOTPStep indicates an expected call of UseTOTPStep.
+func (mr *MockTwoFactorRepositoryInterfaceMockRecorder) UseTOTPStep(ctx, userId, step any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseTOTPStep", reflect.TypeOf((*MockTwoFactorRepositoryInterface)(nil).UseTOTPStep), ctx, userId, step)
+}
+
+// MockIdentityRepositoryInterface is a mock of IdentityRepositoryInterface interface.
+type MockIdentityRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdentityRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockIdentityRepositoryInterfaceMockRecorder is the mock recorder for MockIdentityRepositoryInterface.
+type MockIdentityRepositoryInterfaceMockRecorder struct {
+	mock *MockIdentityRepositoryInterface
+}
+
+// NewMockIdentityRepositoryInterface creates a new mock instance.
+func NewMockIdentityRepositoryInterface(ctrl *gomock.Controller) *MockIdentityRepositoryInterface {
+	mock := &MockIdentityRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockIdentityRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdentityRepositoryInterface) EXPECT() *MockIdentityRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateOIDCLoginState mocks base method.
+func (m *MockIdentityRepositoryInterface) CreateOIDCLoginState(ctx context.Context, state *models.OIDCLoginState) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOIDCLoginState", ctx, state)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOIDCLoginState indicates an expected call of CreateOIDCLoginState.
+func (mr *MockIdentityRepositoryInterfaceMockRecorder) CreateOIDCLoginState(ctx, state any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOIDCLoginState", reflect.TypeOf((*MockIdentityRepositoryInterface)(nil).CreateOIDCLoginState), ctx, state)
+}
+
+// CreateUserIdentity mocks base method.
+func (m *MockIdentityRepositoryInterface) CreateUserIdentity(ctx context.Context, identity *models.UserIdentity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserIdentity", ctx, identity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateUserIdentity indicates an expected call of CreateUserIdentity.
+func (mr *MockIdentityRepositoryInterfaceMockRecorder) CreateUserIdentity(ctx, identity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserIdentity", reflect.TypeOf((*MockIdentityRepositoryInterface)(nil).CreateUserIdentity), ctx, identity)
+}
+
+// GetUserIdentity mocks base method.
+func (m *MockIdentityRepositoryInterface) GetUserIdentity(ctx context.Context, issuer, subject string) (*models.UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserIdentity", ctx, issuer, subject)
+	ret0, _ := ret[0].(*models.UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserIdentity indicates an expected call of GetUserIdentity.
+func (mr *MockIdentityRepositoryInterfaceMockRecorder) GetUserIdentity(ctx, issuer, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserIdentity", reflect.TypeOf((*MockIdentityRepositoryInterface)(nil).GetUserIdentity), ctx, issuer, subject)
+}
+
+// UseOIDCLoginState mocks base method.
+func (m *MockIdentityRepositoryInterface) UseOIDCLoginState(ctx context.Context, stateHash string) (*models.OIDCLoginState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseOIDCLoginState", ctx, stateHash)
+	ret0, _ := ret[0].(*models.OIDCLoginState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseOIDCLoginState indicates an expected call of UseOIDCLoginState.
+func (mr *MockIdentityRepositoryInterfaceMockRecorder) UseOIDCLoginState(ctx, stateHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseOIDCLoginState", reflect.TypeOf((*MockIdentityRepositoryInterface)(nil).UseOIDCLoginState), ctx, stateHash)
+}
+
+// MockTransactionManager is a mock of TransactionManager interface.
+type MockTransactionManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionManagerMockRecorder
+	isgomock struct{}
+}
+
+// MockTransactionManagerMockRecorder is the mock recorder for MockTransactionManager.
+type MockTransactionManagerMockRecorder struct {
+	mock *MockTransactionManager
+}
+
+// NewMockTransactionManager creates a new mock instance.
+func NewMockTransactionManager(ctrl *gomock.Controller) *MockTransactionManager {
+	mock := &MockTransactionManager{ctrl: ctrl}
+	mock.recorder = &MockTransactionManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionManager) EXPECT() *MockTransactionManagerMockRecorder {
+	return m.recorder
 }
 
-// Rollback mocks base method.
-func (m *MockTransactionStorage) Rollback() error {
+// Do mocks base method.
+func (m *MockTransactionManager) Do(ctx context.Context, fn func(context.Context) error) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Rollback")
+	ret := m.ctrl.Call(m, "Do", ctx, fn)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Rollback indicates an expected call of Rollback.
-func (mr *MockTransactionStorageMockRecorder) Rollback() *gomock.Call {
+// Do indicates an expected call of Do.
+func (mr *MockTransactionManagerMockRecorder) Do(ctx, fn any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rollback", reflect.TypeOf((*MockTransactionStorage)(nil).Rollback))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockTransactionManager)(nil).Do), ctx, fn)
 }
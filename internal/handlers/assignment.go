@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+	"github.com/itisalisas/avito-backend/internal/service/assignment"
+	"github.com/itisalisas/avito-backend/internal/utils"
+)
+
+type AssignmentHandler struct {
+	assignmentService assignment.ServiceInterface
+}
+
+func NewAssignmentHandler(assignmentService assignment.ServiceInterface) *AssignmentHandler {
+	return &AssignmentHandler{assignmentService: assignmentService}
+}
+
+func (h *AssignmentHandler) GetAssignedPvzs(w http.ResponseWriter, r *http.Request) {
+	userId, err := uuid.Parse(r.PathValue("userId"))
+	if err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	pvzIds, err := h.assignmentService.GetAssignedPvzIds(r.Context(), userId)
+	switch {
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		utils.WriteResponse(w, pvzIds, http.StatusOK)
+	}
+}
+
+func (h *AssignmentHandler) AssignPvz(w http.ResponseWriter, r *http.Request) {
+	userId, err := uuid.Parse(r.PathValue("userId"))
+	if err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	var request dto.PostUsersUserIdPvzJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	addedAssignment, err := h.assignmentService.AssignPvz(r.Context(), userId, request.PvzId)
+	switch {
+	case errors.Is(err, models.ErrIncorrectUserRole) || errors.Is(err, models.ErrAlreadyAssigned) ||
+		errors.Is(err, models.ErrPvzDecommissioned):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusBadRequest)
+	case errors.Is(err, models.ErrUserNotFound) || errors.Is(err, models.ErrPvzNotFound):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusNotFound)
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		utils.WriteResponse(w, addedAssignment, http.StatusCreated)
+	}
+}
+
+func (h *AssignmentHandler) UnassignPvz(w http.ResponseWriter, r *http.Request) {
+	userId, err := uuid.Parse(r.PathValue("userId"))
+	if err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+	pvzId, err := uuid.Parse(r.PathValue("pvzId"))
+	if err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	err = h.assignmentService.UnassignPvz(r.Context(), userId, pvzId)
+	switch {
+	case errors.Is(err, models.ErrAssignmentNotFound):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusNotFound)
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type stubAssignmentService struct {
+	AssignPvzFunc         func(ctx context.Context, userId, pvzId uuid.UUID) (*dto.PvzAssignment, error)
+	UnassignPvzFunc       func(ctx context.Context, userId, pvzId uuid.UUID) error
+	GetAssignedPvzIdsFunc func(ctx context.Context, userId uuid.UUID) ([]uuid.UUID, error)
+}
+
+func (s *stubAssignmentService) AssignPvz(ctx context.Context, userId, pvzId uuid.UUID) (*dto.PvzAssignment, error) {
+	return s.AssignPvzFunc(ctx, userId, pvzId)
+}
+
+func (s *stubAssignmentService) UnassignPvz(ctx context.Context, userId, pvzId uuid.UUID) error {
+	return s.UnassignPvzFunc(ctx, userId, pvzId)
+}
+
+func (s *stubAssignmentService) GetAssignedPvzIds(ctx context.Context, userId uuid.UUID) ([]uuid.UUID, error) {
+	return s.GetAssignedPvzIdsFunc(ctx, userId)
+}
+
+func TestAssignmentHandler_GetAssignedPvzs(t *testing.T) {
+	userId := uuid.New()
+	pvzId := uuid.New()
+
+	tests := []struct {
+		name           string
+		userId         string
+		serviceReturn  []uuid.UUID
+		serviceErr     error
+		wantStatus     int
+		wantBodySubstr string
+	}{
+		{
+			name:           "invalid user id",
+			userId:         "not-a-uuid",
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "Invalid request",
+		},
+		{
+			name:           "internal err",
+			userId:         userId.String(),
+			serviceErr:     errors.New("db error"),
+			wantStatus:     http.StatusInternalServerError,
+			wantBodySubstr: "db error",
+		},
+		{
+			name:           "success",
+			userId:         userId.String(),
+			serviceReturn:  []uuid.UUID{pvzId},
+			wantStatus:     http.StatusOK,
+			wantBodySubstr: pvzId.String(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubAssignmentService{
+				GetAssignedPvzIdsFunc: func(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+					return tt.serviceReturn, tt.serviceErr
+				},
+			}
+			h := NewAssignmentHandler(stub)
+
+			req := httptest.NewRequest(http.MethodGet, "/users/"+tt.userId+"/pvz", nil)
+			req.SetPathValue("userId", tt.userId)
+			w := httptest.NewRecorder()
+
+			h.GetAssignedPvzs(w, req)
+			resp := w.Result()
+			defer func(Body io.ReadCloser) {
+				err := Body.Close()
+				require.NoError(t, err)
+			}(resp.Body)
+
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			respBody, _ := io.ReadAll(resp.Body)
+			require.Contains(t, string(respBody), tt.wantBodySubstr)
+		})
+	}
+}
+
+func TestAssignmentHandler_AssignPvz(t *testing.T) {
+	userId := uuid.New()
+	pvzId := uuid.New()
+	body := []byte(`{"pvzId":"` + pvzId.String() + `"}`)
+
+	tests := []struct {
+		name           string
+		body           []byte
+		serviceReturn  *dto.PvzAssignment
+		serviceErr     error
+		wantStatus     int
+		wantBodySubstr string
+	}{
+		{
+			name:           "invalid JSON",
+			body:           []byte(`qwerty`),
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "Invalid request",
+		},
+		{
+			name:           "not an employee",
+			body:           body,
+			serviceErr:     models.ErrIncorrectUserRole,
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: models.ErrIncorrectUserRole.Error(),
+		},
+		{
+			name:           "already assigned",
+			body:           body,
+			serviceErr:     models.ErrAlreadyAssigned,
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: models.ErrAlreadyAssigned.Error(),
+		},
+		{
+			name:           "pvz not found",
+			body:           body,
+			serviceErr:     models.ErrPvzNotFound,
+			wantStatus:     http.StatusNotFound,
+			wantBodySubstr: models.ErrPvzNotFound.Error(),
+		},
+		{
+			name:           "success",
+			body:           body,
+			serviceReturn:  &dto.PvzAssignment{UserId: userId, PvzId: pvzId},
+			wantStatus:     http.StatusCreated,
+			wantBodySubstr: `"pvzId":"` + pvzId.String() + `"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubAssignmentService{
+				AssignPvzFunc: func(ctx context.Context, gotUserId, gotPvzId uuid.UUID) (*dto.PvzAssignment, error) {
+					require.Equal(t, userId, gotUserId)
+					require.Equal(t, pvzId, gotPvzId)
+					return tt.serviceReturn, tt.serviceErr
+				},
+			}
+			h := NewAssignmentHandler(stub)
+
+			req := httptest.NewRequest(http.MethodPost, "/users/"+userId.String()+"/pvz", bytes.NewReader(tt.body))
+			req.SetPathValue("userId", userId.String())
+			w := httptest.NewRecorder()
+
+			h.AssignPvz(w, req)
+			resp := w.Result()
+			defer func(Body io.ReadCloser) {
+				err := Body.Close()
+				require.NoError(t, err)
+			}(resp.Body)
+
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			respBody, _ := io.ReadAll(resp.Body)
+			require.Contains(t, string(respBody), tt.wantBodySubstr)
+		})
+	}
+}
+
+func TestAssignmentHandler_UnassignPvz(t *testing.T) {
+	userId := uuid.New()
+	pvzId := uuid.New()
+
+	tests := []struct {
+		name       string
+		pvzId      string
+		serviceErr error
+		wantStatus int
+	}{
+		{
+			name:       "invalid pvz id",
+			pvzId:      "not-a-uuid",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "not assigned",
+			pvzId:      pvzId.String(),
+			serviceErr: models.ErrAssignmentNotFound,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "success",
+			pvzId:      pvzId.String(),
+			wantStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubAssignmentService{
+				UnassignPvzFunc: func(ctx context.Context, gotUserId, gotPvzId uuid.UUID) error {
+					return tt.serviceErr
+				},
+			}
+			h := NewAssignmentHandler(stub)
+
+			req := httptest.NewRequest(http.MethodDelete, "/users/"+userId.String()+"/pvz/"+tt.pvzId, nil)
+			req.SetPathValue("userId", userId.String())
+			req.SetPathValue("pvzId", tt.pvzId)
+			w := httptest.NewRecorder()
+
+			h.UnassignPvz(w, req)
+			require.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
@@ -3,7 +3,11 @@ package handlers
 import (
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
 
 	"github.com/itisalisas/avito-backend/internal/generated/dto"
 	"github.com/itisalisas/avito-backend/internal/models"
@@ -32,8 +36,11 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	user, err := h.authService.Register(r.Context(), request)
 
 	switch {
-	case errors.Is(err, models.ErrIncorrectUserRole) || errors.Is(err, models.ErrEmailAlreadyInUse) || errors.Is(err, models.ErrEmptyEmailOrPassword):
+	case errors.Is(err, models.ErrIncorrectUserRole) || errors.Is(err, models.ErrEmailAlreadyInUse) ||
+		errors.Is(err, models.ErrEmptyEmailOrPassword) || errors.Is(err, models.ErrWeakPassword):
 		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusBadRequest)
+	case errors.Is(err, models.ErrInviteRequired) || errors.Is(err, models.ErrInvalidInvite):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusForbidden)
 	case err != nil:
 		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
 	default:
@@ -41,6 +48,28 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (h *AuthHandler) CreateInvite(w http.ResponseWriter, r *http.Request) {
+	var request dto.PostInvitesJSONRequestBody
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	invite, err := h.authService.CreateInvite(r.Context(), request)
+	switch {
+	case errors.Is(err, models.ErrIncorrectUserRole) || errors.Is(err, models.ErrInvalidInviteTTL) ||
+		errors.Is(err, models.ErrPvzDecommissioned):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusBadRequest)
+	case errors.Is(err, models.ErrPvzNotFound):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusNotFound)
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		utils.WriteResponse(w, invite, http.StatusCreated)
+	}
+}
+
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	var request dto.PostLoginJSONRequestBody
 
@@ -49,14 +78,110 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := h.authService.Login(r.Context(), request)
+	result, err := h.authService.Login(r.Context(), request, utils.ClientIP(r))
 	switch {
-	case errors.Is(err, models.ErrUserNotFound) || errors.Is(err, models.ErrWrongPassword) || errors.Is(err, models.ErrEmptyEmailOrPassword):
+	case errors.Is(err, models.ErrInvalidCredentials) || errors.Is(err, models.ErrEmptyEmailOrPassword):
 		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusUnauthorized)
+	case errors.Is(err, models.ErrUserDeactivated):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusForbidden)
+	case errors.Is(err, models.ErrTooManyLoginAttempts):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusTooManyRequests)
 	case err != nil:
 		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	case result.Challenge != nil:
+		utils.WriteResponse(w, result.Challenge, http.StatusAccepted)
 	default:
-		utils.WriteResponse(w, token, http.StatusOK)
+		utils.WriteResponse(w, result.Tokens, http.StatusOK)
+	}
+}
+
+func (h *AuthHandler) CompleteLogin(w http.ResponseWriter, r *http.Request) {
+	var request dto.PostLogin2faJSONRequestBody
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	pair, err := h.authService.CompleteLogin(r.Context(), request)
+	switch {
+	case errors.Is(err, models.ErrInvalidLoginChallenge) || errors.Is(err, models.ErrInvalidTOTPCode):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusUnauthorized)
+	case errors.Is(err, models.ErrUserDeactivated):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusForbidden)
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		utils.WriteResponse(w, pair, http.StatusOK)
+	}
+}
+
+func (h *AuthHandler) SSOLogin(w http.ResponseWriter, r *http.Request) {
+	authURL, err := h.authService.StartSSOLogin(r.Context())
+	switch {
+	case errors.Is(err, models.ErrSSODisabled):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusNotFound)
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		http.Redirect(w, r, authURL, http.StatusFound)
+	}
+}
+
+func (h *AuthHandler) SSOCallback(w http.ResponseWriter, r *http.Request) {
+	result, err := h.authService.CompleteSSOLogin(r.Context(), parseOidcCallbackParams(r.URL.Query()))
+	switch {
+	case errors.Is(err, models.ErrSSODisabled):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusNotFound)
+	case errors.Is(err, models.ErrEmailAlreadyInUse):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusBadRequest)
+	case errors.Is(err, models.ErrInvalidSSOState) || errors.Is(err, models.ErrSSOLoginFailed):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusUnauthorized)
+	case errors.Is(err, models.ErrSSORoleNotMapped) || errors.Is(err, models.ErrUserDeactivated):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusForbidden)
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	case result.Challenge != nil:
+		utils.WriteResponse(w, result.Challenge, http.StatusAccepted)
+	default:
+		utils.WriteResponse(w, result.Tokens, http.StatusOK)
+	}
+}
+
+func parseOidcCallbackParams(query url.Values) dto.GetOidcCallbackParams {
+	var params dto.GetOidcCallbackParams
+	if code := query.Get("code"); code != "" {
+		params.Code = &code
+	}
+	if state := query.Get("state"); state != "" {
+		params.State = &state
+	}
+	if e := query.Get("error"); e != "" {
+		params.Error = &e
+	}
+	return params
+}
+
+func (h *AuthHandler) StartLoginEnrollment(w http.ResponseWriter, r *http.Request) {
+	var request dto.PostLogin2faEnrollJSONRequestBody
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	enrollment, err := h.authService.StartLoginEnrollment(r.Context(), request)
+	switch {
+	case errors.Is(err, models.ErrTOTPAlreadyEnabled):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusBadRequest)
+	case errors.Is(err, models.ErrInvalidLoginChallenge):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusUnauthorized)
+	case errors.Is(err, models.ErrUserDeactivated):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusForbidden)
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		utils.WriteResponse(w, enrollment, http.StatusOK)
 	}
 }
 
@@ -71,6 +196,8 @@ func (h *AuthHandler) DummyLogin(w http.ResponseWriter, r *http.Request) {
 	token, err := h.authService.DummyLogin(request)
 
 	switch {
+	case errors.Is(err, models.ErrDummyLoginDisabled):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusNotFound)
 	case errors.Is(err, models.ErrIncorrectUserRole):
 		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusBadRequest)
 	case err != nil:
@@ -79,3 +206,195 @@ func (h *AuthHandler) DummyLogin(w http.ResponseWriter, r *http.Request) {
 		utils.WriteResponse(w, token, http.StatusOK)
 	}
 }
+
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var request dto.PostTokenRefreshJSONRequestBody
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	pair, err := h.authService.Refresh(r.Context(), request)
+	switch {
+	case errors.Is(err, models.ErrInvalidRefreshToken):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusUnauthorized)
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		utils.WriteResponse(w, pair, http.StatusOK)
+	}
+}
+
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	var request dto.PostLogoutJSONRequestBody
+
+	// The body is optional.
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && !errors.Is(err, io.EOF) {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	err := h.authService.Logout(r.Context(), request)
+	switch {
+	case errors.Is(err, models.ErrUserNotFound):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusUnauthorized)
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (h *AuthHandler) UnlockUser(w http.ResponseWriter, r *http.Request) {
+	userId, err := uuid.Parse(r.PathValue("userId"))
+	if err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	err = h.authService.UnlockUser(r.Context(), userId)
+	switch {
+	case errors.Is(err, models.ErrUserNotFound):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusNotFound)
+	case errors.Is(err, models.ErrPermissionDenied):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusForbidden)
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (h *AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
+	user, err := h.authService.CurrentUser(r.Context())
+	switch {
+	case errors.Is(err, models.ErrUserNotFound):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusUnauthorized)
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		utils.WriteResponse(w, user, http.StatusOK)
+	}
+}
+
+func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	var request dto.PostMePasswordJSONRequestBody
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	err := h.authService.ChangePassword(r.Context(), request)
+	switch {
+	case errors.Is(err, models.ErrWeakPassword):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusBadRequest)
+	case errors.Is(err, models.ErrUserNotFound):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusUnauthorized)
+	case errors.Is(err, models.ErrInvalidCredentials):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusForbidden)
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (h *AuthHandler) RequestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	var request dto.PostPasswordResetJSONRequestBody
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	err := h.authService.RequestPasswordReset(r.Context(), request)
+	switch {
+	case errors.Is(err, models.ErrEmptyEmailOrPassword):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusBadRequest)
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var request dto.PostPasswordResetConfirmJSONRequestBody
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	err := h.authService.ResetPassword(r.Context(), request)
+	switch {
+	case errors.Is(err, models.ErrInvalidResetToken) || errors.Is(err, models.ErrWeakPassword):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusBadRequest)
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (h *AuthHandler) StartTOTPEnrollment(w http.ResponseWriter, r *http.Request) {
+	enrollment, err := h.authService.StartTOTPEnrollment(r.Context())
+	switch {
+	case errors.Is(err, models.ErrTOTPAlreadyEnabled):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusBadRequest)
+	case errors.Is(err, models.ErrUserNotFound):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusUnauthorized)
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		utils.WriteResponse(w, enrollment, http.StatusOK)
+	}
+}
+
+func (h *AuthHandler) ConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	var request dto.PostMe2faConfirmJSONRequestBody
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	err := h.authService.ConfirmTOTP(r.Context(), request)
+	switch {
+	case errors.Is(err, models.ErrTOTPNotFound) || errors.Is(err, models.ErrTOTPAlreadyEnabled):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusBadRequest)
+	case errors.Is(err, models.ErrUserNotFound):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusUnauthorized)
+	case errors.Is(err, models.ErrInvalidTOTPCode):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusForbidden)
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (h *AuthHandler) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+	var request dto.PostMe2faDisableJSONRequestBody
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	err := h.authService.DisableTOTP(r.Context(), request)
+	switch {
+	case errors.Is(err, models.ErrTOTPNotFound):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusBadRequest)
+	case errors.Is(err, models.ErrUserNotFound):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusUnauthorized)
+	case errors.Is(err, models.ErrInvalidTOTPCode) || errors.Is(err, models.ErrTOTPRequired):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusForbidden)
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -5,33 +5,99 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/google/uuid"
 	openapi_types "github.com/oapi-codegen/runtime/types"
 	"github.com/stretchr/testify/require"
 
 	"github.com/itisalisas/avito-backend/internal/generated/dto"
 	"github.com/itisalisas/avito-backend/internal/models"
+	"github.com/itisalisas/avito-backend/internal/service/auth"
 )
 
 type stubAuthService struct {
-	RegisterFunc   func(ctx context.Context, request dto.PostRegisterJSONRequestBody) (*dto.User, error)
-	LoginFunc      func(ctx context.Context, request dto.PostLoginJSONRequestBody) (*dto.Token, error)
-	DummyLoginFunc func(request dto.PostDummyLoginJSONRequestBody) (*dto.Token, error)
+	RegisterFunc             func(ctx context.Context, request dto.PostRegisterJSONRequestBody) (*dto.User, error)
+	LoginFunc                func(ctx context.Context, request dto.PostLoginJSONRequestBody, clientIP string) (*auth.LoginResult, error)
+	CompleteLoginFunc        func(ctx context.Context, request dto.PostLogin2faJSONRequestBody) (*dto.TokenPair, error)
+	StartLoginEnrollmentFunc func(ctx context.Context, request dto.PostLogin2faEnrollJSONRequestBody) (*dto.TotpEnrollment, error)
+	DummyLoginFunc           func(request dto.PostDummyLoginJSONRequestBody) (*dto.Token, error)
+	CurrentUserFunc          func(ctx context.Context) (*dto.User, error)
+	RefreshFunc              func(ctx context.Context, request dto.PostTokenRefreshJSONRequestBody) (*dto.TokenPair, error)
+	LogoutFunc               func(ctx context.Context, request dto.PostLogoutJSONRequestBody) error
+	CreateInviteFunc         func(ctx context.Context, request dto.PostInvitesJSONRequestBody) (*dto.Invite, error)
+	UnlockUserFunc           func(ctx context.Context, userId uuid.UUID) error
+	ChangePasswordFunc       func(ctx context.Context, request dto.PostMePasswordJSONRequestBody) error
+	RequestPasswordResetFunc func(ctx context.Context, request dto.PostPasswordResetJSONRequestBody) error
+	ResetPasswordFunc        func(ctx context.Context, request dto.PostPasswordResetConfirmJSONRequestBody) error
+	StartTOTPEnrollmentFunc  func(ctx context.Context) (*dto.TotpEnrollment, error)
+	ConfirmTOTPFunc          func(ctx context.Context, request dto.PostMe2faConfirmJSONRequestBody) error
+	DisableTOTPFunc          func(ctx context.Context, request dto.PostMe2faDisableJSONRequestBody) error
+	StartSSOLoginFunc        func(ctx context.Context) (string, error)
+	CompleteSSOLoginFunc     func(ctx context.Context, params dto.GetOidcCallbackParams) (*auth.LoginResult, error)
 }
 
 func (s *stubAuthService) Register(ctx context.Context, request dto.PostRegisterJSONRequestBody) (*dto.User, error) {
 	return s.RegisterFunc(ctx, request)
 }
-func (s *stubAuthService) Login(ctx context.Context, request dto.PostLoginJSONRequestBody) (*dto.Token, error) {
-	return s.LoginFunc(ctx, request)
+func (s *stubAuthService) Login(ctx context.Context, request dto.PostLoginJSONRequestBody, clientIP string) (*auth.LoginResult, error) {
+	return s.LoginFunc(ctx, request, clientIP)
+}
+func (s *stubAuthService) CompleteLogin(ctx context.Context, request dto.PostLogin2faJSONRequestBody) (*dto.TokenPair, error) {
+	return s.CompleteLoginFunc(ctx, request)
+}
+func (s *stubAuthService) StartLoginEnrollment(ctx context.Context, request dto.PostLogin2faEnrollJSONRequestBody) (*dto.TotpEnrollment, error) {
+	return s.StartLoginEnrollmentFunc(ctx, request)
+}
+func (s *stubAuthService) StartTOTPEnrollment(ctx context.Context) (*dto.TotpEnrollment, error) {
+	return s.StartTOTPEnrollmentFunc(ctx)
+}
+func (s *stubAuthService) ConfirmTOTP(ctx context.Context, request dto.PostMe2faConfirmJSONRequestBody) error {
+	return s.ConfirmTOTPFunc(ctx, request)
+}
+func (s *stubAuthService) DisableTOTP(ctx context.Context, request dto.PostMe2faDisableJSONRequestBody) error {
+	return s.DisableTOTPFunc(ctx, request)
+}
+func (s *stubAuthService) StartSSOLogin(ctx context.Context) (string, error) {
+	return s.StartSSOLoginFunc(ctx)
+}
+func (s *stubAuthService) CompleteSSOLogin(ctx context.Context, params dto.GetOidcCallbackParams) (*auth.LoginResult, error) {
+	return s.CompleteSSOLoginFunc(ctx, params)
 }
 func (s *stubAuthService) DummyLogin(request dto.PostDummyLoginJSONRequestBody) (*dto.Token, error) {
 	return s.DummyLoginFunc(request)
 }
+func (s *stubAuthService) CurrentUser(ctx context.Context) (*dto.User, error) {
+	return s.CurrentUserFunc(ctx)
+}
+func (s *stubAuthService) Refresh(ctx context.Context, request dto.PostTokenRefreshJSONRequestBody) (*dto.TokenPair, error) {
+	return s.RefreshFunc(ctx, request)
+}
+func (s *stubAuthService) Logout(ctx context.Context, request dto.PostLogoutJSONRequestBody) error {
+	return s.LogoutFunc(ctx, request)
+}
+func (s *stubAuthService) IsTokenRevoked(context.Context, string) (bool, error) {
+	return false, nil
+}
+func (s *stubAuthService) CreateInvite(ctx context.Context, request dto.PostInvitesJSONRequestBody) (*dto.Invite, error) {
+	return s.CreateInviteFunc(ctx, request)
+}
+func (s *stubAuthService) UnlockUser(ctx context.Context, userId uuid.UUID) error {
+	return s.UnlockUserFunc(ctx, userId)
+}
+func (s *stubAuthService) ChangePassword(ctx context.Context, request dto.PostMePasswordJSONRequestBody) error {
+	return s.ChangePasswordFunc(ctx, request)
+}
+func (s *stubAuthService) RequestPasswordReset(ctx context.Context, request dto.PostPasswordResetJSONRequestBody) error {
+	return s.RequestPasswordResetFunc(ctx, request)
+}
+func (s *stubAuthService) ResetPassword(ctx context.Context, request dto.PostPasswordResetConfirmJSONRequestBody) error {
+	return s.ResetPasswordFunc(ctx, request)
+}
 
 func TestAuthHandler_Register(t *testing.T) {
 	invalidJSON := []byte(`{"email":}`)
@@ -63,6 +129,20 @@ func TestAuthHandler_Register(t *testing.T) {
 			wantStatus:     http.StatusBadRequest,
 			wantBodySubstr: models.ErrEmailAlreadyInUse.Error(),
 		},
+		{
+			name:           "invite missing -> 403",
+			body:           []byte(`{"email":"x@y.z","password":"p","role":"moderator"}`),
+			serviceErr:     models.ErrInviteRequired,
+			wantStatus:     http.StatusForbidden,
+			wantBodySubstr: models.ErrInviteRequired.Error(),
+		},
+		{
+			name:           "invalid invite -> 403",
+			body:           []byte(`{"email":"x@y.z","password":"p","role":"moderator","inviteCode":"used"}`),
+			serviceErr:     models.ErrInvalidInvite,
+			wantStatus:     http.StatusForbidden,
+			wantBodySubstr: models.ErrInvalidInvite.Error(),
+		},
 		{
 			name:           "internal error -> 500",
 			body:           []byte(`{"email":"u@v.w","password":"p","role":"moderator"}`),
@@ -111,7 +191,7 @@ func TestAuthHandler_Login(t *testing.T) {
 	tests := []struct {
 		name           string
 		body           []byte
-		serviceToken   dto.Token
+		serviceResult  auth.LoginResult
 		serviceErr     error
 		wantStatus     int
 		wantBodySubstr string
@@ -123,18 +203,25 @@ func TestAuthHandler_Login(t *testing.T) {
 			wantBodySubstr: "Invalid request",
 		},
 		{
-			name:           "user not found -> 401",
+			name:           "invalid credentials -> 401",
 			body:           []byte(`{"email":"a@b.c","password":"p"}`),
-			serviceErr:     models.ErrUserNotFound,
+			serviceErr:     models.ErrInvalidCredentials,
 			wantStatus:     http.StatusUnauthorized,
-			wantBodySubstr: models.ErrUserNotFound.Error(),
+			wantBodySubstr: models.ErrInvalidCredentials.Error(),
 		},
 		{
-			name:           "wrong password -> 401",
+			name:           "deactivated -> 403",
 			body:           []byte(`{"email":"a@b.c","password":"p"}`),
-			serviceErr:     models.ErrWrongPassword,
-			wantStatus:     http.StatusUnauthorized,
-			wantBodySubstr: models.ErrWrongPassword.Error(),
+			serviceErr:     models.ErrUserDeactivated,
+			wantStatus:     http.StatusForbidden,
+			wantBodySubstr: models.ErrUserDeactivated.Error(),
+		},
+		{
+			name:           "throttled -> 429",
+			body:           []byte(`{"email":"a@b.c","password":"p"}`),
+			serviceErr:     models.ErrTooManyLoginAttempts,
+			wantStatus:     http.StatusTooManyRequests,
+			wantBodySubstr: models.ErrTooManyLoginAttempts.Error(),
 		},
 		{
 			name:           "internal error -> 500",
@@ -146,22 +233,34 @@ func TestAuthHandler_Login(t *testing.T) {
 		{
 			name:           "success -> 200",
 			body:           []byte(`{"email":"u@v.w","password":"p"}`),
-			serviceToken:   "token123",
+			serviceResult:  auth.LoginResult{Tokens: &dto.TokenPair{AccessToken: "token123", RefreshToken: "refresh123"}},
 			wantStatus:     http.StatusOK,
-			wantBodySubstr: `"token123"`,
+			wantBodySubstr: `"accessToken":"token123","refreshToken":"refresh123"`,
+		},
+		{
+			name:           "second factor required -> 202",
+			body:           []byte(`{"email":"u@v.w","password":"p"}`),
+			serviceResult:  auth.LoginResult{Challenge: &dto.LoginChallenge{ChallengeToken: "challenge123"}},
+			wantStatus:     http.StatusAccepted,
+			wantBodySubstr: `"challengeToken":"challenge123"`,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			stub := &stubAuthService{
-				LoginFunc: func(ctx context.Context, req dto.PostLoginJSONRequestBody) (*dto.Token, error) {
-					return &tt.serviceToken, tt.serviceErr
+				LoginFunc: func(ctx context.Context, req dto.PostLoginJSONRequestBody, clientIP string) (*auth.LoginResult, error) {
+					require.Equal(t, "192.0.2.1", clientIP)
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &tt.serviceResult, nil
 				},
 			}
 			h := NewAuthHandler(stub)
 
 			req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewReader(tt.body))
+			req.RemoteAddr = "192.0.2.1:1234"
 			w := httptest.NewRecorder()
 
 			h.Login(w, req)
@@ -200,6 +299,13 @@ func TestAuthHandler_DummyLogin(t *testing.T) {
 			wantStatus:     http.StatusBadRequest,
 			wantBodySubstr: models.ErrIncorrectUserRole.Error(),
 		},
+		{
+			name:           "disabled",
+			body:           []byte(`{"role":"employee"}`),
+			serviceErr:     models.ErrDummyLoginDisabled,
+			wantStatus:     http.StatusNotFound,
+			wantBodySubstr: models.ErrDummyLoginDisabled.Error(),
+		},
 		{
 			name:           "internal err",
 			body:           []byte(`{"email":"a@b.c","role":"user"}`),
@@ -241,3 +347,825 @@ func TestAuthHandler_DummyLogin(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthHandler_Me(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceUser    *dto.User
+		serviceErr     error
+		wantStatus     int
+		wantBodySubstr string
+	}{
+		{
+			name:           "user not found",
+			serviceErr:     models.ErrUserNotFound,
+			wantStatus:     http.StatusUnauthorized,
+			wantBodySubstr: models.ErrUserNotFound.Error(),
+		},
+		{
+			name:           "internal err",
+			serviceErr:     errors.New("db error"),
+			wantStatus:     http.StatusInternalServerError,
+			wantBodySubstr: "db error",
+		},
+		{
+			name:           "success",
+			serviceUser:    &dto.User{Email: "a@b.c", Role: dto.UserRoleEmployee},
+			wantStatus:     http.StatusOK,
+			wantBodySubstr: `"email":"a@b.c"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubAuthService{
+				CurrentUserFunc: func(ctx context.Context) (*dto.User, error) {
+					return tt.serviceUser, tt.serviceErr
+				},
+			}
+			h := NewAuthHandler(stub)
+
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			w := httptest.NewRecorder()
+
+			h.Me(w, req)
+			resp := w.Result()
+			defer func(Body io.ReadCloser) {
+				err := Body.Close()
+				require.NoError(t, err)
+			}(resp.Body)
+
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+			respBody, _ := io.ReadAll(resp.Body)
+			require.Contains(t, string(respBody), tt.wantBodySubstr)
+		})
+	}
+}
+
+func TestAuthHandler_Refresh(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           []byte
+		servicePair    *dto.TokenPair
+		serviceErr     error
+		wantStatus     int
+		wantBodySubstr string
+	}{
+		{
+			name:           "invalid JSON",
+			body:           []byte(`{"refreshToken":}`),
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "Invalid request",
+		},
+		{
+			name:           "invalid refresh token",
+			body:           []byte(`{"refreshToken":"old"}`),
+			serviceErr:     models.ErrInvalidRefreshToken,
+			wantStatus:     http.StatusUnauthorized,
+			wantBodySubstr: models.ErrInvalidRefreshToken.Error(),
+		},
+		{
+			name:           "internal err",
+			body:           []byte(`{"refreshToken":"old"}`),
+			serviceErr:     errors.New("db error"),
+			wantStatus:     http.StatusInternalServerError,
+			wantBodySubstr: "db error",
+		},
+		{
+			name:           "success",
+			body:           []byte(`{"refreshToken":"old"}`),
+			servicePair:    &dto.TokenPair{AccessToken: "access", RefreshToken: "new"},
+			wantStatus:     http.StatusOK,
+			wantBodySubstr: `"refreshToken":"new"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubAuthService{
+				RefreshFunc: func(ctx context.Context, req dto.PostTokenRefreshJSONRequestBody) (*dto.TokenPair, error) {
+					require.Equal(t, "old", req.RefreshToken)
+					return tt.servicePair, tt.serviceErr
+				},
+			}
+			h := NewAuthHandler(stub)
+
+			req := httptest.NewRequest(http.MethodPost, "/token/refresh", bytes.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			h.Refresh(w, req)
+			resp := w.Result()
+			defer func(Body io.ReadCloser) {
+				err := Body.Close()
+				require.NoError(t, err)
+			}(resp.Body)
+
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+			respBody, _ := io.ReadAll(resp.Body)
+			require.Contains(t, string(respBody), tt.wantBodySubstr)
+		})
+	}
+}
+
+func TestAuthHandler_Logout(t *testing.T) {
+	refreshToken := "refresh"
+
+	tests := []struct {
+		name           string
+		body           []byte
+		wantRequest    dto.PostLogoutJSONRequestBody
+		serviceErr     error
+		wantStatus     int
+		wantBodySubstr string
+	}{
+		{
+			name:           "invalid JSON",
+			body:           []byte(`{"refreshToken":}`),
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "Invalid request",
+		},
+		{
+			name:       "without body",
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:        "with refresh token",
+			body:        []byte(`{"refreshToken":"refresh"}`),
+			wantRequest: dto.PostLogoutJSONRequestBody{RefreshToken: &refreshToken},
+			wantStatus:  http.StatusNoContent,
+		},
+		{
+			name:           "internal err",
+			serviceErr:     errors.New("db error"),
+			wantStatus:     http.StatusInternalServerError,
+			wantBodySubstr: "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubAuthService{
+				LogoutFunc: func(ctx context.Context, req dto.PostLogoutJSONRequestBody) error {
+					require.Equal(t, tt.wantRequest, req)
+					return tt.serviceErr
+				},
+			}
+			h := NewAuthHandler(stub)
+
+			req := httptest.NewRequest(http.MethodPost, "/logout", bytes.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			h.Logout(w, req)
+			resp := w.Result()
+			defer func(Body io.ReadCloser) {
+				err := Body.Close()
+				require.NoError(t, err)
+			}(resp.Body)
+
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+			respBody, _ := io.ReadAll(resp.Body)
+			require.Contains(t, string(respBody), tt.wantBodySubstr)
+		})
+	}
+}
+
+func TestAuthHandler_CreateInvite(t *testing.T) {
+	code := "invite-code"
+	tests := []struct {
+		name           string
+		body           []byte
+		serviceReturn  *dto.Invite
+		serviceErr     error
+		wantStatus     int
+		wantBodySubstr string
+	}{
+		{
+			name:           "invalid JSON",
+			body:           []byte(`{"role":}`),
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "Invalid request",
+		},
+		{
+			name:           "bad ttl -> 400",
+			body:           []byte(`{"role":"employee","ttlHours":0}`),
+			serviceErr:     models.ErrInvalidInviteTTL,
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: models.ErrInvalidInviteTTL.Error(),
+		},
+		{
+			name:           "decommissioned pvz -> 400",
+			body:           []byte(`{"role":"employee","pvzIds":["6f1c7c5e-3a43-4b0f-9a1e-1f2d3c4b5a69"]}`),
+			serviceErr:     models.ErrPvzDecommissioned,
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: models.ErrPvzDecommissioned.Error(),
+		},
+		{
+			name:           "unknown pvz -> 404",
+			body:           []byte(`{"role":"employee","pvzIds":["6f1c7c5e-3a43-4b0f-9a1e-1f2d3c4b5a69"]}`),
+			serviceErr:     models.ErrPvzNotFound,
+			wantStatus:     http.StatusNotFound,
+			wantBodySubstr: models.ErrPvzNotFound.Error(),
+		},
+		{
+			name:           "internal error -> 500",
+			body:           []byte(`{"role":"moderator"}`),
+			serviceErr:     errors.New("boom"),
+			wantStatus:     http.StatusInternalServerError,
+			wantBodySubstr: "boom",
+		},
+		{
+			name:           "success -> 201",
+			body:           []byte(`{"role":"moderator"}`),
+			serviceReturn:  &dto.Invite{Code: &code, Role: dto.InviteRoleModerator, PvzIds: []openapi_types.UUID{}},
+			wantStatus:     http.StatusCreated,
+			wantBodySubstr: `"code":"invite-code"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubAuthService{
+				CreateInviteFunc: func(ctx context.Context, req dto.PostInvitesJSONRequestBody) (*dto.Invite, error) {
+					return tt.serviceReturn, tt.serviceErr
+				},
+			}
+			h := NewAuthHandler(stub)
+
+			req := httptest.NewRequest(http.MethodPost, "/invites", bytes.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			h.CreateInvite(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+			require.Contains(t, w.Body.String(), tt.wantBodySubstr)
+		})
+	}
+}
+
+func TestAuthHandler_UnlockUser(t *testing.T) {
+	userId := uuid.New()
+	tests := []struct {
+		name           string
+		userId         string
+		serviceErr     error
+		wantStatus     int
+		wantBodySubstr string
+	}{
+		{
+			name:           "invalid user id",
+			userId:         "not-a-uuid",
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "Invalid request",
+		},
+		{
+			name:           "user not found -> 404",
+			userId:         userId.String(),
+			serviceErr:     models.ErrUserNotFound,
+			wantStatus:     http.StatusNotFound,
+			wantBodySubstr: models.ErrUserNotFound.Error(),
+		},
+		{
+			name:           "admin account -> 403",
+			userId:         userId.String(),
+			serviceErr:     models.ErrPermissionDenied,
+			wantStatus:     http.StatusForbidden,
+			wantBodySubstr: models.ErrPermissionDenied.Error(),
+		},
+		{
+			name:           "internal error -> 500",
+			userId:         userId.String(),
+			serviceErr:     errors.New("boom"),
+			wantStatus:     http.StatusInternalServerError,
+			wantBodySubstr: "boom",
+		},
+		{
+			name:       "success -> 204",
+			userId:     userId.String(),
+			wantStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubAuthService{
+				UnlockUserFunc: func(ctx context.Context, id uuid.UUID) error {
+					require.Equal(t, userId, id)
+					return tt.serviceErr
+				},
+			}
+			h := NewAuthHandler(stub)
+
+			req := httptest.NewRequest(http.MethodPost, "/users/"+tt.userId+"/unlock", nil)
+			req.SetPathValue("userId", tt.userId)
+			w := httptest.NewRecorder()
+
+			h.UnlockUser(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+			require.Contains(t, w.Body.String(), tt.wantBodySubstr)
+		})
+	}
+}
+
+func TestAuthHandler_ChangePassword(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           []byte
+		serviceErr     error
+		wantStatus     int
+		wantBodySubstr string
+	}{
+		{
+			name:           "invalid JSON",
+			body:           []byte(`{"currentPassword":}`),
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "Invalid request",
+		},
+		{
+			name:           "weak password -> 400",
+			body:           []byte(`{"currentPassword":"old","newPassword":"short"}`),
+			serviceErr:     fmt.Errorf("%w: too short", models.ErrWeakPassword),
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: models.ErrWeakPassword.Error(),
+		},
+		{
+			name:           "unknown user -> 401",
+			body:           []byte(`{"currentPassword":"old","newPassword":"password123"}`),
+			serviceErr:     models.ErrUserNotFound,
+			wantStatus:     http.StatusUnauthorized,
+			wantBodySubstr: models.ErrUserNotFound.Error(),
+		},
+		{
+			name:           "wrong current password -> 403",
+			body:           []byte(`{"currentPassword":"old","newPassword":"password123"}`),
+			serviceErr:     models.ErrInvalidCredentials,
+			wantStatus:     http.StatusForbidden,
+			wantBodySubstr: models.ErrInvalidCredentials.Error(),
+		},
+		{
+			name:       "success -> 204",
+			body:       []byte(`{"currentPassword":"old","newPassword":"password123"}`),
+			wantStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubAuthService{
+				ChangePasswordFunc: func(ctx context.Context, req dto.PostMePasswordJSONRequestBody) error {
+					require.Equal(t, "old", req.CurrentPassword)
+					return tt.serviceErr
+				},
+			}
+			h := NewAuthHandler(stub)
+
+			req := httptest.NewRequest(http.MethodPost, "/me/password", bytes.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			h.ChangePassword(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+			require.Contains(t, w.Body.String(), tt.wantBodySubstr)
+		})
+	}
+}
+
+func TestAuthHandler_RequestPasswordReset(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           []byte
+		serviceErr     error
+		wantStatus     int
+		wantBodySubstr string
+	}{
+		{
+			name:           "invalid JSON",
+			body:           []byte(`{"email":}`),
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "Invalid request",
+		},
+		{
+			name:           "missing email -> 400",
+			body:           []byte(`{}`),
+			serviceErr:     models.ErrEmptyEmailOrPassword,
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: models.ErrEmptyEmailOrPassword.Error(),
+		},
+		{
+			name:           "internal error -> 500",
+			body:           []byte(`{"email":"user@example.com"}`),
+			serviceErr:     errors.New("db error"),
+			wantStatus:     http.StatusInternalServerError,
+			wantBodySubstr: "db error",
+		},
+		{
+			name:       "accepted -> 202",
+			body:       []byte(`{"email":"user@example.com"}`),
+			wantStatus: http.StatusAccepted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubAuthService{
+				RequestPasswordResetFunc: func(ctx context.Context, req dto.PostPasswordResetJSONRequestBody) error {
+					return tt.serviceErr
+				},
+			}
+			h := NewAuthHandler(stub)
+
+			req := httptest.NewRequest(http.MethodPost, "/password/reset", bytes.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			h.RequestPasswordReset(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+			require.Contains(t, w.Body.String(), tt.wantBodySubstr)
+		})
+	}
+}
+
+func TestAuthHandler_ResetPassword(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           []byte
+		serviceErr     error
+		wantStatus     int
+		wantBodySubstr string
+	}{
+		{
+			name:           "invalid JSON",
+			body:           []byte(`{"token":}`),
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "Invalid request",
+		},
+		{
+			name:           "invalid token -> 400",
+			body:           []byte(`{"token":"reset","newPassword":"password123"}`),
+			serviceErr:     models.ErrInvalidResetToken,
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: models.ErrInvalidResetToken.Error(),
+		},
+		{
+			name:           "weak password -> 400",
+			body:           []byte(`{"token":"reset","newPassword":"short"}`),
+			serviceErr:     fmt.Errorf("%w: too short", models.ErrWeakPassword),
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: models.ErrWeakPassword.Error(),
+		},
+		{
+			name:       "success -> 204",
+			body:       []byte(`{"token":"reset","newPassword":"password123"}`),
+			wantStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubAuthService{
+				ResetPasswordFunc: func(ctx context.Context, req dto.PostPasswordResetConfirmJSONRequestBody) error {
+					require.Equal(t, "reset", req.Token)
+					return tt.serviceErr
+				},
+			}
+			h := NewAuthHandler(stub)
+
+			req := httptest.NewRequest(http.MethodPost, "/password/reset/confirm", bytes.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			h.ResetPassword(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+			require.Contains(t, w.Body.String(), tt.wantBodySubstr)
+		})
+	}
+}
+
+func TestAuthHandler_CompleteLogin(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           []byte
+		serviceErr     error
+		wantStatus     int
+		wantBodySubstr string
+	}{
+		{
+			name:           "invalid JSON",
+			body:           []byte(`{"challengeToken":}`),
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "Invalid request",
+		},
+		{
+			name:           "invalid challenge -> 401",
+			body:           []byte(`{"challengeToken":"challenge","code":"123456"}`),
+			serviceErr:     models.ErrInvalidLoginChallenge,
+			wantStatus:     http.StatusUnauthorized,
+			wantBodySubstr: models.ErrInvalidLoginChallenge.Error(),
+		},
+		{
+			name:           "wrong code -> 401",
+			body:           []byte(`{"challengeToken":"challenge","code":"123456"}`),
+			serviceErr:     models.ErrInvalidTOTPCode,
+			wantStatus:     http.StatusUnauthorized,
+			wantBodySubstr: models.ErrInvalidTOTPCode.Error(),
+		},
+		{
+			name:           "deactivated -> 403",
+			body:           []byte(`{"challengeToken":"challenge","code":"123456"}`),
+			serviceErr:     models.ErrUserDeactivated,
+			wantStatus:     http.StatusForbidden,
+			wantBodySubstr: models.ErrUserDeactivated.Error(),
+		},
+		{
+			name:           "success -> 200",
+			body:           []byte(`{"challengeToken":"challenge","code":"123456"}`),
+			wantStatus:     http.StatusOK,
+			wantBodySubstr: `"accessToken":"token123"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubAuthService{
+				CompleteLoginFunc: func(ctx context.Context, req dto.PostLogin2faJSONRequestBody) (*dto.TokenPair, error) {
+					require.Equal(t, "challenge", req.ChallengeToken)
+					require.Equal(t, "123456", req.Code)
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &dto.TokenPair{AccessToken: "token123", RefreshToken: "refresh123"}, nil
+				},
+			}
+			h := NewAuthHandler(stub)
+
+			req := httptest.NewRequest(http.MethodPost, "/login/2fa", bytes.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			h.CompleteLogin(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+			require.Contains(t, w.Body.String(), tt.wantBodySubstr)
+		})
+	}
+}
+
+func TestAuthHandler_StartTOTPEnrollment(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceErr     error
+		wantStatus     int
+		wantBodySubstr string
+	}{
+		{
+			name:           "already enabled -> 400",
+			serviceErr:     models.ErrTOTPAlreadyEnabled,
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: models.ErrTOTPAlreadyEnabled.Error(),
+		},
+		{
+			name:           "unknown user -> 401",
+			serviceErr:     models.ErrUserNotFound,
+			wantStatus:     http.StatusUnauthorized,
+			wantBodySubstr: models.ErrUserNotFound.Error(),
+		},
+		{
+			name:           "success -> 200",
+			wantStatus:     http.StatusOK,
+			wantBodySubstr: `"secret":"SECRET"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubAuthService{
+				StartTOTPEnrollmentFunc: func(ctx context.Context) (*dto.TotpEnrollment, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &dto.TotpEnrollment{Secret: "SECRET", RecoveryCodes: []string{"abcde-fghjk"}}, nil
+				},
+			}
+			h := NewAuthHandler(stub)
+
+			req := httptest.NewRequest(http.MethodPost, "/me/2fa", nil)
+			w := httptest.NewRecorder()
+
+			h.StartTOTPEnrollment(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+			require.Contains(t, w.Body.String(), tt.wantBodySubstr)
+		})
+	}
+}
+
+func TestAuthHandler_DisableTOTP(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           []byte
+		serviceErr     error
+		wantStatus     int
+		wantBodySubstr string
+	}{
+		{
+			name:           "invalid JSON",
+			body:           []byte(`{"code":}`),
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "Invalid request",
+		},
+		{
+			name:           "not set up -> 400",
+			body:           []byte(`{"code":"123456"}`),
+			serviceErr:     models.ErrTOTPNotFound,
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: models.ErrTOTPNotFound.Error(),
+		},
+		{
+			name:           "wrong code -> 403",
+			body:           []byte(`{"code":"123456"}`),
+			serviceErr:     models.ErrInvalidTOTPCode,
+			wantStatus:     http.StatusForbidden,
+			wantBodySubstr: models.ErrInvalidTOTPCode.Error(),
+		},
+		{
+			name:           "required for role -> 403",
+			body:           []byte(`{"code":"123456"}`),
+			serviceErr:     models.ErrTOTPRequired,
+			wantStatus:     http.StatusForbidden,
+			wantBodySubstr: models.ErrTOTPRequired.Error(),
+		},
+		{
+			name:       "success -> 204",
+			body:       []byte(`{"code":"123456"}`),
+			wantStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubAuthService{
+				DisableTOTPFunc: func(ctx context.Context, req dto.PostMe2faDisableJSONRequestBody) error {
+					require.Equal(t, "123456", req.Code)
+					return tt.serviceErr
+				},
+			}
+			h := NewAuthHandler(stub)
+
+			req := httptest.NewRequest(http.MethodPost, "/me/2fa/disable", bytes.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			h.DisableTOTP(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+			require.Contains(t, w.Body.String(), tt.wantBodySubstr)
+		})
+	}
+}
+
+func TestAuthHandler_SSOLogin(t *testing.T) {
+	tests := []struct {
+		name         string
+		serviceURL   string
+		serviceErr   error
+		wantStatus   int
+		wantLocation string
+	}{
+		{
+			name:       "disabled -> 404",
+			serviceErr: models.ErrSSODisabled,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "internal err",
+			serviceErr: errors.New("err"),
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:         "success -> 302",
+			serviceURL:   "https://idp.example.com/authorize?state=abc",
+			wantStatus:   http.StatusFound,
+			wantLocation: "https://idp.example.com/authorize?state=abc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubAuthService{
+				StartSSOLoginFunc: func(ctx context.Context) (string, error) {
+					return tt.serviceURL, tt.serviceErr
+				},
+			}
+			h := NewAuthHandler(stub)
+
+			req := httptest.NewRequest(http.MethodGet, "/oidc/login", nil)
+			w := httptest.NewRecorder()
+
+			h.SSOLogin(w, req)
+			resp := w.Result()
+			defer func(Body io.ReadCloser) {
+				err := Body.Close()
+				require.NoError(t, err)
+			}(resp.Body)
+
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+			require.Equal(t, tt.wantLocation, resp.Header.Get("Location"))
+		})
+	}
+}
+
+func TestAuthHandler_SSOCallback(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		serviceResult  auth.LoginResult
+		serviceErr     error
+		wantStatus     int
+		wantBodySubstr string
+	}{
+		{
+			name:           "disabled -> 404",
+			query:          "?code=c&state=s",
+			serviceErr:     models.ErrSSODisabled,
+			wantStatus:     http.StatusNotFound,
+			wantBodySubstr: models.ErrSSODisabled.Error(),
+		},
+		{
+			name:           "invalid state -> 401",
+			query:          "?code=c&state=s",
+			serviceErr:     models.ErrInvalidSSOState,
+			wantStatus:     http.StatusUnauthorized,
+			wantBodySubstr: models.ErrInvalidSSOState.Error(),
+		},
+		{
+			name:           "provider error -> 401",
+			query:          "?error=access_denied&state=s",
+			serviceErr:     fmt.Errorf("%w: access_denied", models.ErrSSOLoginFailed),
+			wantStatus:     http.StatusUnauthorized,
+			wantBodySubstr: "access_denied",
+		},
+		{
+			name:           "unverified email in use -> 400",
+			query:          "?code=c&state=s",
+			serviceErr:     models.ErrEmailAlreadyInUse,
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: models.ErrEmailAlreadyInUse.Error(),
+		},
+		{
+			name:           "no role -> 403",
+			query:          "?code=c&state=s",
+			serviceErr:     models.ErrSSORoleNotMapped,
+			wantStatus:     http.StatusForbidden,
+			wantBodySubstr: models.ErrSSORoleNotMapped.Error(),
+		},
+		{
+			name:           "internal err",
+			query:          "?code=c&state=s",
+			serviceErr:     errors.New("err"),
+			wantStatus:     http.StatusInternalServerError,
+			wantBodySubstr: "err",
+		},
+		{
+			name:           "second factor required -> 202",
+			query:          "?code=c&state=s",
+			serviceResult:  auth.LoginResult{Challenge: &dto.LoginChallenge{ChallengeToken: "challenge123"}},
+			wantStatus:     http.StatusAccepted,
+			wantBodySubstr: `"challengeToken":"challenge123"`,
+		},
+		{
+			name:           "success",
+			query:          "?code=c&state=s",
+			serviceResult:  auth.LoginResult{Tokens: &dto.TokenPair{AccessToken: "token123", RefreshToken: "refresh123"}},
+			wantStatus:     http.StatusOK,
+			wantBodySubstr: `"accessToken":"token123"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubAuthService{
+				CompleteSSOLoginFunc: func(ctx context.Context, params dto.GetOidcCallbackParams) (*auth.LoginResult, error) {
+					require.NotNil(t, params.State)
+					require.Equal(t, "s", *params.State)
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &tt.serviceResult, nil
+				},
+			}
+			h := NewAuthHandler(stub)
+
+			req := httptest.NewRequest(http.MethodGet, "/oidc/callback"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			h.SSOCallback(w, req)
+			resp := w.Result()
+			defer func(Body io.ReadCloser) {
+				err := Body.Close()
+				require.NoError(t, err)
+			}(resp.Body)
+
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+			respBody, _ := io.ReadAll(resp.Body)
+			require.Contains(t, string(respBody), tt.wantBodySubstr)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+	"github.com/itisalisas/avito-backend/internal/service/city"
+	"github.com/itisalisas/avito-backend/internal/utils"
+)
+
+type CityHandler struct {
+	cityService city.ServiceInterface
+}
+
+func NewCityHandler(cityService city.ServiceInterface) *CityHandler {
+	return &CityHandler{cityService: cityService}
+}
+
+func (h *CityHandler) GetCities(w http.ResponseWriter, r *http.Request) {
+	cities, err := h.cityService.GetCities(r.Context())
+	switch {
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		utils.WriteResponse(w, cities, http.StatusOK)
+	}
+}
+
+func (h *CityHandler) AddCity(w http.ResponseWriter, r *http.Request) {
+	var request dto.PostCitiesJSONRequestBody
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	addedCity, err := h.cityService.AddCity(r.Context(), request.Name)
+	switch {
+	case errors.Is(err, models.ErrEmptyName) || errors.Is(err, models.ErrCityExists):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusBadRequest)
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		utils.WriteResponse(w, addedCity, http.StatusCreated)
+	}
+}
+
+func (h *CityHandler) DisableCity(w http.ResponseWriter, r *http.Request) {
+	cityId, err := uuid.Parse(r.PathValue("cityId"))
+	if err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	disabledCity, err := h.cityService.DisableCity(r.Context(), cityId)
+	switch {
+	case errors.Is(err, models.ErrCityNotFound):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusNotFound)
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		utils.WriteResponse(w, disabledCity, http.StatusOK)
+	}
+}
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type stubCityService struct {
+	GetCitiesFunc   func(ctx context.Context) ([]dto.City, error)
+	AddCityFunc     func(ctx context.Context, name string) (*dto.City, error)
+	DisableCityFunc func(ctx context.Context, id uuid.UUID) (*dto.City, error)
+}
+
+func (s *stubCityService) GetCities(ctx context.Context) ([]dto.City, error) {
+	return s.GetCitiesFunc(ctx)
+}
+
+func (s *stubCityService) AddCity(ctx context.Context, name string) (*dto.City, error) {
+	return s.AddCityFunc(ctx, name)
+}
+
+func (s *stubCityService) DisableCity(ctx context.Context, id uuid.UUID) (*dto.City, error) {
+	return s.DisableCityFunc(ctx, id)
+}
+
+func TestCityHandler_GetCities(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceReturn  []dto.City
+		serviceErr     error
+		wantStatus     int
+		wantBodySubstr string
+	}{
+		{
+			name:           "internal err",
+			serviceErr:     errors.New("db error"),
+			wantStatus:     http.StatusInternalServerError,
+			wantBodySubstr: "db error",
+		},
+		{
+			name:           "success",
+			serviceReturn:  []dto.City{{Id: uuid.New(), Name: "Казань", Active: true}},
+			wantStatus:     http.StatusOK,
+			wantBodySubstr: `"name":"Казань"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubCityService{
+				GetCitiesFunc: func(ctx context.Context) ([]dto.City, error) {
+					return tt.serviceReturn, tt.serviceErr
+				},
+			}
+			h := NewCityHandler(stub)
+
+			req := httptest.NewRequest(http.MethodGet, "/cities", nil)
+			w := httptest.NewRecorder()
+
+			h.GetCities(w, req)
+			resp := w.Result()
+			defer func(Body io.ReadCloser) {
+				err := Body.Close()
+				require.NoError(t, err)
+			}(resp.Body)
+
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			respBody, _ := io.ReadAll(resp.Body)
+			require.Contains(t, string(respBody), tt.wantBodySubstr)
+		})
+	}
+}
+
+func TestCityHandler_AddCity(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           []byte
+		serviceReturn  *dto.City
+		serviceErr     error
+		wantStatus     int
+		wantBodySubstr string
+	}{
+		{
+			name:           "invalid JSON",
+			body:           []byte(`qwerty`),
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "Invalid request",
+		},
+		{
+			name:           "empty name",
+			body:           []byte(`{"name":""}`),
+			serviceErr:     models.ErrEmptyName,
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: models.ErrEmptyName.Error(),
+		},
+		{
+			name:           "already exists",
+			body:           []byte(`{"name":"Москва"}`),
+			serviceErr:     models.ErrCityExists,
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: models.ErrCityExists.Error(),
+		},
+		{
+			name:           "success",
+			body:           []byte(`{"name":"Новосибирск"}`),
+			serviceReturn:  &dto.City{Id: uuid.New(), Name: "Новосибирск", Active: true},
+			wantStatus:     http.StatusCreated,
+			wantBodySubstr: `"name":"Новосибирск"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubCityService{
+				AddCityFunc: func(ctx context.Context, name string) (*dto.City, error) {
+					return tt.serviceReturn, tt.serviceErr
+				},
+			}
+			h := NewCityHandler(stub)
+
+			req := httptest.NewRequest(http.MethodPost, "/cities", bytes.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			h.AddCity(w, req)
+			resp := w.Result()
+			defer func(Body io.ReadCloser) {
+				err := Body.Close()
+				require.NoError(t, err)
+			}(resp.Body)
+
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			respBody, _ := io.ReadAll(resp.Body)
+			require.Contains(t, string(respBody), tt.wantBodySubstr)
+		})
+	}
+}
+
+func TestCityHandler_DisableCity(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name           string
+		cityId         string
+		serviceReturn  *dto.City
+		serviceErr     error
+		wantStatus     int
+		wantBodySubstr string
+	}{
+		{
+			name:           "invalid UUID",
+			cityId:         "qwerty",
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "Invalid request",
+		},
+		{
+			name:           "not found",
+			cityId:         id.String(),
+			serviceErr:     models.ErrCityNotFound,
+			wantStatus:     http.StatusNotFound,
+			wantBodySubstr: models.ErrCityNotFound.Error(),
+		},
+		{
+			name:           "internal err",
+			cityId:         id.String(),
+			serviceErr:     errors.New("fail"),
+			wantStatus:     http.StatusInternalServerError,
+			wantBodySubstr: "fail",
+		},
+		{
+			name:           "success",
+			cityId:         id.String(),
+			serviceReturn:  &dto.City{Id: id, Name: "Казань", Active: false},
+			wantStatus:     http.StatusOK,
+			wantBodySubstr: `"active":false`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubCityService{
+				DisableCityFunc: func(ctx context.Context, id uuid.UUID) (*dto.City, error) {
+					return tt.serviceReturn, tt.serviceErr
+				},
+			}
+			h := NewCityHandler(stub)
+
+			req := httptest.NewRequest(http.MethodPost, "/cities/"+tt.cityId+"/disable", nil)
+			req.SetPathValue("cityId", tt.cityId)
+			w := httptest.NewRecorder()
+
+			h.DisableCity(w, req)
+			resp := w.Result()
+			defer func(Body io.ReadCloser) {
+				err := Body.Close()
+				require.NoError(t, err)
+			}(resp.Body)
+
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			respBody, _ := io.ReadAll(resp.Body)
+			require.Contains(t, string(respBody), tt.wantBodySubstr)
+		})
+	}
+}
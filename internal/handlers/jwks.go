@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/utils"
+)
+
+// KeySet publishes the public keys access tokens are verified with.
+type KeySet interface {
+	JWKS() dto.JWKSet
+}
+
+type JWKSHandler struct {
+	keys KeySet
+}
+
+func NewJWKSHandler(keys KeySet) *JWKSHandler {
+	return &JWKSHandler{keys: keys}
+}
+
+func (h *JWKSHandler) GetJWKS(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	utils.WriteResponse(w, h.keys.JWKS(), http.StatusOK)
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/jwtkeys"
+)
+
+func TestJWKSHandler_GetJWKS(t *testing.T) {
+	_, signing, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	retired, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	keys, err := jwtkeys.NewManager(signing, retired)
+	require.NoError(t, err)
+
+	h := NewJWKSHandler(keys)
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	w := httptest.NewRecorder()
+	h.GetJWKS(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NotEmpty(t, w.Header().Get("Cache-Control"))
+
+	var set dto.JWKSet
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &set))
+	require.Len(t, set.Keys, 2)
+	for _, key := range set.Keys {
+		require.Equal(t, "OKP", string(key.Kty))
+		require.NotEmpty(t, key.Kid)
+		require.Nil(t, key.N)
+	}
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+	"github.com/itisalisas/avito-backend/internal/service/producttype"
+	"github.com/itisalisas/avito-backend/internal/utils"
+)
+
+type ProductTypeHandler struct {
+	productTypeService producttype.ServiceInterface
+}
+
+func NewProductTypeHandler(productTypeService producttype.ServiceInterface) *ProductTypeHandler {
+	return &ProductTypeHandler{productTypeService: productTypeService}
+}
+
+func (h *ProductTypeHandler) GetProductTypes(w http.ResponseWriter, r *http.Request) {
+	productTypes, err := h.productTypeService.GetProductTypes(r.Context())
+	switch {
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		utils.WriteResponse(w, productTypes, http.StatusOK)
+	}
+}
+
+func (h *ProductTypeHandler) AddProductType(w http.ResponseWriter, r *http.Request) {
+	var request dto.PostProductTypesJSONRequestBody
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	productType, err := h.productTypeService.AddProductType(r.Context(), request.Name)
+	switch {
+	case errors.Is(err, models.ErrEmptyName) || errors.Is(err, models.ErrProductTypeExists):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusBadRequest)
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		utils.WriteResponse(w, productType, http.StatusCreated)
+	}
+}
+
+func (h *ProductTypeHandler) RenameProductType(w http.ResponseWriter, r *http.Request) {
+	productTypeId, err := uuid.Parse(r.PathValue("productTypeId"))
+	if err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	var request dto.PatchProductTypesProductTypeIdJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	productType, err := h.productTypeService.RenameProductType(r.Context(), productTypeId, request.Name)
+	writeProductType(w, productType, err)
+}
+
+func (h *ProductTypeHandler) DeactivateProductType(w http.ResponseWriter, r *http.Request) {
+	productTypeId, err := uuid.Parse(r.PathValue("productTypeId"))
+	if err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	productType, err := h.productTypeService.DeactivateProductType(r.Context(), productTypeId)
+	writeProductType(w, productType, err)
+}
+
+func writeProductType(w http.ResponseWriter, productType *dto.ProductType, err error) {
+	switch {
+	case errors.Is(err, models.ErrEmptyName) || errors.Is(err, models.ErrProductTypeExists):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusBadRequest)
+	case errors.Is(err, models.ErrProductTypeNotFound):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusNotFound)
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		utils.WriteResponse(w, productType, http.StatusOK)
+	}
+}
@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type stubProductTypeService struct {
+	GetProductTypesFunc       func(ctx context.Context) ([]dto.ProductType, error)
+	AddProductTypeFunc        func(ctx context.Context, name string) (*dto.ProductType, error)
+	RenameProductTypeFunc     func(ctx context.Context, id uuid.UUID, name string) (*dto.ProductType, error)
+	DeactivateProductTypeFunc func(ctx context.Context, id uuid.UUID) (*dto.ProductType, error)
+}
+
+func (s *stubProductTypeService) GetProductTypes(ctx context.Context) ([]dto.ProductType, error) {
+	return s.GetProductTypesFunc(ctx)
+}
+
+func (s *stubProductTypeService) AddProductType(ctx context.Context, name string) (*dto.ProductType, error) {
+	return s.AddProductTypeFunc(ctx, name)
+}
+
+func (s *stubProductTypeService) RenameProductType(ctx context.Context, id uuid.UUID, name string) (*dto.ProductType, error) {
+	return s.RenameProductTypeFunc(ctx, id, name)
+}
+
+func (s *stubProductTypeService) DeactivateProductType(ctx context.Context, id uuid.UUID) (*dto.ProductType, error) {
+	return s.DeactivateProductTypeFunc(ctx, id)
+}
+
+func TestProductTypeHandler_GetProductTypes(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceReturn  []dto.ProductType
+		serviceErr     error
+		wantStatus     int
+		wantBodySubstr string
+	}{
+		{
+			name:           "internal err",
+			serviceErr:     errors.New("db error"),
+			wantStatus:     http.StatusInternalServerError,
+			wantBodySubstr: "db error",
+		},
+		{
+			name:           "success",
+			serviceReturn:  []dto.ProductType{{Id: uuid.New(), Name: "обувь", Active: true}},
+			wantStatus:     http.StatusOK,
+			wantBodySubstr: `"name":"обувь"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubProductTypeService{
+				GetProductTypesFunc: func(ctx context.Context) ([]dto.ProductType, error) {
+					return tt.serviceReturn, tt.serviceErr
+				},
+			}
+			h := NewProductTypeHandler(stub)
+
+			req := httptest.NewRequest(http.MethodGet, "/product_types", nil)
+			w := httptest.NewRecorder()
+
+			h.GetProductTypes(w, req)
+			resp := w.Result()
+			defer func(Body io.ReadCloser) {
+				err := Body.Close()
+				require.NoError(t, err)
+			}(resp.Body)
+
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			respBody, _ := io.ReadAll(resp.Body)
+			require.Contains(t, string(respBody), tt.wantBodySubstr)
+		})
+	}
+}
+
+func TestProductTypeHandler_AddProductType(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           []byte
+		serviceReturn  *dto.ProductType
+		serviceErr     error
+		wantStatus     int
+		wantBodySubstr string
+	}{
+		{
+			name:           "invalid JSON",
+			body:           []byte(`qwerty`),
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "Invalid request",
+		},
+		{
+			name:           "empty name",
+			body:           []byte(`{"name":""}`),
+			serviceErr:     models.ErrEmptyName,
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: models.ErrEmptyName.Error(),
+		},
+		{
+			name:           "already exists",
+			body:           []byte(`{"name":"обувь"}`),
+			serviceErr:     models.ErrProductTypeExists,
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: models.ErrProductTypeExists.Error(),
+		},
+		{
+			name:           "internal err",
+			body:           []byte(`{"name":"книги"}`),
+			serviceErr:     errors.New("fail"),
+			wantStatus:     http.StatusInternalServerError,
+			wantBodySubstr: "fail",
+		},
+		{
+			name:           "success",
+			body:           []byte(`{"name":"книги"}`),
+			serviceReturn:  &dto.ProductType{Id: uuid.New(), Name: "книги", Active: true},
+			wantStatus:     http.StatusCreated,
+			wantBodySubstr: `"name":"книги"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubProductTypeService{
+				AddProductTypeFunc: func(ctx context.Context, name string) (*dto.ProductType, error) {
+					return tt.serviceReturn, tt.serviceErr
+				},
+			}
+			h := NewProductTypeHandler(stub)
+
+			req := httptest.NewRequest(http.MethodPost, "/product_types", bytes.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			h.AddProductType(w, req)
+			resp := w.Result()
+			defer func(Body io.ReadCloser) {
+				err := Body.Close()
+				require.NoError(t, err)
+			}(resp.Body)
+
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			respBody, _ := io.ReadAll(resp.Body)
+			require.Contains(t, string(respBody), tt.wantBodySubstr)
+		})
+	}
+}
+
+func TestProductTypeHandler_RenameProductType(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name           string
+		productTypeId  string
+		body           []byte
+		serviceReturn  *dto.ProductType
+		serviceErr     error
+		wantStatus     int
+		wantBodySubstr string
+	}{
+		{
+			name:           "invalid UUID",
+			productTypeId:  "qwerty",
+			body:           []byte(`{"name":"книги"}`),
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "Invalid request",
+		},
+		{
+			name:           "invalid JSON",
+			productTypeId:  id.String(),
+			body:           []byte(`qwerty`),
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "Invalid request",
+		},
+		{
+			name:           "not found",
+			productTypeId:  id.String(),
+			body:           []byte(`{"name":"книги"}`),
+			serviceErr:     models.ErrProductTypeNotFound,
+			wantStatus:     http.StatusNotFound,
+			wantBodySubstr: models.ErrProductTypeNotFound.Error(),
+		},
+		{
+			name:           "name taken",
+			productTypeId:  id.String(),
+			body:           []byte(`{"name":"обувь"}`),
+			serviceErr:     models.ErrProductTypeExists,
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: models.ErrProductTypeExists.Error(),
+		},
+		{
+			name:           "success",
+			productTypeId:  id.String(),
+			body:           []byte(`{"name":"книги"}`),
+			serviceReturn:  &dto.ProductType{Id: id, Name: "книги", Active: true},
+			wantStatus:     http.StatusOK,
+			wantBodySubstr: `"name":"книги"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubProductTypeService{
+				RenameProductTypeFunc: func(ctx context.Context, id uuid.UUID, name string) (*dto.ProductType, error) {
+					return tt.serviceReturn, tt.serviceErr
+				},
+			}
+			h := NewProductTypeHandler(stub)
+
+			req := httptest.NewRequest(http.MethodPatch, "/product_types/"+tt.productTypeId, bytes.NewReader(tt.body))
+			req.SetPathValue("productTypeId", tt.productTypeId)
+			w := httptest.NewRecorder()
+
+			h.RenameProductType(w, req)
+			resp := w.Result()
+			defer func(Body io.ReadCloser) {
+				err := Body.Close()
+				require.NoError(t, err)
+			}(resp.Body)
+
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			respBody, _ := io.ReadAll(resp.Body)
+			require.Contains(t, string(respBody), tt.wantBodySubstr)
+		})
+	}
+}
+
+func TestProductTypeHandler_DeactivateProductType(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name           string
+		productTypeId  string
+		serviceReturn  *dto.ProductType
+		serviceErr     error
+		wantStatus     int
+		wantBodySubstr string
+	}{
+		{
+			name:           "invalid UUID",
+			productTypeId:  "qwerty",
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "Invalid request",
+		},
+		{
+			name:           "not found",
+			productTypeId:  id.String(),
+			serviceErr:     models.ErrProductTypeNotFound,
+			wantStatus:     http.StatusNotFound,
+			wantBodySubstr: models.ErrProductTypeNotFound.Error(),
+		},
+		{
+			name:           "success",
+			productTypeId:  id.String(),
+			serviceReturn:  &dto.ProductType{Id: id, Name: "обувь", Active: false},
+			wantStatus:     http.StatusOK,
+			wantBodySubstr: `"active":false`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubProductTypeService{
+				DeactivateProductTypeFunc: func(ctx context.Context, id uuid.UUID) (*dto.ProductType, error) {
+					return tt.serviceReturn, tt.serviceErr
+				},
+			}
+			h := NewProductTypeHandler(stub)
+
+			req := httptest.NewRequest(http.MethodPost, "/product_types/"+tt.productTypeId+"/deactivate", nil)
+			req.SetPathValue("productTypeId", tt.productTypeId)
+			w := httptest.NewRecorder()
+
+			h.DeactivateProductType(w, req)
+			resp := w.Result()
+			defer func(Body io.ReadCloser) {
+				err := Body.Close()
+				require.NoError(t, err)
+			}(resp.Body)
+
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			respBody, _ := io.ReadAll(resp.Body)
+			require.Contains(t, string(respBody), tt.wantBodySubstr)
+		})
+	}
+}
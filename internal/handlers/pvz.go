@@ -8,6 +8,8 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/itisalisas/avito-backend/internal/generated/dto"
 	"github.com/itisalisas/avito-backend/internal/models"
 	"github.com/itisalisas/avito-backend/internal/service/pvz"
@@ -30,15 +32,29 @@ func (h *PvzHandler) GetPvz(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	page := uint64(*params.Page)
-	limit := uint64(*params.Limit)
+	listParams := models.PvzListParams{
+		PvzFilter: pvzFilter(params),
+		Page:      uint64(*params.Page),
+		Limit:     uint64(*params.Limit),
+	}
+	if params.Cursor != nil {
+		listParams.Cursor, err = models.DecodePvzCursor(*params.Cursor)
+		if err != nil {
+			utils.WriteResponse(w, utils.Error(err.Error()), http.StatusBadRequest)
+			return
+		}
+	}
 
-	pvzList, err := h.pvzService.GetPvzList(r.Context(), params.StartDate, params.EndDate, page, limit)
+	page, err := h.pvzService.GetPvzList(r.Context(), listParams)
 	switch {
 	case err != nil:
 		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
 	default:
-		utils.WriteResponse(w, pvzList, http.StatusOK)
+		w.Header().Set("X-Total-Count", strconv.FormatUint(page.TotalCount, 10))
+		if page.NextCursor != nil {
+			w.Header().Set("X-Next-Cursor", page.NextCursor.Encode())
+		}
+		utils.WriteResponse(w, page.Items, http.StatusOK)
 	}
 }
 
@@ -53,7 +69,8 @@ func (h *PvzHandler) AddPvz(w http.ResponseWriter, r *http.Request) {
 	addedPvz, err := h.pvzService.AddPvz(r.Context(), &request)
 
 	switch {
-	case errors.Is(err, models.ErrIncorrectCity):
+	case errors.Is(err, models.ErrIncorrectCity) || errors.Is(err, models.ErrIncorrectCoordinates) ||
+		errors.Is(err, models.ErrIncorrectPhone):
 		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusBadRequest)
 	case err != nil:
 		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
@@ -63,6 +80,70 @@ func (h *PvzHandler) AddPvz(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (h *PvzHandler) GetPvzById(w http.ResponseWriter, r *http.Request) {
+	pvzId, err := uuid.Parse(r.PathValue("pvzId"))
+	if err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	foundPvz, err := h.pvzService.GetPvz(r.Context(), pvzId)
+	switch {
+	case errors.Is(err, models.ErrPvzNotFound):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusNotFound)
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		utils.WriteResponse(w, foundPvz, http.StatusOK)
+	}
+}
+
+func (h *PvzHandler) UpdatePvz(w http.ResponseWriter, r *http.Request) {
+	pvzId, err := uuid.Parse(r.PathValue("pvzId"))
+	if err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	var request dto.PatchPvzPvzIdJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	updatedPvz, err := h.pvzService.UpdatePvz(r.Context(), pvzId, &request)
+	switch {
+	case errors.Is(err, models.ErrIncorrectCoordinates) || errors.Is(err, models.ErrIncorrectPhone):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusBadRequest)
+	case errors.Is(err, models.ErrPvzNotFound):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusNotFound)
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		utils.WriteResponse(w, updatedPvz, http.StatusOK)
+	}
+}
+
+func (h *PvzHandler) DecommissionPvz(w http.ResponseWriter, r *http.Request) {
+	pvzId, err := uuid.Parse(r.PathValue("pvzId"))
+	if err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	decommissionedPvz, err := h.pvzService.DecommissionPvz(r.Context(), pvzId)
+	switch {
+	case errors.Is(err, models.ErrPvzDecommissioned):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusBadRequest)
+	case errors.Is(err, models.ErrPvzNotFound):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusNotFound)
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		utils.WriteResponse(w, decommissionedPvz, http.StatusOK)
+	}
+}
+
 func parseGetPvzParams(query url.Values) (*dto.GetPvzParams, error) {
 	params := &dto.GetPvzParams{}
 
@@ -86,6 +167,59 @@ func parseGetPvzParams(query url.Values) (*dto.GetPvzParams, error) {
 		return nil, errors.New("startDate must be before endDate")
 	}
 
+	if cities := query["city"]; len(cities) > 0 {
+		params.City = &cities
+	}
+
+	if status := query.Get("receptionStatus"); status != "" {
+		switch dto.ReceptionStatus(status) {
+		case dto.InProgress, dto.Paused, dto.Close, dto.Cancelled:
+		default:
+			return nil, errors.New("invalid receptionStatus")
+		}
+		params.ReceptionStatus = &status
+	}
+
+	if productType := query.Get("productType"); productType != "" {
+		params.ProductType = &productType
+	}
+
+	if productStartDateStr := query.Get("productStartDate"); productStartDateStr != "" {
+		t, err := time.Parse(time.RFC3339, productStartDateStr)
+		if err != nil {
+			return nil, errors.New("invalid productStartDate format")
+		}
+		params.ProductStartDate = &t
+	}
+
+	if productEndDateStr := query.Get("productEndDate"); productEndDateStr != "" {
+		t, err := time.Parse(time.RFC3339, productEndDateStr)
+		if err != nil {
+			return nil, errors.New("invalid productEndDate format")
+		}
+		params.ProductEndDate = &t
+	}
+
+	if params.ProductStartDate != nil && params.ProductEndDate != nil &&
+		params.ProductStartDate.After(*params.ProductEndDate) {
+		return nil, errors.New("productStartDate must be before productEndDate")
+	}
+
+	if hasOpenReceptionStr := query.Get("hasOpenReception"); hasOpenReceptionStr != "" {
+		hasOpenReception, err := strconv.ParseBool(hasOpenReceptionStr)
+		if err != nil {
+			return nil, errors.New("invalid hasOpenReception format")
+		}
+		params.HasOpenReception = &hasOpenReception
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		if query.Get("page") != "" {
+			return nil, errors.New("page and cursor are mutually exclusive")
+		}
+		params.Cursor = &cursor
+	}
+
 	if pageStr := query.Get("page"); pageStr != "" {
 		page, err := strconv.Atoi(pageStr)
 		if err != nil || page < 1 {
@@ -110,3 +244,24 @@ func parseGetPvzParams(query url.Values) (*dto.GetPvzParams, error) {
 
 	return params, nil
 }
+
+func pvzFilter(params *dto.GetPvzParams) models.PvzFilter {
+	filter := models.PvzFilter{
+		StartDate:        params.StartDate,
+		EndDate:          params.EndDate,
+		ProductType:      params.ProductType,
+		ProductStartDate: params.ProductStartDate,
+		ProductEndDate:   params.ProductEndDate,
+	}
+	if params.City != nil {
+		filter.Cities = *params.City
+	}
+	if params.ReceptionStatus != nil {
+		status := dto.ReceptionStatus(*params.ReceptionStatus)
+		filter.ReceptionStatus = &status
+	}
+	if params.HasOpenReception != nil {
+		filter.HasOpenReception = *params.HasOpenReception
+	}
+	return filter
+}
@@ -7,9 +7,11 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
 	"github.com/itisalisas/avito-backend/internal/generated/dto"
@@ -17,26 +19,48 @@ import (
 )
 
 type stubPvzService struct {
-	GetPvzListFunc func(ctx context.Context, startTime, endTime *time.Time, page, limit uint64) ([]*models.ExtendedPvz, error)
-	AddPvzFunc     func(ctx context.Context, pvz *dto.PostPvzJSONRequestBody) (*dto.PVZ, error)
+	GetPvzListFunc      func(ctx context.Context, params models.PvzListParams) (*models.PvzPage, error)
+	AddPvzFunc          func(ctx context.Context, pvz *dto.PostPvzJSONRequestBody) (*dto.PVZ, error)
+	GetPvzFunc          func(ctx context.Context, id uuid.UUID) (*dto.PVZ, error)
+	UpdatePvzFunc       func(ctx context.Context, id uuid.UUID, update *dto.PVZUpdate) (*dto.PVZ, error)
+	DecommissionPvzFunc func(ctx context.Context, id uuid.UUID) (*dto.PVZ, error)
 }
 
-func (s *stubPvzService) GetPvzList(ctx context.Context, startTime, endTime *time.Time, page, limit uint64) ([]*models.ExtendedPvz, error) {
-	return s.GetPvzListFunc(ctx, startTime, endTime, page, limit)
+func (s *stubPvzService) GetPvzList(ctx context.Context, params models.PvzListParams) (*models.PvzPage, error) {
+	return s.GetPvzListFunc(ctx, params)
 }
 
 func (s *stubPvzService) AddPvz(ctx context.Context, pvz *dto.PostPvzJSONRequestBody) (*dto.PVZ, error) {
 	return s.AddPvzFunc(ctx, pvz)
 }
 
+func (s *stubPvzService) GetPvz(ctx context.Context, id uuid.UUID) (*dto.PVZ, error) {
+	return s.GetPvzFunc(ctx, id)
+}
+
+func (s *stubPvzService) UpdatePvz(ctx context.Context, id uuid.UUID, update *dto.PVZUpdate) (*dto.PVZ, error) {
+	return s.UpdatePvzFunc(ctx, id, update)
+}
+
+func (s *stubPvzService) DecommissionPvz(ctx context.Context, id uuid.UUID) (*dto.PVZ, error) {
+	return s.DecommissionPvzFunc(ctx, id)
+}
+
 func TestPvzHandler_GetPvz(t *testing.T) {
+	cursor := &models.PvzCursor{RegistrationDate: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), ID: uuid.New()}
+	productStart := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	status := dto.Close
+	productType := "обувь"
+
 	tests := []struct {
 		name           string
 		queryParams    string
-		serviceReturn  []*models.ExtendedPvz
+		serviceReturn  *models.PvzPage
 		serviceErr     error
 		wantStatus     int
 		wantBodySubstr string
+		wantHeaders    map[string]string
+		wantFilter     *models.PvzFilter
 	}{
 		{
 			name:           "invalid startDate format",
@@ -62,6 +86,44 @@ func TestPvzHandler_GetPvz(t *testing.T) {
 			wantStatus:     http.StatusBadRequest,
 			wantBodySubstr: "invalid limit format",
 		},
+		{
+			name:           "invalid receptionStatus",
+			queryParams:    "?receptionStatus=done",
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "invalid receptionStatus",
+		},
+		{
+			name:           "productStartDate after productEndDate",
+			queryParams:    "?productStartDate=2025-04-10T00:00:00Z&productEndDate=2025-04-01T00:00:00Z",
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "productStartDate must be before productEndDate",
+		},
+		{
+			name:           "invalid hasOpenReception format",
+			queryParams:    "?hasOpenReception=maybe",
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "invalid hasOpenReception format",
+		},
+		{
+			name: "filters are passed to service",
+			queryParams: "?" + url.Values{
+				"city":             {"Москва", "Казань"},
+				"receptionStatus":  {"close"},
+				"productType":      {"обувь"},
+				"productStartDate": {"2025-04-01T00:00:00Z"},
+				"hasOpenReception": {"true"},
+			}.Encode(),
+			serviceReturn:  &models.PvzPage{Items: []*models.ExtendedPvz{}},
+			wantStatus:     http.StatusOK,
+			wantBodySubstr: "[]",
+			wantFilter: &models.PvzFilter{
+				Cities:           []string{"Москва", "Казань"},
+				ReceptionStatus:  &status,
+				ProductType:      &productType,
+				ProductStartDate: &productStart,
+				HasOpenReception: true,
+			},
+		},
 		{
 			name:           "internal err",
 			queryParams:    "",
@@ -70,18 +132,49 @@ func TestPvzHandler_GetPvz(t *testing.T) {
 			wantBodySubstr: "db error",
 		},
 		{
-			name:           "success",
-			queryParams:    "?page=1&limit=2",
-			serviceReturn:  []*models.ExtendedPvz{{PVZ: dto.PVZ{City: dto.СанктПетербург}}},
+			name:           "page with cursor",
+			queryParams:    "?page=2&cursor=" + cursor.Encode(),
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "page and cursor are mutually exclusive",
+		},
+		{
+			name:           "invalid cursor",
+			queryParams:    "?cursor=qwerty",
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: models.ErrInvalidCursor.Error(),
+		},
+		{
+			name:        "success",
+			queryParams: "?page=1&limit=2",
+			serviceReturn: &models.PvzPage{
+				Items:      []*models.ExtendedPvz{{PVZ: dto.PVZ{City: "Санкт-Петербург"}}},
+				TotalCount: 3,
+				NextCursor: cursor,
+			},
 			wantStatus:     http.StatusOK,
 			wantBodySubstr: `"city":"Санкт-Петербург"`,
+			wantHeaders:    map[string]string{"X-Total-Count": "3", "X-Next-Cursor": cursor.Encode()},
+		},
+		{
+			name:        "success with cursor on last page",
+			queryParams: "?limit=2&cursor=" + cursor.Encode(),
+			serviceReturn: &models.PvzPage{
+				Items:      []*models.ExtendedPvz{{PVZ: dto.PVZ{City: "Казань"}}},
+				TotalCount: 3,
+			},
+			wantStatus:     http.StatusOK,
+			wantBodySubstr: `"city":"Казань"`,
+			wantHeaders:    map[string]string{"X-Total-Count": "3", "X-Next-Cursor": ""},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			stub := &stubPvzService{
-				GetPvzListFunc: func(ctx context.Context, startTime, endTime *time.Time, page, limit uint64) ([]*models.ExtendedPvz, error) {
+				GetPvzListFunc: func(ctx context.Context, params models.PvzListParams) (*models.PvzPage, error) {
+					if tt.wantFilter != nil {
+						require.Equal(t, *tt.wantFilter, params.PvzFilter)
+					}
 					return tt.serviceReturn, tt.serviceErr
 				},
 			}
@@ -101,6 +194,10 @@ func TestPvzHandler_GetPvz(t *testing.T) {
 
 			respBody, _ := io.ReadAll(resp.Body)
 			require.Contains(t, string(respBody), tt.wantBodySubstr)
+
+			for header, value := range tt.wantHeaders {
+				require.Equal(t, value, resp.Header.Get(header))
+			}
 		})
 	}
 }
@@ -141,7 +238,7 @@ func TestPvzHandler_AddPvz(t *testing.T) {
 			body:       []byte(`{"city":"Москва"}`),
 			wantStatus: http.StatusCreated,
 			serviceReturn: &dto.PVZ{
-				City: dto.Москва,
+				City: "Москва",
 			},
 			wantBodySubstr: `"city":"Москва"`,
 		},
@@ -173,3 +270,216 @@ func TestPvzHandler_AddPvz(t *testing.T) {
 		})
 	}
 }
+
+func TestPvzHandler_GetPvzById(t *testing.T) {
+	pvzId := uuid.New()
+	name := "ПВЗ на Тверской"
+
+	tests := []struct {
+		name           string
+		pvzId          string
+		serviceErr     error
+		serviceReturn  *dto.PVZ
+		wantStatus     int
+		wantBodySubstr string
+	}{
+		{
+			name:           "invalid id",
+			pvzId:          "not-a-uuid",
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "Invalid request",
+		},
+		{
+			name:           "not found",
+			pvzId:          pvzId.String(),
+			serviceErr:     models.ErrPvzNotFound,
+			wantStatus:     http.StatusNotFound,
+			wantBodySubstr: models.ErrPvzNotFound.Error(),
+		},
+		{
+			name:           "success",
+			pvzId:          pvzId.String(),
+			serviceReturn:  &dto.PVZ{Id: &pvzId, City: "Москва", Name: &name},
+			wantStatus:     http.StatusOK,
+			wantBodySubstr: `"name":"ПВЗ на Тверской"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubPvzService{
+				GetPvzFunc: func(ctx context.Context, id uuid.UUID) (*dto.PVZ, error) {
+					require.Equal(t, pvzId, id)
+					return tt.serviceReturn, tt.serviceErr
+				},
+			}
+			h := NewPvzHandler(stub)
+
+			req := httptest.NewRequest(http.MethodGet, "/pvz/"+tt.pvzId, nil)
+			req.SetPathValue("pvzId", tt.pvzId)
+			w := httptest.NewRecorder()
+
+			h.GetPvzById(w, req)
+			resp := w.Result()
+			defer func(Body io.ReadCloser) {
+				err := Body.Close()
+				require.NoError(t, err)
+			}(resp.Body)
+
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			respBody, _ := io.ReadAll(resp.Body)
+			require.Contains(t, string(respBody), tt.wantBodySubstr)
+		})
+	}
+}
+
+func TestPvzHandler_UpdatePvz(t *testing.T) {
+	pvzId := uuid.New()
+	phone := "+7 (495) 123-45-67"
+
+	tests := []struct {
+		name           string
+		pvzId          string
+		body           []byte
+		serviceErr     error
+		serviceReturn  *dto.PVZ
+		wantStatus     int
+		wantBodySubstr string
+	}{
+		{
+			name:           "invalid id",
+			pvzId:          "not-a-uuid",
+			body:           []byte(`{}`),
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "Invalid request",
+		},
+		{
+			name:           "invalid JSON",
+			pvzId:          pvzId.String(),
+			body:           []byte(`qwerty`),
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "Invalid request",
+		},
+		{
+			name:           "incorrect coordinates",
+			pvzId:          pvzId.String(),
+			body:           []byte(`{"latitude":55.75}`),
+			serviceErr:     models.ErrIncorrectCoordinates,
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: models.ErrIncorrectCoordinates.Error(),
+		},
+		{
+			name:           "not found",
+			pvzId:          pvzId.String(),
+			body:           []byte(`{"phone":"+7 (495) 123-45-67"}`),
+			serviceErr:     models.ErrPvzNotFound,
+			wantStatus:     http.StatusNotFound,
+			wantBodySubstr: models.ErrPvzNotFound.Error(),
+		},
+		{
+			name:           "success",
+			pvzId:          pvzId.String(),
+			body:           []byte(`{"phone":"+7 (495) 123-45-67"}`),
+			serviceReturn:  &dto.PVZ{Id: &pvzId, City: "Москва", Phone: &phone},
+			wantStatus:     http.StatusOK,
+			wantBodySubstr: `"phone":"+7 (495) 123-45-67"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubPvzService{
+				UpdatePvzFunc: func(ctx context.Context, id uuid.UUID, update *dto.PVZUpdate) (*dto.PVZ, error) {
+					return tt.serviceReturn, tt.serviceErr
+				},
+			}
+			h := NewPvzHandler(stub)
+
+			req := httptest.NewRequest(http.MethodPatch, "/pvz/"+tt.pvzId, bytes.NewReader(tt.body))
+			req.SetPathValue("pvzId", tt.pvzId)
+			w := httptest.NewRecorder()
+
+			h.UpdatePvz(w, req)
+			resp := w.Result()
+			defer func(Body io.ReadCloser) {
+				err := Body.Close()
+				require.NoError(t, err)
+			}(resp.Body)
+
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			respBody, _ := io.ReadAll(resp.Body)
+			require.Contains(t, string(respBody), tt.wantBodySubstr)
+		})
+	}
+}
+
+func TestPvzHandler_DecommissionPvz(t *testing.T) {
+	pvzId := uuid.New()
+	decommissionedAt := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		pvzId          string
+		serviceErr     error
+		serviceReturn  *dto.PVZ
+		wantStatus     int
+		wantBodySubstr string
+	}{
+		{
+			name:           "invalid id",
+			pvzId:          "not-a-uuid",
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "Invalid request",
+		},
+		{
+			name:           "already decommissioned",
+			pvzId:          pvzId.String(),
+			serviceErr:     models.ErrPvzDecommissioned,
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: models.ErrPvzDecommissioned.Error(),
+		},
+		{
+			name:           "not found",
+			pvzId:          pvzId.String(),
+			serviceErr:     models.ErrPvzNotFound,
+			wantStatus:     http.StatusNotFound,
+			wantBodySubstr: models.ErrPvzNotFound.Error(),
+		},
+		{
+			name:           "success",
+			pvzId:          pvzId.String(),
+			serviceReturn:  &dto.PVZ{Id: &pvzId, City: "Москва", DecommissionedAt: &decommissionedAt},
+			wantStatus:     http.StatusOK,
+			wantBodySubstr: `"decommissionedAt":"2025-04-01T12:00:00Z"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubPvzService{
+				DecommissionPvzFunc: func(ctx context.Context, id uuid.UUID) (*dto.PVZ, error) {
+					return tt.serviceReturn, tt.serviceErr
+				},
+			}
+			h := NewPvzHandler(stub)
+
+			req := httptest.NewRequest(http.MethodPost, "/pvz/"+tt.pvzId+"/decommission", nil)
+			req.SetPathValue("pvzId", tt.pvzId)
+			w := httptest.NewRecorder()
+
+			h.DecommissionPvz(w, req)
+			resp := w.Result()
+			defer func(Body io.ReadCloser) {
+				err := Body.Close()
+				require.NoError(t, err)
+			}(resp.Body)
+
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			respBody, _ := io.ReadAll(resp.Body)
+			require.Contains(t, string(respBody), tt.wantBodySubstr)
+		})
+	}
+}
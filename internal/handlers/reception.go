@@ -33,7 +33,8 @@ func (h *ReceptionHandler) AddReception(w http.ResponseWriter, r *http.Request)
 	addedReception, err := h.receptionService.AddReception(r.Context(), request)
 
 	switch {
-	case errors.Is(err, models.ErrReceptionNotClosed):
+	case errors.Is(err, models.ErrReceptionNotClosed) || errors.Is(err, models.ErrPvzNotFound) ||
+		errors.Is(err, models.ErrPvzDecommissioned):
 		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusBadRequest)
 	case err != nil:
 		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
@@ -58,3 +59,45 @@ func (h *ReceptionHandler) CloseLastReception(w http.ResponseWriter, r *http.Req
 		utils.WriteResponse(w, closedReception, http.StatusOK)
 	}
 }
+
+func (h *ReceptionHandler) PauseReception(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, reception.Pause)
+}
+
+func (h *ReceptionHandler) ResumeReception(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, reception.Resume)
+}
+
+func (h *ReceptionHandler) CloseReception(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, reception.Close)
+}
+
+func (h *ReceptionHandler) CancelReception(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, reception.Cancel)
+}
+
+func (h *ReceptionHandler) ReopenReception(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, reception.Reopen)
+}
+
+func (h *ReceptionHandler) changeStatus(w http.ResponseWriter, r *http.Request, action reception.Action) {
+	receptionId, err := uuid.Parse(r.PathValue("receptionId"))
+	if err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	updReception, err := h.receptionService.ChangeStatus(r.Context(), receptionId, action)
+	switch {
+	case errors.Is(err, models.ErrInvalidTransition) || errors.Is(err, models.ErrReceptionNotClosed):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusBadRequest)
+	case errors.Is(err, models.ErrTransitionForbidden):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusForbidden)
+	case errors.Is(err, models.ErrReceptionNotFound):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusNotFound)
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		utils.WriteResponse(w, updReception, http.StatusOK)
+	}
+}
@@ -15,11 +15,13 @@ import (
 
 	"github.com/itisalisas/avito-backend/internal/generated/dto"
 	"github.com/itisalisas/avito-backend/internal/models"
+	"github.com/itisalisas/avito-backend/internal/service/reception"
 )
 
 type stubReceptionService struct {
 	AddReceptionFunc       func(ctx context.Context, request dto.PostReceptionsJSONRequestBody) (*dto.Reception, error)
 	CloseLastReceptionFunc func(ctx context.Context, pvzID uuid.UUID) (*dto.Reception, error)
+	ChangeStatusFunc       func(ctx context.Context, receptionID uuid.UUID, action reception.Action) (*dto.Reception, error)
 }
 
 func (s *stubReceptionService) AddReception(ctx context.Context, request dto.PostReceptionsJSONRequestBody) (*dto.Reception, error) {
@@ -30,6 +32,10 @@ func (s *stubReceptionService) CloseLastReception(ctx context.Context, pvzID uui
 	return s.CloseLastReceptionFunc(ctx, pvzID)
 }
 
+func (s *stubReceptionService) ChangeStatus(ctx context.Context, receptionID uuid.UUID, action reception.Action) (*dto.Reception, error) {
+	return s.ChangeStatusFunc(ctx, receptionID, action)
+}
+
 func TestReceptionHandler_AddReception(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -52,6 +58,13 @@ func TestReceptionHandler_AddReception(t *testing.T) {
 			wantStatus:     http.StatusBadRequest,
 			wantBodySubstr: models.ErrReceptionNotClosed.Error(),
 		},
+		{
+			name:           "pvz decommissioned",
+			requestBody:    dto.PostReceptionsJSONRequestBody{PvzId: uuid.New()},
+			serviceErr:     models.ErrPvzDecommissioned,
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: models.ErrPvzDecommissioned.Error(),
+		},
 		{
 			name:           "internal err",
 			requestBody:    dto.PostReceptionsJSONRequestBody{PvzId: uuid.New()},
@@ -159,3 +172,99 @@ func TestReceptionHandler_CloseLastReception(t *testing.T) {
 		})
 	}
 }
+
+func TestReceptionHandler_ChangeStatus(t *testing.T) {
+	receptionID := uuid.New()
+
+	tests := []struct {
+		name           string
+		receptionID    string
+		handle         func(h *ReceptionHandler) http.HandlerFunc
+		wantAction     reception.Action
+		serviceReturn  *dto.Reception
+		serviceErr     error
+		wantStatus     int
+		wantBodySubstr string
+	}{
+		{
+			name:           "invalid UUID",
+			receptionID:    "invalid-uuid",
+			handle:         func(h *ReceptionHandler) http.HandlerFunc { return h.PauseReception },
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "Invalid request",
+		},
+		{
+			name:           "pause",
+			receptionID:    receptionID.String(),
+			handle:         func(h *ReceptionHandler) http.HandlerFunc { return h.PauseReception },
+			wantAction:     reception.Pause,
+			serviceReturn:  &dto.Reception{Id: &receptionID, Status: dto.Paused},
+			wantStatus:     http.StatusOK,
+			wantBodySubstr: `"status":"paused"`,
+		},
+		{
+			name:           "resume not paused",
+			receptionID:    receptionID.String(),
+			handle:         func(h *ReceptionHandler) http.HandlerFunc { return h.ResumeReception },
+			wantAction:     reception.Resume,
+			serviceErr:     models.ErrInvalidTransition,
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: models.ErrInvalidTransition.Error(),
+		},
+		{
+			name:           "close missing reception",
+			receptionID:    receptionID.String(),
+			handle:         func(h *ReceptionHandler) http.HandlerFunc { return h.CloseReception },
+			wantAction:     reception.Close,
+			serviceErr:     models.ErrReceptionNotFound,
+			wantStatus:     http.StatusNotFound,
+			wantBodySubstr: models.ErrReceptionNotFound.Error(),
+		},
+		{
+			name:           "cancel",
+			receptionID:    receptionID.String(),
+			handle:         func(h *ReceptionHandler) http.HandlerFunc { return h.CancelReception },
+			wantAction:     reception.Cancel,
+			serviceReturn:  &dto.Reception{Id: &receptionID, Status: dto.Cancelled},
+			wantStatus:     http.StatusOK,
+			wantBodySubstr: `"status":"cancelled"`,
+		},
+		{
+			name:           "reopen by employee",
+			receptionID:    receptionID.String(),
+			handle:         func(h *ReceptionHandler) http.HandlerFunc { return h.ReopenReception },
+			wantAction:     reception.Reopen,
+			serviceErr:     models.ErrTransitionForbidden,
+			wantStatus:     http.StatusForbidden,
+			wantBodySubstr: models.ErrTransitionForbidden.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubReceptionService{
+				ChangeStatusFunc: func(ctx context.Context, id uuid.UUID, action reception.Action) (*dto.Reception, error) {
+					require.Equal(t, receptionID, id)
+					require.Equal(t, tt.wantAction, action)
+					return tt.serviceReturn, tt.serviceErr
+				},
+			}
+			h := NewReceptionHandler(stub)
+
+			req := httptest.NewRequest(http.MethodPost, "/receptions/"+tt.receptionID+"/"+string(tt.wantAction), nil)
+			req.SetPathValue("receptionId", tt.receptionID)
+			w := httptest.NewRecorder()
+
+			tt.handle(h)(w, req)
+			resp := w.Result()
+			defer func(Body io.ReadCloser) {
+				err := Body.Close()
+				require.NoError(t, err)
+			}(resp.Body)
+
+			respBody, _ := io.ReadAll(resp.Body)
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+			require.Contains(t, string(respBody), tt.wantBodySubstr)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+	"github.com/itisalisas/avito-backend/internal/service/rbac"
+	"github.com/itisalisas/avito-backend/internal/utils"
+)
+
+type RoleHandler struct {
+	rbacService rbac.ServiceInterface
+}
+
+func NewRoleHandler(rbacService rbac.ServiceInterface) *RoleHandler {
+	return &RoleHandler{rbacService: rbacService}
+}
+
+func (h *RoleHandler) GetPermissions(w http.ResponseWriter, r *http.Request) {
+	utils.WriteResponse(w, h.rbacService.ListPermissions(r.Context()), http.StatusOK)
+}
+
+func (h *RoleHandler) GetRoles(w http.ResponseWriter, r *http.Request) {
+	roles, err := h.rbacService.ListRoles(r.Context())
+	switch {
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		utils.WriteResponse(w, roles, http.StatusOK)
+	}
+}
+
+func (h *RoleHandler) SetRolePermissions(w http.ResponseWriter, r *http.Request) {
+	var request dto.PutRolesRolePermissionsJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	role, err := h.rbacService.SetRolePermissions(r.Context(), r.PathValue("role"), request)
+	switch {
+	case errors.Is(err, models.ErrIncorrectUserRole) || errors.Is(err, models.ErrUnknownPermission) ||
+		errors.Is(err, models.ErrRoleLockout):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusBadRequest)
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		utils.WriteResponse(w, role, http.StatusOK)
+	}
+}
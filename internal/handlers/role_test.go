@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type stubRBACService struct {
+	ListRolesFunc          func(ctx context.Context) ([]*dto.Role, error)
+	SetRolePermissionsFunc func(ctx context.Context, role string, request dto.PutRolesRolePermissionsJSONRequestBody) (*dto.Role, error)
+}
+
+func (s *stubRBACService) RolePermissions(context.Context, dto.UserRole) ([]models.Permission, error) {
+	return nil, nil
+}
+func (s *stubRBACService) ListPermissions(context.Context) []string {
+	return []string{string(models.PermPvzRead)}
+}
+func (s *stubRBACService) ListRoles(ctx context.Context) ([]*dto.Role, error) {
+	return s.ListRolesFunc(ctx)
+}
+func (s *stubRBACService) SetRolePermissions(ctx context.Context, role string, request dto.PutRolesRolePermissionsJSONRequestBody) (*dto.Role, error) {
+	return s.SetRolePermissionsFunc(ctx, role, request)
+}
+
+func TestRoleHandler_SetRolePermissions(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           []byte
+		serviceErr     error
+		wantStatus     int
+		wantBodySubstr string
+	}{
+		{
+			name:           "invalid JSON",
+			body:           []byte(`{"permissions":}`),
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "Invalid request",
+		},
+		{
+			name:           "unknown role -> 400",
+			body:           []byte(`{"permissions":["pvz:read"]}`),
+			serviceErr:     models.ErrIncorrectUserRole,
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: models.ErrIncorrectUserRole.Error(),
+		},
+		{
+			name:           "unknown permission -> 400",
+			body:           []byte(`{"permissions":["pvz:burn"]}`),
+			serviceErr:     models.ErrUnknownPermission,
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: models.ErrUnknownPermission.Error(),
+		},
+		{
+			name:           "admin lockout -> 400",
+			body:           []byte(`{"permissions":[]}`),
+			serviceErr:     models.ErrRoleLockout,
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: models.ErrRoleLockout.Error(),
+		},
+		{
+			name:           "internal error",
+			body:           []byte(`{"permissions":["pvz:read"]}`),
+			serviceErr:     errors.New("db error"),
+			wantStatus:     http.StatusInternalServerError,
+			wantBodySubstr: "db error",
+		},
+		{
+			name:           "success -> 200",
+			body:           []byte(`{"permissions":["pvz:read"]}`),
+			wantStatus:     http.StatusOK,
+			wantBodySubstr: `"permissions":["pvz:read"]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubRBACService{
+				SetRolePermissionsFunc: func(ctx context.Context, role string, request dto.PutRolesRolePermissionsJSONRequestBody) (*dto.Role, error) {
+					require.Equal(t, "employee", role)
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &dto.Role{Role: role, Permissions: request.Permissions}, nil
+				},
+			}
+			h := NewRoleHandler(stub)
+
+			req := httptest.NewRequest(http.MethodPut, "/roles/employee/permissions", bytes.NewReader(tt.body))
+			req.SetPathValue("role", "employee")
+			w := httptest.NewRecorder()
+
+			h.SetRolePermissions(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+			require.Contains(t, w.Body.String(), tt.wantBodySubstr)
+		})
+	}
+}
+
+func TestRoleHandler_GetRoles(t *testing.T) {
+	stub := &stubRBACService{
+		ListRolesFunc: func(ctx context.Context) ([]*dto.Role, error) {
+			return []*dto.Role{{Role: "employee", Permissions: []string{"pvz:read"}}}, nil
+		},
+	}
+	h := NewRoleHandler(stub)
+
+	w := httptest.NewRecorder()
+	h.GetRoles(w, httptest.NewRequest(http.MethodGet, "/roles", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"role":"employee"`)
+
+	w = httptest.NewRecorder()
+	h.GetPermissions(w, httptest.NewRequest(http.MethodGet, "/permissions", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"pvz:read"`)
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+	"github.com/itisalisas/avito-backend/internal/service/serviceaccount"
+	"github.com/itisalisas/avito-backend/internal/utils"
+)
+
+type ServiceAccountHandler struct {
+	serviceAccountService serviceaccount.ServiceInterface
+}
+
+func NewServiceAccountHandler(serviceAccountService serviceaccount.ServiceInterface) *ServiceAccountHandler {
+	return &ServiceAccountHandler{serviceAccountService: serviceAccountService}
+}
+
+func (h *ServiceAccountHandler) CreateServiceAccount(w http.ResponseWriter, r *http.Request) {
+	var request dto.PostServiceAccountsJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	account, err := h.serviceAccountService.CreateServiceAccount(r.Context(), request)
+	switch {
+	case errors.Is(err, models.ErrEmptyName) || errors.Is(err, models.ErrIncorrectUserRole) ||
+		errors.Is(err, models.ErrServiceAccountExists):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusBadRequest)
+	case errors.Is(err, models.ErrPvzNotFound):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusNotFound)
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		utils.WriteResponse(w, account, http.StatusCreated)
+	}
+}
+
+func (h *ServiceAccountHandler) GetServiceAccounts(w http.ResponseWriter, r *http.Request) {
+	accounts, err := h.serviceAccountService.ListServiceAccounts(r.Context())
+	switch {
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		utils.WriteResponse(w, accounts, http.StatusOK)
+	}
+}
+
+func (h *ServiceAccountHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	accountId, err := uuid.Parse(r.PathValue("serviceAccountId"))
+	if err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	var request dto.PostServiceAccountsServiceAccountIdApiKeysJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	key, err := h.serviceAccountService.CreateAPIKey(r.Context(), accountId, request)
+	switch {
+	case errors.Is(err, models.ErrEmptyName) || errors.Is(err, models.ErrInvalidAPIKeyTTL):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusBadRequest)
+	case errors.Is(err, models.ErrServiceAccountNotFound):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusNotFound)
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		utils.WriteResponse(w, key, http.StatusCreated)
+	}
+}
+
+func (h *ServiceAccountHandler) GetAPIKeys(w http.ResponseWriter, r *http.Request) {
+	accountId, err := uuid.Parse(r.PathValue("serviceAccountId"))
+	if err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	keys, err := h.serviceAccountService.ListAPIKeys(r.Context(), accountId)
+	switch {
+	case errors.Is(err, models.ErrServiceAccountNotFound):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusNotFound)
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		utils.WriteResponse(w, keys, http.StatusOK)
+	}
+}
+
+func (h *ServiceAccountHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	keyId, err := uuid.Parse(r.PathValue("keyId"))
+	if err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	err = h.serviceAccountService.RevokeAPIKey(r.Context(), keyId)
+	switch {
+	case errors.Is(err, models.ErrAPIKeyNotFound):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusNotFound)
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type stubServiceAccountService struct {
+	CreateServiceAccountFunc func(ctx context.Context, request dto.PostServiceAccountsJSONRequestBody) (*dto.ServiceAccount, error)
+	ListServiceAccountsFunc  func(ctx context.Context) ([]*dto.ServiceAccount, error)
+	CreateAPIKeyFunc         func(ctx context.Context, accountId uuid.UUID, request dto.PostServiceAccountsServiceAccountIdApiKeysJSONRequestBody) (*dto.ApiKey, error)
+	ListAPIKeysFunc          func(ctx context.Context, accountId uuid.UUID) ([]*dto.ApiKey, error)
+	RevokeAPIKeyFunc         func(ctx context.Context, id uuid.UUID) error
+}
+
+func (s *stubServiceAccountService) CreateServiceAccount(ctx context.Context, request dto.PostServiceAccountsJSONRequestBody) (*dto.ServiceAccount, error) {
+	return s.CreateServiceAccountFunc(ctx, request)
+}
+func (s *stubServiceAccountService) ListServiceAccounts(ctx context.Context) ([]*dto.ServiceAccount, error) {
+	return s.ListServiceAccountsFunc(ctx)
+}
+func (s *stubServiceAccountService) CreateAPIKey(ctx context.Context, accountId uuid.UUID, request dto.PostServiceAccountsServiceAccountIdApiKeysJSONRequestBody) (*dto.ApiKey, error) {
+	return s.CreateAPIKeyFunc(ctx, accountId, request)
+}
+func (s *stubServiceAccountService) ListAPIKeys(ctx context.Context, accountId uuid.UUID) ([]*dto.ApiKey, error) {
+	return s.ListAPIKeysFunc(ctx, accountId)
+}
+func (s *stubServiceAccountService) RevokeAPIKey(ctx context.Context, id uuid.UUID) error {
+	return s.RevokeAPIKeyFunc(ctx, id)
+}
+func (s *stubServiceAccountService) AuthenticateAPIKey(context.Context, string) (models.Principal, error) {
+	return models.Principal{}, models.ErrInvalidAPIKey
+}
+
+func TestServiceAccountHandler_CreateServiceAccount(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           []byte
+		serviceErr     error
+		wantStatus     int
+		wantBodySubstr string
+	}{
+		{
+			name:           "invalid JSON",
+			body:           []byte(`{"name":}`),
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "Invalid request",
+		},
+		{
+			name:           "invalid role -> 400",
+			body:           []byte(`{"name":"warehouse","role":"admin"}`),
+			serviceErr:     models.ErrIncorrectUserRole,
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: models.ErrIncorrectUserRole.Error(),
+		},
+		{
+			name:           "name taken -> 400",
+			body:           []byte(`{"name":"warehouse","role":"employee"}`),
+			serviceErr:     models.ErrServiceAccountExists,
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: models.ErrServiceAccountExists.Error(),
+		},
+		{
+			name:           "unknown pvz -> 404",
+			body:           []byte(`{"name":"warehouse","role":"employee","pvzIds":["` + uuid.NewString() + `"]}`),
+			serviceErr:     models.ErrPvzNotFound,
+			wantStatus:     http.StatusNotFound,
+			wantBodySubstr: models.ErrPvzNotFound.Error(),
+		},
+		{
+			name:           "internal error",
+			body:           []byte(`{"name":"warehouse","role":"employee"}`),
+			serviceErr:     errors.New("db error"),
+			wantStatus:     http.StatusInternalServerError,
+			wantBodySubstr: "db error",
+		},
+		{
+			name:           "success -> 201",
+			body:           []byte(`{"name":"warehouse","role":"employee"}`),
+			wantStatus:     http.StatusCreated,
+			wantBodySubstr: `"name":"warehouse"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubServiceAccountService{
+				CreateServiceAccountFunc: func(ctx context.Context, request dto.PostServiceAccountsJSONRequestBody) (*dto.ServiceAccount, error) {
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &dto.ServiceAccount{Id: uuid.New(), Name: request.Name, Role: request.Role, PvzIds: []uuid.UUID{}}, nil
+				},
+			}
+			h := NewServiceAccountHandler(stub)
+
+			req := httptest.NewRequest(http.MethodPost, "/service-accounts", bytes.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			h.CreateServiceAccount(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+			require.Contains(t, w.Body.String(), tt.wantBodySubstr)
+		})
+	}
+}
+
+func TestServiceAccountHandler_CreateAPIKey(t *testing.T) {
+	accountId := uuid.New()
+	tests := []struct {
+		name           string
+		accountId      string
+		body           []byte
+		serviceErr     error
+		wantStatus     int
+		wantBodySubstr string
+	}{
+		{
+			name:           "invalid account id",
+			accountId:      "not-a-uuid",
+			body:           []byte(`{"name":"sync"}`),
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "Invalid request",
+		},
+		{
+			name:           "invalid ttl -> 400",
+			accountId:      accountId.String(),
+			body:           []byte(`{"name":"sync","ttlDays":0}`),
+			serviceErr:     models.ErrInvalidAPIKeyTTL,
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: models.ErrInvalidAPIKeyTTL.Error(),
+		},
+		{
+			name:           "account not found -> 404",
+			accountId:      accountId.String(),
+			body:           []byte(`{"name":"sync"}`),
+			serviceErr:     models.ErrServiceAccountNotFound,
+			wantStatus:     http.StatusNotFound,
+			wantBodySubstr: models.ErrServiceAccountNotFound.Error(),
+		},
+		{
+			name:           "success -> 201",
+			accountId:      accountId.String(),
+			body:           []byte(`{"name":"sync"}`),
+			wantStatus:     http.StatusCreated,
+			wantBodySubstr: `"key":"pvz_secret"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubServiceAccountService{
+				CreateAPIKeyFunc: func(ctx context.Context, id uuid.UUID, request dto.PostServiceAccountsServiceAccountIdApiKeysJSONRequestBody) (*dto.ApiKey, error) {
+					require.Equal(t, accountId, id)
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					key := "pvz_secret"
+					return &dto.ApiKey{Id: uuid.New(), ServiceAccountId: id, Name: request.Name, Key: &key, Prefix: "pvz_secret", CreatedAt: time.Now()}, nil
+				},
+			}
+			h := NewServiceAccountHandler(stub)
+
+			req := httptest.NewRequest(http.MethodPost, "/service-accounts/"+tt.accountId+"/api-keys", bytes.NewReader(tt.body))
+			req.SetPathValue("serviceAccountId", tt.accountId)
+			w := httptest.NewRecorder()
+
+			h.CreateAPIKey(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+			require.Contains(t, w.Body.String(), tt.wantBodySubstr)
+		})
+	}
+}
+
+func TestServiceAccountHandler_GetAPIKeys(t *testing.T) {
+	accountId := uuid.New()
+	key := &dto.ApiKey{Id: uuid.New(), ServiceAccountId: accountId, Name: "sync", Prefix: "pvz_abcdefgh", CreatedAt: time.Now().UTC()}
+
+	stub := &stubServiceAccountService{
+		ListAPIKeysFunc: func(ctx context.Context, id uuid.UUID) ([]*dto.ApiKey, error) {
+			if id != accountId {
+				return nil, models.ErrServiceAccountNotFound
+			}
+			return []*dto.ApiKey{key}, nil
+		},
+	}
+	h := NewServiceAccountHandler(stub)
+
+	req := httptest.NewRequest(http.MethodGet, "/service-accounts/"+accountId.String()+"/api-keys", nil)
+	req.SetPathValue("serviceAccountId", accountId.String())
+	w := httptest.NewRecorder()
+	h.GetAPIKeys(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var keys []dto.ApiKey
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &keys))
+	require.Len(t, keys, 1)
+	require.Equal(t, key.Id, keys[0].Id)
+	require.NotContains(t, w.Body.String(), `"key"`)
+
+	otherId := uuid.NewString()
+	req = httptest.NewRequest(http.MethodGet, "/service-accounts/"+otherId+"/api-keys", nil)
+	req.SetPathValue("serviceAccountId", otherId)
+	w = httptest.NewRecorder()
+	h.GetAPIKeys(w, req)
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestServiceAccountHandler_RevokeAPIKey(t *testing.T) {
+	keyId := uuid.New()
+	tests := []struct {
+		name       string
+		keyId      string
+		serviceErr error
+		wantStatus int
+	}{
+		{
+			name:       "invalid key id",
+			keyId:      "not-a-uuid",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "key not found -> 404",
+			keyId:      keyId.String(),
+			serviceErr: models.ErrAPIKeyNotFound,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "internal error",
+			keyId:      keyId.String(),
+			serviceErr: errors.New("db error"),
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:       "success -> 204",
+			keyId:      keyId.String(),
+			wantStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubServiceAccountService{
+				RevokeAPIKeyFunc: func(ctx context.Context, id uuid.UUID) error {
+					require.Equal(t, keyId, id)
+					return tt.serviceErr
+				},
+			}
+			h := NewServiceAccountHandler(stub)
+
+			req := httptest.NewRequest(http.MethodPost, "/api-keys/"+tt.keyId+"/revoke", nil)
+			req.SetPathValue("keyId", tt.keyId)
+			w := httptest.NewRecorder()
+
+			h.RevokeAPIKey(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
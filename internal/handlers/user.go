@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+	"github.com/itisalisas/avito-backend/internal/service/user"
+	"github.com/itisalisas/avito-backend/internal/utils"
+)
+
+type UserHandler struct {
+	userService user.ServiceInterface
+}
+
+func NewUserHandler(userService user.ServiceInterface) *UserHandler {
+	return &UserHandler{userService: userService}
+}
+
+func (h *UserHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
+	params, err := parseGetUsersParams(r.URL.Query())
+	if err != nil {
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	listParams := models.UserListParams{
+		Page:  uint64(*params.Page),
+		Limit: uint64(*params.Limit),
+	}
+	listParams.Active = params.Active
+	if params.Email != nil {
+		listParams.Email = *params.Email
+	}
+	if params.Role != nil {
+		role := dto.UserRole(*params.Role)
+		listParams.Role = &role
+	}
+
+	page, err := h.userService.ListUsers(r.Context(), listParams)
+	switch {
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		users := make([]*dto.User, 0, len(page.Items))
+		for _, u := range page.Items {
+			users = append(users, u.DTO())
+		}
+		w.Header().Set("X-Total-Count", strconv.FormatUint(page.TotalCount, 10))
+		utils.WriteResponse(w, users, http.StatusOK)
+	}
+}
+
+func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	userId, err := uuid.Parse(r.PathValue("userId"))
+	if err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	var request dto.PatchUsersUserIdJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	updatedUser, err := h.userService.ChangeRole(r.Context(), userId, request)
+	writeUserResponse(w, updatedUser, err)
+}
+
+func (h *UserHandler) DeactivateUser(w http.ResponseWriter, r *http.Request) {
+	userId, err := uuid.Parse(r.PathValue("userId"))
+	if err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	updatedUser, err := h.userService.DeactivateUser(r.Context(), userId)
+	writeUserResponse(w, updatedUser, err)
+}
+
+func (h *UserHandler) ReactivateUser(w http.ResponseWriter, r *http.Request) {
+	userId, err := uuid.Parse(r.PathValue("userId"))
+	if err != nil {
+		utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	updatedUser, err := h.userService.ReactivateUser(r.Context(), userId)
+	writeUserResponse(w, updatedUser, err)
+}
+
+func writeUserResponse(w http.ResponseWriter, updatedUser *dto.User, err error) {
+	switch {
+	case errors.Is(err, models.ErrIncorrectUserRole) || errors.Is(err, models.ErrSelfModification):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusBadRequest)
+	case errors.Is(err, models.ErrPermissionDenied):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusForbidden)
+	case errors.Is(err, models.ErrUserNotFound):
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusNotFound)
+	case err != nil:
+		utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+	default:
+		utils.WriteResponse(w, updatedUser, http.StatusOK)
+	}
+}
+
+func parseGetUsersParams(query url.Values) (*dto.GetUsersParams, error) {
+	params := &dto.GetUsersParams{}
+
+	if email := query.Get("email"); email != "" {
+		params.Email = &email
+	}
+
+	if role := query.Get("role"); role != "" {
+		switch dto.UserRole(role) {
+		case dto.UserRoleEmployee, dto.UserRoleModerator, dto.UserRoleAdmin:
+		default:
+			return nil, errors.New("invalid role")
+		}
+		params.Role = &role
+	}
+
+	if activeStr := query.Get("active"); activeStr != "" {
+		active, err := strconv.ParseBool(activeStr)
+		if err != nil {
+			return nil, errors.New("invalid active format")
+		}
+		params.Active = &active
+	}
+
+	page := 1
+	if pageStr := query.Get("page"); pageStr != "" {
+		var err error
+		page, err = strconv.Atoi(pageStr)
+		if err != nil || page < 1 {
+			return nil, errors.New("invalid page format")
+		}
+	}
+	params.Page = &page
+
+	limit := 20
+	if limitStr := query.Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > 100 {
+			return nil, errors.New("invalid limit format")
+		}
+	}
+	params.Limit = &limit
+
+	return params, nil
+}
@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type stubUserService struct {
+	ListUsersFunc      func(ctx context.Context, params models.UserListParams) (*models.UserPage, error)
+	ChangeRoleFunc     func(ctx context.Context, id uuid.UUID, request dto.PatchUsersUserIdJSONRequestBody) (*dto.User, error)
+	DeactivateUserFunc func(ctx context.Context, id uuid.UUID) (*dto.User, error)
+	ReactivateUserFunc func(ctx context.Context, id uuid.UUID) (*dto.User, error)
+}
+
+func (s *stubUserService) ListUsers(ctx context.Context, params models.UserListParams) (*models.UserPage, error) {
+	return s.ListUsersFunc(ctx, params)
+}
+func (s *stubUserService) ChangeRole(ctx context.Context, id uuid.UUID, request dto.PatchUsersUserIdJSONRequestBody) (*dto.User, error) {
+	return s.ChangeRoleFunc(ctx, id, request)
+}
+func (s *stubUserService) DeactivateUser(ctx context.Context, id uuid.UUID) (*dto.User, error) {
+	return s.DeactivateUserFunc(ctx, id)
+}
+func (s *stubUserService) ReactivateUser(ctx context.Context, id uuid.UUID) (*dto.User, error) {
+	return s.ReactivateUserFunc(ctx, id)
+}
+func (s *stubUserService) ActiveUserRole(context.Context, uuid.UUID) (dto.UserRole, error) {
+	return dto.UserRoleEmployee, nil
+}
+
+func TestUserHandler_GetUsers(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "employee@example.com", Role: dto.UserRoleEmployee, Active: true}
+	role := dto.UserRoleEmployee
+	active := false
+
+	tests := []struct {
+		name           string
+		query          string
+		wantParams     models.UserListParams
+		serviceErr     error
+		wantStatus     int
+		wantBodySubstr string
+	}{
+		{
+			name:       "defaults",
+			wantParams: models.UserListParams{Page: 1, Limit: 20},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:  "filters",
+			query: "?email=Example&role=employee&active=false&page=2&limit=5",
+			wantParams: models.UserListParams{
+				UserFilter: models.UserFilter{Email: "Example", Role: &role, Active: &active},
+				Page:       2,
+				Limit:      5,
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid role",
+			query:          "?role=owner",
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "invalid role",
+		},
+		{
+			name:           "invalid active",
+			query:          "?active=maybe",
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "invalid active format",
+		},
+		{
+			name:           "invalid limit",
+			query:          "?limit=101",
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "invalid limit format",
+		},
+		{
+			name:           "internal error",
+			wantParams:     models.UserListParams{Page: 1, Limit: 20},
+			serviceErr:     errors.New("db error"),
+			wantStatus:     http.StatusInternalServerError,
+			wantBodySubstr: "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubUserService{
+				ListUsersFunc: func(ctx context.Context, params models.UserListParams) (*models.UserPage, error) {
+					require.Equal(t, tt.wantParams, params)
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &models.UserPage{Items: []*models.User{user}, TotalCount: 7}, nil
+				},
+			}
+			h := NewUserHandler(stub)
+
+			req := httptest.NewRequest(http.MethodGet, "/users"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			h.GetUsers(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+			require.Contains(t, w.Body.String(), tt.wantBodySubstr)
+			if tt.wantStatus == http.StatusOK {
+				require.Equal(t, "7", w.Header().Get("X-Total-Count"))
+				var users []dto.User
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &users))
+				require.Equal(t, []dto.User{*user.DTO()}, users)
+				require.NotContains(t, w.Body.String(), "password")
+			}
+		})
+	}
+}
+
+func TestUserHandler_UpdateUser(t *testing.T) {
+	userId := uuid.New()
+	tests := []struct {
+		name           string
+		userId         string
+		body           []byte
+		serviceErr     error
+		wantStatus     int
+		wantBodySubstr string
+	}{
+		{
+			name:           "invalid user id",
+			userId:         "not-a-uuid",
+			body:           []byte(`{"role":"moderator"}`),
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "Invalid request",
+		},
+		{
+			name:           "invalid JSON",
+			userId:         userId.String(),
+			body:           []byte(`{"role":}`),
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "Invalid request",
+		},
+		{
+			name:           "own role -> 400",
+			userId:         userId.String(),
+			body:           []byte(`{"role":"moderator"}`),
+			serviceErr:     models.ErrSelfModification,
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: models.ErrSelfModification.Error(),
+		},
+		{
+			name:           "user not found -> 404",
+			userId:         userId.String(),
+			body:           []byte(`{"role":"moderator"}`),
+			serviceErr:     models.ErrUserNotFound,
+			wantStatus:     http.StatusNotFound,
+			wantBodySubstr: models.ErrUserNotFound.Error(),
+		},
+		{
+			name:           "success -> 200",
+			userId:         userId.String(),
+			body:           []byte(`{"role":"moderator"}`),
+			wantStatus:     http.StatusOK,
+			wantBodySubstr: `"role":"moderator"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubUserService{
+				ChangeRoleFunc: func(ctx context.Context, id uuid.UUID, req dto.PatchUsersUserIdJSONRequestBody) (*dto.User, error) {
+					require.Equal(t, userId, id)
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &dto.User{Id: &id, Email: "user@example.com", Role: dto.UserRole(req.Role)}, nil
+				},
+			}
+			h := NewUserHandler(stub)
+
+			req := httptest.NewRequest(http.MethodPatch, "/users/"+tt.userId, bytes.NewReader(tt.body))
+			req.SetPathValue("userId", tt.userId)
+			w := httptest.NewRecorder()
+
+			h.UpdateUser(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+			require.Contains(t, w.Body.String(), tt.wantBodySubstr)
+		})
+	}
+}
+
+func TestUserHandler_SetActive(t *testing.T) {
+	userId := uuid.New()
+	tests := []struct {
+		name           string
+		userId         string
+		serviceErr     error
+		wantStatus     int
+		wantBodySubstr string
+	}{
+		{
+			name:           "invalid user id",
+			userId:         "not-a-uuid",
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "Invalid request",
+		},
+		{
+			name:           "self -> 400",
+			userId:         userId.String(),
+			serviceErr:     models.ErrSelfModification,
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: models.ErrSelfModification.Error(),
+		},
+		{
+			name:           "user not found -> 404",
+			userId:         userId.String(),
+			serviceErr:     models.ErrUserNotFound,
+			wantStatus:     http.StatusNotFound,
+			wantBodySubstr: models.ErrUserNotFound.Error(),
+		},
+		{
+			name:       "success -> 200",
+			userId:     userId.String(),
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		for _, active := range []bool{false, true} {
+			t.Run(tt.name, func(t *testing.T) {
+				setActive := func(ctx context.Context, id uuid.UUID) (*dto.User, error) {
+					require.Equal(t, userId, id)
+					if tt.serviceErr != nil {
+						return nil, tt.serviceErr
+					}
+					return &dto.User{Id: &id, Email: "user@example.com", Role: dto.UserRoleEmployee, Active: &active}, nil
+				}
+				h := NewUserHandler(&stubUserService{DeactivateUserFunc: setActive, ReactivateUserFunc: setActive})
+
+				req := httptest.NewRequest(http.MethodPost, "/users/"+tt.userId, nil)
+				req.SetPathValue("userId", tt.userId)
+				w := httptest.NewRecorder()
+
+				if active {
+					h.ReactivateUser(w, req)
+				} else {
+					h.DeactivateUser(w, req)
+				}
+
+				require.Equal(t, tt.wantStatus, w.Code)
+				require.Contains(t, w.Body.String(), tt.wantBodySubstr)
+			})
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+)
+
+// JWKS returns the public keys accepted for verification, signing key first.
+func (m *Manager) JWKS() dto.JWKSet {
+	set := dto.JWKSet{Keys: make([]dto.JWK, 0, len(m.order))}
+	for _, id := range m.order {
+		k := m.keys[id]
+		jwk := publicJWK(k.public)
+		jwk.Kid = k.id
+		jwk.Use = "sig"
+		jwk.Alg = k.method.Alg()
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+// publicJWK holds the members of a key that its RFC 7638 thumbprint is
+// computed over.
+func publicJWK(public crypto.PublicKey) dto.JWK {
+	switch public := public.(type) {
+	case *rsa.PublicKey:
+		n := encode(public.N.Bytes())
+		e := encode(big.NewInt(int64(public.E)).Bytes())
+		return dto.JWK{Kty: "RSA", N: &n, E: &e}
+	case ed25519.PublicKey:
+		crv := "Ed25519"
+		x := encode(public)
+		return dto.JWK{Kty: "OKP", Crv: &crv, X: &x}
+	default:
+		return dto.JWK{}
+	}
+}
+
+// thumbprint computes the RFC 7638 JWK thumbprint used as the key id.
+func thumbprint(public crypto.PublicKey) (string, error) {
+	jwk := publicJWK(public)
+
+	// The required members in lexicographic order, without whitespace.
+	var members any
+	switch jwk.Kty {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{*jwk.E, jwk.Kty, *jwk.N}
+	case "OKP":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{*jwk.Crv, jwk.Kty, *jwk.X}
+	default:
+		return "", ErrUnsupportedKey
+	}
+
+	b, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return encode(sum[:]), nil
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
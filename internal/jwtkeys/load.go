@@ -0,0 +1,97 @@
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LoadFromEnv loads the signing key from the PEM file named by
+// JWT_SIGNING_KEY_FILE and extra verification keys from the comma-separated
+// PEM files in JWT_VERIFY_KEY_FILES.
+func LoadFromEnv() (*Manager, error) {
+	var verifyFiles []string
+	for _, path := range strings.Split(os.Getenv("JWT_VERIFY_KEY_FILES"), ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			verifyFiles = append(verifyFiles, path)
+		}
+	}
+	return LoadFiles(os.Getenv("JWT_SIGNING_KEY_FILE"), verifyFiles...)
+}
+
+// LoadFiles loads a PKCS#8 or PKCS#1 private signing key and PKIX public
+// verification keys, all PEM encoded.
+func LoadFiles(signingFile string, verifyFiles ...string) (*Manager, error) {
+	if signingFile == "" {
+		return nil, ErrNoSigningKey
+	}
+
+	block, err := readPEM(signingFile)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := parsePrivateKey(block)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", signingFile, err)
+	}
+
+	verify := make([]crypto.PublicKey, 0, len(verifyFiles))
+	for _, path := range verifyFiles {
+		block, err := readPEM(path)
+		if err != nil {
+			return nil, err
+		}
+		public, err := parsePublicKey(block)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		verify = append(verify, public)
+	}
+
+	return NewManager(signer, verify...)
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jwt key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s: no PEM block found", path)
+	}
+	return block, nil
+}
+
+func parsePrivateKey(block *pem.Block) (crypto.Signer, error) {
+	if block.Type == "RSA PRIVATE KEY" {
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+
+	private, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := private.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedKey, private)
+	}
+	return signer, nil
+}
+
+// parsePublicKey also accepts a private key, so the file of a retired signing
+// key can be kept in the verification set as is.
+func parsePublicKey(block *pem.Block) (crypto.PublicKey, error) {
+	if block.Type == "PUBLIC KEY" {
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	}
+
+	signer, err := parsePrivateKey(block)
+	if err != nil {
+		return nil, err
+	}
+	return signer.Public(), nil
+}
@@ -0,0 +1,113 @@
+// Package jwtkeys holds the asymmetric keys access tokens are signed and
+// verified with.
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const minRSABits = 2048
+
+var (
+	ErrNoSigningKey   = errors.New("no jwt signing key configured")
+	ErrUnknownKey     = errors.New("unknown jwt key id")
+	ErrKeyAlgorithm   = errors.New("jwt algorithm does not match the key")
+	ErrUnsupportedKey = errors.New("unsupported jwt key type")
+)
+
+type key struct {
+	id     string
+	method jwt.SigningMethod
+	public crypto.PublicKey
+}
+
+// Manager signs tokens with one private key and verifies them with any of
+// its public keys. During rotation the public keys of retired signing keys
+// stay in the verification set until the tokens they signed expire.
+type Manager struct {
+	signer  crypto.Signer
+	signKey key
+	keys    map[string]key
+	order   []string
+}
+
+// NewManager builds a manager that signs with signer and additionally accepts
+// tokens signed by the private halves of verify.
+func NewManager(signer crypto.Signer, verify ...crypto.PublicKey) (*Manager, error) {
+	if signer == nil {
+		return nil, ErrNoSigningKey
+	}
+
+	signKey, err := newKey(signer.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manager{signer: signer, signKey: signKey, keys: map[string]key{}}
+	m.add(signKey)
+	for _, public := range verify {
+		k, err := newKey(public)
+		if err != nil {
+			return nil, err
+		}
+		m.add(k)
+	}
+	return m, nil
+}
+
+func (m *Manager) add(k key) {
+	if _, ok := m.keys[k.id]; ok {
+		return
+	}
+	m.keys[k.id] = k
+	m.order = append(m.order, k.id)
+}
+
+// Sign returns the signed token with the kid header of the signing key.
+func (m *Manager) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(m.signKey.method, claims)
+	token.Header["kid"] = m.signKey.id
+	return token.SignedString(m.signer)
+}
+
+// Keyfunc resolves the verification key of a token by its kid header. It
+// rejects tokens whose alg does not belong to that key, so a public key can
+// never be used as an HMAC secret.
+func (m *Manager) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	k, ok := m.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	if token.Method.Alg() != k.method.Alg() {
+		return nil, ErrKeyAlgorithm
+	}
+	return k.public, nil
+}
+
+func newKey(public crypto.PublicKey) (key, error) {
+	var method jwt.SigningMethod
+	switch public := public.(type) {
+	case *rsa.PublicKey:
+		if public.N.BitLen() < minRSABits {
+			return key{}, fmt.Errorf("rsa key must be at least %d bits", minRSABits)
+		}
+		method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		method = jwt.SigningMethodEdDSA
+	default:
+		return key{}, fmt.Errorf("%w: %T", ErrUnsupportedKey, public)
+	}
+
+	id, err := thumbprint(public)
+	if err != nil {
+		return key{}, err
+	}
+	return key{id: id, method: method, public: public}, nil
+}
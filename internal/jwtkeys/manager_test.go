@@ -0,0 +1,154 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newEd25519(t *testing.T) ed25519.PrivateKey {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return private
+}
+
+func claims() jwt.RegisteredClaims {
+	return jwt.RegisteredClaims{Subject: "user", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))}
+}
+
+func parse(m *Manager, token string) error {
+	_, err := jwt.ParseWithClaims(token, &jwt.RegisteredClaims{}, m.Keyfunc)
+	return err
+}
+
+func TestManager_SignAndVerify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		manager func() (*Manager, error)
+		alg     string
+	}{
+		{
+			name:    "EdDSA",
+			manager: func() (*Manager, error) { return NewManager(newEd25519(t)) },
+			alg:     "EdDSA",
+		},
+		{
+			name:    "RS256",
+			manager: func() (*Manager, error) { return NewManager(rsaKey) },
+			alg:     "RS256",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := tt.manager()
+			require.NoError(t, err)
+
+			token, err := m.Sign(claims())
+			require.NoError(t, err)
+
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, &jwt.RegisteredClaims{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.alg, parsed.Method.Alg())
+			assert.Equal(t, m.JWKS().Keys[0].Kid, parsed.Header["kid"])
+
+			assert.NoError(t, parse(m, token))
+		})
+	}
+}
+
+func TestManager_Rotation(t *testing.T) {
+	oldKey := newEd25519(t)
+	oldManager, err := NewManager(oldKey)
+	require.NoError(t, err)
+	oldToken, err := oldManager.Sign(claims())
+	require.NoError(t, err)
+
+	rotated, err := NewManager(newEd25519(t), oldKey.Public())
+	require.NoError(t, err)
+	assert.Len(t, rotated.JWKS().Keys, 2)
+	assert.NoError(t, parse(rotated, oldToken))
+
+	newToken, err := rotated.Sign(claims())
+	require.NoError(t, err)
+	assert.ErrorIs(t, parse(oldManager, newToken), ErrUnknownKey)
+}
+
+func TestManager_RejectsForeignAlgorithm(t *testing.T) {
+	m, err := NewManager(newEd25519(t))
+	require.NoError(t, err)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims())
+	token.Header["kid"] = m.JWKS().Keys[0].Kid
+	signed, err := token.SignedString([]byte("secret"))
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, parse(m, signed), ErrKeyAlgorithm)
+}
+
+func TestManager_RejectsWeakRSA(t *testing.T) {
+	weak, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	_, err = NewManager(weak)
+	assert.Error(t, err)
+}
+
+func TestThumbprint(t *testing.T) {
+	// The example key of RFC 7638, section 3.1.
+	n, err := base64.RawURLEncoding.DecodeString("0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw")
+	require.NoError(t, err)
+	public := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: 65537}
+
+	kid, err := thumbprint(public)
+	require.NoError(t, err)
+	assert.Equal(t, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", kid)
+}
+
+func TestLoadFiles(t *testing.T) {
+	dir := t.TempDir()
+	signing := newEd25519(t)
+	retired := newEd25519(t)
+
+	writePEM := func(name, blockType string, der []byte) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+		return path
+	}
+
+	signingDER, err := x509.MarshalPKCS8PrivateKey(signing)
+	require.NoError(t, err)
+	retiredDER, err := x509.MarshalPKIXPublicKey(retired.Public())
+	require.NoError(t, err)
+
+	signingFile := writePEM("signing.pem", "PRIVATE KEY", signingDER)
+	retiredFile := writePEM("retired.pem", "PUBLIC KEY", retiredDER)
+
+	m, err := LoadFiles(signingFile, retiredFile)
+	require.NoError(t, err)
+	assert.Len(t, m.JWKS().Keys, 2)
+
+	_, err = LoadFiles("")
+	assert.ErrorIs(t, err, ErrNoSigningKey)
+
+	_, err = LoadFiles(filepath.Join(dir, "missing.pem"))
+	assert.Error(t, err)
+
+	_, err = LoadFiles(retiredFile)
+	assert.Error(t, err)
+}
@@ -2,54 +2,153 @@ package middleware
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"net/http"
-	"os"
 	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
 	"github.com/itisalisas/avito-backend/internal/utils"
 )
 
-type contextKey string
+// TokenRevocations tells whether an access token was revoked before it
+// expired.
+type TokenRevocations interface {
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+}
 
-const userRoleKey contextKey = "userRole"
+// ActiveUsers resolves the current role of a user who may still use the
+// tokens issued to them. Deactivated and deleted users get
+// models.ErrUserDeactivated.
+type ActiveUsers interface {
+	ActiveUserRole(ctx context.Context, userId uuid.UUID) (dto.UserRole, error)
+}
 
-var jwtSecretKey = os.Getenv("JWT_SECRET_KEY")
+// APIKeys authenticates service accounts by the API keys issued to them.
+type APIKeys interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (models.Principal, error)
+}
 
-func CheckAuth() func(next http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			authHeader := r.Header.Get("Authorization")
-			if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
-				utils.WriteResponse(w, utils.Error("Authorization header required"), http.StatusUnauthorized)
-				return
-			}
+// RolePermissions resolves what a role is allowed to do.
+type RolePermissions interface {
+	RolePermissions(ctx context.Context, role dto.UserRole) ([]models.Permission, error)
+}
 
-			tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
-			if tokenStr == "" {
-				utils.WriteResponse(w, utils.Error("Authorization header required"), http.StatusUnauthorized)
-				return
-			}
+// APIKeyHeader carries the API key of a service account.
+const APIKeyHeader = "X-API-Key"
 
-			token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-					return nil, fmt.Errorf("error while parsing token")
-				}
-				return []byte(jwtSecretKey), nil
-			})
+// Authenticator resolves the principal of a request from its credentials.
+// It is shared by the HTTP and gRPC transports so both accept the same API
+// keys and tokens.
+type Authenticator struct {
+	keyfunc     jwt.Keyfunc
+	revocations TokenRevocations
+	users       ActiveUsers
+	apiKeys     APIKeys
+	permissions RolePermissions
+}
 
-			if err != nil || !token.Valid {
-				utils.WriteResponse(w, utils.Error("error while parsing token"), http.StatusUnauthorized)
-				return
-			}
+// NewAuthenticator returns an Authenticator. keyfunc resolves the key the
+// token signature is verified with.
+func NewAuthenticator(keyfunc jwt.Keyfunc, revocations TokenRevocations, users ActiveUsers,
+	apiKeys APIKeys, permissions RolePermissions) *Authenticator {
+	return &Authenticator{
+		keyfunc:     keyfunc,
+		revocations: revocations,
+		users:       users,
+		apiKeys:     apiKeys,
+		permissions: permissions,
+	}
+}
+
+// Authenticate returns the principal of the API key or, if there is none, of
+// the bearer token in authorization. Tokens of deactivated users are refused
+// even before they expire. The role of the user and its permissions are
+// loaded into the principal every time, so role changes and changes to the
+// role mapping apply without reissuing tokens.
+func (a *Authenticator) Authenticate(ctx context.Context, apiKey, authorization string) (models.Principal, error) {
+	principal, err := a.principal(ctx, apiKey, authorization)
+	if err != nil {
+		return models.Principal{}, err
+	}
+	principal.Permissions, err = a.permissions.RolePermissions(ctx, principal.Role)
+	if err != nil {
+		return models.Principal{}, err
+	}
+	return principal, nil
+}
 
-			if claims, ok := token.Claims.(jwt.MapClaims); ok {
-				ctx := context.WithValue(r.Context(), userRoleKey, claims["role"])
-				next.ServeHTTP(w, r.WithContext(ctx))
-			} else {
-				utils.WriteResponse(w, utils.Error("Token invalid"), http.StatusUnauthorized)
+func (a *Authenticator) principal(ctx context.Context, apiKey, authorization string) (models.Principal, error) {
+	if apiKey != "" {
+		return a.apiKeys.AuthenticateAPIKey(ctx, apiKey)
+	}
+
+	tokenStr, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok || tokenStr == "" {
+		return models.Principal{}, models.ErrAuthRequired
+	}
+
+	claims := &models.TokenClaims{}
+	token, err := jwt.ParseWithClaims(tokenStr, claims, a.keyfunc)
+	if err != nil || !token.Valid {
+		return models.Principal{}, models.ErrMalformedToken
+	}
+
+	userId, err := uuid.Parse(claims.Subject)
+	if err != nil || claims.ID == "" || claims.ExpiresAt == nil {
+		return models.Principal{}, models.ErrInvalidToken
+	}
+
+	revoked, err := a.revocations.IsTokenRevoked(ctx, claims.ID)
+	switch {
+	case err != nil:
+		return models.Principal{}, err
+	case revoked:
+		return models.Principal{}, models.ErrTokenRevoked
+	}
+
+	// Dummy tokens stand for no stored user and keep the role they were
+	// issued with.
+	role := claims.Role
+	if userId != models.DummyUserID {
+		if role, err = a.users.ActiveUserRole(ctx, userId); err != nil {
+			return models.Principal{}, err
+		}
+	}
+
+	return models.Principal{
+		UserID:         userId,
+		Email:          claims.Email,
+		Role:           role,
+		TokenID:        claims.ID,
+		TokenExpiresAt: claims.ExpiresAt.Time,
+	}, nil
+}
+
+// CheckAuth authenticates the request by its API key or, if it has none, by
+// its bearer token, as Authenticator.Authenticate does.
+func CheckAuth(keyfunc jwt.Keyfunc, revocations TokenRevocations, users ActiveUsers,
+	apiKeys APIKeys, permissions RolePermissions) func(next http.Handler) http.Handler {
+	authenticator := NewAuthenticator(keyfunc, revocations, users, apiKeys, permissions)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, err := authenticator.Authenticate(r.Context(), r.Header.Get(APIKeyHeader), r.Header.Get("Authorization"))
+			switch {
+			case errors.Is(err, models.ErrInvalidAPIKey),
+				errors.Is(err, models.ErrAuthRequired),
+				errors.Is(err, models.ErrMalformedToken),
+				errors.Is(err, models.ErrInvalidToken),
+				errors.Is(err, models.ErrTokenRevoked):
+				utils.WriteResponse(w, utils.Error(err.Error()), http.StatusUnauthorized)
+			case errors.Is(err, models.ErrUserDeactivated):
+				utils.WriteResponse(w, utils.Error(err.Error()), http.StatusForbidden)
+			case err != nil:
+				utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+			default:
+				next.ServeHTTP(w, r.WithContext(models.WithPrincipal(r.Context(), principal)))
 			}
 		})
 	}
@@ -1,6 +1,10 @@
 package middleware
 
 import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -8,28 +12,125 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/jwtkeys"
+	"github.com/itisalisas/avito-backend/internal/models"
 )
 
-func generateToken(t *testing.T, secretKey string, role string, exp time.Time) string {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+type stubRevocations struct {
+	revoked map[string]bool
+	err     error
+}
+
+func (s *stubRevocations) IsTokenRevoked(_ context.Context, jti string) (bool, error) {
+	return s.revoked[jti], s.err
+}
+
+// stubActiveUsers stores every user as an active employee unless roles or
+// inactive say otherwise.
+type stubActiveUsers struct {
+	roles    map[uuid.UUID]dto.UserRole
+	inactive map[uuid.UUID]bool
+	err      error
+}
+
+func (s *stubActiveUsers) ActiveUserRole(_ context.Context, userId uuid.UUID) (dto.UserRole, error) {
+	switch {
+	case s.err != nil:
+		return "", s.err
+	case s.inactive[userId]:
+		return "", models.ErrUserDeactivated
+	case s.roles[userId] != "":
+		return s.roles[userId], nil
+	default:
+		return dto.UserRoleEmployee, nil
+	}
+}
+
+type stubAPIKeys struct {
+	principals map[string]models.Principal
+	err        error
+}
+
+func (s *stubAPIKeys) AuthenticateAPIKey(_ context.Context, key string) (models.Principal, error) {
+	if s.err != nil {
+		return models.Principal{}, s.err
+	}
+	principal, ok := s.principals[key]
+	if !ok {
+		return models.Principal{}, models.ErrInvalidAPIKey
+	}
+	return principal, nil
+}
+
+type stubRolePermissions struct {
+	err error
+}
+
+func (s *stubRolePermissions) RolePermissions(_ context.Context, role dto.UserRole) ([]models.Permission, error) {
+	return models.DefaultRolePermissions[role], s.err
+}
+
+func newKeys(t *testing.T) *jwtkeys.Manager {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	keys, err := jwtkeys.NewManager(private)
+	require.NoError(t, err)
+	return keys
+}
+
+func generateToken(t *testing.T, keys *jwtkeys.Manager, jti string, role string, exp time.Time) string {
+	claims := jwt.MapClaims{
+		"sub":  uuid.New().String(),
 		"role": role,
 		"exp":  exp.Unix(),
-	})
-	tokenString, err := token.SignedString([]byte(secretKey))
+	}
+	if jti != "" {
+		claims["jti"] = jti
+	}
+	tokenString, err := keys.Sign(claims)
 	require.NoError(t, err)
 	return tokenString
 }
 
 func TestCheckAuth(t *testing.T) {
-	jwtSecretKey = "test_secret"
+	keys := newKeys(t)
 
-	validToken := generateToken(t, jwtSecretKey, "admin", time.Now().Add(time.Hour))
-	expiredToken := generateToken(t, jwtSecretKey, "admin", time.Now().Add(-time.Hour*25))
+	validToken := generateToken(t, keys, "valid", "admin", time.Now().Add(time.Hour))
+	expiredToken := generateToken(t, keys, "expired", "admin", time.Now().Add(-time.Hour*25))
+	revokedToken := generateToken(t, keys, "revoked", "admin", time.Now().Add(time.Hour))
+	noJtiToken := generateToken(t, keys, "", "admin", time.Now().Add(time.Hour))
+	foreignToken := generateToken(t, newKeys(t), "foreign", "admin", time.Now().Add(time.Hour))
+	hmacToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": uuid.New().String(),
+		"jti": "hmac",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte("secret"))
+	require.NoError(t, err)
+	revocations := &stubRevocations{revoked: map[string]bool{"revoked": true}}
+	deactivatedUserId := uuid.New()
+	deactivatedToken, err := keys.Sign(jwt.MapClaims{
+		"sub": deactivatedUserId.String(),
+		"jti": "deactivated",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+	require.NoError(t, err)
+	users := &stubActiveUsers{inactive: map[uuid.UUID]bool{deactivatedUserId: true}}
+	apiKeys := &stubAPIKeys{principals: map[string]models.Principal{
+		"pvz_valid": {UserID: uuid.New(), Role: dto.UserRoleEmployee, ServiceAccount: true},
+	}}
 
 	tests := []struct {
 		name            string
 		authHeader      string
+		apiKey          string
+		revocations     TokenRevocations
+		users           ActiveUsers
+		apiKeys         APIKeys
+		permissions     RolePermissions
 		wantStatus      int
 		wantResponseSub string
 	}{
@@ -51,22 +152,121 @@ func TestCheckAuth(t *testing.T) {
 			wantStatus:      http.StatusUnauthorized,
 			wantResponseSub: "error while parsing token",
 		},
+		{
+			name:            "token signed by another key",
+			authHeader:      "Bearer " + foreignToken,
+			wantStatus:      http.StatusUnauthorized,
+			wantResponseSub: "error while parsing token",
+		},
+		{
+			name:            "hmac token",
+			authHeader:      "Bearer " + hmacToken,
+			wantStatus:      http.StatusUnauthorized,
+			wantResponseSub: "error while parsing token",
+		},
+		{
+			name:            "token without jti",
+			authHeader:      "Bearer " + noJtiToken,
+			wantStatus:      http.StatusUnauthorized,
+			wantResponseSub: "Token invalid",
+		},
+		{
+			name:            "revoked token",
+			authHeader:      "Bearer " + revokedToken,
+			wantStatus:      http.StatusUnauthorized,
+			wantResponseSub: "Token revoked",
+		},
+		{
+			name:            "revocation lookup failure",
+			authHeader:      "Bearer " + validToken,
+			revocations:     &stubRevocations{err: errors.New("db error")},
+			wantStatus:      http.StatusInternalServerError,
+			wantResponseSub: "db error",
+		},
+		{
+			name:            "deactivated user",
+			authHeader:      "Bearer " + deactivatedToken,
+			wantStatus:      http.StatusForbidden,
+			wantResponseSub: models.ErrUserDeactivated.Error(),
+		},
+		{
+			name:            "user lookup failure",
+			authHeader:      "Bearer " + validToken,
+			users:           &stubActiveUsers{err: errors.New("db error")},
+			wantStatus:      http.StatusInternalServerError,
+			wantResponseSub: "db error",
+		},
+		{
+			name:            "permission lookup failure",
+			authHeader:      "Bearer " + validToken,
+			permissions:     &stubRolePermissions{err: errors.New("db error")},
+			wantStatus:      http.StatusInternalServerError,
+			wantResponseSub: "db error",
+		},
 		{
 			name:            "valid token",
 			authHeader:      "Bearer " + validToken,
 			wantStatus:      http.StatusOK,
 			wantResponseSub: "OK",
 		},
+		{
+			name:            "invalid api key",
+			apiKey:          "pvz_unknown",
+			wantStatus:      http.StatusUnauthorized,
+			wantResponseSub: models.ErrInvalidAPIKey.Error(),
+		},
+		{
+			name:            "api key lookup failure",
+			apiKey:          "pvz_valid",
+			apiKeys:         &stubAPIKeys{err: errors.New("db error")},
+			wantStatus:      http.StatusInternalServerError,
+			wantResponseSub: "db error",
+		},
+		{
+			name:            "api key permission lookup failure",
+			apiKey:          "pvz_valid",
+			permissions:     &stubRolePermissions{err: errors.New("db error")},
+			wantStatus:      http.StatusInternalServerError,
+			wantResponseSub: "db error",
+		},
+		{
+			name:            "valid api key",
+			apiKey:          "pvz_valid",
+			wantStatus:      http.StatusOK,
+			wantResponseSub: "OK",
+		},
+		{
+			name:            "api key checked before bearer token",
+			authHeader:      "Bearer " + validToken,
+			apiKey:          "pvz_unknown",
+			wantStatus:      http.StatusUnauthorized,
+			wantResponseSub: models.ErrInvalidAPIKey.Error(),
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mw := CheckAuth()(http.HandlerFunc(dummyHandler))
+			if tt.revocations == nil {
+				tt.revocations = revocations
+			}
+			if tt.users == nil {
+				tt.users = users
+			}
+			if tt.apiKeys == nil {
+				tt.apiKeys = apiKeys
+			}
+			if tt.permissions == nil {
+				tt.permissions = &stubRolePermissions{}
+			}
+			mw := CheckAuth(keys.Keyfunc, tt.revocations, tt.users, tt.apiKeys, tt.permissions)(http.HandlerFunc(dummyHandler))
 
 			req := httptest.NewRequest(http.MethodGet, "/", nil)
 			if tt.authHeader != "" {
 				req.Header.Set("Authorization", tt.authHeader)
 			}
+			if tt.apiKey != "" {
+				req.Header.Set(APIKeyHeader, tt.apiKey)
+			}
 
 			w := httptest.NewRecorder()
 			mw.ServeHTTP(w, req)
@@ -83,3 +283,103 @@ func TestCheckAuth(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckAuth_Principal(t *testing.T) {
+	keys := newKeys(t)
+	userId := uuid.New()
+	promotedUserId := uuid.New()
+	users := &stubActiveUsers{roles: map[uuid.UUID]dto.UserRole{promotedUserId: dto.UserRoleModerator}}
+
+	tests := []struct {
+		name          string
+		subject       string
+		wantStatus    int
+		wantPrincipal *models.Principal
+	}{
+		{
+			name:       "no subject",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "malformed subject",
+			subject:    "not-a-uuid",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:          "user id subject",
+			subject:       userId.String(),
+			wantStatus:    http.StatusOK,
+			wantPrincipal: &models.Principal{UserID: userId, Email: "employee@example.com", Role: dto.UserRoleEmployee},
+		},
+		{
+			name:          "role changed after the token was issued",
+			subject:       promotedUserId.String(),
+			wantStatus:    http.StatusOK,
+			wantPrincipal: &models.Principal{UserID: promotedUserId, Email: "employee@example.com", Role: dto.UserRoleModerator},
+		},
+		{
+			name:          "dummy subject",
+			subject:       models.DummyUserID.String(),
+			wantStatus:    http.StatusOK,
+			wantPrincipal: &models.Principal{UserID: models.DummyUserID, Email: "employee@example.com", Role: dto.UserRoleEmployee},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+			tokenString, err := keys.Sign(jwt.MapClaims{
+				"role":  "employee",
+				"email": "employee@example.com",
+				"sub":   tt.subject,
+				"jti":   "token-id",
+				"exp":   expiresAt.Unix(),
+			})
+			require.NoError(t, err)
+
+			if tt.wantPrincipal != nil {
+				tt.wantPrincipal.TokenID = "token-id"
+				tt.wantPrincipal.TokenExpiresAt = expiresAt
+				tt.wantPrincipal.Permissions = models.DefaultRolePermissions[tt.wantPrincipal.Role]
+			}
+
+			var gotPrincipal *models.Principal
+			mw := CheckAuth(keys.Keyfunc, &stubRevocations{}, users, &stubAPIKeys{}, &stubRolePermissions{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if principal, ok := models.PrincipalFromContext(r.Context()); ok {
+					gotPrincipal = &principal
+				}
+				dummyHandler(w, r)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+tokenString)
+			w := httptest.NewRecorder()
+			mw.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+			require.Equal(t, tt.wantPrincipal, gotPrincipal)
+		})
+	}
+}
+
+func TestCheckAuth_APIKeyPrincipal(t *testing.T) {
+	keys := newKeys(t)
+	want := models.Principal{UserID: uuid.New(), Role: dto.UserRoleModerator, ServiceAccount: true, PvzIds: []uuid.UUID{uuid.New()}}
+	apiKeys := &stubAPIKeys{principals: map[string]models.Principal{"pvz_key": want}}
+
+	want.Permissions = models.DefaultRolePermissions[dto.UserRoleModerator]
+
+	var got models.Principal
+	mw := CheckAuth(keys.Keyfunc, &stubRevocations{}, &stubActiveUsers{}, apiKeys, &stubRolePermissions{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = models.PrincipalFromContext(r.Context())
+		dummyHandler(w, r)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(APIKeyHeader, "pvz_key")
+	w := httptest.NewRecorder()
+	mw.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, want, got)
+}
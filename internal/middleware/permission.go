@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/itisalisas/avito-backend/internal/models"
+	"github.com/itisalisas/avito-backend/internal/utils"
+)
+
+// RequirePermission lets the request through only if its principal, put in
+// place by CheckAuth, holds the permission.
+func RequirePermission(permission models.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := models.PrincipalFromContext(r.Context())
+			switch {
+			case !ok:
+				utils.WriteResponse(w, utils.Error("Authorization required"), http.StatusUnauthorized)
+			case !principal.Can(permission):
+				utils.WriteResponse(w, utils.Error(models.ErrPermissionDenied.Error()), http.StatusForbidden)
+			default:
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
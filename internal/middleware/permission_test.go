@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+func dummyHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	_, err := w.Write([]byte("OK"))
+	if err != nil {
+		return
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	tests := []struct {
+		name           string
+		principal      *models.Principal
+		wantStatus     int
+		wantBodySubstr string
+	}{
+		{
+			name:           "no principal",
+			wantStatus:     http.StatusUnauthorized,
+			wantBodySubstr: "Authorization required",
+		},
+		{
+			name: "permission missing",
+			principal: &models.Principal{
+				Role:        dto.UserRoleEmployee,
+				Permissions: []models.Permission{models.PermPvzRead},
+			},
+			wantStatus:     http.StatusForbidden,
+			wantBodySubstr: models.ErrPermissionDenied.Error(),
+		},
+		{
+			name: "permission granted",
+			principal: &models.Principal{
+				Role:        dto.UserRoleModerator,
+				Permissions: []models.Permission{models.PermPvzRead, models.PermPvzCreate},
+			},
+			wantStatus:     http.StatusOK,
+			wantBodySubstr: "OK",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mw := RequirePermission(models.PermPvzCreate)(http.HandlerFunc(dummyHandler))
+
+			ctx := context.Background()
+			if tt.principal != nil {
+				ctx = models.WithPrincipal(ctx, *tt.principal)
+			}
+			req := httptest.NewRequest(http.MethodPost, "/pvz", nil).WithContext(ctx)
+
+			w := httptest.NewRecorder()
+			mw.ServeHTTP(w, req)
+
+			resp := w.Result()
+			defer func(Body io.ReadCloser) {
+				err := Body.Close()
+				require.NoError(t, err)
+			}(resp.Body)
+
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.Contains(t, string(body), tt.wantBodySubstr)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+	"github.com/itisalisas/avito-backend/internal/utils"
+)
+
+// PvzAccess decides whether an employee may work with a PVZ.
+type PvzAccess interface {
+	CheckAccess(ctx context.Context, userId, pvzId uuid.UUID) error
+	ReceptionPvzId(ctx context.Context, receptionId uuid.UUID) (uuid.UUID, error)
+}
+
+// PvzResolver returns the PVZ the request operates on.
+type PvzResolver func(r *http.Request, access PvzAccess) (uuid.UUID, error)
+
+// PvzFromPath takes the PVZ from the pvzId path parameter.
+func PvzFromPath(r *http.Request, _ PvzAccess) (uuid.UUID, error) {
+	return uuid.Parse(r.PathValue("pvzId"))
+}
+
+// maxPvzBodySize bounds the bodies PvzFromBody reads into memory, well
+// above any request that carries a pvzId.
+const maxPvzBodySize = 1 << 20
+
+// PvzFromBody takes the PVZ from the pvzId field of a JSON body and leaves
+// the body readable for the handler. Bodies over maxPvzBodySize are refused.
+func PvzFromBody(r *http.Request, _ PvzAccess) (uuid.UUID, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, maxPvzBodySize))
+	if err != nil {
+		return uuid.Nil, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	var request struct {
+		PvzId uuid.UUID `json:"pvzId"`
+	}
+	if err := json.Unmarshal(body, &request); err != nil {
+		return uuid.Nil, err
+	}
+	return request.PvzId, nil
+}
+
+// PvzFromReception takes the PVZ of the reception in the receptionId path
+// parameter.
+func PvzFromReception(r *http.Request, access PvzAccess) (uuid.UUID, error) {
+	receptionId, err := uuid.Parse(r.PathValue("receptionId"))
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return access.ReceptionPvzId(r.Context(), receptionId)
+}
+
+// CheckPvzAccess rejects employees that are not assigned to the PVZ of the
+// request. Moderators are not bound to PVZs and pass through. Service
+// accounts of either role are bound only by their own PVZ scope.
+func CheckPvzAccess(access PvzAccess, resolve PvzResolver) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, _ := models.PrincipalFromContext(r.Context())
+			scoped := principal.ServiceAccount && len(principal.PvzIds) > 0
+			if principal.Role != dto.UserRoleEmployee && !scoped {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			pvzId, err := resolve(r, access)
+			var tooLarge *http.MaxBytesError
+			switch {
+			case errors.As(err, &tooLarge):
+				utils.WriteResponse(w, utils.Error(err.Error()), http.StatusRequestEntityTooLarge)
+				return
+			case errors.Is(err, models.ErrReceptionNotFound):
+				utils.WriteResponse(w, utils.Error(err.Error()), http.StatusNotFound)
+				return
+			case err != nil:
+				utils.WriteResponse(w, utils.Error("Invalid request: "+err.Error()), http.StatusBadRequest)
+				return
+			}
+
+			if principal.ServiceAccount {
+				if !principal.CanAccessPvz(pvzId) {
+					utils.WriteResponse(w, utils.Error(models.ErrPvzAccessDenied.Error()), http.StatusForbidden)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			err = access.CheckAccess(r.Context(), principal.UserID, pvzId)
+			switch {
+			case errors.Is(err, models.ErrPvzAccessDenied):
+				utils.WriteResponse(w, utils.Error(err.Error()), http.StatusForbidden)
+			case err != nil:
+				utils.WriteResponse(w, utils.Error(err.Error()), http.StatusInternalServerError)
+			default:
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type stubPvzAccess struct {
+	userId     uuid.UUID
+	assigned   map[uuid.UUID]bool
+	receptions map[uuid.UUID]uuid.UUID
+	err        error
+}
+
+func (s *stubPvzAccess) CheckAccess(_ context.Context, userId, pvzId uuid.UUID) error {
+	if s.err != nil {
+		return s.err
+	}
+	if userId != s.userId || !s.assigned[pvzId] {
+		return models.ErrPvzAccessDenied
+	}
+	return nil
+}
+
+func (s *stubPvzAccess) ReceptionPvzId(_ context.Context, receptionId uuid.UUID) (uuid.UUID, error) {
+	pvzId, ok := s.receptions[receptionId]
+	if !ok {
+		return uuid.Nil, models.ErrReceptionNotFound
+	}
+	return pvzId, nil
+}
+
+func TestCheckPvzAccess(t *testing.T) {
+	userId := uuid.New()
+	assignedPvz := uuid.New()
+	otherPvz := uuid.New()
+	receptionId := uuid.New()
+
+	access := &stubPvzAccess{
+		userId:     userId,
+		assigned:   map[uuid.UUID]bool{assignedPvz: true},
+		receptions: map[uuid.UUID]uuid.UUID{receptionId: otherPvz},
+	}
+
+	tests := []struct {
+		name        string
+		role        string
+		withUser    bool
+		scope       []uuid.UUID
+		resolve     PvzResolver
+		pathKey     string
+		pathValue   string
+		body        string
+		access      PvzAccess
+		wantStatus  int
+		wantBodySub string
+	}{
+		{
+			name:       "moderator is not scoped",
+			role:       "moderator",
+			resolve:    PvzFromPath,
+			pathKey:    "pvzId",
+			pathValue:  otherPvz.String(),
+			access:     access,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:        "dummy employee",
+			role:        "employee",
+			resolve:     PvzFromPath,
+			pathKey:     "pvzId",
+			pathValue:   assignedPvz.String(),
+			access:      access,
+			wantStatus:  http.StatusForbidden,
+			wantBodySub: models.ErrPvzAccessDenied.Error(),
+		},
+		{
+			name:       "assigned pvz in path",
+			role:       "employee",
+			withUser:   true,
+			resolve:    PvzFromPath,
+			pathKey:    "pvzId",
+			pathValue:  assignedPvz.String(),
+			access:     access,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:        "other pvz in path",
+			role:        "employee",
+			withUser:    true,
+			resolve:     PvzFromPath,
+			pathKey:     "pvzId",
+			pathValue:   otherPvz.String(),
+			access:      access,
+			wantStatus:  http.StatusForbidden,
+			wantBodySub: models.ErrPvzAccessDenied.Error(),
+		},
+		{
+			name:       "assigned pvz in body",
+			role:       "employee",
+			withUser:   true,
+			resolve:    PvzFromBody,
+			body:       `{"pvzId":"` + assignedPvz.String() + `","type":"обувь"}`,
+			access:     access,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:        "invalid body",
+			role:        "employee",
+			withUser:    true,
+			resolve:     PvzFromBody,
+			body:        `qwerty`,
+			access:      access,
+			wantStatus:  http.StatusBadRequest,
+			wantBodySub: "Invalid request",
+		},
+		{
+			name:        "body too large",
+			role:        "employee",
+			withUser:    true,
+			resolve:     PvzFromBody,
+			body:        `{"pvzId":"` + assignedPvz.String() + `","type":"` + strings.Repeat("a", maxPvzBodySize) + `"}`,
+			access:      access,
+			wantStatus:  http.StatusRequestEntityTooLarge,
+			wantBodySub: "request body too large",
+		},
+		{
+			name:        "reception of other pvz",
+			role:        "employee",
+			withUser:    true,
+			resolve:     PvzFromReception,
+			pathKey:     "receptionId",
+			pathValue:   receptionId.String(),
+			access:      access,
+			wantStatus:  http.StatusForbidden,
+			wantBodySub: models.ErrPvzAccessDenied.Error(),
+		},
+		{
+			name:        "unknown reception",
+			role:        "employee",
+			withUser:    true,
+			resolve:     PvzFromReception,
+			pathKey:     "receptionId",
+			pathValue:   uuid.New().String(),
+			access:      access,
+			wantStatus:  http.StatusNotFound,
+			wantBodySub: models.ErrReceptionNotFound.Error(),
+		},
+		{
+			name:        "lookup failure",
+			role:        "employee",
+			withUser:    true,
+			resolve:     PvzFromPath,
+			pathKey:     "pvzId",
+			pathValue:   assignedPvz.String(),
+			access:      &stubPvzAccess{err: errors.New("db error")},
+			wantStatus:  http.StatusInternalServerError,
+			wantBodySub: "db error",
+		},
+		{
+			name:       "unscoped service account",
+			role:       "employee",
+			scope:      []uuid.UUID{},
+			resolve:    PvzFromPath,
+			pathKey:    "pvzId",
+			pathValue:  otherPvz.String(),
+			access:     access,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "service account within scope",
+			role:       "moderator",
+			scope:      []uuid.UUID{otherPvz},
+			resolve:    PvzFromPath,
+			pathKey:    "pvzId",
+			pathValue:  otherPvz.String(),
+			access:     access,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:        "service account outside scope",
+			role:        "moderator",
+			scope:       []uuid.UUID{assignedPvz},
+			resolve:     PvzFromReception,
+			pathKey:     "receptionId",
+			pathValue:   receptionId.String(),
+			access:      access,
+			wantStatus:  http.StatusForbidden,
+			wantBodySub: models.ErrPvzAccessDenied.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var handlerBody string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				handlerBody = string(body)
+				dummyHandler(w, r)
+			})
+			mw := CheckPvzAccess(tt.access, tt.resolve)(next)
+
+			principal := models.Principal{UserID: models.DummyUserID, Role: dto.UserRole(tt.role)}
+			if tt.withUser {
+				principal.UserID = userId
+			}
+			if tt.scope != nil {
+				principal.UserID = uuid.New()
+				principal.ServiceAccount = true
+				principal.PvzIds = tt.scope
+			}
+			ctx := models.WithPrincipal(context.Background(), principal)
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body)).WithContext(ctx)
+			if tt.pathKey != "" {
+				req.SetPathValue(tt.pathKey, tt.pathValue)
+			}
+
+			w := httptest.NewRecorder()
+			mw.ServeHTTP(w, req)
+
+			resp := w.Result()
+			defer func(Body io.ReadCloser) {
+				err := Body.Close()
+				require.NoError(t, err)
+			}(resp.Body)
+
+			body, _ := io.ReadAll(resp.Body)
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+			require.Contains(t, string(body), tt.wantBodySub)
+			if tt.wantStatus == http.StatusOK {
+				require.Equal(t, tt.body, handlerBody)
+			}
+		})
+	}
+}
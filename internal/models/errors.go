@@ -7,11 +7,70 @@ var (
 	ErrIncorrectCity         = errors.New("incorrect city")
 	ErrEmptyEmailOrPassword  = errors.New("empty email or password")
 	ErrIncorrectUserRole     = errors.New("incorrect user role")
+	ErrDummyLoginDisabled    = errors.New("dummy login is disabled")
 	ErrEmailAlreadyInUse     = errors.New("user with this email already exists")
-	ErrWrongPassword         = errors.New("wrong password")
 	ErrUserNotFound          = errors.New("user not found")
 	ErrReceptionNotFound     = errors.New("reception not found")
 	ErrReceptionClosed       = errors.New("reception closed")
 	ErrNoProductsInReception = errors.New("reception is empty")
 	ErrReceptionNotClosed    = errors.New("previous reception not closed")
+	ErrInvalidCursor         = errors.New("invalid cursor")
+	ErrEmptyName             = errors.New("empty name")
+	ErrProductTypeNotFound   = errors.New("product type not found")
+	ErrProductTypeExists     = errors.New("product type already exists")
+	ErrCityNotFound          = errors.New("city not found")
+	ErrCityExists            = errors.New("city already exists")
+	ErrPvzNotFound           = errors.New("pvz not found")
+	ErrPvzDecommissioned     = errors.New("pvz decommissioned")
+	ErrIncorrectCoordinates  = errors.New("incorrect coordinates")
+	ErrIncorrectPhone        = errors.New("incorrect phone")
+	ErrInvalidTransition     = errors.New("invalid reception status transition")
+	ErrTransitionForbidden   = errors.New("reception status transition is not allowed for this role")
+	ErrPvzAccessDenied       = errors.New("pvz is not assigned to the employee")
+	ErrAlreadyAssigned       = errors.New("employee is already assigned to the pvz")
+	ErrAssignmentNotFound    = errors.New("assignment not found")
+	ErrRefreshTokenNotFound  = errors.New("refresh token not found")
+	ErrInvalidRefreshToken   = errors.New("invalid refresh token")
+	ErrInviteRequired        = errors.New("invite required for this role")
+	ErrInviteNotFound        = errors.New("invite not found")
+	ErrInvalidInvite         = errors.New("invalid or expired invite")
+	ErrInvalidInviteTTL      = errors.New("invite ttl must be between 1 and 720 hours")
+	ErrInvalidCredentials    = errors.New("invalid credentials")
+	ErrTooManyLoginAttempts  = errors.New("too many login attempts, try again later")
+	ErrWeakPassword          = errors.New("password does not meet the strength policy")
+	ErrResetTokenNotFound    = errors.New("password reset token not found")
+	ErrInvalidResetToken     = errors.New("invalid or expired password reset token")
+	ErrUserDeactivated       = errors.New("user is deactivated")
+	ErrSelfModification      = errors.New("moderators cannot change their own role or status")
+
+	ErrServiceAccountExists   = errors.New("service account with this name already exists")
+	ErrServiceAccountNotFound = errors.New("service account not found")
+	ErrAPIKeyNotFound         = errors.New("api key not found")
+	ErrInvalidAPIKey          = errors.New("invalid, expired or revoked api key")
+	ErrInvalidAPIKeyTTL       = errors.New("api key ttl must be between 1 and 365 days")
+
+	ErrAuthRequired   = errors.New("Authorization header required")
+	ErrMalformedToken = errors.New("error while parsing token")
+	ErrInvalidToken   = errors.New("Token invalid")
+	ErrTokenRevoked   = errors.New("Token revoked")
+
+	ErrPermissionDenied  = errors.New("permission denied")
+	ErrUnknownPermission = errors.New("unknown permission")
+	ErrRoleLockout       = errors.New("admin role must keep the role:manage permission")
+
+	ErrTOTPNotFound           = errors.New("two-factor authentication is not set up")
+	ErrTOTPAlreadyEnabled     = errors.New("two-factor authentication is already enabled")
+	ErrTOTPRequired           = errors.New("two-factor authentication is required for this role")
+	ErrInvalidTOTPCode        = errors.New("invalid two-factor code")
+	ErrRecoveryCodeNotFound   = errors.New("recovery code not found")
+	ErrLoginChallengeNotFound = errors.New("login challenge not found")
+	ErrInvalidLoginChallenge  = errors.New("invalid or expired login challenge")
+
+	ErrSSODisabled          = errors.New("single sign-on is not configured")
+	ErrInvalidSSOState      = errors.New("invalid or expired single sign-on state")
+	ErrSSOLoginFailed       = errors.New("single sign-on login failed")
+	ErrSSORoleNotMapped     = errors.New("identity provider claims do not map to a role")
+	ErrUserIdentityNotFound = errors.New("user identity not found")
+	ErrUserIdentityExists   = errors.New("identity is already linked to a user")
+	ErrOIDCStateNotFound    = errors.New("oidc login state not found")
 )
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+)
+
+// Invite is a single-use registration invitation. Only the hash of the code
+// handed to the invitee is stored.
+type Invite struct {
+	ID        uuid.UUID
+	CodeHash  string
+	Role      dto.UserRole
+	PvzIds    []uuid.UUID
+	CreatedBy *uuid.UUID
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
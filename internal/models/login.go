@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// LoginFailures tracks failed login attempts for one subject, an account or
+// a client IP.
+type LoginFailures struct {
+	Subject       string
+	Failures      int
+	LastFailureAt time.Time
+	BlockedUntil  *time.Time
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserIdentity links a user to an account at an external identity provider.
+type UserIdentity struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Issuer    string
+	Subject   string
+	CreatedAt time.Time
+}
+
+// OIDCLoginState is kept between redirecting a user to the identity provider
+// and the provider redirecting back. Only the hash of the state parameter is
+// stored.
+type OIDCLoginState struct {
+	ID           uuid.UUID
+	StateHash    string
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+)
+
+// Permission names an action guarded by role-based access control. Roles are
+// mapped to permissions in the database, so the mapping can change at runtime.
+type Permission string
+
+const (
+	PermInviteCreate         Permission = "invite:create"
+	PermCityManage           Permission = "city:manage"
+	PermPvzCreate            Permission = "pvz:create"
+	PermPvzRead              Permission = "pvz:read"
+	PermPvzUpdate            Permission = "pvz:update"
+	PermPvzDecommission      Permission = "pvz:decommission"
+	PermReceptionCreate      Permission = "reception:create"
+	PermReceptionPause       Permission = "reception:pause"
+	PermReceptionResume      Permission = "reception:resume"
+	PermReceptionClose       Permission = "reception:close"
+	PermReceptionCancel      Permission = "reception:cancel"
+	PermReceptionReopen      Permission = "reception:reopen"
+	PermProductCreate        Permission = "product:create"
+	PermProductDelete        Permission = "product:delete"
+	PermProductTypeRead      Permission = "product_type:read"
+	PermProductTypeManage    Permission = "product_type:manage"
+	PermUserRead             Permission = "user:read"
+	PermUserManage           Permission = "user:manage"
+	PermAssignmentManage     Permission = "assignment:manage"
+	PermServiceAccountManage Permission = "service_account:manage"
+	PermRoleManage           Permission = "role:manage"
+)
+
+// Permissions lists every permission known to the service.
+var Permissions = []Permission{
+	PermInviteCreate,
+	PermCityManage,
+	PermPvzCreate,
+	PermPvzRead,
+	PermPvzUpdate,
+	PermPvzDecommission,
+	PermReceptionCreate,
+	PermReceptionPause,
+	PermReceptionResume,
+	PermReceptionClose,
+	PermReceptionCancel,
+	PermReceptionReopen,
+	PermProductCreate,
+	PermProductDelete,
+	PermProductTypeRead,
+	PermProductTypeManage,
+	PermUserRead,
+	PermUserManage,
+	PermAssignmentManage,
+	PermServiceAccountManage,
+	PermRoleManage,
+}
+
+// Roles lists every user role in order of increasing privilege. Admins manage
+// the role permissions and cannot register themselves.
+var Roles = []dto.UserRole{dto.UserRoleEmployee, dto.UserRoleModerator, dto.UserRoleAdmin}
+
+// DefaultRolePermissions is what each role may do until an admin changes it.
+// The in-memory storage starts from it and migration 016 seeds the same rows.
+var DefaultRolePermissions = map[dto.UserRole][]Permission{
+	dto.UserRoleEmployee: {
+		PermPvzRead,
+		PermReceptionCreate,
+		PermReceptionPause,
+		PermReceptionResume,
+		PermReceptionClose,
+		PermReceptionCancel,
+		PermProductCreate,
+		PermProductDelete,
+		PermProductTypeRead,
+	},
+	dto.UserRoleModerator: {
+		PermInviteCreate,
+		PermCityManage,
+		PermPvzCreate,
+		PermPvzRead,
+		PermPvzUpdate,
+		PermPvzDecommission,
+		PermReceptionCancel,
+		PermReceptionReopen,
+		PermProductTypeRead,
+		PermProductTypeManage,
+		PermUserRead,
+		PermUserManage,
+		PermAssignmentManage,
+		PermServiceAccountManage,
+	},
+	dto.UserRoleAdmin: Permissions,
+}
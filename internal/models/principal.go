@@ -0,0 +1,79 @@
+package models
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+)
+
+// Identity of tokens issued by /dummyLogin. The sentinel user has no row in
+// the user table.
+var (
+	DummyUserID = uuid.Nil
+	DummyEmail  = "dummy@pvz.local"
+)
+
+// Principal is the authenticated caller of a request. TokenID and
+// TokenExpiresAt describe the access token it was authenticated with.
+// Service accounts authenticate with an API key instead: their UserID is the
+// service account id and PvzIds, if not empty, limits them to those PVZs.
+// Permissions are those of the role at the time of the request.
+type Principal struct {
+	UserID         uuid.UUID
+	Email          string
+	Role           dto.UserRole
+	TokenID        string
+	TokenExpiresAt time.Time
+	ServiceAccount bool
+	PvzIds         []uuid.UUID
+	Permissions    []Permission
+}
+
+// IsDummy reports whether the principal comes from a /dummyLogin token.
+func (p Principal) IsDummy() bool {
+	return p.UserID == DummyUserID
+}
+
+// Can reports whether the role of the principal grants the permission.
+func (p Principal) Can(permission Permission) bool {
+	return slices.Contains(p.Permissions, permission)
+}
+
+// CanManageUser reports whether the principal may change an account with the
+// role. Admin accounts are left to those who may manage roles.
+func (p Principal) CanManageUser(role dto.UserRole) bool {
+	return role != dto.UserRoleAdmin || p.Can(PermRoleManage)
+}
+
+// CanAccessPvz reports whether the PVZ scope of a service account allows the
+// PVZ. Users are not limited by it.
+func (p Principal) CanAccessPvz(pvzId uuid.UUID) bool {
+	return !p.ServiceAccount || len(p.PvzIds) == 0 || slices.Contains(p.PvzIds, pvzId)
+}
+
+type principalKey struct{}
+
+// WithPrincipal stores the authenticated caller in ctx.
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the authenticated caller, if there is one.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
+
+// ActorID returns the id of the caller to record as the author of a change,
+// or nil for unauthenticated calls.
+func ActorID(ctx context.Context) *uuid.UUID {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	return &principal.UserID
+}
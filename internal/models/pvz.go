@@ -1,9 +1,20 @@
 package models
 
 import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
 	"github.com/itisalisas/avito-backend/internal/generated/dto"
 )
 
+// DefaultCities are the cities PVZs can be registered in out of the box.
+// Migration 005 inserts the same names, which the storage tests check.
+var DefaultCities = []string{"Москва", "Санкт-Петербург", "Казань"}
+
 type ExtendedPvz struct {
 	PVZ        dto.PVZ             `json:"pvz"`
 	Receptions []ExtendedReception `json:"receptions"`
@@ -13,3 +24,115 @@ type ExtendedReception struct {
 	Reception dto.Reception `json:"reception"`
 	Products  []dto.Product `json:"products"`
 }
+
+// PvzFilter narrows the PVZ listing. Reception fields select receptions and
+// product fields select products inside them. Once any of them is set, only
+// PVZs with a matching reception are listed and only matching receptions and
+// products are returned. Date ranges may be open on either side.
+type PvzFilter struct {
+	// PvzIds, unless nil, limits the listing to those PVZs, so an empty
+	// slice lists none.
+	PvzIds           []uuid.UUID
+	Cities           []string
+	StartDate        *time.Time
+	EndDate          *time.Time
+	ReceptionStatus  *dto.ReceptionStatus
+	ProductType      *string
+	ProductStartDate *time.Time
+	ProductEndDate   *time.Time
+	HasOpenReception bool
+}
+
+// FiltersProducts reports whether products are filtered.
+func (f PvzFilter) FiltersProducts() bool {
+	return f.ProductType != nil || f.ProductStartDate != nil || f.ProductEndDate != nil
+}
+
+// FiltersReceptions reports whether receptions are filtered, either directly
+// or through their products.
+func (f PvzFilter) FiltersReceptions() bool {
+	return f.StartDate != nil || f.EndDate != nil || f.ReceptionStatus != nil || f.FiltersProducts()
+}
+
+// MatchReception reports whether the reception passes the reception fields.
+func (f PvzFilter) MatchReception(reception dto.Reception) bool {
+	return inRange(reception.DateTime, f.StartDate, f.EndDate) &&
+		(f.ReceptionStatus == nil || reception.Status == *f.ReceptionStatus)
+}
+
+// MatchProduct reports whether the product passes the product fields.
+func (f PvzFilter) MatchProduct(product dto.Product) bool {
+	return inRange(*product.DateTime, f.ProductStartDate, f.ProductEndDate) &&
+		(f.ProductType == nil || product.Type == *f.ProductType)
+}
+
+func inRange(t time.Time, from, to *time.Time) bool {
+	return (from == nil || !t.Before(*from)) && (to == nil || !t.After(*to))
+}
+
+// PvzListParams selects one page of the PVZ listing. PVZs are ordered by
+// registration date and then by id, both descending. When Cursor is set the
+// page starts right after it and Page is ignored.
+type PvzListParams struct {
+	PvzFilter
+	Page   uint64
+	Limit  uint64
+	Cursor *PvzCursor
+	// SkipReceptions leaves the receptions of the items out. The filters
+	// still apply.
+	SkipReceptions bool
+	// SkipCount leaves TotalCount zero, for callers that walk the whole
+	// listing and would otherwise count it on every page.
+	SkipCount bool
+}
+
+// Offset is the number of PVZs skipped before the page. Pages start from 1.
+func (p PvzListParams) Offset() uint64 {
+	if p.Page == 0 {
+		return 0
+	}
+	return (p.Page - 1) * p.Limit
+}
+
+type PvzPage struct {
+	Items      []*ExtendedPvz
+	TotalCount uint64
+	NextCursor *PvzCursor
+}
+
+// PvzCursor is the keyset position of the last PVZ on a page.
+type PvzCursor struct {
+	RegistrationDate time.Time `json:"d"`
+	ID               uuid.UUID `json:"id"`
+}
+
+func NewPvzCursor(pvz dto.PVZ) *PvzCursor {
+	return &PvzCursor{RegistrationDate: *pvz.RegistrationDate, ID: *pvz.Id}
+}
+
+// Encode returns the opaque form of the cursor that is handed to clients.
+func (c *PvzCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodePvzCursor(s string) (*PvzCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor PvzCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == uuid.Nil {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// After reports whether pvz comes after the cursor in listing order.
+func (c *PvzCursor) After(pvz dto.PVZ) bool {
+	if !pvz.RegistrationDate.Equal(c.RegistrationDate) {
+		return pvz.RegistrationDate.Before(c.RegistrationDate)
+	}
+	return bytes.Compare(pvz.Id[:], c.ID[:]) < 0
+}
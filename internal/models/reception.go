@@ -0,0 +1,19 @@
+package models
+
+import (
+	"slices"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+)
+
+// DefaultProductTypes are the product types every deployment starts with.
+// Migration 004 inserts the same names, which the storage tests check.
+var DefaultProductTypes = []string{"электроника", "одежда", "обувь"}
+
+// OpenReceptionStatuses are the statuses of an unfinished reception. A PVZ
+// has at most one reception in any of them.
+var OpenReceptionStatuses = []dto.ReceptionStatus{dto.InProgress, dto.Paused}
+
+func IsOpenReception(status dto.ReceptionStatus) bool {
+	return slices.Contains(OpenReceptionStatuses, status)
+}
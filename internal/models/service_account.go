@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+)
+
+// ServiceAccount is a non-human client that authenticates with API keys. Its
+// keys act with its role and, if PvzIds is not empty, only on those PVZs.
+type ServiceAccount struct {
+	ID        uuid.UUID
+	Name      string
+	Role      dto.UserRole
+	PvzIds    []uuid.UUID
+	CreatedBy *uuid.UUID
+	CreatedAt time.Time
+}
+
+// DTO returns the public view of the service account.
+func (a *ServiceAccount) DTO() *dto.ServiceAccount {
+	return &dto.ServiceAccount{
+		Id:        a.ID,
+		Name:      a.Name,
+		Role:      string(a.Role),
+		PvzIds:    a.PvzIds,
+		CreatedBy: a.CreatedBy,
+		CreatedAt: a.CreatedAt,
+	}
+}
+
+// APIKey is a key of a service account. Only the hash of the key handed to
+// the client is stored, along with a short prefix to recognise it by.
+type APIKey struct {
+	ID               uuid.UUID
+	ServiceAccountID uuid.UUID
+	Name             string
+	KeyHash          string
+	Prefix           string
+	CreatedBy        *uuid.UUID
+	CreatedAt        time.Time
+	ExpiresAt        *time.Time
+	LastUsedAt       *time.Time
+	RevokedAt        *time.Time
+}
+
+// DTO returns the public view of the key, without the key itself.
+func (k *APIKey) DTO() *dto.ApiKey {
+	return &dto.ApiKey{
+		Id:               k.ID,
+		ServiceAccountId: k.ServiceAccountID,
+		Name:             k.Name,
+		Prefix:           k.Prefix,
+		CreatedAt:        k.CreatedAt,
+		ExpiresAt:        k.ExpiresAt,
+		LastUsedAt:       k.LastUsedAt,
+		RevokedAt:        k.RevokedAt,
+	}
+}
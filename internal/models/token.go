@@ -1,12 +1,37 @@
 package models
 
 import (
+	"time"
+
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 
 	"github.com/itisalisas/avito-backend/internal/generated/dto"
 )
 
+// TokenClaims are the claims of access tokens. The subject is the user id.
 type TokenClaims struct {
-	Role dto.UserRole `json:"role"`
+	Role  dto.UserRole `json:"role"`
+	Email string       `json:"email"`
 	jwt.RegisteredClaims
 }
+
+// RefreshToken is a server-side refresh token. Only the hash of the token
+// handed to the client is stored.
+type RefreshToken struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	TokenHash string
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+}
+
+// PasswordResetToken is a single-use token for setting a new password. Only
+// the hash of the token sent to the user is stored.
+type PasswordResetToken struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TOTP is the authenticator app secret of a user. It protects logins only
+// once confirmed with a valid code.
+type TOTP struct {
+	UserID      uuid.UUID
+	Secret      string
+	ConfirmedAt *time.Time
+	// LastUsedStep is the period of the last accepted code, so that a code
+	// cannot be used twice.
+	LastUsedStep int64
+	CreatedAt    time.Time
+}
+
+// Confirmed reports whether logins require a code.
+func (t *TOTP) Confirmed() bool {
+	return t != nil && t.ConfirmedAt != nil
+}
+
+// LoginChallenge is issued by a login whose password was correct but which
+// still needs a second factor. Only the hash of the token handed to the
+// client is stored.
+type LoginChallenge struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	TokenHash string
+	Attempts  int
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
@@ -1,14 +1,59 @@
 package models
 
 import (
+	"time"
+
 	openapi_types "github.com/oapi-codegen/runtime/types"
 
 	"github.com/itisalisas/avito-backend/internal/generated/dto"
 )
 
 type User struct {
-	ID       openapi_types.UUID  `json:"id"`
-	Email    openapi_types.Email `json:"email"`
-	Role     dto.UserRole        `json:"role"`
-	Password string
+	ID          openapi_types.UUID  `json:"id"`
+	Email       openapi_types.Email `json:"email"`
+	Role        dto.UserRole        `json:"role"`
+	Password    string
+	Active      bool
+	CreatedAt   time.Time
+	LastLoginAt *time.Time
+}
+
+// DTO returns the public view of the user.
+func (u *User) DTO() *dto.User {
+	return &dto.User{
+		Id:          &u.ID,
+		Email:       u.Email,
+		Role:        u.Role,
+		Active:      &u.Active,
+		CreatedAt:   &u.CreatedAt,
+		LastLoginAt: u.LastLoginAt,
+	}
+}
+
+// UserFilter narrows the user listing. Email matches a case-insensitive
+// substring.
+type UserFilter struct {
+	Email  string
+	Role   *dto.UserRole
+	Active *bool
+}
+
+// UserListParams selects one page of users ordered from the newest.
+type UserListParams struct {
+	UserFilter
+	Page  uint64
+	Limit uint64
+}
+
+// Offset is the number of users skipped before the page. Pages start from 1.
+func (p UserListParams) Offset() uint64 {
+	if p.Page == 0 {
+		return 0
+	}
+	return (p.Page - 1) * p.Limit
+}
+
+type UserPage struct {
+	Items      []*User
+	TotalCount uint64
 }
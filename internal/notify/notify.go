@@ -0,0 +1,30 @@
+// Package notify delivers messages to users.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// LogNotifier writes notifications to a writer instead of delivering them,
+// for local runs and tests. Point it at a file to pick reset tokens up from
+// there.
+type LogNotifier struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewLogNotifier(w io.Writer) *LogNotifier {
+	return &LogNotifier{w: w}
+}
+
+func (n *LogNotifier) SendPasswordReset(_ context.Context, email string, token string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	_, err := fmt.Fprintf(n.w, "%s password reset for %s: token=%s\n", time.Now().UTC().Format(time.RFC3339), email, token)
+	return err
+}
@@ -0,0 +1,23 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogNotifier_SendPasswordReset(t *testing.T) {
+	var buf bytes.Buffer
+	n := NewLogNotifier(&buf)
+
+	require.NoError(t, n.SendPasswordReset(context.Background(), "user@example.com", "reset-token"))
+	require.NoError(t, n.SendPasswordReset(context.Background(), "other@example.com", "other-token"))
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	assert.Contains(t, string(lines[0]), "password reset for user@example.com: token=reset-token")
+	assert.Contains(t, string(lines[1]), "password reset for other@example.com: token=other-token")
+}
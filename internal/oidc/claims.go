@@ -0,0 +1,123 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// leeway allows for clock skew between the service and the provider.
+const leeway = time.Minute
+
+// signingMethods are the algorithms ID tokens are accepted with. HS256 is
+// left out, since it would make the client secret a signing key.
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// Claims are the verified claims of an ID token.
+type Claims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	// Raw holds every claim, for mapping custom claims such as groups.
+	Raw map[string]any
+}
+
+// Verify checks the signature, issuer, audience, expiry and nonce of an ID
+// token.
+func (p *Provider) Verify(ctx context.Context, rawIDToken, nonce string) (*Claims, error) {
+	parser := jwt.NewParser(
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithIssuer(p.metadata.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(leeway),
+	)
+
+	raw := jwt.MapClaims{}
+	_, err := parser.ParseWithClaims(rawIDToken, raw, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, err := p.keys.key(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+		if !keyMatches(token.Method, key) {
+			return nil, errors.New("algorithm does not match the key")
+		}
+		return key, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIDToken, err)
+	}
+
+	// With several audiences the token must name this client as the party
+	// it was issued to.
+	if audience, _ := raw.GetAudience(); len(audience) > 1 {
+		if azp, _ := raw["azp"].(string); azp != p.config.ClientID {
+			return nil, fmt.Errorf("%w: issued to another party", ErrInvalidIDToken)
+		}
+	}
+
+	tokenNonce, _ := raw["nonce"].(string)
+	if subtle.ConstantTimeCompare([]byte(tokenNonce), []byte(nonce)) != 1 {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+
+	subject, _ := raw.GetSubject()
+	if subject == "" {
+		return nil, fmt.Errorf("%w: no subject", ErrInvalidIDToken)
+	}
+
+	claims := &Claims{Subject: subject, Raw: raw}
+	claims.Email, _ = raw["email"].(string)
+	// Some providers send email_verified as a string.
+	switch verified := raw["email_verified"].(type) {
+	case bool:
+		claims.EmailVerified = verified
+	case string:
+		claims.EmailVerified = verified == "true"
+	}
+	return claims, nil
+}
+
+// Strings returns a claim that holds a string or a list of strings.
+func (c *Claims) Strings(name string) []string {
+	switch value := c.Raw[name].(type) {
+	case string:
+		return []string{value}
+	case []any:
+		values := make([]string, 0, len(value))
+		for _, v := range value {
+			if s, ok := v.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+func keyMatches(method jwt.SigningMethod, key any) bool {
+	switch key.(type) {
+	case *rsa.PublicKey:
+		_, rsaOK := method.(*jwt.SigningMethodRSA)
+		_, pssOK := method.(*jwt.SigningMethodRSAPSS)
+		return rsaOK || pssOK
+	case *ecdsa.PublicKey:
+		_, ok := method.(*jwt.SigningMethodECDSA)
+		return ok
+	case ed25519.PublicKey:
+		_, ok := method.(*jwt.SigningMethodEd25519)
+		return ok
+	default:
+		return false
+	}
+}
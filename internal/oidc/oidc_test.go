@@ -0,0 +1,185 @@
+package oidc_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/itisalisas/avito-backend/internal/oidc"
+	"github.com/itisalisas/avito-backend/internal/oidc/oidctest"
+)
+
+const redirectURL = "http://pvz.local/oidc/callback"
+
+func newProvider(t *testing.T) (*oidc.Provider, *oidctest.Server) {
+	idp, err := oidctest.NewServer("pvz", "secret")
+	require.NoError(t, err)
+	t.Cleanup(idp.Close)
+
+	provider, err := oidc.Discover(context.Background(), oidc.Config{
+		IssuerURL:    idp.Issuer(),
+		ClientID:     "pvz",
+		ClientSecret: "secret",
+		RedirectURL:  redirectURL,
+	})
+	require.NoError(t, err)
+	return provider, idp
+}
+
+func validClaims(idp *oidctest.Server) map[string]any {
+	now := time.Now()
+	return map[string]any{
+		"iss":   idp.Issuer(),
+		"aud":   "pvz",
+		"sub":   "staff-1",
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Minute).Unix(),
+		"nonce": "nonce",
+	}
+}
+
+func TestProvider_Flow(t *testing.T) {
+	provider, idp := newProvider(t)
+	idp.SetUser(map[string]any{
+		"sub":            "staff-1",
+		"email":          "staff@example.com",
+		"email_verified": "true",
+		"groups":         []string{"pvz-staff", "pvz-moderators"},
+	})
+
+	authURL := provider.AuthCodeURL("state", "nonce", "verifier")
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, "S256", parsed.Query().Get("code_challenge_method"))
+	assert.Equal(t, "openid email profile", parsed.Query().Get("scope"))
+
+	code, state, err := idp.SignIn(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, "state", state)
+
+	claims, err := provider.Exchange(context.Background(), code, "verifier", "nonce")
+	require.NoError(t, err)
+	assert.Equal(t, "staff-1", claims.Subject)
+	assert.Equal(t, "staff@example.com", claims.Email)
+	assert.True(t, claims.EmailVerified)
+	assert.Equal(t, []string{"pvz-staff", "pvz-moderators"}, claims.Strings("groups"))
+
+	// Codes work only once.
+	_, err = provider.Exchange(context.Background(), code, "verifier", "nonce")
+	assert.ErrorIs(t, err, oidc.ErrExchange)
+}
+
+func TestProvider_ExchangeWrongVerifier(t *testing.T) {
+	provider, idp := newProvider(t)
+	idp.SetUser(map[string]any{"sub": "staff-1"})
+
+	code, _, err := idp.SignIn(provider.AuthCodeURL("state", "nonce", "verifier"))
+	require.NoError(t, err)
+
+	_, err = provider.Exchange(context.Background(), code, "other", "nonce")
+	assert.ErrorIs(t, err, oidc.ErrExchange)
+}
+
+func TestProvider_Verify(t *testing.T) {
+	provider, idp := newProvider(t)
+
+	tests := []struct {
+		name   string
+		modify func(claims map[string]any)
+		valid  bool
+	}{
+		{
+			name:   "valid",
+			modify: func(map[string]any) {},
+			valid:  true,
+		},
+		{
+			name:   "wrong nonce",
+			modify: func(claims map[string]any) { claims["nonce"] = "other" },
+		},
+		{
+			name:   "no nonce",
+			modify: func(claims map[string]any) { delete(claims, "nonce") },
+		},
+		{
+			name:   "wrong audience",
+			modify: func(claims map[string]any) { claims["aud"] = "other" },
+		},
+		{
+			name:   "wrong issuer",
+			modify: func(claims map[string]any) { claims["iss"] = "http://evil.example.com" },
+		},
+		{
+			name:   "expired",
+			modify: func(claims map[string]any) { claims["exp"] = time.Now().Add(-time.Hour).Unix() },
+		},
+		{
+			name:   "no expiry",
+			modify: func(claims map[string]any) { delete(claims, "exp") },
+		},
+		{
+			name:   "no subject",
+			modify: func(claims map[string]any) { delete(claims, "sub") },
+		},
+		{
+			name: "several audiences without azp",
+			modify: func(claims map[string]any) {
+				claims["aud"] = []string{"pvz", "other"}
+			},
+		},
+		{
+			name: "several audiences with azp",
+			modify: func(claims map[string]any) {
+				claims["aud"] = []string{"pvz", "other"}
+				claims["azp"] = "pvz"
+			},
+			valid: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validClaims(idp)
+			tt.modify(claims)
+			token, err := idp.IDToken(claims)
+			require.NoError(t, err)
+
+			_, err = provider.Verify(context.Background(), token, "nonce")
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+			}
+		})
+	}
+}
+
+func TestProvider_VerifyForgedToken(t *testing.T) {
+	provider, idp := newProvider(t)
+	forger, err := oidctest.NewServer("pvz", "secret")
+	require.NoError(t, err)
+	defer forger.Close()
+
+	// Signed by another key under the same key id.
+	token, err := forger.IDToken(validClaims(idp))
+	require.NoError(t, err)
+
+	_, err = provider.Verify(context.Background(), token, "nonce")
+	assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+}
+
+func TestDiscover_UnknownIssuer(t *testing.T) {
+	idp, err := oidctest.NewServer("pvz", "secret")
+	require.NoError(t, err)
+	defer idp.Close()
+
+	_, err = oidc.Discover(context.Background(), oidc.Config{
+		IssuerURL: idp.Issuer() + "/tenant",
+		ClientID:  "pvz",
+	})
+	assert.ErrorIs(t, err, oidc.ErrDiscovery)
+}
@@ -0,0 +1,55 @@
+// Package passwordhash hashes passwords into self-describing strings, so
+// that hashes made with different algorithms or parameters can be told apart
+// and verified side by side.
+package passwordhash
+
+import "errors"
+
+var ErrUnknownScheme = errors.New("unknown password hash scheme")
+
+// Scheme is one hashing algorithm with fixed parameters.
+type Scheme interface {
+	Hash(password string) (string, error)
+	// Verify reports whether password matches a hash of this scheme.
+	Verify(hash, password string) bool
+	// Owns reports whether the hash was made by this algorithm, whatever
+	// parameters it was made with.
+	Owns(hash string) bool
+	// Outdated reports whether a hash of this algorithm was made with other
+	// parameters than the scheme's.
+	Outdated(hash string) bool
+}
+
+// Hasher hashes new passwords with its current scheme and verifies hashes of
+// any scheme it knows.
+type Hasher struct {
+	current Scheme
+	schemes []Scheme
+}
+
+// New builds a hasher that hashes with current and also verifies hashes of
+// the legacy schemes.
+func New(current Scheme, legacy ...Scheme) *Hasher {
+	return &Hasher{current: current, schemes: append([]Scheme{current}, legacy...)}
+}
+
+func (h *Hasher) Hash(password string) (string, error) {
+	return h.current.Hash(password)
+}
+
+// Verify reports whether password matches hash. Hashes of unknown schemes
+// never match.
+func (h *Hasher) Verify(hash, password string) bool {
+	for _, scheme := range h.schemes {
+		if scheme.Owns(hash) {
+			return scheme.Verify(hash, password)
+		}
+	}
+	return false
+}
+
+// NeedsRehash reports whether hash should be replaced by a hash of the
+// current scheme once the password is known.
+func (h *Hasher) NeedsRehash(hash string) bool {
+	return !h.current.Owns(hash) || h.current.Outdated(hash)
+}
@@ -0,0 +1,122 @@
+package passwordhash
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testArgon2id keeps the tests fast; production parameters are far larger.
+var testArgon2id = Argon2id{Memory: 64, Iterations: 1, Parallelism: 1}
+
+func TestArgon2id(t *testing.T) {
+	hash, err := testArgon2id.Hash("secret")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$"))
+	assert.True(t, testArgon2id.Owns(hash))
+	assert.False(t, testArgon2id.Outdated(hash))
+
+	assert.True(t, testArgon2id.Verify(hash, "secret"))
+	assert.False(t, testArgon2id.Verify(hash, "Secret"))
+
+	other, err := testArgon2id.Hash("secret")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, other, "salt must be random")
+
+	stronger := Argon2id{Memory: 128, Iterations: 2, Parallelism: 1}
+	assert.True(t, stronger.Outdated(hash))
+	assert.True(t, stronger.Verify(hash, "secret"), "parameters are read from the hash")
+
+	for _, malformed := range []string{
+		"$argon2id$v=19$m=64,t=1,p=1$c2FsdA",
+		"$argon2id$v=16$m=64,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=64,t=0,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=64,t=1,p=1$!!!$a2V5",
+	} {
+		assert.False(t, testArgon2id.Verify(malformed, "secret"), malformed)
+		assert.True(t, testArgon2id.Outdated(malformed), malformed)
+	}
+}
+
+func TestBcrypt(t *testing.T) {
+	scheme := Bcrypt{Cost: bcrypt.MinCost}
+	hash, err := scheme.Hash("secret")
+	require.NoError(t, err)
+	assert.True(t, scheme.Owns(hash))
+	assert.False(t, scheme.Outdated(hash))
+	assert.True(t, scheme.Verify(hash, "secret"))
+	assert.False(t, scheme.Verify(hash, "Secret"))
+	assert.True(t, Bcrypt{Cost: bcrypt.MinCost + 1}.Outdated(hash))
+}
+
+func TestHasher(t *testing.T) {
+	bcryptScheme := Bcrypt{Cost: bcrypt.MinCost}
+	hasher := New(testArgon2id, bcryptScheme)
+
+	hash, err := hasher.Hash("secret")
+	require.NoError(t, err)
+	assert.True(t, testArgon2id.Owns(hash))
+	assert.True(t, hasher.Verify(hash, "secret"))
+	assert.False(t, hasher.NeedsRehash(hash))
+
+	legacy, err := bcryptScheme.Hash("secret")
+	require.NoError(t, err)
+	assert.True(t, hasher.Verify(legacy, "secret"))
+	assert.False(t, hasher.Verify(legacy, "wrong"))
+	assert.True(t, hasher.NeedsRehash(legacy))
+
+	assert.False(t, hasher.Verify("plain-text", "plain-text"))
+	assert.True(t, hasher.NeedsRehash("plain-text"))
+
+	t.Run("outdated parameters", func(t *testing.T) {
+		stronger := New(Argon2id{Memory: 128, Iterations: 1, Parallelism: 1})
+		assert.True(t, stronger.Verify(hash, "secret"))
+		assert.True(t, stronger.NeedsRehash(hash))
+	})
+
+	t.Run("unknown legacy scheme", func(t *testing.T) {
+		argonOnly := New(testArgon2id)
+		assert.False(t, argonOnly.Verify(legacy, "secret"))
+	})
+}
+
+func TestLoadFromEnv(t *testing.T) {
+	t.Run("argon2id by default", func(t *testing.T) {
+		hasher, err := LoadFromEnv()
+		require.NoError(t, err)
+		assert.Equal(t, DefaultArgon2id, hasher.current)
+	})
+
+	t.Run("bcrypt", func(t *testing.T) {
+		t.Setenv("PASSWORD_HASH", "bcrypt")
+		t.Setenv("BCRYPT_COST", "12")
+		hasher, err := LoadFromEnv()
+		require.NoError(t, err)
+		assert.Equal(t, Bcrypt{Cost: 12}, hasher.current)
+	})
+
+	t.Run("argon2 parameters", func(t *testing.T) {
+		t.Setenv("ARGON2_MEMORY_KIB", "65536")
+		t.Setenv("ARGON2_ITERATIONS", "3")
+		t.Setenv("ARGON2_PARALLELISM", "2")
+		hasher, err := LoadFromEnv()
+		require.NoError(t, err)
+		assert.Equal(t, Argon2id{Memory: 65536, Iterations: 3, Parallelism: 2}, hasher.current)
+	})
+
+	for name, env := range map[string][2]string{
+		"unknown scheme":       {"PASSWORD_HASH", "md5"},
+		"bcrypt cost too high": {"BCRYPT_COST", "40"},
+		"zero iterations":      {"ARGON2_ITERATIONS", "0"},
+		"malformed memory":     {"ARGON2_MEMORY_KIB", "lots"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(env[0], env[1])
+			_, err := LoadFromEnv()
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package passwordhash
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// LoadFromEnv builds a hasher that hashes with the scheme named by
+// PASSWORD_HASH (argon2id by default, or bcrypt) and verifies hashes of both.
+// ARGON2_MEMORY_KIB, ARGON2_ITERATIONS, ARGON2_PARALLELISM and BCRYPT_COST
+// override the parameters; hashes made with other parameters are replaced on
+// the next login.
+func LoadFromEnv() (*Hasher, error) {
+	argon := DefaultArgon2id
+	if err := parseEnv("ARGON2_MEMORY_KIB", 32, &argon.Memory); err != nil {
+		return nil, err
+	}
+	if err := parseEnv("ARGON2_ITERATIONS", 32, &argon.Iterations); err != nil {
+		return nil, err
+	}
+	if err := parseEnv("ARGON2_PARALLELISM", 8, &argon.Parallelism); err != nil {
+		return nil, err
+	}
+
+	cost := uint32(bcrypt.DefaultCost)
+	if err := parseEnv("BCRYPT_COST", 32, &cost); err != nil {
+		return nil, err
+	}
+	bcryptScheme := Bcrypt{Cost: int(cost)}
+	if bcryptScheme.Cost < bcrypt.MinCost || bcryptScheme.Cost > bcrypt.MaxCost {
+		return nil, fmt.Errorf("BCRYPT_COST must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+	if argon.Iterations == 0 || argon.Parallelism == 0 || argon.Memory < 8*uint32(argon.Parallelism) {
+		return nil, fmt.Errorf("invalid argon2 parameters %+v", argon)
+	}
+
+	switch scheme := os.Getenv("PASSWORD_HASH"); scheme {
+	case "", "argon2id":
+		return New(argon, bcryptScheme), nil
+	case "bcrypt":
+		return New(bcryptScheme, argon), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownScheme, scheme)
+	}
+}
+
+func parseEnv[T uint8 | uint32](name string, bits int, value *T) error {
+	s := os.Getenv(name)
+	if s == "" {
+		return nil
+	}
+
+	v, err := strconv.ParseUint(s, 10, bits)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	*value = T(v)
+	return nil
+}
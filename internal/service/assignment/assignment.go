@@ -0,0 +1,99 @@
+package assignment
+
+import (
+	"context"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+	"github.com/itisalisas/avito-backend/internal/storage"
+)
+
+type Service struct {
+	txManager      storage.TransactionManager
+	assignmentRepo storage.AssignmentRepositoryInterface
+	userRepo       storage.UserRepositoryInterface
+	pvzRepo        storage.PvzRepositoryInterface
+	receptionRepo  storage.ReceptionRepositoryInterface
+}
+
+func NewAssignmentService(txManager storage.TransactionManager, assignmentRepo storage.AssignmentRepositoryInterface,
+	userRepo storage.UserRepositoryInterface, pvzRepo storage.PvzRepositoryInterface,
+	receptionRepo storage.ReceptionRepositoryInterface) *Service {
+	return &Service{
+		txManager:      txManager,
+		assignmentRepo: assignmentRepo,
+		userRepo:       userRepo,
+		pvzRepo:        pvzRepo,
+		receptionRepo:  receptionRepo,
+	}
+}
+
+func (s *Service) AssignPvz(ctx context.Context, userId, pvzId openapi_types.UUID) (*dto.PvzAssignment, error) {
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		user, err := s.userRepo.GetUserById(ctx, userId)
+		if err != nil {
+			return err
+		}
+		if user.Role != dto.UserRoleEmployee {
+			return models.ErrIncorrectUserRole
+		}
+
+		pvz, err := s.pvzRepo.GetPvzById(ctx, pvzId)
+		if err != nil {
+			return err
+		}
+		if pvz.DecommissionedAt != nil {
+			return models.ErrPvzDecommissioned
+		}
+
+		return s.assignmentRepo.AssignPvz(ctx, userId, pvzId)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.PvzAssignment{UserId: userId, PvzId: pvzId}, nil
+}
+
+func (s *Service) UnassignPvz(ctx context.Context, userId, pvzId openapi_types.UUID) error {
+	return s.txManager.Do(ctx, func(ctx context.Context) error {
+		return s.assignmentRepo.UnassignPvz(ctx, userId, pvzId)
+	})
+}
+
+func (s *Service) GetAssignedPvzIds(ctx context.Context, userId openapi_types.UUID) ([]openapi_types.UUID, error) {
+	var pvzIds []openapi_types.UUID
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		pvzIds, err = s.assignmentRepo.GetAssignedPvzIds(ctx, userId)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pvzIds, nil
+}
+
+// CheckAccess returns ErrPvzAccessDenied unless the employee is assigned to
+// the PVZ.
+func (s *Service) CheckAccess(ctx context.Context, userId, pvzId openapi_types.UUID) error {
+	assigned, err := s.assignmentRepo.IsAssigned(ctx, userId, pvzId)
+	if err != nil {
+		return err
+	}
+	if !assigned {
+		return models.ErrPvzAccessDenied
+	}
+	return nil
+}
+
+// ReceptionPvzId returns the PVZ the reception belongs to.
+func (s *Service) ReceptionPvzId(ctx context.Context, receptionId openapi_types.UUID) (openapi_types.UUID, error) {
+	reception, err := s.receptionRepo.GetReceptionById(ctx, receptionId)
+	if err != nil {
+		return openapi_types.UUID{}, err
+	}
+	return reception.PvzId, nil
+}
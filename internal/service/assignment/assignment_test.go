@@ -0,0 +1,146 @@
+package assignment
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/generated/mocks"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+func TestAssignmentService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mocks.NewMockTransactionManager(ctrl)
+	mockAssignmentRepo := mocks.NewMockAssignmentRepositoryInterface(ctrl)
+	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	mockPvzRepo := mocks.NewMockPvzRepositoryInterface(ctrl)
+	mockReceptionRepo := mocks.NewMockReceptionRepositoryInterface(ctrl)
+	service := NewAssignmentService(mockTxManager, mockAssignmentRepo, mockUserRepo, mockPvzRepo, mockReceptionRepo)
+	userId := uuid.New()
+	pvzId := uuid.New()
+	decommissionedAt := time.Now()
+
+	tests := []struct {
+		name               string
+		method             string
+		mockActions        func()
+		expectedErr        error
+		expectedAssignment *dto.PvzAssignment
+	}{
+		{
+			name:   "assign employee",
+			method: "AssignPvz",
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockUserRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(&models.User{ID: userId, Role: dto.UserRoleEmployee}, nil).Times(1)
+				mockPvzRepo.EXPECT().GetPvzById(gomock.Any(), pvzId).Return(&dto.PVZ{Id: &pvzId}, nil).Times(1)
+				mockAssignmentRepo.EXPECT().AssignPvz(gomock.Any(), userId, pvzId).Return(nil).Times(1)
+			},
+			expectedAssignment: &dto.PvzAssignment{UserId: userId, PvzId: pvzId},
+		},
+		{
+			name:   "assign moderator",
+			method: "AssignPvz",
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockUserRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(&models.User{ID: userId, Role: dto.UserRoleModerator}, nil).Times(1)
+			},
+			expectedErr: models.ErrIncorrectUserRole,
+		},
+		{
+			name:   "assign to decommissioned pvz",
+			method: "AssignPvz",
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockUserRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(&models.User{ID: userId, Role: dto.UserRoleEmployee}, nil).Times(1)
+				mockPvzRepo.EXPECT().GetPvzById(gomock.Any(), pvzId).Return(&dto.PVZ{Id: &pvzId, DecommissionedAt: &decommissionedAt}, nil).Times(1)
+			},
+			expectedErr: models.ErrPvzDecommissioned,
+		},
+		{
+			name:   "assign twice",
+			method: "AssignPvz",
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockUserRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(&models.User{ID: userId, Role: dto.UserRoleEmployee}, nil).Times(1)
+				mockPvzRepo.EXPECT().GetPvzById(gomock.Any(), pvzId).Return(&dto.PVZ{Id: &pvzId}, nil).Times(1)
+				mockAssignmentRepo.EXPECT().AssignPvz(gomock.Any(), userId, pvzId).Return(models.ErrAlreadyAssigned).Times(1)
+			},
+			expectedErr: models.ErrAlreadyAssigned,
+		},
+		{
+			name:   "unassign missing",
+			method: "UnassignPvz",
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockAssignmentRepo.EXPECT().UnassignPvz(gomock.Any(), userId, pvzId).Return(models.ErrAssignmentNotFound).Times(1)
+			},
+			expectedErr: models.ErrAssignmentNotFound,
+		},
+		{
+			name:   "check access assigned",
+			method: "CheckAccess",
+			mockActions: func() {
+				mockAssignmentRepo.EXPECT().IsAssigned(gomock.Any(), userId, pvzId).Return(true, nil).Times(1)
+			},
+		},
+		{
+			name:   "check access not assigned",
+			method: "CheckAccess",
+			mockActions: func() {
+				mockAssignmentRepo.EXPECT().IsAssigned(gomock.Any(), userId, pvzId).Return(false, nil).Times(1)
+			},
+			expectedErr: models.ErrPvzAccessDenied,
+		},
+		{
+			name:   "check access lookup failure",
+			method: "CheckAccess",
+			mockActions: func() {
+				mockAssignmentRepo.EXPECT().IsAssigned(gomock.Any(), userId, pvzId).Return(false, errors.New("db error")).Times(1)
+			},
+			expectedErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockActions()
+
+			var err error
+			var assignment *dto.PvzAssignment
+
+			switch tt.method {
+			case "AssignPvz":
+				assignment, err = service.AssignPvz(context.Background(), userId, pvzId)
+			case "UnassignPvz":
+				err = service.UnassignPvz(context.Background(), userId, pvzId)
+			case "CheckAccess":
+				err = service.CheckAccess(context.Background(), userId, pvzId)
+			}
+
+			if tt.expectedErr != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedErr.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if tt.expectedAssignment != nil {
+				assert.Equal(t, tt.expectedAssignment, assignment)
+			}
+		})
+	}
+}
+
+func runInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
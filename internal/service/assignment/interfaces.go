@@ -0,0 +1,15 @@
+package assignment
+
+import (
+	"context"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+)
+
+type ServiceInterface interface {
+	AssignPvz(ctx context.Context, userId, pvzId openapi_types.UUID) (*dto.PvzAssignment, error)
+	UnassignPvz(ctx context.Context, userId, pvzId openapi_types.UUID) error
+	GetAssignedPvzIds(ctx context.Context, userId openapi_types.UUID) ([]openapi_types.UUID, error)
+}
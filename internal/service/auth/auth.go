@@ -2,41 +2,84 @@ package auth
 
 import (
 	"context"
-	"log"
-	"os"
+	"errors"
+	"sync"
 	"time"
 
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
+	openapi_types "github.com/oapi-codegen/runtime/types"
 
 	"github.com/itisalisas/avito-backend/internal/generated/dto"
 	"github.com/itisalisas/avito-backend/internal/models"
 	"github.com/itisalisas/avito-backend/internal/storage"
 )
 
-var jwtSecretKey = os.Getenv("JWT_SECRET_KEY")
+// Config holds the tunable parts of the auth service.
+type Config struct {
+	// RequireEmployeeInvite makes employee registration invite-only too.
+	// Moderators always need an invite.
+	RequireEmployeeInvite bool
+	// PasswordPolicy is enforced on registration and on every password
+	// change.
+	PasswordPolicy PasswordPolicy
+	// RequireModeratorTwoFactor makes moderators log in with a TOTP code,
+	// enrolling on their next login if they have not yet. Admins hold every
+	// moderator permission, so it applies to them as well.
+	RequireModeratorTwoFactor bool
+	// TOTPIssuer names the service in authenticator apps.
+	TOTPIssuer string
+	// SSO lets users sign in with an OpenID Connect provider.
+	SSO SSOConfig
+	// DummyLogin enables DummyLogin. Its tokens need no credentials and carry
+	// every permission of their role, so it is meant for development only.
+	DummyLogin bool
+}
 
 type Service struct {
-	userRepo storage.UserRepositoryInterface
+	txManager        storage.TransactionManager
+	userRepo         storage.UserRepositoryInterface
+	tokenRepo        storage.TokenRepositoryInterface
+	inviteRepo       storage.InviteRepositoryInterface
+	pvzRepo          storage.PvzRepositoryInterface
+	assignmentRepo   storage.AssignmentRepositoryInterface
+	loginAttemptRepo storage.LoginAttemptRepositoryInterface
+	twoFactorRepo    storage.TwoFactorRepositoryInterface
+	identityRepo     storage.IdentityRepositoryInterface
+	signer           TokenSigner
+	notifier         Notifier
+	hasher           PasswordHasher
+	config           Config
+	// dummyPasswordHash is verified against when the email is unknown.
+	dummyPasswordHash func() string
 }
 
-func NewAuthService(userRepo storage.UserRepositoryInterface) *Service {
-	return &Service{userRepo: userRepo}
+func NewAuthService(txManager storage.TransactionManager, userRepo storage.UserRepositoryInterface,
+	tokenRepo storage.TokenRepositoryInterface, inviteRepo storage.InviteRepositoryInterface,
+	pvzRepo storage.PvzRepositoryInterface, assignmentRepo storage.AssignmentRepositoryInterface,
+	loginAttemptRepo storage.LoginAttemptRepositoryInterface, twoFactorRepo storage.TwoFactorRepositoryInterface,
+	identityRepo storage.IdentityRepositoryInterface, signer TokenSigner, notifier Notifier, hasher PasswordHasher, config Config) *Service {
+	return &Service{
+		txManager:        txManager,
+		userRepo:         userRepo,
+		tokenRepo:        tokenRepo,
+		inviteRepo:       inviteRepo,
+		pvzRepo:          pvzRepo,
+		assignmentRepo:   assignmentRepo,
+		loginAttemptRepo: loginAttemptRepo,
+		twoFactorRepo:    twoFactorRepo,
+		identityRepo:     identityRepo,
+		signer:           signer,
+		notifier:         notifier,
+		hasher:           hasher,
+		config:           config,
+		dummyPasswordHash: sync.OnceValue(func() string {
+			hash, _ := hasher.Hash(uuid.NewString())
+			return hash
+		}),
+	}
 }
 
 func (s *Service) Register(ctx context.Context, request dto.PostRegisterJSONRequestBody) (*dto.User, error) {
-	_, err := s.userRepo.BeginTx(ctx, nil)
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		err := s.userRepo.Rollback()
-		if err != nil {
-			log.Fatalf("Error while rolling back transaction: %s", err)
-		}
-	}()
-
 	if request.Email == "" || request.Password == "" {
 		return nil, models.ErrEmptyEmailOrPassword
 	}
@@ -45,7 +88,19 @@ func (s *Service) Register(ctx context.Context, request dto.PostRegisterJSONRequ
 		return nil, models.ErrIncorrectUserRole
 	}
 
-	hashedPassword, err := hashPassword(request.Password)
+	if err := s.config.PasswordPolicy.Validate(request.Password); err != nil {
+		return nil, err
+	}
+
+	var inviteCode string
+	if request.InviteCode != nil {
+		inviteCode = *request.InviteCode
+	}
+	if inviteCode == "" && s.inviteRequired(dto.UserRole(request.Role)) {
+		return nil, models.ErrInviteRequired
+	}
+
+	hashedPassword, err := s.hasher.Hash(request.Password)
 	if err != nil {
 		return nil, err
 	}
@@ -56,38 +111,40 @@ func (s *Service) Register(ctx context.Context, request dto.PostRegisterJSONRequ
 		Role:     dto.UserRole(request.Role),
 	}
 
-	if err = s.userRepo.CreateUser(ctx, user); err != nil {
-		return nil, err
-	}
-
-	if err = s.userRepo.Commit(); err != nil {
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		if inviteCode == "" {
+			return s.userRepo.CreateUser(ctx, user)
+		}
+		return s.registerWithInvite(ctx, user, inviteCode)
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	userDto := &dto.User{
-		Id:    &user.ID,
-		Email: user.Email,
-		Role:  user.Role,
-	}
-
-	return userDto, nil
-}
-
-func hashPassword(password string) (string, error) {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	return string(hashedPassword), err
+	return user.DTO(), nil
 }
 
 func isValidRole(role dto.UserRole) bool {
 	return role == dto.UserRoleEmployee || role == dto.UserRoleModerator
 }
 
+// DummyLogin issues a token for an employee or a moderator when
+// Config.DummyLogin is set. Admin tokens are refused: the endpoint is public,
+// and an admin can rewrite the permissions of every role. The first admin is
+// set up with BootstrapAdmin instead.
 func (s *Service) DummyLogin(request dto.PostDummyLoginJSONRequestBody) (*dto.Token, error) {
+	if !s.config.DummyLogin {
+		return nil, models.ErrDummyLoginDisabled
+	}
 	if !isValidRole(dto.UserRole(request.Role)) {
 		return nil, models.ErrIncorrectUserRole
 	}
 
-	token, err := generateToken(dto.UserRole(request.Role))
+	token, err := s.generateToken(models.Principal{
+		UserID: models.DummyUserID,
+		Email:  models.DummyEmail,
+		Role:   dto.UserRole(request.Role),
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -95,38 +152,153 @@ func (s *Service) DummyLogin(request dto.PostDummyLoginJSONRequestBody) (*dto.To
 	return token, nil
 }
 
-func (s *Service) Login(ctx context.Context, request dto.PostLoginJSONRequestBody) (*dto.Token, error) {
+// BootstrapAdmin makes the user with the email an admin, creating it with the
+// password if there is none. Admins can be neither registered nor invited,
+// so this is how the first one is set up.
+func (s *Service) BootstrapAdmin(ctx context.Context, email openapi_types.Email, password string) error {
+	return s.txManager.Do(ctx, func(ctx context.Context) error {
+		user, err := s.userRepo.GetUserByEmail(ctx, email)
+		switch {
+		case errors.Is(err, models.ErrUserNotFound):
+		case err != nil:
+			return err
+		case user.Role == dto.UserRoleAdmin:
+			return nil
+		default:
+			return s.userRepo.UpdateRole(ctx, user.ID, dto.UserRoleAdmin)
+		}
+
+		if email == "" || password == "" {
+			return models.ErrEmptyEmailOrPassword
+		}
+		if err := s.config.PasswordPolicy.Validate(password); err != nil {
+			return err
+		}
+		hashedPassword, err := s.hasher.Hash(password)
+		if err != nil {
+			return err
+		}
+		return s.userRepo.CreateUser(ctx, &models.User{Email: email, Password: hashedPassword, Role: dto.UserRoleAdmin})
+	})
+}
+
+// Login checks the credentials of a user. Unknown emails and wrong passwords
+// are both reported as ErrInvalidCredentials, and repeated failures for the
+// email or the client IP throttle further attempts. Deactivated users are
+// refused once their password is verified. Users with two-factor
+// authentication get a challenge to complete with CompleteLogin instead of
+// tokens.
+func (s *Service) Login(ctx context.Context, request dto.PostLoginJSONRequestBody, clientIP string) (*LoginResult, error) {
 	if request.Email == "" || request.Password == "" {
 		return nil, models.ErrEmptyEmailOrPassword
 	}
 
+	subjects := loginSubjects(string(request.Email), clientIP)
+	if err := s.checkLoginAllowed(ctx, subjects); err != nil {
+		return nil, err
+	}
+
 	user, err := s.userRepo.GetUserByEmail(ctx, request.Email)
-	if err != nil {
-		return nil, models.ErrUserNotFound
+	if err != nil && !errors.Is(err, models.ErrUserNotFound) {
+		return nil, err
+	}
+
+	passwordHash := s.dummyPasswordHash()
+	if user != nil {
+		passwordHash = user.Password
+	}
+	// The hash is compared even for unknown emails so that response times do
+	// not reveal which accounts exist.
+	if !s.hasher.Verify(passwordHash, request.Password) || user == nil {
+		if err := s.recordLoginFailure(ctx, subjects); err != nil {
+			return nil, err
+		}
+		return nil, models.ErrInvalidCredentials
+	}
+
+	if !user.Active {
+		return nil, models.ErrUserDeactivated
 	}
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.Password))
+	// The password is known only now, so this is when a hash made with an
+	// outdated algorithm or parameters can be replaced.
+	rehashed := ""
+	if s.hasher.NeedsRehash(user.Password) {
+		if rehashed, err = s.hasher.Hash(request.Password); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := s.finishLogin(ctx, user, rehashed)
 	if err != nil {
-		return nil, models.ErrWrongPassword
+		return nil, err
 	}
+	// With a second factor still to provide, the failures of the account are
+	// kept until CompleteLogin succeeds.
+	if result.Tokens != nil {
+		if err := s.loginAttemptRepo.ResetLoginFailures(ctx, accountSubject(string(request.Email))); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// finishLogin completes a login whose first factor has been checked: it
+// either issues the tokens or, if the user has to provide a second factor,
+// a login challenge. A non-empty rehashed replaces the password hash unless
+// the password has been changed since it was verified.
+func (s *Service) finishLogin(ctx context.Context, user *models.User, rehashed string) (*LoginResult, error) {
+	userTOTP, err := s.twoFactorRepo.GetTOTP(ctx, user.ID)
+	if err != nil && !errors.Is(err, models.ErrTOTPNotFound) {
+		return nil, err
+	}
+	challenge := userTOTP.Confirmed() || s.twoFactorRequired(user.Role)
+
+	var result LoginResult
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		if rehashed != "" {
+			if _, err := s.userRepo.RehashPassword(ctx, user.ID, user.Password, rehashed); err != nil {
+				return err
+			}
+		}
+		if challenge {
+			result.Challenge, err = s.createLoginChallenge(ctx, user.ID, !userTOTP.Confirmed())
+			return err
+		}
 
-	token, err := generateToken(user.Role)
+		if err := s.userRepo.UpdateLastLogin(ctx, user.ID, time.Now().UTC()); err != nil {
+			return err
+		}
+		result.Tokens, err = s.issueTokenPair(ctx, user)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return token, nil
+	return &result, nil
 }
 
-func generateToken(role dto.UserRole) (*dto.Token, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, models.TokenClaims{Role: role, RegisteredClaims: jwt.RegisteredClaims{
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-		ID:        uuid.New().String(),
-	}})
+// CurrentUser returns the authenticated user. The dummy identity is answered
+// from the token alone.
+func (s *Service) CurrentUser(ctx context.Context) (*dto.User, error) {
+	principal, ok := models.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, models.ErrUserNotFound
+	}
+
+	if principal.IsDummy() {
+		return &dto.User{
+			Id:    &principal.UserID,
+			Email: openapi_types.Email(principal.Email),
+			Role:  principal.Role,
+		}, nil
+	}
 
-	tokenString, err := token.SignedString([]byte(jwtSecretKey))
+	user, err := s.userRepo.GetUserById(ctx, principal.UserID)
 	if err != nil {
 		return nil, err
 	}
-	return &tokenString, nil
+
+	return user.DTO(), nil
 }
@@ -2,24 +2,39 @@ package auth
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/oapi-codegen/runtime/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"golang.org/x/crypto/bcrypt"
 
 	"github.com/itisalisas/avito-backend/internal/generated/dto"
 	"github.com/itisalisas/avito-backend/internal/generated/mocks"
+	"github.com/itisalisas/avito-backend/internal/jwtkeys"
 	"github.com/itisalisas/avito-backend/internal/models"
+	"github.com/itisalisas/avito-backend/internal/passwordhash"
+	"github.com/itisalisas/avito-backend/internal/storage/memory"
 )
 
 func TestAuthService(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	mockTxManager := mocks.NewMockTransactionManager(ctrl)
 	mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
-	service := NewAuthService(mockRepo)
+	mockTokenRepo := mocks.NewMockTokenRepositoryInterface(ctrl)
+	mockLoginAttemptRepo := mocks.NewMockLoginAttemptRepositoryInterface(ctrl)
+	mockTwoFactorRepo := mocks.NewMockTwoFactorRepositoryInterface(ctrl)
+	withoutTOTP(mockTwoFactorRepo)
+	service := NewAuthService(mockTxManager, mockRepo, mockTokenRepo, nil, nil, nil, mockLoginAttemptRepo, mockTwoFactorRepo, nil, testKeys, nil, testHasher,
+		Config{DummyLogin: true})
 
 	tests := []struct {
 		name          string
@@ -39,10 +54,8 @@ func TestAuthService(t *testing.T) {
 				Role:     "employee",
 			},
 			mockActions: func() {
-				mockRepo.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil)
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
 				mockRepo.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(nil).Times(1)
-				mockRepo.EXPECT().Commit().Return(nil)
-				mockRepo.EXPECT().Rollback().Return(nil)
 			},
 			expectedErr: nil,
 			expectedUser: &models.User{
@@ -60,8 +73,6 @@ func TestAuthService(t *testing.T) {
 				Role:     "invalid_role",
 			},
 			mockActions: func() {
-				mockRepo.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil)
-				mockRepo.EXPECT().Rollback().Return(nil)
 			},
 			expectedErr:   models.ErrIncorrectUserRole,
 			expectedUser:  nil,
@@ -75,19 +86,47 @@ func TestAuthService(t *testing.T) {
 				Password: "password123",
 			},
 			mockActions: func() {
-				hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+				hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
 
 				user := &models.User{
 					Email:    "test@example.com",
 					Password: string(hashedPassword),
 					Role:     dto.UserRoleEmployee,
+					Active:   true,
 				}
+				allowLogin(mockLoginAttemptRepo)
 				mockRepo.EXPECT().GetUserByEmail(gomock.Any(), types.Email("test@example.com")).Return(user, nil).Times(1)
+				mockLoginAttemptRepo.EXPECT().ResetLoginFailures(gomock.Any(), "email:test@example.com").Return(nil)
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockRepo.EXPECT().UpdateLastLogin(gomock.Any(), user.ID, gomock.Any()).Return(nil).Times(1)
+				mockTokenRepo.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Return(nil).Times(1)
 			},
 			expectedErr:   nil,
 			expectedUser:  nil,
 			expectedToken: strPtr("token"),
 		},
+		{
+			name:   "login deactivated user",
+			method: "Login",
+			request: dto.PostLoginJSONRequestBody{
+				Email:    "test@example.com",
+				Password: "password123",
+			},
+			mockActions: func() {
+				hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+
+				user := &models.User{
+					Email:    "test@example.com",
+					Password: string(hashedPassword),
+					Role:     dto.UserRoleEmployee,
+				}
+				allowLogin(mockLoginAttemptRepo)
+				mockRepo.EXPECT().GetUserByEmail(gomock.Any(), types.Email("test@example.com")).Return(user, nil).Times(1)
+			},
+			expectedErr:   models.ErrUserDeactivated,
+			expectedUser:  nil,
+			expectedToken: nil,
+		},
 		{
 			name:   "login wrong password",
 			method: "Login",
@@ -101,9 +140,13 @@ func TestAuthService(t *testing.T) {
 					Password: "qwertrewq",
 					Role:     dto.UserRoleEmployee,
 				}
+				allowLogin(mockLoginAttemptRepo)
 				mockRepo.EXPECT().GetUserByEmail(gomock.Any(), types.Email("test@example.com")).Return(user, nil).Times(1)
+				mockLoginAttemptRepo.EXPECT().RecordLoginFailure(gomock.Any(), "email:test@example.com", gomock.Any(), gomock.Any()).
+					Return(&models.LoginFailures{Failures: 1}, nil)
+				mockLoginAttemptRepo.EXPECT().BlockLogin(gomock.Any(), "email:test@example.com", gomock.Any()).Return(nil)
 			},
-			expectedErr:   models.ErrWrongPassword,
+			expectedErr:   models.ErrInvalidCredentials,
 			expectedUser:  nil,
 			expectedToken: nil,
 		},
@@ -118,6 +161,17 @@ func TestAuthService(t *testing.T) {
 			expectedUser:  nil,
 			expectedToken: strPtr("token"),
 		},
+		{
+			name:   "dummy login admin",
+			method: "DummyLogin",
+			request: dto.PostDummyLoginJSONRequestBody{
+				Role: "admin",
+			},
+			mockActions:   func() {},
+			expectedErr:   models.ErrIncorrectUserRole,
+			expectedUser:  nil,
+			expectedToken: nil,
+		},
 		{
 			name:   "dummy invalid role",
 			method: "DummyLogin",
@@ -143,7 +197,11 @@ func TestAuthService(t *testing.T) {
 			case "Register":
 				user, err = service.Register(context.Background(), tt.request.(dto.PostRegisterJSONRequestBody))
 			case "Login":
-				token, err = service.Login(context.Background(), tt.request.(dto.PostLoginJSONRequestBody))
+				var result *LoginResult
+				result, err = service.Login(context.Background(), tt.request.(dto.PostLoginJSONRequestBody), "")
+				if result != nil {
+					token = &result.Tokens.AccessToken
+				}
 			case "DummyLogin":
 				token, err = service.DummyLogin(tt.request.(dto.PostDummyLoginJSONRequestBody))
 			}
@@ -169,6 +227,188 @@ func TestAuthService(t *testing.T) {
 	}
 }
 
+func TestAuthService_TokenIdentity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mocks.NewMockTransactionManager(ctrl)
+	mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	mockTokenRepo := mocks.NewMockTokenRepositoryInterface(ctrl)
+	mockLoginAttemptRepo := mocks.NewMockLoginAttemptRepositoryInterface(ctrl)
+	mockTwoFactorRepo := mocks.NewMockTwoFactorRepositoryInterface(ctrl)
+	withoutTOTP(mockTwoFactorRepo)
+	service := NewAuthService(mockTxManager, mockRepo, mockTokenRepo, nil, nil, nil, mockLoginAttemptRepo, mockTwoFactorRepo, nil, testKeys, nil, testHasher,
+		Config{DummyLogin: true})
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	user := &models.User{
+		ID:       uuid.New(),
+		Email:    "test@example.com",
+		Password: string(hashedPassword),
+		Role:     dto.UserRoleModerator,
+		Active:   true,
+	}
+	allowLogin(mockLoginAttemptRepo)
+	mockRepo.EXPECT().GetUserByEmail(gomock.Any(), types.Email("test@example.com")).Return(user, nil).Times(1)
+	mockLoginAttemptRepo.EXPECT().ResetLoginFailures(gomock.Any(), gomock.Any()).Return(nil)
+	mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+	mockRepo.EXPECT().UpdateLastLogin(gomock.Any(), user.ID, gomock.Any()).Return(nil).Times(1)
+	mockTokenRepo.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	result, err := service.Login(context.Background(), dto.PostLoginJSONRequestBody{Email: "test@example.com", Password: "password123"}, "")
+	assert.NoError(t, err)
+	claims := parseClaims(t, result.Tokens.AccessToken)
+	assert.Equal(t, user.ID.String(), claims.Subject)
+	assert.Equal(t, "test@example.com", claims.Email)
+	assert.Equal(t, dto.UserRoleModerator, claims.Role)
+
+	token, err := service.DummyLogin(dto.PostDummyLoginJSONRequestBody{Role: "employee"})
+	assert.NoError(t, err)
+	claims = parseClaims(t, *token)
+	assert.Equal(t, models.DummyUserID.String(), claims.Subject)
+	assert.Equal(t, models.DummyEmail, claims.Email)
+	assert.Equal(t, dto.UserRoleEmployee, claims.Role)
+}
+
+func TestAuthService_DummyLoginDisabled(t *testing.T) {
+	service := NewAuthService(nil, nil, nil, nil, nil, nil, nil, nil, nil, testKeys, nil, testHasher, Config{})
+
+	token, err := service.DummyLogin(dto.PostDummyLoginJSONRequestBody{Role: "moderator"})
+	assert.ErrorIs(t, err, models.ErrDummyLoginDisabled)
+	assert.Nil(t, token)
+}
+
+func TestAuthService_CurrentUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	service := NewAuthService(mocks.NewMockTransactionManager(ctrl), mockRepo, mocks.NewMockTokenRepositoryInterface(ctrl), nil, nil, nil, nil, nil, nil, testKeys, nil, testHasher, Config{DummyLogin: true})
+	userId := uuid.New()
+	createdAt := time.Now().UTC()
+	active := true
+
+	tests := []struct {
+		name         string
+		principal    *models.Principal
+		mockActions  func()
+		expectedErr  error
+		expectedUser *dto.User
+	}{
+		{
+			name:        "no principal",
+			mockActions: func() {},
+			expectedErr: models.ErrUserNotFound,
+		},
+		{
+			name:        "dummy principal",
+			principal:   &models.Principal{UserID: models.DummyUserID, Email: models.DummyEmail, Role: dto.UserRoleEmployee},
+			mockActions: func() {},
+			expectedUser: &dto.User{
+				Id:    &models.DummyUserID,
+				Email: types.Email(models.DummyEmail),
+				Role:  dto.UserRoleEmployee,
+			},
+		},
+		{
+			name:      "registered user",
+			principal: &models.Principal{UserID: userId, Email: "old@example.com", Role: dto.UserRoleEmployee},
+			mockActions: func() {
+				mockRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(&models.User{
+					ID:        userId,
+					Email:     "test@example.com",
+					Role:      dto.UserRoleEmployee,
+					Active:    true,
+					CreatedAt: createdAt,
+				}, nil).Times(1)
+			},
+			expectedUser: &dto.User{
+				Id:        &userId,
+				Email:     "test@example.com",
+				Role:      dto.UserRoleEmployee,
+				Active:    &active,
+				CreatedAt: &createdAt,
+			},
+		},
+		{
+			name:      "deleted user",
+			principal: &models.Principal{UserID: userId, Role: dto.UserRoleEmployee},
+			mockActions: func() {
+				mockRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(nil, models.ErrUserNotFound).Times(1)
+			},
+			expectedErr: models.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockActions()
+
+			ctx := context.Background()
+			if tt.principal != nil {
+				ctx = models.WithPrincipal(ctx, *tt.principal)
+			}
+
+			user, err := service.CurrentUser(ctx)
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedUser, user)
+		})
+	}
+}
+
+var testKeys = func() *jwtkeys.Manager {
+	_, private, _ := ed25519.GenerateKey(rand.Reader)
+	keys, _ := jwtkeys.NewManager(private)
+	return keys
+}()
+
+// testHasher hashes with bcrypt at its minimum cost to keep the tests fast.
+var testHasher = passwordhash.New(passwordhash.Bcrypt{Cost: bcrypt.MinCost})
+
+func parseClaims(t *testing.T, token string) *models.TokenClaims {
+	claims := &models.TokenClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		return testKeys.Keyfunc(token)
+	})
+	assert.NoError(t, err)
+	return claims
+}
+
 func strPtr(s string) *string {
 	return &s
 }
+
+func runInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestAuthService_BootstrapAdmin(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.NewRepositories()
+	service := NewAuthService(repos.TxManager, repos.User, repos.Token, repos.Invite, repos.Pvz, repos.Assignment,
+		repos.LoginAttempt, repos.TwoFactor, repos.Identity, testKeys, nil, testHasher, Config{PasswordPolicy: PasswordPolicy{MinLength: 8}})
+
+	assert.ErrorIs(t, service.BootstrapAdmin(ctx, "admin@example.com", "short"), models.ErrWeakPassword)
+	assert.ErrorIs(t, service.BootstrapAdmin(ctx, "admin@example.com", ""), models.ErrEmptyEmailOrPassword)
+
+	require.NoError(t, service.BootstrapAdmin(ctx, "admin@example.com", "password123"))
+	admin, err := repos.User.GetUserByEmail(ctx, "admin@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, dto.UserRoleAdmin, admin.Role)
+	assert.True(t, testHasher.Verify(admin.Password, "password123"))
+
+	// Bootstrapping again on every start changes nothing.
+	require.NoError(t, service.BootstrapAdmin(ctx, "admin@example.com", "other-password"))
+	again, err := repos.User.GetUserByEmail(ctx, "admin@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, admin.ID, again.ID)
+	assert.True(t, testHasher.Verify(again.Password, "password123"))
+
+	// Existing users are promoted and keep their password.
+	_, err = service.Register(ctx, dto.PostRegisterJSONRequestBody{Email: "lead@example.com", Password: "password123", Role: dto.Employee})
+	require.NoError(t, err)
+	require.NoError(t, service.BootstrapAdmin(ctx, "lead@example.com", ""))
+	lead, err := repos.User.GetUserByEmail(ctx, "lead@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, dto.UserRoleAdmin, lead.Role)
+}
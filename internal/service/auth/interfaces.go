@@ -3,11 +3,29 @@ package auth
 import (
 	"context"
 
+	"github.com/google/uuid"
+
 	"github.com/itisalisas/avito-backend/internal/generated/dto"
 )
 
 type ServiceInterface interface {
 	Register(ctx context.Context, request dto.PostRegisterJSONRequestBody) (*dto.User, error)
 	DummyLogin(request dto.PostDummyLoginJSONRequestBody) (*dto.Token, error)
-	Login(ctx context.Context, request dto.PostLoginJSONRequestBody) (*dto.Token, error)
+	Login(ctx context.Context, request dto.PostLoginJSONRequestBody, clientIP string) (*LoginResult, error)
+	CompleteLogin(ctx context.Context, request dto.PostLogin2faJSONRequestBody) (*dto.TokenPair, error)
+	StartLoginEnrollment(ctx context.Context, request dto.PostLogin2faEnrollJSONRequestBody) (*dto.TotpEnrollment, error)
+	StartSSOLogin(ctx context.Context) (string, error)
+	CompleteSSOLogin(ctx context.Context, params dto.GetOidcCallbackParams) (*LoginResult, error)
+	CurrentUser(ctx context.Context) (*dto.User, error)
+	Refresh(ctx context.Context, request dto.PostTokenRefreshJSONRequestBody) (*dto.TokenPair, error)
+	Logout(ctx context.Context, request dto.PostLogoutJSONRequestBody) error
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+	CreateInvite(ctx context.Context, request dto.PostInvitesJSONRequestBody) (*dto.Invite, error)
+	UnlockUser(ctx context.Context, userId uuid.UUID) error
+	ChangePassword(ctx context.Context, request dto.PostMePasswordJSONRequestBody) error
+	RequestPasswordReset(ctx context.Context, request dto.PostPasswordResetJSONRequestBody) error
+	ResetPassword(ctx context.Context, request dto.PostPasswordResetConfirmJSONRequestBody) error
+	StartTOTPEnrollment(ctx context.Context) (*dto.TotpEnrollment, error)
+	ConfirmTOTP(ctx context.Context, request dto.PostMe2faConfirmJSONRequestBody) error
+	DisableTOTP(ctx context.Context, request dto.PostMe2faDisableJSONRequestBody) error
 }
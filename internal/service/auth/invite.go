@@ -0,0 +1,136 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+const (
+	defaultInviteTTLHours = 72
+	maxInviteTTLHours     = 720
+)
+
+// CreateInvite issues a single-use registration invite. The code is only
+// returned here; afterwards just its hash is kept.
+func (s *Service) CreateInvite(ctx context.Context, request dto.PostInvitesJSONRequestBody) (*dto.Invite, error) {
+	role := dto.UserRole(request.Role)
+	if !isValidRole(role) {
+		return nil, models.ErrIncorrectUserRole
+	}
+
+	ttlHours := defaultInviteTTLHours
+	if request.TtlHours != nil {
+		ttlHours = *request.TtlHours
+	}
+	if ttlHours < 1 || ttlHours > maxInviteTTLHours {
+		return nil, models.ErrInvalidInviteTTL
+	}
+
+	pvzIds := []uuid.UUID{}
+	if request.PvzIds != nil {
+		for _, pvzId := range *request.PvzIds {
+			if !slices.Contains(pvzIds, pvzId) {
+				pvzIds = append(pvzIds, pvzId)
+			}
+		}
+	}
+	if len(pvzIds) > 0 && role != dto.UserRoleEmployee {
+		return nil, models.ErrIncorrectUserRole
+	}
+
+	code, err := generateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	invite := &models.Invite{
+		CodeHash:  hashSecret(code),
+		Role:      role,
+		PvzIds:    pvzIds,
+		CreatedBy: models.ActorID(ctx),
+		ExpiresAt: time.Now().UTC().Add(time.Duration(ttlHours) * time.Hour),
+	}
+
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		for _, pvzId := range pvzIds {
+			pvz, err := s.pvzRepo.GetPvzById(ctx, pvzId)
+			if err != nil {
+				return err
+			}
+			if pvz.DecommissionedAt != nil {
+				return models.ErrPvzDecommissioned
+			}
+		}
+		return s.inviteRepo.CreateInvite(ctx, invite)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.Invite{
+		Id:        invite.ID,
+		Code:      &code,
+		Role:      dto.InviteRole(invite.Role),
+		PvzIds:    invite.PvzIds,
+		ExpiresAt: invite.ExpiresAt,
+		CreatedBy: invite.CreatedBy,
+	}, nil
+}
+
+func (s *Service) inviteRequired(role dto.UserRole) bool {
+	return role == dto.UserRoleModerator || s.config.RequireEmployeeInvite
+}
+
+// registerWithInvite creates the user, redeems the invite and assigns the
+// PVZs bound to it. It must run inside a transaction. Unknown, used, expired
+// and other-role invites are all reported as ErrInvalidInvite.
+func (s *Service) registerWithInvite(ctx context.Context, user *models.User, code string) error {
+	invite, err := s.inviteRepo.GetInviteByHash(ctx, hashSecret(code))
+	if errors.Is(err, models.ErrInviteNotFound) {
+		return models.ErrInvalidInvite
+	}
+	if err != nil {
+		return err
+	}
+	if invite.UsedAt != nil || invite.Role != user.Role || !time.Now().UTC().Before(invite.ExpiresAt) {
+		return models.ErrInvalidInvite
+	}
+
+	if err := s.userRepo.CreateUser(ctx, user); err != nil {
+		return err
+	}
+
+	// A concurrent registration may have redeemed the invite since it was read.
+	err = s.inviteRepo.UseInvite(ctx, invite.ID, user.ID)
+	if errors.Is(err, models.ErrInviteNotFound) {
+		return models.ErrInvalidInvite
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, pvzId := range invite.PvzIds {
+		pvz, err := s.pvzRepo.GetPvzById(ctx, pvzId)
+		if errors.Is(err, models.ErrPvzNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		// PVZs decommissioned after the invite was issued are skipped.
+		if pvz.DecommissionedAt != nil {
+			continue
+		}
+		if err := s.assignmentRepo.AssignPvz(ctx, user.ID, pvzId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
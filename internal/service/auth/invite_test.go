@@ -0,0 +1,309 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/generated/mocks"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+func TestAuthService_CreateInvite(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mocks.NewMockTransactionManager(ctrl)
+	mockInviteRepo := mocks.NewMockInviteRepositoryInterface(ctrl)
+	mockPvzRepo := mocks.NewMockPvzRepositoryInterface(ctrl)
+	service := NewAuthService(mockTxManager, nil, nil, mockInviteRepo, mockPvzRepo, nil, nil, nil, nil, testKeys, nil, testHasher, Config{})
+
+	pvzId := uuid.New()
+	moderatorId := uuid.New()
+	ctx := models.WithPrincipal(context.Background(), models.Principal{UserID: moderatorId, Role: dto.UserRoleModerator})
+	decommissionedAt := time.Now()
+	ttl := func(hours int) *int { return &hours }
+
+	tests := []struct {
+		name        string
+		request     dto.PostInvitesJSONRequestBody
+		mockActions func()
+		wantErr     error
+		wantPvzIds  []uuid.UUID
+	}{
+		{
+			name:    "invalid role",
+			request: dto.PostInvitesJSONRequestBody{Role: "admin"},
+			wantErr: models.ErrIncorrectUserRole,
+		},
+		{
+			name:    "ttl too long",
+			request: dto.PostInvitesJSONRequestBody{Role: dto.PostInvitesJSONBodyRoleEmployee, TtlHours: ttl(maxInviteTTLHours + 1)},
+			wantErr: models.ErrInvalidInviteTTL,
+		},
+		{
+			name: "pvz bound to moderator invite",
+			request: dto.PostInvitesJSONRequestBody{
+				Role:   dto.PostInvitesJSONBodyRoleModerator,
+				PvzIds: &[]uuid.UUID{pvzId},
+			},
+			wantErr: models.ErrIncorrectUserRole,
+		},
+		{
+			name: "decommissioned pvz",
+			request: dto.PostInvitesJSONRequestBody{
+				Role:   dto.PostInvitesJSONBodyRoleEmployee,
+				PvzIds: &[]uuid.UUID{pvzId},
+			},
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				mockPvzRepo.EXPECT().GetPvzById(gomock.Any(), pvzId).Return(&dto.PVZ{Id: &pvzId, DecommissionedAt: &decommissionedAt}, nil)
+			},
+			wantErr: models.ErrPvzDecommissioned,
+		},
+		{
+			name:    "moderator invite",
+			request: dto.PostInvitesJSONRequestBody{Role: dto.PostInvitesJSONBodyRoleModerator},
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				mockInviteRepo.EXPECT().CreateInvite(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, invite *models.Invite) error {
+						assert.Equal(t, &moderatorId, invite.CreatedBy)
+						assert.WithinDuration(t, time.Now().UTC().Add(defaultInviteTTLHours*time.Hour), invite.ExpiresAt, time.Minute)
+						return nil
+					})
+			},
+			wantPvzIds: []uuid.UUID{},
+		},
+		{
+			name: "employee invite with duplicate pvzs",
+			request: dto.PostInvitesJSONRequestBody{
+				Role:     dto.PostInvitesJSONBodyRoleEmployee,
+				PvzIds:   &[]uuid.UUID{pvzId, pvzId},
+				TtlHours: ttl(1),
+			},
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				mockPvzRepo.EXPECT().GetPvzById(gomock.Any(), pvzId).Return(&dto.PVZ{Id: &pvzId}, nil)
+				mockInviteRepo.EXPECT().CreateInvite(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wantPvzIds: []uuid.UUID{pvzId},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockActions != nil {
+				tt.mockActions()
+			}
+
+			invite, err := service.CreateInvite(ctx, tt.request)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, invite)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotEmpty(t, *invite.Code)
+			assert.Equal(t, tt.wantPvzIds, invite.PvzIds)
+		})
+	}
+}
+
+func TestAuthService_RegisterWithInvite(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mocks.NewMockTransactionManager(ctrl)
+	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	mockInviteRepo := mocks.NewMockInviteRepositoryInterface(ctrl)
+	mockPvzRepo := mocks.NewMockPvzRepositoryInterface(ctrl)
+	mockAssignmentRepo := mocks.NewMockAssignmentRepositoryInterface(ctrl)
+	newService := func(config Config) *Service {
+		return NewAuthService(mockTxManager, mockUserRepo, nil, mockInviteRepo, mockPvzRepo, mockAssignmentRepo, nil, nil, nil, testKeys, nil, testHasher, config)
+	}
+
+	code := "invite-code"
+	userId := uuid.New()
+	pvzId := uuid.New()
+	decommissionedPvzId := uuid.New()
+	decommissionedAt := time.Now()
+	usedAt := time.Now()
+	invite := func(role dto.UserRole, pvzIds ...uuid.UUID) *models.Invite {
+		return &models.Invite{
+			ID:        uuid.New(),
+			CodeHash:  hashSecret(code),
+			Role:      role,
+			PvzIds:    pvzIds,
+			ExpiresAt: time.Now().UTC().Add(time.Hour),
+		}
+	}
+	createUser := func() {
+		mockUserRepo.EXPECT().CreateUser(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, user *models.User) error {
+				user.ID = userId
+				return nil
+			})
+	}
+
+	tests := []struct {
+		name        string
+		config      Config
+		role        dto.PostRegisterJSONBodyRole
+		inviteCode  *string
+		mockActions func()
+		wantErr     error
+	}{
+		{
+			name:    "moderator without invite",
+			role:    dto.Moderator,
+			wantErr: models.ErrInviteRequired,
+		},
+		{
+			name:    "employee without invite when required",
+			config:  Config{RequireEmployeeInvite: true},
+			role:    dto.Employee,
+			wantErr: models.ErrInviteRequired,
+		},
+		{
+			name: "employee without invite when not required",
+			role: dto.Employee,
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				createUser()
+			},
+		},
+		{
+			name:       "unknown invite",
+			role:       dto.Moderator,
+			inviteCode: &code,
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				mockInviteRepo.EXPECT().GetInviteByHash(gomock.Any(), hashSecret(code)).Return(nil, models.ErrInviteNotFound)
+			},
+			wantErr: models.ErrInvalidInvite,
+		},
+		{
+			name:       "used invite",
+			role:       dto.Moderator,
+			inviteCode: &code,
+			mockActions: func() {
+				used := invite(dto.UserRoleModerator)
+				used.UsedAt = &usedAt
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				mockInviteRepo.EXPECT().GetInviteByHash(gomock.Any(), hashSecret(code)).Return(used, nil)
+			},
+			wantErr: models.ErrInvalidInvite,
+		},
+		{
+			name:       "expired invite",
+			role:       dto.Moderator,
+			inviteCode: &code,
+			mockActions: func() {
+				expired := invite(dto.UserRoleModerator)
+				expired.ExpiresAt = time.Now().UTC().Add(-time.Minute)
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				mockInviteRepo.EXPECT().GetInviteByHash(gomock.Any(), hashSecret(code)).Return(expired, nil)
+			},
+			wantErr: models.ErrInvalidInvite,
+		},
+		{
+			name:       "invite for another role",
+			role:       dto.Moderator,
+			inviteCode: &code,
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				mockInviteRepo.EXPECT().GetInviteByHash(gomock.Any(), hashSecret(code)).Return(invite(dto.UserRoleEmployee), nil)
+			},
+			wantErr: models.ErrInvalidInvite,
+		},
+		{
+			name:       "invite redeemed concurrently",
+			role:       dto.Moderator,
+			inviteCode: &code,
+			mockActions: func() {
+				moderatorInvite := invite(dto.UserRoleModerator)
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				mockInviteRepo.EXPECT().GetInviteByHash(gomock.Any(), hashSecret(code)).Return(moderatorInvite, nil)
+				createUser()
+				mockInviteRepo.EXPECT().UseInvite(gomock.Any(), moderatorInvite.ID, userId).Return(models.ErrInviteNotFound)
+			},
+			wantErr: models.ErrInvalidInvite,
+		},
+		{
+			name:       "moderator with invite",
+			role:       dto.Moderator,
+			inviteCode: &code,
+			mockActions: func() {
+				moderatorInvite := invite(dto.UserRoleModerator)
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				mockInviteRepo.EXPECT().GetInviteByHash(gomock.Any(), hashSecret(code)).Return(moderatorInvite, nil)
+				createUser()
+				mockInviteRepo.EXPECT().UseInvite(gomock.Any(), moderatorInvite.ID, userId).Return(nil)
+			},
+		},
+		{
+			name:       "employee invite assigns active pvzs",
+			config:     Config{RequireEmployeeInvite: true},
+			role:       dto.Employee,
+			inviteCode: &code,
+			mockActions: func() {
+				employeeInvite := invite(dto.UserRoleEmployee, pvzId, decommissionedPvzId)
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				mockInviteRepo.EXPECT().GetInviteByHash(gomock.Any(), hashSecret(code)).Return(employeeInvite, nil)
+				createUser()
+				mockInviteRepo.EXPECT().UseInvite(gomock.Any(), employeeInvite.ID, userId).Return(nil)
+				mockPvzRepo.EXPECT().GetPvzById(gomock.Any(), pvzId).Return(&dto.PVZ{Id: &pvzId}, nil)
+				mockPvzRepo.EXPECT().GetPvzById(gomock.Any(), decommissionedPvzId).
+					Return(&dto.PVZ{Id: &decommissionedPvzId, DecommissionedAt: &decommissionedAt}, nil)
+				mockAssignmentRepo.EXPECT().AssignPvz(gomock.Any(), userId, pvzId).Return(nil)
+			},
+		},
+		{
+			name:       "assignment failure",
+			role:       dto.Employee,
+			inviteCode: &code,
+			mockActions: func() {
+				employeeInvite := invite(dto.UserRoleEmployee, pvzId)
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				mockInviteRepo.EXPECT().GetInviteByHash(gomock.Any(), hashSecret(code)).Return(employeeInvite, nil)
+				createUser()
+				mockInviteRepo.EXPECT().UseInvite(gomock.Any(), employeeInvite.ID, userId).Return(nil)
+				mockPvzRepo.EXPECT().GetPvzById(gomock.Any(), pvzId).Return(&dto.PVZ{Id: &pvzId}, nil)
+				mockAssignmentRepo.EXPECT().AssignPvz(gomock.Any(), userId, pvzId).Return(errors.New("db error"))
+			},
+			wantErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockActions != nil {
+				tt.mockActions()
+			}
+
+			user, err := newService(tt.config).Register(context.Background(), dto.PostRegisterJSONRequestBody{
+				Email:      "new@example.com",
+				Password:   "password123",
+				Role:       tt.role,
+				InviteCode: tt.inviteCode,
+			})
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				assert.Nil(t, user)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, userId, *user.Id)
+			assert.Equal(t, dto.UserRole(tt.role), user.Role)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/itisalisas/avito-backend/internal/models"
+	"github.com/itisalisas/avito-backend/pkg/metrics"
+)
+
+const (
+	// loginFailureWindow is how long a failed attempt is remembered.
+	loginFailureWindow = 15 * time.Minute
+	loginLockout       = 15 * time.Minute
+	maxLoginDelay      = 30 * time.Second
+)
+
+// loginSubject is a key failed logins are counted under, together with the
+// number of failures within the window that locks it out.
+type loginSubject struct {
+	subject     string
+	scope       string
+	maxFailures int
+}
+
+func accountSubject(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// loginSubjects counts failures per email rather than per user, so unknown
+// emails are throttled exactly like existing ones.
+func loginSubjects(email, clientIP string) []loginSubject {
+	subjects := []loginSubject{{subject: accountSubject(email), scope: "account", maxFailures: 5}}
+	if clientIP != "" {
+		subjects = append(subjects, loginSubject{subject: "ip:" + clientIP, scope: "ip", maxFailures: 20})
+	}
+	return subjects
+}
+
+func (s *Service) checkLoginAllowed(ctx context.Context, subjects []loginSubject) error {
+	now := time.Now().UTC()
+	for _, subject := range subjects {
+		failures, err := s.loginAttemptRepo.GetLoginFailures(ctx, subject.subject)
+		if err != nil {
+			return err
+		}
+		if failures.BlockedUntil != nil && now.Before(*failures.BlockedUntil) {
+			return models.ErrTooManyLoginAttempts
+		}
+	}
+	return nil
+}
+
+// recordLoginFailure counts a failed attempt for every subject and blocks the
+// subject for a delay that doubles with each failure, or locks it out once it
+// reaches its limit.
+func (s *Service) recordLoginFailure(ctx context.Context, subjects []loginSubject) error {
+	now := time.Now().UTC()
+	for _, subject := range subjects {
+		failures, err := s.loginAttemptRepo.RecordLoginFailure(ctx, subject.subject, now, now.Add(-loginFailureWindow))
+		if err != nil {
+			return err
+		}
+
+		delay := loginDelay(failures.Failures)
+		if failures.Failures >= subject.maxFailures {
+			delay = loginLockout
+			metrics.LoginLockouts.WithLabelValues(subject.scope).Inc()
+		}
+		if err := s.loginAttemptRepo.BlockLogin(ctx, subject.subject, now.Add(delay)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func loginDelay(failures int) time.Duration {
+	if failures > 6 {
+		return maxLoginDelay
+	}
+	return min(time.Second<<(failures-1), maxLoginDelay)
+}
+
+// UnlockUser clears the failed logins of the user's account, lifting a
+// lockout. Failures counted per client IP are kept. Admin accounts are only
+// unlocked by those who may manage roles.
+func (s *Service) UnlockUser(ctx context.Context, userId uuid.UUID) error {
+	user, err := s.userRepo.GetUserById(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if principal, _ := models.PrincipalFromContext(ctx); !principal.CanManageUser(user.Role) {
+		return models.ErrPermissionDenied
+	}
+	return s.loginAttemptRepo.ResetLoginFailures(ctx, accountSubject(string(user.Email)))
+}
@@ -0,0 +1,292 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/oapi-codegen/runtime/types"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/generated/mocks"
+	"github.com/itisalisas/avito-backend/internal/models"
+	"github.com/itisalisas/avito-backend/internal/passwordhash"
+	"github.com/itisalisas/avito-backend/pkg/metrics"
+)
+
+// allowLogin expects the throttle check of a login that is not blocked.
+func allowLogin(repo *mocks.MockLoginAttemptRepositoryInterface) {
+	repo.EXPECT().GetLoginFailures(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, subject string) (*models.LoginFailures, error) {
+			return &models.LoginFailures{Subject: subject}, nil
+		}).AnyTimes()
+}
+
+func TestAuthService_LoginThrottling(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mocks.NewMockTransactionManager(ctrl)
+	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	mockTokenRepo := mocks.NewMockTokenRepositoryInterface(ctrl)
+	mockLoginAttemptRepo := mocks.NewMockLoginAttemptRepositoryInterface(ctrl)
+	mockTwoFactorRepo := mocks.NewMockTwoFactorRepositoryInterface(ctrl)
+	withoutTOTP(mockTwoFactorRepo)
+	service := NewAuthService(mockTxManager, mockUserRepo, mockTokenRepo, nil, nil, nil, mockLoginAttemptRepo, mockTwoFactorRepo, nil, testKeys, nil,
+		testHasher, Config{})
+
+	const (
+		email      = "User@Example.com"
+		account    = "email:user@example.com"
+		clientIP   = "192.0.2.1"
+		ipSubject  = "ip:192.0.2.1"
+		password   = "password123"
+		wrongInput = "wrong"
+	)
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	user := &models.User{ID: uuid.New(), Email: email, Password: string(hashedPassword), Role: dto.UserRoleEmployee, Active: true}
+	blockedUntil := time.Now().UTC().Add(time.Minute)
+
+	// expectBlock checks that a failure recorded with the given count blocks
+	// the subject for the expected delay.
+	expectBlock := func(subject string, failures int, delay time.Duration) {
+		mockLoginAttemptRepo.EXPECT().RecordLoginFailure(gomock.Any(), subject, gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ string, at, windowStart time.Time) (*models.LoginFailures, error) {
+				assert.Equal(t, loginFailureWindow, at.Sub(windowStart))
+				return &models.LoginFailures{Subject: subject, Failures: failures, LastFailureAt: at}, nil
+			})
+		mockLoginAttemptRepo.EXPECT().BlockLogin(gomock.Any(), subject, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ string, until time.Time) error {
+				assert.WithinDuration(t, time.Now().UTC().Add(delay), until, time.Second)
+				return nil
+			})
+	}
+
+	tests := []struct {
+		name        string
+		password    string
+		mockActions func()
+		wantErr     error
+		wantLockout map[string]float64
+	}{
+		{
+			name:     "account blocked",
+			password: password,
+			mockActions: func() {
+				mockLoginAttemptRepo.EXPECT().GetLoginFailures(gomock.Any(), account).
+					Return(&models.LoginFailures{Subject: account, Failures: 2, BlockedUntil: &blockedUntil}, nil)
+			},
+			wantErr: models.ErrTooManyLoginAttempts,
+		},
+		{
+			name:     "ip blocked",
+			password: password,
+			mockActions: func() {
+				mockLoginAttemptRepo.EXPECT().GetLoginFailures(gomock.Any(), account).Return(&models.LoginFailures{Subject: account}, nil)
+				mockLoginAttemptRepo.EXPECT().GetLoginFailures(gomock.Any(), ipSubject).
+					Return(&models.LoginFailures{Subject: ipSubject, Failures: 20, BlockedUntil: &blockedUntil}, nil)
+			},
+			wantErr: models.ErrTooManyLoginAttempts,
+		},
+		{
+			name:     "unknown email counts as failure",
+			password: password,
+			mockActions: func() {
+				allowLogin(mockLoginAttemptRepo)
+				mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), types.Email(email)).Return(nil, models.ErrUserNotFound)
+				expectBlock(account, 1, time.Second)
+				expectBlock(ipSubject, 1, time.Second)
+			},
+			wantErr: models.ErrInvalidCredentials,
+		},
+		{
+			name:     "wrong password delays progressively",
+			password: wrongInput,
+			mockActions: func() {
+				allowLogin(mockLoginAttemptRepo)
+				mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), types.Email(email)).Return(user, nil)
+				expectBlock(account, 3, 4*time.Second)
+				expectBlock(ipSubject, 12, maxLoginDelay)
+			},
+			wantErr: models.ErrInvalidCredentials,
+		},
+		{
+			name:     "account lockout",
+			password: wrongInput,
+			mockActions: func() {
+				allowLogin(mockLoginAttemptRepo)
+				mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), types.Email(email)).Return(user, nil)
+				expectBlock(account, 5, loginLockout)
+				expectBlock(ipSubject, 5, 16*time.Second)
+			},
+			wantErr:     models.ErrInvalidCredentials,
+			wantLockout: map[string]float64{"account": 1},
+		},
+		{
+			name:     "ip lockout",
+			password: wrongInput,
+			mockActions: func() {
+				allowLogin(mockLoginAttemptRepo)
+				mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), types.Email(email)).Return(user, nil)
+				expectBlock(account, 1, time.Second)
+				expectBlock(ipSubject, 20, loginLockout)
+			},
+			wantErr:     models.ErrInvalidCredentials,
+			wantLockout: map[string]float64{"ip": 1},
+		},
+		{
+			name:     "user lookup failure",
+			password: password,
+			mockActions: func() {
+				allowLogin(mockLoginAttemptRepo)
+				mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), types.Email(email)).Return(nil, errors.New("db error"))
+			},
+			wantErr: errors.New("db error"),
+		},
+		{
+			name:     "success resets account failures",
+			password: password,
+			mockActions: func() {
+				allowLogin(mockLoginAttemptRepo)
+				mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), types.Email(email)).Return(user, nil)
+				mockLoginAttemptRepo.EXPECT().ResetLoginFailures(gomock.Any(), account).Return(nil)
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				mockUserRepo.EXPECT().UpdateLastLogin(gomock.Any(), user.ID, gomock.Any()).DoAndReturn(
+					func(_ context.Context, _ uuid.UUID, at time.Time) error {
+						assert.WithinDuration(t, time.Now().UTC(), at, time.Second)
+						return nil
+					})
+				mockTokenRepo.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Each case sets up its own throttle expectations.
+			ctrl := gomock.NewController(t)
+			mockLoginAttemptRepo = mocks.NewMockLoginAttemptRepositoryInterface(ctrl)
+			service.loginAttemptRepo = mockLoginAttemptRepo
+
+			accountLockouts := testutil.ToFloat64(metrics.LoginLockouts.WithLabelValues("account"))
+			ipLockouts := testutil.ToFloat64(metrics.LoginLockouts.WithLabelValues("ip"))
+			tt.mockActions()
+
+			result, err := service.Login(context.Background(), dto.PostLoginJSONRequestBody{Email: email, Password: tt.password}, clientIP)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, result.Tokens.AccessToken)
+			}
+
+			assert.Equal(t, accountLockouts+tt.wantLockout["account"], testutil.ToFloat64(metrics.LoginLockouts.WithLabelValues("account")))
+			assert.Equal(t, ipLockouts+tt.wantLockout["ip"], testutil.ToFloat64(metrics.LoginLockouts.WithLabelValues("ip")))
+		})
+	}
+}
+
+func TestAuthService_LoginRehash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mocks.NewMockTransactionManager(ctrl)
+	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	mockTokenRepo := mocks.NewMockTokenRepositoryInterface(ctrl)
+	mockLoginAttemptRepo := mocks.NewMockLoginAttemptRepositoryInterface(ctrl)
+	mockTwoFactorRepo := mocks.NewMockTwoFactorRepositoryInterface(ctrl)
+	withoutTOTP(mockTwoFactorRepo)
+	argon := passwordhash.Argon2id{Memory: 64, Iterations: 1, Parallelism: 1}
+	hasher := passwordhash.New(argon, passwordhash.Bcrypt{Cost: bcrypt.MinCost})
+	service := NewAuthService(mockTxManager, mockUserRepo, mockTokenRepo, nil, nil, nil, mockLoginAttemptRepo, mockTwoFactorRepo, nil, testKeys, nil, hasher,
+		Config{})
+
+	const password = "password123"
+	bcryptHash, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	argonHash, err := hasher.Hash(password)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		hash       string
+		wantRehash bool
+		// changed means the password changed after it was verified, so the
+		// rehash finds another hash and replaces nothing.
+		changed bool
+	}{
+		{name: "legacy bcrypt hash", hash: string(bcryptHash), wantRehash: true},
+		{name: "outdated argon2id parameters", hash: func() string {
+			hash, _ := passwordhash.Argon2id{Memory: 32, Iterations: 1, Parallelism: 1}.Hash(password)
+			return hash
+		}(), wantRehash: true},
+		{name: "password changed meanwhile", hash: string(bcryptHash), wantRehash: true, changed: true},
+		{name: "current hash", hash: argonHash},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &models.User{ID: uuid.New(), Email: "user@example.com", Password: tt.hash, Role: dto.UserRoleEmployee, Active: true}
+			allowLogin(mockLoginAttemptRepo)
+			mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), types.Email("user@example.com")).Return(user, nil)
+			mockLoginAttemptRepo.EXPECT().ResetLoginFailures(gomock.Any(), "email:user@example.com").Return(nil)
+			mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+			mockUserRepo.EXPECT().UpdateLastLogin(gomock.Any(), user.ID, gomock.Any()).Return(nil)
+			if tt.wantRehash {
+				mockUserRepo.EXPECT().RehashPassword(gomock.Any(), user.ID, tt.hash, gomock.Any()).DoAndReturn(
+					func(_ context.Context, _ uuid.UUID, _, passwordHash string) (bool, error) {
+						assert.True(t, argon.Owns(passwordHash))
+						assert.False(t, hasher.NeedsRehash(passwordHash))
+						assert.True(t, hasher.Verify(passwordHash, password))
+						return !tt.changed, nil
+					})
+			}
+			mockTokenRepo.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Return(nil)
+
+			result, err := service.Login(context.Background(), dto.PostLoginJSONRequestBody{Email: "user@example.com", Password: password}, "")
+			assert.NoError(t, err)
+			assert.NotEmpty(t, result.Tokens.AccessToken)
+		})
+	}
+}
+
+func TestAuthService_UnlockUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	mockLoginAttemptRepo := mocks.NewMockLoginAttemptRepositoryInterface(ctrl)
+	service := NewAuthService(nil, mockUserRepo, nil, nil, nil, nil, mockLoginAttemptRepo, nil, nil, testKeys, nil, testHasher, Config{})
+
+	userId := uuid.New()
+
+	mockUserRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(nil, models.ErrUserNotFound)
+	assert.ErrorIs(t, service.UnlockUser(context.Background(), userId), models.ErrUserNotFound)
+
+	mockUserRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(&models.User{ID: userId, Email: "Employee@Example.com"}, nil)
+	mockLoginAttemptRepo.EXPECT().ResetLoginFailures(gomock.Any(), "email:employee@example.com").Return(nil)
+	assert.NoError(t, service.UnlockUser(context.Background(), userId))
+
+	// Admin accounts are left to those who may manage roles.
+	admin := &models.User{ID: userId, Email: "admin@example.com", Role: dto.UserRoleAdmin}
+	moderatorCtx := models.WithPrincipal(context.Background(), models.Principal{
+		Role:        dto.UserRoleModerator,
+		Permissions: models.DefaultRolePermissions[dto.UserRoleModerator],
+	})
+	mockUserRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(admin, nil)
+	assert.ErrorIs(t, service.UnlockUser(moderatorCtx, userId), models.ErrPermissionDenied)
+
+	adminCtx := models.WithPrincipal(context.Background(), models.Principal{
+		Role:        dto.UserRoleAdmin,
+		Permissions: models.DefaultRolePermissions[dto.UserRoleAdmin],
+	})
+	mockUserRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(admin, nil)
+	mockLoginAttemptRepo.EXPECT().ResetLoginFailures(gomock.Any(), "email:admin@example.com").Return(nil)
+	assert.NoError(t, service.UnlockUser(adminCtx, userId))
+}
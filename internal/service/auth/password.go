@@ -0,0 +1,217 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+const (
+	resetTokenTTL = time.Hour
+	// maxPasswordLength is the most bcrypt can hash. It holds for argon2id
+	// too, so that switching back to bcrypt never truncates passwords.
+	maxPasswordLength = 72
+)
+
+// Notifier delivers messages to users.
+type Notifier interface {
+	SendPasswordReset(ctx context.Context, email string, token string) error
+}
+
+// PasswordHasher hashes passwords into self-describing hashes and checks
+// passwords against hashes of any algorithm it supports.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(hash, password string) bool
+	// NeedsRehash reports whether the hash was made with an outdated
+	// algorithm or parameters.
+	NeedsRehash(hash string) bool
+}
+
+// PasswordPolicy is the strength a new password must have.
+type PasswordPolicy struct {
+	MinLength        int
+	RequireMixedCase bool
+	RequireDigit     bool
+	RequireSymbol    bool
+}
+
+// Validate returns an error wrapping ErrWeakPassword that names the first
+// requirement the password misses.
+func (p PasswordPolicy) Validate(password string) error {
+	if len([]rune(password)) < p.MinLength {
+		return fmt.Errorf("%w: at least %d characters required", models.ErrWeakPassword, p.MinLength)
+	}
+	if len(password) > maxPasswordLength {
+		return fmt.Errorf("%w: at most %d bytes allowed", models.ErrWeakPassword, maxPasswordLength)
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+
+	switch {
+	case p.RequireMixedCase && !(upper && lower):
+		return fmt.Errorf("%w: upper and lower case letters required", models.ErrWeakPassword)
+	case p.RequireDigit && !digit:
+		return fmt.Errorf("%w: a digit required", models.ErrWeakPassword)
+	case p.RequireSymbol && !symbol:
+		return fmt.Errorf("%w: a symbol required", models.ErrWeakPassword)
+	}
+	return nil
+}
+
+// ChangePassword sets a new password for the authenticated user after
+// checking the current one, and signs the user out of other sessions by
+// revoking all refresh tokens.
+func (s *Service) ChangePassword(ctx context.Context, request dto.PostMePasswordJSONRequestBody) error {
+	principal, ok := models.PrincipalFromContext(ctx)
+	if !ok || principal.IsDummy() {
+		return models.ErrUserNotFound
+	}
+
+	if err := s.config.PasswordPolicy.Validate(request.NewPassword); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetUserById(ctx, principal.UserID)
+	if err != nil {
+		return err
+	}
+	if !s.hasher.Verify(user.Password, request.CurrentPassword) {
+		return models.ErrInvalidCredentials
+	}
+
+	hashedPassword, err := s.hasher.Hash(request.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	return s.txManager.Do(ctx, func(ctx context.Context) error {
+		return s.setPassword(ctx, user.ID, hashedPassword)
+	})
+}
+
+// RequestPasswordReset sends a reset token to the user with the given email.
+// Unknown emails and deactivated users are silently ignored so the response
+// does not reveal which accounts exist.
+func (s *Service) RequestPasswordReset(ctx context.Context, request dto.PostPasswordResetJSONRequestBody) error {
+	if request.Email == "" {
+		return models.ErrEmptyEmailOrPassword
+	}
+
+	user, err := s.userRepo.GetUserByEmail(ctx, request.Email)
+	if errors.Is(err, models.ErrUserNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !user.Active {
+		return nil
+	}
+
+	token, err := generateSecret()
+	if err != nil {
+		return err
+	}
+
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		return s.tokenRepo.CreatePasswordResetToken(ctx, &models.PasswordResetToken{
+			UserID:    user.ID,
+			TokenHash: hashSecret(token),
+			ExpiresAt: time.Now().UTC().Add(resetTokenTTL),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.notifier.SendPasswordReset(ctx, string(user.Email), token)
+}
+
+// ResetPassword sets a new password using a reset token. Unknown, used and
+// expired tokens are all reported as ErrInvalidResetToken.
+func (s *Service) ResetPassword(ctx context.Context, request dto.PostPasswordResetConfirmJSONRequestBody) error {
+	if request.Token == "" {
+		return models.ErrInvalidResetToken
+	}
+	if err := s.config.PasswordPolicy.Validate(request.NewPassword); err != nil {
+		return err
+	}
+
+	hashedPassword, err := s.hasher.Hash(request.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	var user *models.User
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		token, err := s.tokenRepo.GetPasswordResetTokenByHash(ctx, hashSecret(request.Token))
+		if errors.Is(err, models.ErrResetTokenNotFound) {
+			return models.ErrInvalidResetToken
+		}
+		if err != nil {
+			return err
+		}
+		if token.UsedAt != nil || !time.Now().UTC().Before(token.ExpiresAt) {
+			return models.ErrInvalidResetToken
+		}
+
+		// A concurrent reset with the same token may have used it since it
+		// was read.
+		err = s.tokenRepo.UsePasswordResetToken(ctx, token.ID)
+		if errors.Is(err, models.ErrResetTokenNotFound) {
+			return models.ErrInvalidResetToken
+		}
+		if err != nil {
+			return err
+		}
+
+		user, err = s.userRepo.GetUserById(ctx, token.UserID)
+		if errors.Is(err, models.ErrUserNotFound) {
+			return models.ErrInvalidResetToken
+		}
+		if err != nil {
+			return err
+		}
+
+		return s.setPassword(ctx, user.ID, hashedPassword)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Proving control of the mailbox lifts a lockout of the account.
+	return s.loginAttemptRepo.ResetLoginFailures(ctx, accountSubject(string(user.Email)))
+}
+
+// setPassword stores the new password hash, invalidates outstanding reset
+// tokens and revokes all refresh tokens of the user. It must run inside a
+// transaction.
+func (s *Service) setPassword(ctx context.Context, userId uuid.UUID, passwordHash string) error {
+	if err := s.userRepo.UpdatePassword(ctx, userId, passwordHash); err != nil {
+		return err
+	}
+	if err := s.tokenRepo.UseUserPasswordResetTokens(ctx, userId); err != nil {
+		return err
+	}
+	return s.tokenRepo.RevokeUserRefreshTokens(ctx, userId)
+}
@@ -0,0 +1,296 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/oapi-codegen/runtime/types"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/generated/mocks"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type stubNotifier struct {
+	email string
+	token string
+	err   error
+}
+
+func (n *stubNotifier) SendPasswordReset(_ context.Context, email string, token string) error {
+	n.email, n.token = email, token
+	return n.err
+}
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	policy := PasswordPolicy{MinLength: 8, RequireMixedCase: true, RequireDigit: true, RequireSymbol: true}
+
+	tests := []struct {
+		name     string
+		password string
+		wantErr  string
+	}{
+		{name: "too short", password: "Ab1!", wantErr: "at least 8 characters"},
+		{name: "too long", password: "Ab1!" + strings.Repeat("a", 69), wantErr: "at most 72 bytes"},
+		{name: "no upper case", password: "abcdef1!", wantErr: "upper and lower case"},
+		{name: "no digit", password: "Abcdefg!", wantErr: "a digit"},
+		{name: "no symbol", password: "Abcdefg1", wantErr: "a symbol"},
+		{name: "strong", password: "Abcdef1!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Validate(tt.password)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, models.ErrWeakPassword)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+
+	assert.NoError(t, PasswordPolicy{}.Validate("x"))
+}
+
+func TestAuthService_ChangePassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mocks.NewMockTransactionManager(ctrl)
+	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	mockTokenRepo := mocks.NewMockTokenRepositoryInterface(ctrl)
+	service := NewAuthService(mockTxManager, mockUserRepo, mockTokenRepo, nil, nil, nil, nil, nil, nil, testKeys, nil, testHasher,
+		Config{PasswordPolicy: PasswordPolicy{MinLength: 8, RequireDigit: true}})
+
+	userId := uuid.New()
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	user := &models.User{ID: userId, Email: "user@example.com", Password: string(hashedPassword), Role: dto.UserRoleEmployee}
+	userCtx := models.WithPrincipal(context.Background(), models.Principal{UserID: userId, Role: dto.UserRoleEmployee})
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		request     dto.PostMePasswordJSONRequestBody
+		mockActions func()
+		wantErr     error
+	}{
+		{
+			name:    "dummy user",
+			ctx:     models.WithPrincipal(context.Background(), models.Principal{UserID: models.DummyUserID, Role: dto.UserRoleEmployee}),
+			request: dto.PostMePasswordJSONRequestBody{CurrentPassword: "password123", NewPassword: "newpassword1"},
+			wantErr: models.ErrUserNotFound,
+		},
+		{
+			name:    "weak new password",
+			ctx:     userCtx,
+			request: dto.PostMePasswordJSONRequestBody{CurrentPassword: "password123", NewPassword: "short"},
+			wantErr: models.ErrWeakPassword,
+		},
+		{
+			name:    "wrong current password",
+			ctx:     userCtx,
+			request: dto.PostMePasswordJSONRequestBody{CurrentPassword: "wrong", NewPassword: "newpassword1"},
+			mockActions: func() {
+				mockUserRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(user, nil)
+			},
+			wantErr: models.ErrInvalidCredentials,
+		},
+		{
+			name:    "success revokes sessions",
+			ctx:     userCtx,
+			request: dto.PostMePasswordJSONRequestBody{CurrentPassword: "password123", NewPassword: "newpassword1"},
+			mockActions: func() {
+				mockUserRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(user, nil)
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				mockUserRepo.EXPECT().UpdatePassword(gomock.Any(), userId, gomock.Any()).DoAndReturn(
+					func(_ context.Context, _ uuid.UUID, passwordHash string) error {
+						assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte("newpassword1")))
+						return nil
+					})
+				mockTokenRepo.EXPECT().UseUserPasswordResetTokens(gomock.Any(), userId).Return(nil)
+				mockTokenRepo.EXPECT().RevokeUserRefreshTokens(gomock.Any(), userId).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockActions != nil {
+				tt.mockActions()
+			}
+
+			err := service.ChangePassword(tt.ctx, tt.request)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAuthService_RequestPasswordReset(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mocks.NewMockTransactionManager(ctrl)
+	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	mockTokenRepo := mocks.NewMockTokenRepositoryInterface(ctrl)
+	notifier := &stubNotifier{}
+	service := NewAuthService(mockTxManager, mockUserRepo, mockTokenRepo, nil, nil, nil, nil, nil, nil, testKeys, notifier, testHasher, Config{})
+
+	const email = types.Email("user@example.com")
+	user := &models.User{ID: uuid.New(), Email: email, Role: dto.UserRoleEmployee, Active: true}
+
+	assert.ErrorIs(t, service.RequestPasswordReset(context.Background(), dto.PostPasswordResetJSONRequestBody{}),
+		models.ErrEmptyEmailOrPassword)
+
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(nil, models.ErrUserNotFound)
+	assert.NoError(t, service.RequestPasswordReset(context.Background(), dto.PostPasswordResetJSONRequestBody{Email: email}))
+	assert.Empty(t, notifier.token)
+
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(&models.User{ID: user.ID, Email: email}, nil)
+	assert.NoError(t, service.RequestPasswordReset(context.Background(), dto.PostPasswordResetJSONRequestBody{Email: email}))
+	assert.Empty(t, notifier.token)
+
+	var stored *models.PasswordResetToken
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(user, nil)
+	mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+	mockTokenRepo.EXPECT().CreatePasswordResetToken(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, token *models.PasswordResetToken) error {
+			stored = token
+			return nil
+		})
+	assert.NoError(t, service.RequestPasswordReset(context.Background(), dto.PostPasswordResetJSONRequestBody{Email: email}))
+
+	assert.Equal(t, string(email), notifier.email)
+	assert.NotEmpty(t, notifier.token)
+	assert.Equal(t, user.ID, stored.UserID)
+	assert.Equal(t, hashSecret(notifier.token), stored.TokenHash)
+	assert.WithinDuration(t, time.Now().UTC().Add(resetTokenTTL), stored.ExpiresAt, time.Second)
+}
+
+func TestAuthService_ResetPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mocks.NewMockTransactionManager(ctrl)
+	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	mockTokenRepo := mocks.NewMockTokenRepositoryInterface(ctrl)
+	mockLoginAttemptRepo := mocks.NewMockLoginAttemptRepositoryInterface(ctrl)
+	service := NewAuthService(mockTxManager, mockUserRepo, mockTokenRepo, nil, nil, nil, mockLoginAttemptRepo, nil, nil, testKeys, nil, testHasher,
+		Config{PasswordPolicy: PasswordPolicy{MinLength: 8}})
+
+	const rawToken = "reset-token"
+	userId := uuid.New()
+	user := &models.User{ID: userId, Email: "User@Example.com", Role: dto.UserRoleEmployee}
+	now := time.Now().UTC()
+	validToken := &models.PasswordResetToken{ID: uuid.New(), UserID: userId, TokenHash: hashSecret(rawToken), ExpiresAt: now.Add(time.Hour)}
+	usedToken := *validToken
+	usedToken.UsedAt = &now
+	expiredToken := *validToken
+	expiredToken.ExpiresAt = now.Add(-time.Minute)
+
+	expectToken := func(token *models.PasswordResetToken, err error) {
+		mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+		mockTokenRepo.EXPECT().GetPasswordResetTokenByHash(gomock.Any(), hashSecret(rawToken)).Return(token, err)
+	}
+
+	tests := []struct {
+		name        string
+		request     dto.PostPasswordResetConfirmJSONRequestBody
+		mockActions func()
+		wantErr     error
+	}{
+		{
+			name:    "empty token",
+			request: dto.PostPasswordResetConfirmJSONRequestBody{NewPassword: "newpassword"},
+			wantErr: models.ErrInvalidResetToken,
+		},
+		{
+			name:    "weak password",
+			request: dto.PostPasswordResetConfirmJSONRequestBody{Token: rawToken, NewPassword: "short"},
+			wantErr: models.ErrWeakPassword,
+		},
+		{
+			name:    "unknown token",
+			request: dto.PostPasswordResetConfirmJSONRequestBody{Token: rawToken, NewPassword: "newpassword"},
+			mockActions: func() {
+				expectToken(nil, models.ErrResetTokenNotFound)
+			},
+			wantErr: models.ErrInvalidResetToken,
+		},
+		{
+			name:    "used token",
+			request: dto.PostPasswordResetConfirmJSONRequestBody{Token: rawToken, NewPassword: "newpassword"},
+			mockActions: func() {
+				expectToken(&usedToken, nil)
+			},
+			wantErr: models.ErrInvalidResetToken,
+		},
+		{
+			name:    "expired token",
+			request: dto.PostPasswordResetConfirmJSONRequestBody{Token: rawToken, NewPassword: "newpassword"},
+			mockActions: func() {
+				expectToken(&expiredToken, nil)
+			},
+			wantErr: models.ErrInvalidResetToken,
+		},
+		{
+			name:    "token used concurrently",
+			request: dto.PostPasswordResetConfirmJSONRequestBody{Token: rawToken, NewPassword: "newpassword"},
+			mockActions: func() {
+				expectToken(validToken, nil)
+				mockTokenRepo.EXPECT().UsePasswordResetToken(gomock.Any(), validToken.ID).Return(models.ErrResetTokenNotFound)
+			},
+			wantErr: models.ErrInvalidResetToken,
+		},
+		{
+			name:    "storage failure",
+			request: dto.PostPasswordResetConfirmJSONRequestBody{Token: rawToken, NewPassword: "newpassword"},
+			mockActions: func() {
+				expectToken(nil, errors.New("db error"))
+			},
+			wantErr: errors.New("db error"),
+		},
+		{
+			name:    "success unlocks account",
+			request: dto.PostPasswordResetConfirmJSONRequestBody{Token: rawToken, NewPassword: "newpassword"},
+			mockActions: func() {
+				expectToken(validToken, nil)
+				mockTokenRepo.EXPECT().UsePasswordResetToken(gomock.Any(), validToken.ID).Return(nil)
+				mockUserRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(user, nil)
+				mockUserRepo.EXPECT().UpdatePassword(gomock.Any(), userId, gomock.Any()).Return(nil)
+				mockTokenRepo.EXPECT().UseUserPasswordResetTokens(gomock.Any(), userId).Return(nil)
+				mockTokenRepo.EXPECT().RevokeUserRefreshTokens(gomock.Any(), userId).Return(nil)
+				mockLoginAttemptRepo.EXPECT().ResetLoginFailures(gomock.Any(), "email:user@example.com").Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockActions != nil {
+				tt.mockActions()
+			}
+
+			err := service.ResetPassword(context.Background(), tt.request)
+			switch {
+			case tt.wantErr == nil:
+				assert.NoError(t, err)
+			case errors.Is(tt.wantErr, models.ErrWeakPassword) || errors.Is(tt.wantErr, models.ErrInvalidResetToken):
+				assert.ErrorIs(t, err, tt.wantErr)
+			default:
+				assert.Equal(t, tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+	"github.com/itisalisas/avito-backend/internal/oidc"
+)
+
+// ssoStateTTL bounds how long the user may take to sign in at the provider.
+const ssoStateTTL = 10 * time.Minute
+
+// IdentityProvider is an OpenID Connect provider users can sign in with.
+type IdentityProvider interface {
+	Issuer() string
+	AuthCodeURL(state, nonce, codeVerifier string) string
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*oidc.Claims, error)
+}
+
+// SSOConfig configures single sign-on with an OpenID Connect provider.
+type SSOConfig struct {
+	// Provider is nil when single sign-on is disabled.
+	Provider IdentityProvider
+	// RoleClaim names the claim, a string or a list of strings such as
+	// groups, that RoleMapping translates into a role.
+	RoleClaim   string
+	RoleMapping map[string]dto.UserRole
+	// DefaultRole is given to users none of whose claim values are mapped.
+	// If empty, such users cannot sign in.
+	DefaultRole dto.UserRole
+}
+
+// StartSSOLogin returns the URL of the provider's login page. The state,
+// nonce and PKCE verifier of the login are kept until the provider
+// redirects back.
+func (s *Service) StartSSOLogin(ctx context.Context) (string, error) {
+	provider := s.config.SSO.Provider
+	if provider == nil {
+		return "", models.ErrSSODisabled
+	}
+
+	state, err := generateSecret()
+	if err != nil {
+		return "", err
+	}
+	nonce, err := generateSecret()
+	if err != nil {
+		return "", err
+	}
+	verifier, err := generateSecret()
+	if err != nil {
+		return "", err
+	}
+
+	err = s.identityRepo.CreateOIDCLoginState(ctx, &models.OIDCLoginState{
+		StateHash:    hashSecret(state),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().UTC().Add(ssoStateTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return provider.AuthCodeURL(state, nonce, verifier), nil
+}
+
+// CompleteSSOLogin handles the redirect back from the provider. The user is
+// found by the provider's subject, linked by email if the provider verified
+// it, or provisioned otherwise. The role follows the provider's claims on
+// every login, except that admins keep theirs, and a second factor is asked
+// for just as with a password.
+func (s *Service) CompleteSSOLogin(ctx context.Context, params dto.GetOidcCallbackParams) (*LoginResult, error) {
+	provider := s.config.SSO.Provider
+	if provider == nil {
+		return nil, models.ErrSSODisabled
+	}
+
+	if params.Error != nil && *params.Error != "" {
+		return nil, fmt.Errorf("%w: %s", models.ErrSSOLoginFailed, *params.Error)
+	}
+	if params.State == nil || *params.State == "" || params.Code == nil || *params.Code == "" {
+		return nil, models.ErrInvalidSSOState
+	}
+
+	// Using the state removes it, so a redirect cannot be replayed.
+	loginState, err := s.identityRepo.UseOIDCLoginState(ctx, hashSecret(*params.State))
+	if errors.Is(err, models.ErrOIDCStateNotFound) {
+		return nil, models.ErrInvalidSSOState
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !time.Now().UTC().Before(loginState.ExpiresAt) {
+		return nil, models.ErrInvalidSSOState
+	}
+
+	claims, err := provider.Exchange(ctx, *params.Code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrSSOLoginFailed, err)
+	}
+
+	role, err := s.ssoRole(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	var user *models.User
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		user, err = s.ssoUser(ctx, provider.Issuer(), claims, role)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !user.Active {
+		return nil, models.ErrUserDeactivated
+	}
+
+	return s.finishLogin(ctx, user, "")
+}
+
+// ssoRole maps the role claim to the most privileged role any of its values
+// is mapped to.
+func (s *Service) ssoRole(claims *oidc.Claims) (dto.UserRole, error) {
+	config := s.config.SSO
+
+	best := -1
+	if config.RoleClaim != "" {
+		for _, value := range claims.Strings(config.RoleClaim) {
+			role, ok := config.RoleMapping[value]
+			if !ok {
+				continue
+			}
+			best = max(best, slices.Index(models.Roles, role))
+		}
+	}
+	if best >= 0 {
+		return models.Roles[best], nil
+	}
+
+	if config.DefaultRole != "" {
+		return config.DefaultRole, nil
+	}
+	return "", models.ErrSSORoleNotMapped
+}
+
+// ssoUser returns the user signed in by the provider, creating or linking
+// one on the first login. It must run inside a transaction.
+func (s *Service) ssoUser(ctx context.Context, issuer string, claims *oidc.Claims,
+	role dto.UserRole) (*models.User, error) {
+	identity, err := s.identityRepo.GetUserIdentity(ctx, issuer, claims.Subject)
+	switch {
+	case err == nil:
+		user, err := s.userRepo.GetUserById(ctx, identity.UserID)
+		if err != nil {
+			return nil, err
+		}
+		return user, s.syncSSORole(ctx, user, role)
+	case !errors.Is(err, models.ErrUserIdentityNotFound):
+		return nil, err
+	}
+
+	if claims.Email == "" {
+		return nil, fmt.Errorf("%w: the provider did not share an email", models.ErrSSOLoginFailed)
+	}
+	email := openapi_types.Email(claims.Email)
+
+	user, err := s.userRepo.GetUserByEmail(ctx, email)
+	switch {
+	case err == nil:
+		// Linking to an account with an address the provider has not
+		// verified would let anyone take that account over.
+		if !claims.EmailVerified {
+			return nil, models.ErrEmailAlreadyInUse
+		}
+		if err := s.syncSSORole(ctx, user, role); err != nil {
+			return nil, err
+		}
+	case errors.Is(err, models.ErrUserNotFound):
+		user, err = s.provisionSSOUser(ctx, email, role)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	err = s.identityRepo.CreateUserIdentity(ctx, &models.UserIdentity{
+		UserID:  user.ID,
+		Issuer:  issuer,
+		Subject: claims.Subject,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// syncSSORole gives the user the role mapped from the provider's claims.
+// Admin roles are granted in the service itself and are never changed, so a
+// provider group mapped to a lower role cannot demote an admin.
+func (s *Service) syncSSORole(ctx context.Context, user *models.User, role dto.UserRole) error {
+	if user.Role == role || user.Role == dto.UserRoleAdmin {
+		return nil
+	}
+	if err := s.userRepo.UpdateRole(ctx, user.ID, role); err != nil {
+		return err
+	}
+	user.Role = role
+	return nil
+}
+
+// provisionSSOUser creates a user who signs in only through the provider.
+// The password is random and never handed out, though the user may still set
+// one with a password reset.
+func (s *Service) provisionSSOUser(ctx context.Context, email openapi_types.Email,
+	role dto.UserRole) (*models.User, error) {
+	password, err := generateSecret()
+	if err != nil {
+		return nil, err
+	}
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{Email: email, Password: hash, Role: role}
+	if err := s.userRepo.CreateUser(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
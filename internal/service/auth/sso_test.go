@@ -0,0 +1,212 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+	"github.com/itisalisas/avito-backend/internal/oidc"
+	"github.com/itisalisas/avito-backend/internal/oidc/oidctest"
+	"github.com/itisalisas/avito-backend/internal/storage"
+	"github.com/itisalisas/avito-backend/internal/storage/memory"
+)
+
+// newSSOService runs the service on the in-memory storage against a stand-in
+// identity provider, so that the whole redirect flow is exercised.
+func newSSOService(t *testing.T, config Config) (*Service, *storage.Repositories, *oidctest.Server) {
+	idp, err := oidctest.NewServer("pvz", "secret")
+	require.NoError(t, err)
+	t.Cleanup(idp.Close)
+
+	provider, err := oidc.Discover(context.Background(), oidc.Config{
+		IssuerURL:    idp.Issuer(),
+		ClientID:     "pvz",
+		ClientSecret: "secret",
+		RedirectURL:  "http://pvz.local/oidc/callback",
+	})
+	require.NoError(t, err)
+
+	config.SSO.Provider = provider
+	if config.SSO.RoleClaim == "" {
+		config.SSO.RoleClaim = "groups"
+		config.SSO.RoleMapping = map[string]dto.UserRole{
+			"pvz-staff":      dto.UserRoleEmployee,
+			"pvz-moderators": dto.UserRoleModerator,
+		}
+	}
+
+	repos := memory.NewRepositories()
+	service := NewAuthService(repos.TxManager, repos.User, repos.Token, repos.Invite, repos.Pvz, repos.Assignment,
+		repos.LoginAttempt, repos.TwoFactor, repos.Identity, testKeys, nil, testHasher, config)
+	return service, repos, idp
+}
+
+// ssoCallback starts a login, signs in at the provider as user and returns
+// the parameters the provider redirects back with.
+func ssoCallback(t *testing.T, service *Service, idp *oidctest.Server, user map[string]any) dto.GetOidcCallbackParams {
+	authURL, err := service.StartSSOLogin(context.Background())
+	require.NoError(t, err)
+
+	idp.SetUser(user)
+	code, state, err := idp.SignIn(authURL)
+	require.NoError(t, err)
+	return dto.GetOidcCallbackParams{Code: &code, State: &state}
+}
+
+func TestAuthService_SSOProvisioning(t *testing.T) {
+	ctx := context.Background()
+	service, repos, idp := newSSOService(t, Config{})
+
+	staff := map[string]any{
+		"sub":    "staff-1",
+		"email":  "staff@example.com",
+		"groups": []string{"pvz-staff", "pvz-moderators", "unrelated"},
+	}
+	result, err := service.CompleteSSOLogin(ctx, ssoCallback(t, service, idp, staff))
+	require.NoError(t, err)
+	require.NotNil(t, result.Tokens)
+
+	user, err := repos.User.GetUserByEmail(ctx, "staff@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, dto.UserRoleModerator, user.Role, "the most privileged mapped role wins")
+	assert.NotNil(t, user.LastLoginAt)
+	assert.False(t, testHasher.Verify(user.Password, ""), "provisioned users get no usable password")
+
+	claims := parseClaims(t, result.Tokens.AccessToken)
+	assert.Equal(t, user.ID.String(), claims.Subject)
+	assert.Equal(t, dto.UserRoleModerator, claims.Role)
+
+	// The next login finds the user by subject, even with a changed email,
+	// and follows the groups of the provider.
+	staff["email"] = "renamed@example.com"
+	staff["groups"] = []string{"pvz-staff"}
+	result, err = service.CompleteSSOLogin(ctx, ssoCallback(t, service, idp, staff))
+	require.NoError(t, err)
+	assert.Equal(t, user.ID.String(), parseClaims(t, result.Tokens.AccessToken).Subject)
+
+	user, err = repos.User.GetUserById(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, dto.UserRoleEmployee, user.Role)
+}
+
+func TestAuthService_SSOLinking(t *testing.T) {
+	ctx := context.Background()
+	service, repos, idp := newSSOService(t, Config{})
+
+	local := &models.User{Email: "staff@example.com", Password: "hash", Role: dto.UserRoleEmployee}
+	require.NoError(t, repos.User.CreateUser(ctx, local))
+
+	staff := map[string]any{"sub": "staff-1", "email": "staff@example.com", "groups": "pvz-staff"}
+	_, err := service.CompleteSSOLogin(ctx, ssoCallback(t, service, idp, staff))
+	assert.ErrorIs(t, err, models.ErrEmailAlreadyInUse, "an unverified email must not take over an account")
+
+	staff["email_verified"] = true
+	result, err := service.CompleteSSOLogin(ctx, ssoCallback(t, service, idp, staff))
+	require.NoError(t, err)
+	assert.Equal(t, local.ID.String(), parseClaims(t, result.Tokens.AccessToken).Subject)
+
+	identity, err := repos.Identity.GetUserIdentity(ctx, idp.Issuer(), "staff-1")
+	require.NoError(t, err)
+	assert.Equal(t, local.ID, identity.UserID)
+
+	// An admin signing in through the provider is not demoted to the role of
+	// their groups.
+	admin := &models.User{Email: "admin@example.com", Password: "hash", Role: dto.UserRoleAdmin, Active: true}
+	require.NoError(t, repos.User.CreateUser(ctx, admin))
+	adminIdentity := map[string]any{"sub": "admin-1", "email": "admin@example.com", "email_verified": true, "groups": "pvz-staff"}
+	for range 2 {
+		_, err = service.CompleteSSOLogin(ctx, ssoCallback(t, service, idp, adminIdentity))
+		require.NoError(t, err)
+
+		stored, err := repos.User.GetUserById(ctx, admin.ID)
+		require.NoError(t, err)
+		assert.Equal(t, dto.UserRoleAdmin, stored.Role)
+	}
+}
+
+func TestAuthService_SSORoleMapping(t *testing.T) {
+	ctx := context.Background()
+	user := map[string]any{"sub": "contractor-1", "email": "contractor@example.com", "groups": []string{"contractors"}}
+
+	service, _, idp := newSSOService(t, Config{})
+	_, err := service.CompleteSSOLogin(ctx, ssoCallback(t, service, idp, user))
+	assert.ErrorIs(t, err, models.ErrSSORoleNotMapped)
+
+	service, repos, idp := newSSOService(t, Config{SSO: SSOConfig{
+		RoleClaim:   "groups",
+		DefaultRole: dto.UserRoleEmployee,
+	}})
+	_, err = service.CompleteSSOLogin(ctx, ssoCallback(t, service, idp, user))
+	require.NoError(t, err)
+
+	provisioned, err := repos.User.GetUserByEmail(ctx, "contractor@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, dto.UserRoleEmployee, provisioned.Role)
+}
+
+func TestAuthService_SSOState(t *testing.T) {
+	ctx := context.Background()
+	service, repos, idp := newSSOService(t, Config{})
+	staff := map[string]any{"sub": "staff-1", "email": "staff@example.com", "groups": "pvz-staff"}
+
+	params := ssoCallback(t, service, idp, staff)
+	_, err := service.CompleteSSOLogin(ctx, params)
+	require.NoError(t, err)
+	_, err = service.CompleteSSOLogin(ctx, params)
+	assert.ErrorIs(t, err, models.ErrInvalidSSOState, "a state works once")
+
+	unknown := "unknown"
+	_, err = service.CompleteSSOLogin(ctx, dto.GetOidcCallbackParams{Code: params.Code, State: &unknown})
+	assert.ErrorIs(t, err, models.ErrInvalidSSOState)
+	_, err = service.CompleteSSOLogin(ctx, dto.GetOidcCallbackParams{Code: params.Code})
+	assert.ErrorIs(t, err, models.ErrInvalidSSOState)
+
+	denied := "access_denied"
+	_, err = service.CompleteSSOLogin(ctx, dto.GetOidcCallbackParams{State: params.State, Error: &denied})
+	assert.ErrorIs(t, err, models.ErrSSOLoginFailed)
+
+	// A code redeemed with the state of another login fails PKCE.
+	params = ssoCallback(t, service, idp, staff)
+	other := ssoCallback(t, service, idp, staff)
+	_, err = service.CompleteSSOLogin(ctx, dto.GetOidcCallbackParams{Code: params.Code, State: other.State})
+	assert.ErrorIs(t, err, models.ErrSSOLoginFailed)
+
+	expired := &models.OIDCLoginState{StateHash: hashSecret("expired"), ExpiresAt: time.Now().Add(-time.Minute)}
+	require.NoError(t, repos.Identity.CreateOIDCLoginState(ctx, expired))
+	state := "expired"
+	_, err = service.CompleteSSOLogin(ctx, dto.GetOidcCallbackParams{Code: params.Code, State: &state})
+	assert.ErrorIs(t, err, models.ErrInvalidSSOState)
+}
+
+func TestAuthService_SSOTwoFactorAndDeactivation(t *testing.T) {
+	ctx := context.Background()
+	service, repos, idp := newSSOService(t, Config{RequireModeratorTwoFactor: true})
+	moderator := map[string]any{"sub": "moderator-1", "email": "moderator@example.com", "groups": "pvz-moderators"}
+
+	result, err := service.CompleteSSOLogin(ctx, ssoCallback(t, service, idp, moderator))
+	require.NoError(t, err)
+	assert.Nil(t, result.Tokens)
+	require.NotNil(t, result.Challenge)
+	assert.True(t, result.Challenge.EnrollmentRequired)
+
+	user, err := repos.User.GetUserByEmail(ctx, "moderator@example.com")
+	require.NoError(t, err)
+	require.NoError(t, repos.User.SetActive(ctx, user.ID, false))
+
+	_, err = service.CompleteSSOLogin(ctx, ssoCallback(t, service, idp, moderator))
+	assert.ErrorIs(t, err, models.ErrUserDeactivated)
+}
+
+func TestAuthService_SSODisabled(t *testing.T) {
+	service := NewAuthService(nil, nil, nil, nil, nil, nil, nil, nil, nil, testKeys, nil, testHasher, Config{})
+
+	_, err := service.StartSSOLogin(context.Background())
+	assert.ErrorIs(t, err, models.ErrSSODisabled)
+	_, err = service.CompleteSSOLogin(context.Background(), dto.GetOidcCallbackParams{})
+	assert.ErrorIs(t, err, models.ErrSSODisabled)
+}
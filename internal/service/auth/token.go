@@ -0,0 +1,188 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+const (
+	accessTokenTTL  = 15 * time.Minute
+	refreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// TokenSigner signs access tokens.
+type TokenSigner interface {
+	Sign(claims jwt.Claims) (string, error)
+}
+
+// Refresh exchanges a refresh token for a new token pair. Every refresh token
+// is single-use: presenting one that was already rotated revokes all refresh
+// tokens of its user, since it must have leaked.
+func (s *Service) Refresh(ctx context.Context, request dto.PostTokenRefreshJSONRequestBody) (*dto.TokenPair, error) {
+	if request.RefreshToken == "" {
+		return nil, models.ErrInvalidRefreshToken
+	}
+
+	var (
+		pair   *dto.TokenPair
+		reused bool
+	)
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		token, err := s.tokenRepo.GetRefreshTokenByHash(ctx, hashSecret(request.RefreshToken))
+		if errors.Is(err, models.ErrRefreshTokenNotFound) {
+			return models.ErrInvalidRefreshToken
+		}
+		if err != nil {
+			return err
+		}
+
+		if token.RevokedAt != nil {
+			// Not returned as an error so that the revocation is committed.
+			reused = true
+			return s.tokenRepo.RevokeUserRefreshTokens(ctx, token.UserID)
+		}
+		if !time.Now().UTC().Before(token.ExpiresAt) {
+			return models.ErrInvalidRefreshToken
+		}
+
+		user, err := s.userRepo.GetUserById(ctx, token.UserID)
+		if errors.Is(err, models.ErrUserNotFound) {
+			return models.ErrInvalidRefreshToken
+		}
+		if err != nil {
+			return err
+		}
+		if !user.Active {
+			return models.ErrInvalidRefreshToken
+		}
+
+		// A concurrent refresh with the same token may have rotated it since
+		// it was read.
+		err = s.tokenRepo.RevokeRefreshToken(ctx, token.ID)
+		if errors.Is(err, models.ErrRefreshTokenNotFound) {
+			return models.ErrInvalidRefreshToken
+		}
+		if err != nil {
+			return err
+		}
+
+		pair, err = s.issueTokenPair(ctx, user)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if reused {
+		return nil, models.ErrInvalidRefreshToken
+	}
+
+	return pair, nil
+}
+
+// Logout revokes the access token of the caller and, if given, one of the
+// caller's refresh tokens. Unknown refresh tokens are ignored. Service
+// accounts have no session to end.
+func (s *Service) Logout(ctx context.Context, request dto.PostLogoutJSONRequestBody) error {
+	principal, ok := models.PrincipalFromContext(ctx)
+	if !ok || principal.ServiceAccount {
+		return models.ErrUserNotFound
+	}
+
+	return s.txManager.Do(ctx, func(ctx context.Context) error {
+		err := s.tokenRepo.RevokeAccessToken(ctx, principal.TokenID, principal.TokenExpiresAt)
+		if err != nil || request.RefreshToken == nil {
+			return err
+		}
+
+		token, err := s.tokenRepo.GetRefreshTokenByHash(ctx, hashSecret(*request.RefreshToken))
+		switch {
+		case errors.Is(err, models.ErrRefreshTokenNotFound):
+			return nil
+		case err != nil:
+			return err
+		case token.UserID != principal.UserID:
+			return nil
+		}
+
+		err = s.tokenRepo.RevokeRefreshToken(ctx, token.ID)
+		if errors.Is(err, models.ErrRefreshTokenNotFound) {
+			return nil
+		}
+		return err
+	})
+}
+
+// IsTokenRevoked reports whether the access token with the given jti was
+// revoked before its expiry.
+func (s *Service) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	return s.tokenRepo.IsAccessTokenRevoked(ctx, jti)
+}
+
+func (s *Service) issueTokenPair(ctx context.Context, user *models.User) (*dto.TokenPair, error) {
+	accessToken, err := s.generateToken(models.Principal{
+		UserID: user.ID,
+		Email:  string(user.Email),
+		Role:   user.Role,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := generateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.tokenRepo.CreateRefreshToken(ctx, &models.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: hashSecret(refreshToken),
+		ExpiresAt: time.Now().UTC().Add(refreshTokenTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.TokenPair{AccessToken: *accessToken, RefreshToken: refreshToken}, nil
+}
+
+func (s *Service) generateToken(principal models.Principal) (*dto.Token, error) {
+	tokenString, err := s.signer.Sign(models.TokenClaims{
+		Role:  principal.Role,
+		Email: principal.Email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   principal.UserID.String(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(accessTokenTTL)),
+			ID:        uuid.New().String(),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tokenString, nil
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashSecret returns the form refresh tokens and invite codes are stored in.
+// Both are random, so a plain hash is enough.
+func hashSecret(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
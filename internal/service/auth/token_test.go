@@ -0,0 +1,222 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/generated/mocks"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+func TestAuthService_Refresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mocks.NewMockTransactionManager(ctrl)
+	mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	mockTokenRepo := mocks.NewMockTokenRepositoryInterface(ctrl)
+	service := NewAuthService(mockTxManager, mockRepo, mockTokenRepo, nil, nil, nil, nil, nil, nil, testKeys, nil, testHasher, Config{})
+
+	userId := uuid.New()
+	tokenId := uuid.New()
+	hash := hashSecret("refresh")
+	revokedAt := time.Now().UTC().Add(-time.Minute)
+	user := &models.User{ID: userId, Email: "test@example.com", Role: dto.UserRoleEmployee, Active: true}
+	validToken := &models.RefreshToken{ID: tokenId, UserID: userId, TokenHash: hash, ExpiresAt: time.Now().UTC().Add(time.Hour)}
+
+	tests := []struct {
+		name        string
+		token       string
+		mockActions func()
+		expectedErr error
+	}{
+		{
+			name:        "empty token",
+			mockActions: func() {},
+			expectedErr: models.ErrInvalidRefreshToken,
+		},
+		{
+			name:  "unknown token",
+			token: "refresh",
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockTokenRepo.EXPECT().GetRefreshTokenByHash(gomock.Any(), hash).Return(nil, models.ErrRefreshTokenNotFound).Times(1)
+			},
+			expectedErr: models.ErrInvalidRefreshToken,
+		},
+		{
+			name:  "expired token",
+			token: "refresh",
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockTokenRepo.EXPECT().GetRefreshTokenByHash(gomock.Any(), hash).Return(&models.RefreshToken{
+					ID: tokenId, UserID: userId, TokenHash: hash, ExpiresAt: time.Now().UTC().Add(-time.Minute),
+				}, nil).Times(1)
+			},
+			expectedErr: models.ErrInvalidRefreshToken,
+		},
+		{
+			name:  "reused token revokes every session",
+			token: "refresh",
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockTokenRepo.EXPECT().GetRefreshTokenByHash(gomock.Any(), hash).Return(&models.RefreshToken{
+					ID: tokenId, UserID: userId, TokenHash: hash, ExpiresAt: time.Now().UTC().Add(time.Hour), RevokedAt: &revokedAt,
+				}, nil).Times(1)
+				mockTokenRepo.EXPECT().RevokeUserRefreshTokens(gomock.Any(), userId).Return(nil).Times(1)
+			},
+			expectedErr: models.ErrInvalidRefreshToken,
+		},
+		{
+			name:  "deactivated user",
+			token: "refresh",
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockTokenRepo.EXPECT().GetRefreshTokenByHash(gomock.Any(), hash).Return(validToken, nil).Times(1)
+				mockRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(&models.User{ID: userId, Role: dto.UserRoleEmployee}, nil).Times(1)
+			},
+			expectedErr: models.ErrInvalidRefreshToken,
+		},
+		{
+			name:  "concurrent rotation",
+			token: "refresh",
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockTokenRepo.EXPECT().GetRefreshTokenByHash(gomock.Any(), hash).Return(validToken, nil).Times(1)
+				mockRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(user, nil).Times(1)
+				mockTokenRepo.EXPECT().RevokeRefreshToken(gomock.Any(), tokenId).Return(models.ErrRefreshTokenNotFound).Times(1)
+			},
+			expectedErr: models.ErrInvalidRefreshToken,
+		},
+		{
+			name:  "rotate token",
+			token: "refresh",
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockTokenRepo.EXPECT().GetRefreshTokenByHash(gomock.Any(), hash).Return(validToken, nil).Times(1)
+				mockRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(user, nil).Times(1)
+				mockTokenRepo.EXPECT().RevokeRefreshToken(gomock.Any(), tokenId).Return(nil).Times(1)
+				mockTokenRepo.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, token *models.RefreshToken) error {
+						assert.Equal(t, userId, token.UserID)
+						assert.NotEqual(t, hash, token.TokenHash)
+						return nil
+					}).Times(1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockActions()
+
+			pair, err := service.Refresh(context.Background(), dto.PostTokenRefreshJSONRequestBody{RefreshToken: tt.token})
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Nil(t, pair)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotEqual(t, "refresh", pair.RefreshToken)
+			assert.Equal(t, userId.String(), parseClaims(t, pair.AccessToken).Subject)
+		})
+	}
+}
+
+func TestAuthService_Logout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mocks.NewMockTransactionManager(ctrl)
+	mockTokenRepo := mocks.NewMockTokenRepositoryInterface(ctrl)
+	service := NewAuthService(mockTxManager, mocks.NewMockUserRepositoryInterface(ctrl), mockTokenRepo, nil, nil, nil, nil, nil, nil, testKeys, nil, testHasher, Config{})
+
+	userId := uuid.New()
+	tokenId := uuid.New()
+	expiresAt := time.Now().Add(time.Minute)
+	principal := models.Principal{UserID: userId, Role: dto.UserRoleEmployee, TokenID: "jti", TokenExpiresAt: expiresAt}
+	refreshToken := "refresh"
+	hash := hashSecret(refreshToken)
+
+	tests := []struct {
+		name        string
+		principal   *models.Principal
+		request     dto.PostLogoutJSONRequestBody
+		mockActions func()
+		expectedErr error
+	}{
+		{
+			name:        "no principal",
+			mockActions: func() {},
+			expectedErr: models.ErrUserNotFound,
+		},
+		{
+			name:        "service account",
+			principal:   &models.Principal{UserID: uuid.New(), Role: dto.UserRoleEmployee, ServiceAccount: true},
+			mockActions: func() {},
+			expectedErr: models.ErrUserNotFound,
+		},
+		{
+			name:      "access token only",
+			principal: &principal,
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockTokenRepo.EXPECT().RevokeAccessToken(gomock.Any(), "jti", expiresAt).Return(nil).Times(1)
+			},
+		},
+		{
+			name:      "with refresh token",
+			principal: &principal,
+			request:   dto.PostLogoutJSONRequestBody{RefreshToken: &refreshToken},
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockTokenRepo.EXPECT().RevokeAccessToken(gomock.Any(), "jti", expiresAt).Return(nil).Times(1)
+				mockTokenRepo.EXPECT().GetRefreshTokenByHash(gomock.Any(), hash).
+					Return(&models.RefreshToken{ID: tokenId, UserID: userId}, nil).Times(1)
+				mockTokenRepo.EXPECT().RevokeRefreshToken(gomock.Any(), tokenId).Return(nil).Times(1)
+			},
+		},
+		{
+			name:      "refresh token of another user",
+			principal: &principal,
+			request:   dto.PostLogoutJSONRequestBody{RefreshToken: &refreshToken},
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockTokenRepo.EXPECT().RevokeAccessToken(gomock.Any(), "jti", expiresAt).Return(nil).Times(1)
+				mockTokenRepo.EXPECT().GetRefreshTokenByHash(gomock.Any(), hash).
+					Return(&models.RefreshToken{ID: tokenId, UserID: uuid.New()}, nil).Times(1)
+			},
+		},
+		{
+			name:      "revocation failure",
+			principal: &principal,
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockTokenRepo.EXPECT().RevokeAccessToken(gomock.Any(), "jti", expiresAt).Return(errors.New("db error")).Times(1)
+			},
+			expectedErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockActions()
+
+			ctx := context.Background()
+			if tt.principal != nil {
+				ctx = models.WithPrincipal(ctx, *tt.principal)
+			}
+
+			err := service.Logout(ctx, tt.request)
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
@@ -0,0 +1,344 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+	"github.com/itisalisas/avito-backend/internal/totp"
+)
+
+const (
+	loginChallengeTTL = 5 * time.Minute
+	// maxLoginChallengeAttempts is how many wrong codes a challenge takes
+	// before the password has to be entered again.
+	maxLoginChallengeAttempts = 5
+	recoveryCodeCount         = 10
+	defaultTOTPIssuer         = "PVZ Service"
+)
+
+// recoveryCodeAlphabet leaves out characters that are easily confused when
+// the codes are copied by hand.
+const recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+// LoginResult is the outcome of a correct password: either the tokens, or a
+// challenge to complete with a second factor.
+type LoginResult struct {
+	Tokens    *dto.TokenPair
+	Challenge *dto.LoginChallenge
+}
+
+// twoFactorRequired reports whether users of the role may not log in with a
+// password alone.
+func (s *Service) twoFactorRequired(role dto.UserRole) bool {
+	return s.config.RequireModeratorTwoFactor && (role == dto.UserRoleModerator || role == dto.UserRoleAdmin)
+}
+
+// createLoginChallenge must run inside a transaction.
+func (s *Service) createLoginChallenge(ctx context.Context, userId uuid.UUID,
+	enrollmentRequired bool) (*dto.LoginChallenge, error) {
+	token, err := generateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().UTC().Add(loginChallengeTTL)
+	err = s.twoFactorRepo.CreateLoginChallenge(ctx, &models.LoginChallenge{
+		UserID:    userId,
+		TokenHash: hashSecret(token),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.LoginChallenge{
+		ChallengeToken:     token,
+		ExpiresAt:          expiresAt,
+		EnrollmentRequired: enrollmentRequired,
+	}, nil
+}
+
+// CompleteLogin exchanges a login challenge and a code for tokens. The code is
+// either a TOTP code or an unused recovery code. If the user was still
+// enrolling, a valid TOTP code confirms the enrollment. Wrong codes count as
+// failed logins of the account, just as wrong passwords do.
+func (s *Service) CompleteLogin(ctx context.Context, request dto.PostLogin2faJSONRequestBody) (*dto.TokenPair, error) {
+	challenge, user, err := s.loginChallengeUser(ctx, request.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if !user.Active {
+		return nil, models.ErrUserDeactivated
+	}
+
+	userTOTP, err := s.twoFactorRepo.GetTOTP(ctx, user.ID)
+	if err != nil && !errors.Is(err, models.ErrTOTPNotFound) {
+		return nil, err
+	}
+
+	var pair *dto.TokenPair
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		if err := s.verifySecondFactor(ctx, user.ID, userTOTP, request.Code); err != nil {
+			return err
+		}
+
+		// A concurrent attempt with the same challenge may have used it
+		// since it was read.
+		err := s.twoFactorRepo.UseLoginChallenge(ctx, challenge.ID)
+		if errors.Is(err, models.ErrLoginChallengeNotFound) {
+			return models.ErrInvalidLoginChallenge
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := s.userRepo.UpdateLastLogin(ctx, user.ID, time.Now().UTC()); err != nil {
+			return err
+		}
+		pair, err = s.issueTokenPair(ctx, user)
+		return err
+	})
+	if errors.Is(err, models.ErrInvalidTOTPCode) {
+		// Counted outside the transaction, which has been rolled back.
+		if _, err := s.twoFactorRepo.RecordLoginChallengeAttempt(ctx, challenge.ID); err != nil {
+			return nil, err
+		}
+		if err := s.recordLoginFailure(ctx, loginSubjects(string(user.Email), "")); err != nil {
+			return nil, err
+		}
+		return nil, models.ErrInvalidTOTPCode
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loginAttemptRepo.ResetLoginFailures(ctx, accountSubject(string(user.Email))); err != nil {
+		return nil, err
+	}
+	return pair, nil
+}
+
+// StartLoginEnrollment lets a user who must use two-factor authentication
+// but has not set it up yet enroll with a login challenge, since such a user
+// cannot get tokens to call StartTOTPEnrollment.
+func (s *Service) StartLoginEnrollment(ctx context.Context,
+	request dto.PostLogin2faEnrollJSONRequestBody) (*dto.TotpEnrollment, error) {
+	_, user, err := s.loginChallengeUser(ctx, request.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if !user.Active {
+		return nil, models.ErrUserDeactivated
+	}
+	return s.enrollTOTP(ctx, user)
+}
+
+// StartTOTPEnrollment generates a new TOTP secret and recovery codes for the
+// authenticated user. They take effect once confirmed with ConfirmTOTP.
+func (s *Service) StartTOTPEnrollment(ctx context.Context) (*dto.TotpEnrollment, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.enrollTOTP(ctx, user)
+}
+
+// ConfirmTOTP enables two-factor authentication for the authenticated user
+// with the first code of the enrolled secret.
+func (s *Service) ConfirmTOTP(ctx context.Context, request dto.PostMe2faConfirmJSONRequestBody) error {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	userTOTP, err := s.twoFactorRepo.GetTOTP(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if userTOTP.Confirmed() {
+		return models.ErrTOTPAlreadyEnabled
+	}
+
+	return s.txManager.Do(ctx, func(ctx context.Context) error {
+		return s.verifySecondFactor(ctx, user.ID, userTOTP, request.Code)
+	})
+}
+
+// DisableTOTP turns two-factor authentication off for the authenticated user,
+// who has to prove possession of the second factor once more.
+func (s *Service) DisableTOTP(ctx context.Context, request dto.PostMe2faDisableJSONRequestBody) error {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return err
+	}
+	if s.twoFactorRequired(user.Role) {
+		return models.ErrTOTPRequired
+	}
+
+	userTOTP, err := s.twoFactorRepo.GetTOTP(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if !userTOTP.Confirmed() {
+		return models.ErrTOTPNotFound
+	}
+
+	return s.txManager.Do(ctx, func(ctx context.Context) error {
+		if err := s.verifySecondFactor(ctx, user.ID, userTOTP, request.Code); err != nil {
+			return err
+		}
+		return s.twoFactorRepo.DeleteTOTP(ctx, user.ID)
+	})
+}
+
+// currentUser returns the authenticated user. The dummy identity and service
+// accounts have no account to protect.
+func (s *Service) currentUser(ctx context.Context) (*models.User, error) {
+	principal, ok := models.PrincipalFromContext(ctx)
+	if !ok || principal.IsDummy() || principal.ServiceAccount {
+		return nil, models.ErrUserNotFound
+	}
+	return s.userRepo.GetUserById(ctx, principal.UserID)
+}
+
+// loginChallengeUser returns a login challenge that can still be completed
+// together with its user. Unknown, used, expired and exhausted challenges are
+// all reported as ErrInvalidLoginChallenge.
+func (s *Service) loginChallengeUser(ctx context.Context, token string) (*models.LoginChallenge, *models.User, error) {
+	if token == "" {
+		return nil, nil, models.ErrInvalidLoginChallenge
+	}
+
+	challenge, err := s.twoFactorRepo.GetLoginChallengeByHash(ctx, hashSecret(token))
+	if errors.Is(err, models.ErrLoginChallengeNotFound) {
+		return nil, nil, models.ErrInvalidLoginChallenge
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if challenge.UsedAt != nil || challenge.Attempts >= maxLoginChallengeAttempts ||
+		!time.Now().UTC().Before(challenge.ExpiresAt) {
+		return nil, nil, models.ErrInvalidLoginChallenge
+	}
+
+	user, err := s.userRepo.GetUserById(ctx, challenge.UserID)
+	if errors.Is(err, models.ErrUserNotFound) {
+		return nil, nil, models.ErrInvalidLoginChallenge
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return challenge, user, nil
+}
+
+// enrollTOTP replaces any unconfirmed secret of the user. Users who already
+// have two-factor authentication enabled must disable it first.
+func (s *Service) enrollTOTP(ctx context.Context, user *models.User) (*dto.TotpEnrollment, error) {
+	userTOTP, err := s.twoFactorRepo.GetTOTP(ctx, user.ID)
+	if err != nil && !errors.Is(err, models.ErrTOTPNotFound) {
+		return nil, err
+	}
+	if userTOTP.Confirmed() {
+		return nil, models.ErrTOTPAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	codes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	hashes := make([]string, 0, len(codes))
+	for _, code := range codes {
+		hashes = append(hashes, hashSecret(code))
+	}
+
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		if err := s.twoFactorRepo.SaveTOTP(ctx, &models.TOTP{UserID: user.ID, Secret: secret}); err != nil {
+			return err
+		}
+		return s.twoFactorRepo.ReplaceRecoveryCodes(ctx, user.ID, hashes)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	issuer := s.config.TOTPIssuer
+	if issuer == "" {
+		issuer = defaultTOTPIssuer
+	}
+	return &dto.TotpEnrollment{
+		Secret:          secret,
+		ProvisioningUri: totp.ProvisioningURI(secret, issuer, string(user.Email)),
+		RecoveryCodes:   codes,
+	}, nil
+}
+
+// verifySecondFactor accepts a TOTP code, which also confirms an unconfirmed
+// secret, or a recovery code once the secret is confirmed. Every code works
+// only once. Wrong codes are reported as ErrInvalidTOTPCode. It must run
+// inside a transaction.
+func (s *Service) verifySecondFactor(ctx context.Context, userId uuid.UUID, userTOTP *models.TOTP, code string) error {
+	if userTOTP == nil {
+		return models.ErrInvalidTOTPCode
+	}
+
+	now := time.Now().UTC()
+	if step, ok := totp.Validate(userTOTP.Secret, strings.TrimSpace(code), now); ok {
+		if err := s.twoFactorRepo.UseTOTPStep(ctx, userId, step); err != nil {
+			return err
+		}
+		if userTOTP.Confirmed() {
+			return nil
+		}
+		return s.twoFactorRepo.ConfirmTOTP(ctx, userId, now)
+	}
+
+	if !userTOTP.Confirmed() {
+		return models.ErrInvalidTOTPCode
+	}
+	err := s.twoFactorRepo.UseRecoveryCode(ctx, userId, hashSecret(normalizeRecoveryCode(code)))
+	if errors.Is(err, models.ErrRecoveryCodeNotFound) {
+		return models.ErrInvalidTOTPCode
+	}
+	return err
+}
+
+// generateRecoveryCodes returns codes of the form xxxxx-xxxxx.
+func generateRecoveryCodes() ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		b := make([]byte, 10)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+
+		var code strings.Builder
+		for i, c := range b {
+			if i == len(b)/2 {
+				code.WriteByte('-')
+			}
+			// 256 is not a multiple of the alphabet size, but the bias is
+			// negligible for codes of this length.
+			code.WriteByte(recoveryCodeAlphabet[int(c)%len(recoveryCodeAlphabet)])
+		}
+		codes = append(codes, code.String())
+	}
+	return codes, nil
+}
+
+// normalizeRecoveryCode forgives the case and surrounding whitespace of a
+// recovery code typed by hand.
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.TrimSpace(code))
+}
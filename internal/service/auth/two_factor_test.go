@@ -0,0 +1,471 @@
+package auth
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/oapi-codegen/runtime/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/generated/mocks"
+	"github.com/itisalisas/avito-backend/internal/models"
+	"github.com/itisalisas/avito-backend/internal/totp"
+)
+
+// withoutTOTP expects lookups of users that have not set up two-factor
+// authentication.
+func withoutTOTP(repo *mocks.MockTwoFactorRepositoryInterface) {
+	repo.EXPECT().GetTOTP(gomock.Any(), gomock.Any()).Return(nil, models.ErrTOTPNotFound).AnyTimes()
+}
+
+func TestAuthService_LoginTwoFactor(t *testing.T) {
+	const password = "password123"
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	confirmedAt := time.Now().UTC()
+
+	tests := []struct {
+		name           string
+		role           dto.UserRole
+		totp           *models.TOTP
+		config         Config
+		wantChallenge  bool
+		wantEnrollment bool
+	}{
+		{
+			name: "no second factor",
+			role: dto.UserRoleModerator,
+		},
+		{
+			name: "unconfirmed secret is ignored",
+			role: dto.UserRoleModerator,
+			totp: &models.TOTP{Secret: "SECRET"},
+		},
+		{
+			name:          "confirmed secret",
+			role:          dto.UserRoleEmployee,
+			totp:          &models.TOTP{Secret: "SECRET", ConfirmedAt: &confirmedAt},
+			wantChallenge: true,
+		},
+		{
+			name:           "required for moderators",
+			role:           dto.UserRoleModerator,
+			config:         Config{RequireModeratorTwoFactor: true},
+			wantChallenge:  true,
+			wantEnrollment: true,
+		},
+		{
+			name:           "required for admins",
+			role:           dto.UserRoleAdmin,
+			config:         Config{RequireModeratorTwoFactor: true},
+			wantChallenge:  true,
+			wantEnrollment: true,
+		},
+		{
+			name:   "not required for employees",
+			role:   dto.UserRoleEmployee,
+			config: Config{RequireModeratorTwoFactor: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockTxManager := mocks.NewMockTransactionManager(ctrl)
+			mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+			mockTokenRepo := mocks.NewMockTokenRepositoryInterface(ctrl)
+			mockLoginAttemptRepo := mocks.NewMockLoginAttemptRepositoryInterface(ctrl)
+			mockTwoFactorRepo := mocks.NewMockTwoFactorRepositoryInterface(ctrl)
+			service := NewAuthService(mockTxManager, mockUserRepo, mockTokenRepo, nil, nil, nil, mockLoginAttemptRepo,
+				mockTwoFactorRepo, nil, testKeys, nil, testHasher, tt.config)
+
+			user := &models.User{ID: uuid.New(), Email: "user@example.com", Password: string(hashedPassword), Role: tt.role, Active: true}
+			allowLogin(mockLoginAttemptRepo)
+			mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), types.Email("user@example.com")).Return(user, nil)
+			if tt.totp != nil {
+				mockTwoFactorRepo.EXPECT().GetTOTP(gomock.Any(), user.ID).Return(tt.totp, nil)
+			} else {
+				mockTwoFactorRepo.EXPECT().GetTOTP(gomock.Any(), user.ID).Return(nil, models.ErrTOTPNotFound)
+			}
+			mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+			if tt.wantChallenge {
+				mockTwoFactorRepo.EXPECT().CreateLoginChallenge(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, challenge *models.LoginChallenge) error {
+						assert.Equal(t, user.ID, challenge.UserID)
+						assert.WithinDuration(t, time.Now().UTC().Add(loginChallengeTTL), challenge.ExpiresAt, time.Second)
+						return nil
+					})
+			} else {
+				// The failures are kept while a second factor is pending.
+				mockLoginAttemptRepo.EXPECT().ResetLoginFailures(gomock.Any(), "email:user@example.com").Return(nil)
+				mockUserRepo.EXPECT().UpdateLastLogin(gomock.Any(), user.ID, gomock.Any()).Return(nil)
+				mockTokenRepo.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			result, err := service.Login(context.Background(), dto.PostLoginJSONRequestBody{Email: "user@example.com", Password: password}, "")
+			require.NoError(t, err)
+			if tt.wantChallenge {
+				assert.Nil(t, result.Tokens)
+				require.NotNil(t, result.Challenge)
+				assert.NotEmpty(t, result.Challenge.ChallengeToken)
+				assert.Equal(t, tt.wantEnrollment, result.Challenge.EnrollmentRequired)
+			} else {
+				assert.Nil(t, result.Challenge)
+				assert.NotEmpty(t, result.Tokens.AccessToken)
+			}
+		})
+	}
+}
+
+func TestAuthService_CompleteLogin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mocks.NewMockTransactionManager(ctrl)
+	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	mockTokenRepo := mocks.NewMockTokenRepositoryInterface(ctrl)
+	mockLoginAttemptRepo := mocks.NewMockLoginAttemptRepositoryInterface(ctrl)
+	mockTwoFactorRepo := mocks.NewMockTwoFactorRepositoryInterface(ctrl)
+	service := NewAuthService(mockTxManager, mockUserRepo, mockTokenRepo, nil, nil, nil, mockLoginAttemptRepo, mockTwoFactorRepo,
+		nil, testKeys, nil, testHasher, Config{})
+
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	code, err := totp.Code(secret, time.Now())
+	require.NoError(t, err)
+
+	const challengeToken = "challenge"
+	userId := uuid.New()
+	user := &models.User{ID: userId, Email: "moderator@example.com", Role: dto.UserRoleModerator, Active: true}
+	challengeId := uuid.New()
+	confirmedAt := time.Now().UTC().Add(-time.Hour)
+	confirmed := &models.TOTP{UserID: userId, Secret: secret, ConfirmedAt: &confirmedAt}
+	pending := &models.TOTP{UserID: userId, Secret: secret}
+
+	// expectChallenge expects the lookup of a challenge that can be completed.
+	expectChallenge := func(userTOTP *models.TOTP) {
+		mockTwoFactorRepo.EXPECT().GetLoginChallengeByHash(gomock.Any(), hashSecret(challengeToken)).Return(&models.LoginChallenge{
+			ID:        challengeId,
+			UserID:    userId,
+			ExpiresAt: time.Now().UTC().Add(time.Minute),
+		}, nil)
+		mockUserRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(user, nil)
+		if userTOTP != nil {
+			mockTwoFactorRepo.EXPECT().GetTOTP(gomock.Any(), userId).Return(userTOTP, nil)
+		} else {
+			mockTwoFactorRepo.EXPECT().GetTOTP(gomock.Any(), userId).Return(nil, models.ErrTOTPNotFound)
+		}
+		mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+	}
+	expectTokens := func() {
+		mockTwoFactorRepo.EXPECT().UseLoginChallenge(gomock.Any(), challengeId).Return(nil)
+		mockUserRepo.EXPECT().UpdateLastLogin(gomock.Any(), userId, gomock.Any()).Return(nil)
+		mockTokenRepo.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Return(nil)
+		mockLoginAttemptRepo.EXPECT().ResetLoginFailures(gomock.Any(), "email:moderator@example.com").Return(nil)
+	}
+	// expectFailedAttempt expects a wrong code to count against both the
+	// challenge and the account.
+	expectFailedAttempt := func() {
+		mockTwoFactorRepo.EXPECT().RecordLoginChallengeAttempt(gomock.Any(), challengeId).Return(1, nil)
+		mockLoginAttemptRepo.EXPECT().RecordLoginFailure(gomock.Any(), "email:moderator@example.com", gomock.Any(), gomock.Any()).
+			Return(&models.LoginFailures{Failures: 1}, nil)
+		mockLoginAttemptRepo.EXPECT().BlockLogin(gomock.Any(), "email:moderator@example.com", gomock.Any()).Return(nil)
+	}
+
+	tests := []struct {
+		name        string
+		token       string
+		code        string
+		mockActions func()
+		wantErr     error
+	}{
+		{
+			name:        "empty challenge",
+			code:        code,
+			mockActions: func() {},
+			wantErr:     models.ErrInvalidLoginChallenge,
+		},
+		{
+			name:  "unknown challenge",
+			token: challengeToken,
+			code:  code,
+			mockActions: func() {
+				mockTwoFactorRepo.EXPECT().GetLoginChallengeByHash(gomock.Any(), hashSecret(challengeToken)).
+					Return(nil, models.ErrLoginChallengeNotFound)
+			},
+			wantErr: models.ErrInvalidLoginChallenge,
+		},
+		{
+			name:  "expired challenge",
+			token: challengeToken,
+			code:  code,
+			mockActions: func() {
+				mockTwoFactorRepo.EXPECT().GetLoginChallengeByHash(gomock.Any(), hashSecret(challengeToken)).Return(&models.LoginChallenge{
+					ID:        challengeId,
+					UserID:    userId,
+					ExpiresAt: time.Now().UTC().Add(-time.Second),
+				}, nil)
+			},
+			wantErr: models.ErrInvalidLoginChallenge,
+		},
+		{
+			name:  "exhausted challenge",
+			token: challengeToken,
+			code:  code,
+			mockActions: func() {
+				mockTwoFactorRepo.EXPECT().GetLoginChallengeByHash(gomock.Any(), hashSecret(challengeToken)).Return(&models.LoginChallenge{
+					ID:        challengeId,
+					UserID:    userId,
+					Attempts:  maxLoginChallengeAttempts,
+					ExpiresAt: time.Now().UTC().Add(time.Minute),
+				}, nil)
+			},
+			wantErr: models.ErrInvalidLoginChallenge,
+		},
+		{
+			name:  "totp code",
+			token: challengeToken,
+			code:  code,
+			mockActions: func() {
+				expectChallenge(confirmed)
+				mockTwoFactorRepo.EXPECT().UseTOTPStep(gomock.Any(), userId, gomock.Any()).Return(nil)
+				expectTokens()
+			},
+		},
+		{
+			name:  "replayed totp code",
+			token: challengeToken,
+			code:  code,
+			mockActions: func() {
+				expectChallenge(confirmed)
+				mockTwoFactorRepo.EXPECT().UseTOTPStep(gomock.Any(), userId, gomock.Any()).Return(models.ErrInvalidTOTPCode)
+				expectFailedAttempt()
+			},
+			wantErr: models.ErrInvalidTOTPCode,
+		},
+		{
+			name:  "recovery code",
+			token: challengeToken,
+			code:  " ABCDE-FGHJK ",
+			mockActions: func() {
+				expectChallenge(confirmed)
+				mockTwoFactorRepo.EXPECT().UseRecoveryCode(gomock.Any(), userId, hashSecret("abcde-fghjk")).Return(nil)
+				expectTokens()
+			},
+		},
+		{
+			name:  "wrong code",
+			token: challengeToken,
+			code:  "abcde-fghjk",
+			mockActions: func() {
+				expectChallenge(confirmed)
+				mockTwoFactorRepo.EXPECT().UseRecoveryCode(gomock.Any(), userId, gomock.Any()).Return(models.ErrRecoveryCodeNotFound)
+				expectFailedAttempt()
+			},
+			wantErr: models.ErrInvalidTOTPCode,
+		},
+		{
+			name:  "first code confirms enrollment",
+			token: challengeToken,
+			code:  code,
+			mockActions: func() {
+				expectChallenge(pending)
+				mockTwoFactorRepo.EXPECT().UseTOTPStep(gomock.Any(), userId, gomock.Any()).Return(nil)
+				mockTwoFactorRepo.EXPECT().ConfirmTOTP(gomock.Any(), userId, gomock.Any()).Return(nil)
+				expectTokens()
+			},
+		},
+		{
+			name:  "recovery code before enrollment is confirmed",
+			token: challengeToken,
+			code:  "abcde-fghjk",
+			mockActions: func() {
+				expectChallenge(pending)
+				expectFailedAttempt()
+			},
+			wantErr: models.ErrInvalidTOTPCode,
+		},
+		{
+			name:  "not enrolled",
+			token: challengeToken,
+			code:  code,
+			mockActions: func() {
+				expectChallenge(nil)
+				expectFailedAttempt()
+			},
+			wantErr: models.ErrInvalidTOTPCode,
+		},
+		{
+			name:  "challenge used concurrently",
+			token: challengeToken,
+			code:  code,
+			mockActions: func() {
+				expectChallenge(confirmed)
+				mockTwoFactorRepo.EXPECT().UseTOTPStep(gomock.Any(), userId, gomock.Any()).Return(nil)
+				mockTwoFactorRepo.EXPECT().UseLoginChallenge(gomock.Any(), challengeId).Return(models.ErrLoginChallengeNotFound)
+			},
+			wantErr: models.ErrInvalidLoginChallenge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockActions()
+
+			pair, err := service.CompleteLogin(context.Background(), dto.PostLogin2faJSONRequestBody{
+				ChallengeToken: tt.token,
+				Code:           tt.code,
+			})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, pair)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, pair.AccessToken)
+			assert.NotEmpty(t, pair.RefreshToken)
+		})
+	}
+}
+
+func TestAuthService_TOTPEnrollment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mocks.NewMockTransactionManager(ctrl)
+	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	mockTwoFactorRepo := mocks.NewMockTwoFactorRepositoryInterface(ctrl)
+	service := NewAuthService(mockTxManager, mockUserRepo, nil, nil, nil, nil, nil, mockTwoFactorRepo,
+		nil, testKeys, nil, testHasher, Config{TOTPIssuer: "PVZ"})
+
+	userId := uuid.New()
+	user := &models.User{ID: userId, Email: "moderator@example.com", Role: dto.UserRoleModerator, Active: true}
+	ctx := models.WithPrincipal(context.Background(), models.Principal{UserID: userId, Role: dto.UserRoleModerator})
+
+	mockUserRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(user, nil).AnyTimes()
+	mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).AnyTimes()
+
+	var saved models.TOTP
+	var hashes []string
+	mockTwoFactorRepo.EXPECT().GetTOTP(gomock.Any(), userId).Return(nil, models.ErrTOTPNotFound)
+	mockTwoFactorRepo.EXPECT().SaveTOTP(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, userTOTP *models.TOTP) error {
+		saved = *userTOTP
+		return nil
+	})
+	mockTwoFactorRepo.EXPECT().ReplaceRecoveryCodes(gomock.Any(), userId, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ uuid.UUID, codeHashes []string) error {
+			hashes = codeHashes
+			return nil
+		})
+
+	enrollment, err := service.StartTOTPEnrollment(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, saved.Secret, enrollment.Secret)
+	assert.Equal(t, userId, saved.UserID)
+	assert.Contains(t, enrollment.ProvisioningUri, "otpauth://totp/PVZ:moderator@example.com?")
+	require.Len(t, enrollment.RecoveryCodes, recoveryCodeCount)
+	require.Len(t, hashes, recoveryCodeCount)
+	for i, code := range enrollment.RecoveryCodes {
+		assert.Regexp(t, regexp.MustCompile(`^[a-z2-9]{5}-[a-z2-9]{5}$`), code)
+		assert.Equal(t, hashSecret(code), hashes[i], "only hashes are stored")
+	}
+
+	pending := &models.TOTP{UserID: userId, Secret: enrollment.Secret}
+	mockTwoFactorRepo.EXPECT().GetTOTP(gomock.Any(), userId).Return(pending, nil)
+	err = service.ConfirmTOTP(ctx, dto.PostMe2faConfirmJSONRequestBody{Code: "abcdef"})
+	assert.ErrorIs(t, err, models.ErrInvalidTOTPCode)
+
+	code, err := totp.Code(enrollment.Secret, time.Now())
+	require.NoError(t, err)
+	mockTwoFactorRepo.EXPECT().GetTOTP(gomock.Any(), userId).Return(pending, nil)
+	mockTwoFactorRepo.EXPECT().UseTOTPStep(gomock.Any(), userId, gomock.Any()).Return(nil)
+	mockTwoFactorRepo.EXPECT().ConfirmTOTP(gomock.Any(), userId, gomock.Any()).Return(nil)
+	require.NoError(t, service.ConfirmTOTP(ctx, dto.PostMe2faConfirmJSONRequestBody{Code: code}))
+
+	confirmedAt := time.Now().UTC()
+	confirmed := &models.TOTP{UserID: userId, Secret: enrollment.Secret, ConfirmedAt: &confirmedAt}
+	mockTwoFactorRepo.EXPECT().GetTOTP(gomock.Any(), userId).Return(confirmed, nil).Times(2)
+	_, err = service.StartTOTPEnrollment(ctx)
+	assert.ErrorIs(t, err, models.ErrTOTPAlreadyEnabled)
+	assert.ErrorIs(t, service.ConfirmTOTP(ctx, dto.PostMe2faConfirmJSONRequestBody{Code: code}), models.ErrTOTPAlreadyEnabled)
+
+	_, err = service.StartTOTPEnrollment(context.Background())
+	assert.ErrorIs(t, err, models.ErrUserNotFound)
+	dummy := models.WithPrincipal(context.Background(), models.Principal{UserID: models.DummyUserID, Role: dto.UserRoleModerator})
+	_, err = service.StartTOTPEnrollment(dummy)
+	assert.ErrorIs(t, err, models.ErrUserNotFound)
+}
+
+func TestAuthService_DisableTOTP(t *testing.T) {
+	userId := uuid.New()
+	confirmedAt := time.Now().UTC()
+	const recoveryCode = "abcde-fghjk"
+
+	tests := []struct {
+		name        string
+		role        dto.UserRole
+		config      Config
+		mockActions func(repo *mocks.MockTwoFactorRepositoryInterface)
+		wantErr     error
+	}{
+		{
+			name:        "required for role",
+			role:        dto.UserRoleModerator,
+			config:      Config{RequireModeratorTwoFactor: true},
+			mockActions: func(*mocks.MockTwoFactorRepositoryInterface) {},
+			wantErr:     models.ErrTOTPRequired,
+		},
+		{
+			name: "not enabled",
+			role: dto.UserRoleModerator,
+			mockActions: func(repo *mocks.MockTwoFactorRepositoryInterface) {
+				repo.EXPECT().GetTOTP(gomock.Any(), userId).Return(&models.TOTP{UserID: userId, Secret: "SECRET"}, nil)
+			},
+			wantErr: models.ErrTOTPNotFound,
+		},
+		{
+			name: "wrong code",
+			role: dto.UserRoleModerator,
+			mockActions: func(repo *mocks.MockTwoFactorRepositoryInterface) {
+				repo.EXPECT().GetTOTP(gomock.Any(), userId).Return(&models.TOTP{UserID: userId, Secret: "SECRET", ConfirmedAt: &confirmedAt}, nil)
+				repo.EXPECT().UseRecoveryCode(gomock.Any(), userId, hashSecret(recoveryCode)).Return(models.ErrRecoveryCodeNotFound)
+			},
+			wantErr: models.ErrInvalidTOTPCode,
+		},
+		{
+			name:   "success",
+			role:   dto.UserRoleEmployee,
+			config: Config{RequireModeratorTwoFactor: true},
+			mockActions: func(repo *mocks.MockTwoFactorRepositoryInterface) {
+				repo.EXPECT().GetTOTP(gomock.Any(), userId).Return(&models.TOTP{UserID: userId, Secret: "SECRET", ConfirmedAt: &confirmedAt}, nil)
+				repo.EXPECT().UseRecoveryCode(gomock.Any(), userId, hashSecret(recoveryCode)).Return(nil)
+				repo.EXPECT().DeleteTOTP(gomock.Any(), userId).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockTxManager := mocks.NewMockTransactionManager(ctrl)
+			mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+			mockTwoFactorRepo := mocks.NewMockTwoFactorRepositoryInterface(ctrl)
+			service := NewAuthService(mockTxManager, mockUserRepo, nil, nil, nil, nil, nil, mockTwoFactorRepo,
+				nil, testKeys, nil, testHasher, tt.config)
+
+			mockUserRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(&models.User{ID: userId, Role: tt.role, Active: true}, nil)
+			mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).AnyTimes()
+			tt.mockActions(mockTwoFactorRepo)
+
+			ctx := models.WithPrincipal(context.Background(), models.Principal{UserID: userId, Role: tt.role})
+			err := service.DisableTOTP(ctx, dto.PostMe2faDisableJSONRequestBody{Code: recoveryCode})
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
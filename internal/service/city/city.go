@@ -0,0 +1,65 @@
+package city
+
+import (
+	"context"
+	"strings"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+	"github.com/itisalisas/avito-backend/internal/storage"
+)
+
+type Service struct {
+	txManager storage.TransactionManager
+	cityRepo  storage.CityRepositoryInterface
+}
+
+func NewCityService(txManager storage.TransactionManager, cityRepo storage.CityRepositoryInterface) *Service {
+	return &Service{txManager: txManager, cityRepo: cityRepo}
+}
+
+func (s *Service) GetCities(ctx context.Context) ([]dto.City, error) {
+	var cities []dto.City
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		cities, err = s.cityRepo.GetCities(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cities, nil
+}
+
+func (s *Service) AddCity(ctx context.Context, name string) (*dto.City, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, models.ErrEmptyName
+	}
+
+	city := dto.City{Name: name}
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		return s.cityRepo.CreateCity(ctx, &city)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &city, nil
+}
+
+// DisableCity stops new PVZs from being opened in the city. PVZs that
+// already operate there are kept.
+func (s *Service) DisableCity(ctx context.Context, id openapi_types.UUID) (*dto.City, error) {
+	var city *dto.City
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		city, err = s.cityRepo.DisableCity(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return city, nil
+}
@@ -0,0 +1,125 @@
+package city
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/generated/mocks"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+func TestCityService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mocks.NewMockTransactionManager(ctrl)
+	mockCityRepo := mocks.NewMockCityRepositoryInterface(ctrl)
+	service := NewCityService(mockTxManager, mockCityRepo)
+	id := uuid.New()
+
+	tests := []struct {
+		name         string
+		method       string
+		id           uuid.UUID
+		cityName     string
+		mockActions  func()
+		expectedErr  error
+		expectedCity *dto.City
+	}{
+		{
+			name:     "add city success",
+			method:   "AddCity",
+			cityName: " Казань ",
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockCityRepo.EXPECT().CreateCity(gomock.Any(), &dto.City{Name: "Казань"}).Return(nil).Times(1)
+			},
+			expectedCity: &dto.City{Name: "Казань"},
+		},
+		{
+			name:        "add city empty name",
+			method:      "AddCity",
+			cityName:    "",
+			mockActions: func() {},
+			expectedErr: models.ErrEmptyName,
+		},
+		{
+			name:     "add city duplicate",
+			method:   "AddCity",
+			cityName: "Москва",
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockCityRepo.EXPECT().CreateCity(gomock.Any(), gomock.Any()).Return(models.ErrCityExists).Times(1)
+			},
+			expectedErr: models.ErrCityExists,
+		},
+		{
+			name:   "disable city success",
+			method: "DisableCity",
+			id:     id,
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockCityRepo.EXPECT().DisableCity(gomock.Any(), id).Return(&dto.City{Id: id, Name: "Казань"}, nil).Times(1)
+			},
+			expectedCity: &dto.City{Id: id, Name: "Казань"},
+		},
+		{
+			name:   "disable city not found",
+			method: "DisableCity",
+			id:     id,
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockCityRepo.EXPECT().DisableCity(gomock.Any(), id).Return(nil, models.ErrCityNotFound).Times(1)
+			},
+			expectedErr: models.ErrCityNotFound,
+		},
+		{
+			name:   "get cities error",
+			method: "GetCities",
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockCityRepo.EXPECT().GetCities(gomock.Any()).Return(nil, errors.New("db error")).Times(1)
+			},
+			expectedErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockActions()
+
+			var err error
+			var city *dto.City
+
+			switch tt.method {
+			case "AddCity":
+				city, err = service.AddCity(context.Background(), tt.cityName)
+			case "DisableCity":
+				city, err = service.DisableCity(context.Background(), tt.id)
+			case "GetCities":
+				_, err = service.GetCities(context.Background())
+			}
+
+			if tt.expectedErr != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedErr.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if tt.expectedCity != nil {
+				assert.Equal(t, tt.expectedCity, city)
+			}
+		})
+	}
+}
+
+func runInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
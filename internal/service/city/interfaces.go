@@ -0,0 +1,15 @@
+package city
+
+import (
+	"context"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+)
+
+type ServiceInterface interface {
+	GetCities(ctx context.Context) ([]dto.City, error)
+	AddCity(ctx context.Context, name string) (*dto.City, error)
+	DisableCity(ctx context.Context, id openapi_types.UUID) (*dto.City, error)
+}
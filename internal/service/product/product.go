@@ -2,8 +2,7 @@ package product
 
 import (
 	"context"
-	"database/sql"
-	"log"
+	"errors"
 
 	openapi_types "github.com/oapi-codegen/runtime/types"
 
@@ -13,90 +12,72 @@ import (
 )
 
 type Service struct {
-	productRepo   storage.ProductRepositoryInterface
-	receptionRepo storage.ReceptionRepositoryInterface
+	txManager       storage.TransactionManager
+	productRepo     storage.ProductRepositoryInterface
+	receptionRepo   storage.ReceptionRepositoryInterface
+	productTypeRepo storage.ProductTypeRepositoryInterface
 }
 
-func NewProductService(productRepo storage.ProductRepositoryInterface,
-	receptionRepo storage.ReceptionRepositoryInterface) *Service {
-	return &Service{productRepo: productRepo,
-		receptionRepo: receptionRepo}
+func NewProductService(txManager storage.TransactionManager, productRepo storage.ProductRepositoryInterface,
+	receptionRepo storage.ReceptionRepositoryInterface, productTypeRepo storage.ProductTypeRepositoryInterface) *Service {
+	return &Service{txManager: txManager,
+		productRepo:     productRepo,
+		receptionRepo:   receptionRepo,
+		productTypeRepo: productTypeRepo}
 }
 
 func (s *Service) AddProduct(ctx context.Context, request dto.PostProductsJSONRequestBody) (*dto.Product, error) {
-	_, err := s.receptionRepo.BeginTx(ctx, nil)
-	if err != nil {
-		return nil, err
-	}
-	_, err = s.productRepo.BeginTx(ctx, nil)
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		err := s.receptionRepo.Rollback()
-		if err != nil {
-			log.Fatalf("Error while rolling back transaction: %s", err)
-		}
-	}()
-
-	if !isValidProductType(dto.ProductType(request.Type)) {
+	if request.Type == "" {
 		return nil, models.ErrIncorrectProductType
 	}
 
-	reception, err := s.receptionRepo.GetLastReceptionByPvzId(ctx, request.PvzId)
-	if err != nil {
-		return nil, err
-	}
+	var product *dto.Product
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		productType, err := s.productTypeRepo.GetProductTypeByName(ctx, request.Type)
+		switch {
+		case errors.Is(err, models.ErrProductTypeNotFound):
+			return models.ErrIncorrectProductType
+		case err != nil:
+			return err
+		case !productType.Active:
+			return models.ErrIncorrectProductType
+		}
 
-	if reception.Status != dto.InProgress {
-		return nil, models.ErrReceptionClosed
-	}
+		reception, err := s.receptionRepo.GetLastReceptionByPvzId(ctx, request.PvzId)
+		if err != nil {
+			return err
+		}
 
-	product := &dto.Product{
-		Type:        dto.ProductType(request.Type),
-		ReceptionId: *reception.Id,
-	}
+		if reception.Status != dto.InProgress {
+			return models.ErrReceptionClosed
+		}
 
-	if err = s.productRepo.AddProduct(ctx, product); err != nil {
-		return nil, err
-	}
-	if err = s.receptionRepo.Commit(); err != nil {
+		product = &dto.Product{
+			Type:        productType.Name,
+			ReceptionId: *reception.Id,
+			CreatedBy:   models.ActorID(ctx),
+		}
+
+		return s.productRepo.AddProduct(ctx, product)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return product, nil
 }
 
-func isValidProductType(productType dto.ProductType) bool {
-	return productType == dto.ProductTypeЭлектроника ||
-		productType == dto.ProductTypeОдежда ||
-		productType == dto.ProductTypeОбувь
-}
-
 func (s *Service) DeleteLastProduct(ctx context.Context, pvzId openapi_types.UUID) error {
-	tx, err := s.receptionRepo.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
-	defer func(tx *sql.Tx) {
-		err := s.receptionRepo.Rollback()
+	return s.txManager.Do(ctx, func(ctx context.Context) error {
+		reception, err := s.receptionRepo.GetLastReceptionByPvzId(ctx, pvzId)
 		if err != nil {
-			log.Fatalf("Error while rolling back transaction: %s", err)
+			return err
 		}
-	}(tx)
 
-	reception, err := s.receptionRepo.GetLastReceptionByPvzId(ctx, pvzId)
-	if err != nil {
-		return err
-	}
-
-	product, err := s.productRepo.GetLastProduct(ctx, *reception.Id)
-	if err != nil {
-		return err
-	}
+		product, err := s.productRepo.GetLastProduct(ctx, *reception.Id)
+		if err != nil {
+			return err
+		}
 
-	err = s.productRepo.DeleteProductById(ctx, *product.Id)
-	if err != nil {
-		return err
-	}
-	return s.receptionRepo.Commit()
+		return s.productRepo.DeleteProductById(ctx, *product.Id)
+	})
 }
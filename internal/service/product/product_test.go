@@ -2,7 +2,6 @@ package product
 
 import (
 	"context"
-	"database/sql"
 	"testing"
 
 	"github.com/google/uuid"
@@ -19,12 +18,15 @@ func TestProductService(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	mockTxManager := mocks.NewMockTransactionManager(ctrl)
 	mockProductRepo := mocks.NewMockProductRepositoryInterface(ctrl)
 	mockReceptionRepo := mocks.NewMockReceptionRepositoryInterface(ctrl)
-	service := NewProductService(mockProductRepo, mockReceptionRepo)
+	mockProductTypeRepo := mocks.NewMockProductTypeRepositoryInterface(ctrl)
+	service := NewProductService(mockTxManager, mockProductRepo, mockReceptionRepo, mockProductTypeRepo)
 	pvzId := uuid.New()
 	receptionId := uuid.New()
 	productId := uuid.New()
+	electronics := &dto.ProductType{Id: uuid.New(), Name: "электроника", Active: true}
 
 	tests := []struct {
 		name            string
@@ -42,19 +44,17 @@ func TestProductService(t *testing.T) {
 				Type:  "электроника",
 			},
 			mockActions: func() {
-				mockReceptionRepo.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(&sql.Tx{}, nil).Times(1)
-				mockProductRepo.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(&sql.Tx{}, nil).Times(1)
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockProductTypeRepo.EXPECT().GetProductTypeByName(gomock.Any(), "электроника").Return(electronics, nil).Times(1)
 				mockReceptionRepo.EXPECT().GetLastReceptionByPvzId(gomock.Any(), gomock.Any()).Return(&dto.Reception{
 					Id:     &receptionId,
 					Status: dto.InProgress,
 				}, nil).Times(1)
 				mockProductRepo.EXPECT().AddProduct(gomock.Any(), gomock.Any()).Return(nil).Times(1)
-				mockReceptionRepo.EXPECT().Commit().Return(nil).Times(1)
-				mockReceptionRepo.EXPECT().Rollback().Return(nil).Times(1)
 			},
 			expectedErr: nil,
 			expectedProduct: &dto.Product{
-				Type:        dto.ProductTypeЭлектроника,
+				Type:        "электроника",
 				ReceptionId: receptionId,
 			},
 		},
@@ -66,13 +66,36 @@ func TestProductService(t *testing.T) {
 				Type:  "InvalidType",
 			},
 			mockActions: func() {
-				mockReceptionRepo.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(&sql.Tx{}, nil).Times(1)
-				mockProductRepo.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(&sql.Tx{}, nil).Times(1)
-				mockReceptionRepo.EXPECT().GetLastReceptionByPvzId(gomock.Any(), gomock.Any()).Return(&dto.Reception{
-					Id:     &receptionId,
-					Status: dto.Close,
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockProductTypeRepo.EXPECT().GetProductTypeByName(gomock.Any(), "InvalidType").Return(nil, models.ErrProductTypeNotFound).Times(1)
+			},
+			expectedErr:     models.ErrIncorrectProductType,
+			expectedProduct: nil,
+		},
+		{
+			name:   "add product empty type",
+			method: "AddProduct",
+			request: dto.PostProductsJSONRequestBody{
+				PvzId: pvzId,
+			},
+			mockActions:     func() {},
+			expectedErr:     models.ErrIncorrectProductType,
+			expectedProduct: nil,
+		},
+		{
+			name:   "add product deactivated type",
+			method: "AddProduct",
+			request: dto.PostProductsJSONRequestBody{
+				PvzId: pvzId,
+				Type:  "обувь",
+			},
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockProductTypeRepo.EXPECT().GetProductTypeByName(gomock.Any(), "обувь").Return(&dto.ProductType{
+					Id:     uuid.New(),
+					Name:   "обувь",
+					Active: false,
 				}, nil).Times(1)
-				mockReceptionRepo.EXPECT().Rollback().Return(nil).Times(1)
 			},
 			expectedErr:     models.ErrIncorrectProductType,
 			expectedProduct: nil,
@@ -85,9 +108,12 @@ func TestProductService(t *testing.T) {
 				Type:  "электроника",
 			},
 			mockActions: func() {
-				mockReceptionRepo.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(&sql.Tx{}, nil).Times(1)
-				mockProductRepo.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(&sql.Tx{}, nil).Times(1)
-				mockReceptionRepo.EXPECT().Rollback().Return(nil).Times(1)
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockProductTypeRepo.EXPECT().GetProductTypeByName(gomock.Any(), "электроника").Return(electronics, nil).Times(1)
+				mockReceptionRepo.EXPECT().GetLastReceptionByPvzId(gomock.Any(), gomock.Any()).Return(&dto.Reception{
+					Id:     &receptionId,
+					Status: dto.Close,
+				}, nil).Times(1)
 			},
 			expectedErr:     models.ErrReceptionClosed,
 			expectedProduct: nil,
@@ -97,7 +123,7 @@ func TestProductService(t *testing.T) {
 			method:  "DeleteLastProduct",
 			request: pvzId,
 			mockActions: func() {
-				mockReceptionRepo.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(&sql.Tx{}, nil).Times(1)
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
 				mockReceptionRepo.EXPECT().GetLastReceptionByPvzId(gomock.Any(), gomock.Any()).Return(&dto.Reception{
 					Id:     &receptionId,
 					Status: dto.InProgress,
@@ -106,8 +132,6 @@ func TestProductService(t *testing.T) {
 					Id: &productId,
 				}, nil).Times(1)
 				mockProductRepo.EXPECT().DeleteProductById(gomock.Any(), gomock.Any()).Return(nil).Times(1)
-				mockReceptionRepo.EXPECT().Commit().Return(nil).Times(1)
-				mockReceptionRepo.EXPECT().Rollback().Return(nil).Times(1)
 			},
 			expectedErr:     nil,
 			expectedProduct: nil,
@@ -117,9 +141,8 @@ func TestProductService(t *testing.T) {
 			method:  "DeleteLastProduct",
 			request: pvzId,
 			mockActions: func() {
-				mockReceptionRepo.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(&sql.Tx{}, nil).Times(1)
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
 				mockReceptionRepo.EXPECT().GetLastReceptionByPvzId(gomock.Any(), gomock.Any()).Return(nil, models.ErrReceptionClosed).Times(1)
-				mockReceptionRepo.EXPECT().Rollback().Return(nil).Times(1)
 			},
 			expectedErr:     models.ErrReceptionClosed,
 			expectedProduct: nil,
@@ -129,12 +152,11 @@ func TestProductService(t *testing.T) {
 			method:  "DeleteLastProduct",
 			request: pvzId,
 			mockActions: func() {
-				mockReceptionRepo.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(&sql.Tx{}, nil).Times(1)
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
 				mockReceptionRepo.EXPECT().GetLastReceptionByPvzId(gomock.Any(), gomock.Any()).Return(&dto.Reception{
 					Id: &receptionId,
 				}, nil).Times(1)
 				mockProductRepo.EXPECT().GetLastProduct(gomock.Any(), gomock.Any()).Return(nil, models.ErrNoProductsInReception).Times(1)
-				mockReceptionRepo.EXPECT().Rollback().Return(nil).Times(1)
 			},
 			expectedErr:     models.ErrNoProductsInReception,
 			expectedProduct: nil,
@@ -169,3 +191,7 @@ func TestProductService(t *testing.T) {
 		})
 	}
 }
+
+func runInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
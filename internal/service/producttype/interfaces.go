@@ -0,0 +1,16 @@
+package producttype
+
+import (
+	"context"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+)
+
+type ServiceInterface interface {
+	GetProductTypes(ctx context.Context) ([]dto.ProductType, error)
+	AddProductType(ctx context.Context, name string) (*dto.ProductType, error)
+	RenameProductType(ctx context.Context, id openapi_types.UUID, name string) (*dto.ProductType, error)
+	DeactivateProductType(ctx context.Context, id openapi_types.UUID) (*dto.ProductType, error)
+}
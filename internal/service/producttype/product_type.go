@@ -0,0 +1,85 @@
+package producttype
+
+import (
+	"context"
+	"strings"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+	"github.com/itisalisas/avito-backend/internal/storage"
+)
+
+type Service struct {
+	txManager       storage.TransactionManager
+	productTypeRepo storage.ProductTypeRepositoryInterface
+}
+
+func NewProductTypeService(txManager storage.TransactionManager, productTypeRepo storage.ProductTypeRepositoryInterface) *Service {
+	return &Service{txManager: txManager, productTypeRepo: productTypeRepo}
+}
+
+func (s *Service) GetProductTypes(ctx context.Context) ([]dto.ProductType, error) {
+	var productTypes []dto.ProductType
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		productTypes, err = s.productTypeRepo.GetProductTypes(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return productTypes, nil
+}
+
+func (s *Service) AddProductType(ctx context.Context, name string) (*dto.ProductType, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, models.ErrEmptyName
+	}
+
+	productType := dto.ProductType{Name: name}
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		return s.productTypeRepo.CreateProductType(ctx, &productType)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &productType, nil
+}
+
+// RenameProductType changes the name of the type. Products already stored
+// with the old name follow the rename.
+func (s *Service) RenameProductType(ctx context.Context, id openapi_types.UUID, name string) (*dto.ProductType, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, models.ErrEmptyName
+	}
+
+	var productType *dto.ProductType
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		productType, err = s.productTypeRepo.RenameProductType(ctx, id, name)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return productType, nil
+}
+
+// DeactivateProductType stops new products of the type from being accepted.
+// Products that were already received keep it.
+func (s *Service) DeactivateProductType(ctx context.Context, id openapi_types.UUID) (*dto.ProductType, error) {
+	var productType *dto.ProductType
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		productType, err = s.productTypeRepo.DeactivateProductType(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return productType, nil
+}
@@ -0,0 +1,148 @@
+package producttype
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/generated/mocks"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+func TestProductTypeService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mocks.NewMockTransactionManager(ctrl)
+	mockProductTypeRepo := mocks.NewMockProductTypeRepositoryInterface(ctrl)
+	service := NewProductTypeService(mockTxManager, mockProductTypeRepo)
+	id := uuid.New()
+
+	tests := []struct {
+		name                string
+		method              string
+		id                  uuid.UUID
+		productTypeName     string
+		mockActions         func()
+		expectedErr         error
+		expectedProductType *dto.ProductType
+	}{
+		{
+			name:            "add product type success",
+			method:          "AddProductType",
+			productTypeName: "  книги ",
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockProductTypeRepo.EXPECT().CreateProductType(gomock.Any(), &dto.ProductType{Name: "книги"}).Return(nil).Times(1)
+			},
+			expectedProductType: &dto.ProductType{Name: "книги"},
+		},
+		{
+			name:            "add product type empty name",
+			method:          "AddProductType",
+			productTypeName: "   ",
+			mockActions:     func() {},
+			expectedErr:     models.ErrEmptyName,
+		},
+		{
+			name:            "add product type duplicate",
+			method:          "AddProductType",
+			productTypeName: "обувь",
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockProductTypeRepo.EXPECT().CreateProductType(gomock.Any(), gomock.Any()).Return(models.ErrProductTypeExists).Times(1)
+			},
+			expectedErr: models.ErrProductTypeExists,
+		},
+		{
+			name:            "rename product type success",
+			method:          "RenameProductType",
+			id:              id,
+			productTypeName: "книги",
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockProductTypeRepo.EXPECT().RenameProductType(gomock.Any(), id, "книги").
+					Return(&dto.ProductType{Id: id, Name: "книги", Active: true}, nil).Times(1)
+			},
+			expectedProductType: &dto.ProductType{Id: id, Name: "книги", Active: true},
+		},
+		{
+			name:            "rename product type empty name",
+			method:          "RenameProductType",
+			id:              id,
+			productTypeName: "",
+			mockActions:     func() {},
+			expectedErr:     models.ErrEmptyName,
+		},
+		{
+			name:   "deactivate product type not found",
+			method: "DeactivateProductType",
+			id:     id,
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockProductTypeRepo.EXPECT().DeactivateProductType(gomock.Any(), id).Return(nil, models.ErrProductTypeNotFound).Times(1)
+			},
+			expectedErr: models.ErrProductTypeNotFound,
+		},
+		{
+			name:   "deactivate product type success",
+			method: "DeactivateProductType",
+			id:     id,
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockProductTypeRepo.EXPECT().DeactivateProductType(gomock.Any(), id).
+					Return(&dto.ProductType{Id: id, Name: "обувь", Active: false}, nil).Times(1)
+			},
+			expectedProductType: &dto.ProductType{Id: id, Name: "обувь", Active: false},
+		},
+		{
+			name:   "get product types error",
+			method: "GetProductTypes",
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockProductTypeRepo.EXPECT().GetProductTypes(gomock.Any()).Return(nil, errors.New("db error")).Times(1)
+			},
+			expectedErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockActions()
+
+			var err error
+			var productType *dto.ProductType
+
+			switch tt.method {
+			case "AddProductType":
+				productType, err = service.AddProductType(context.Background(), tt.productTypeName)
+			case "RenameProductType":
+				productType, err = service.RenameProductType(context.Background(), tt.id, tt.productTypeName)
+			case "DeactivateProductType":
+				productType, err = service.DeactivateProductType(context.Background(), tt.id)
+			case "GetProductTypes":
+				_, err = service.GetProductTypes(context.Background())
+			}
+
+			if tt.expectedErr != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedErr.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if tt.expectedProductType != nil {
+				assert.Equal(t, tt.expectedProductType, productType)
+			}
+		})
+	}
+}
+
+func runInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
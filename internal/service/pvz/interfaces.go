@@ -2,7 +2,8 @@ package pvz
 
 import (
 	"context"
-	"time"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
 
 	"github.com/itisalisas/avito-backend/internal/generated/dto"
 	"github.com/itisalisas/avito-backend/internal/models"
@@ -10,5 +11,8 @@ import (
 
 type ServiceInterface interface {
 	AddPvz(ctx context.Context, pvz *dto.PostPvzJSONRequestBody) (*dto.PVZ, error)
-	GetPvzList(ctx context.Context, startTime *time.Time, endTime *time.Time, page uint64, limit uint64) ([]*models.ExtendedPvz, error)
+	GetPvz(ctx context.Context, id openapi_types.UUID) (*dto.PVZ, error)
+	UpdatePvz(ctx context.Context, id openapi_types.UUID, update *dto.PVZUpdate) (*dto.PVZ, error)
+	DecommissionPvz(ctx context.Context, id openapi_types.UUID) (*dto.PVZ, error)
+	GetPvzList(ctx context.Context, params models.PvzListParams) (*models.PvzPage, error)
 }
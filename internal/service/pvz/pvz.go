@@ -2,8 +2,11 @@ package pvz
 
 import (
 	"context"
-	"log"
-	"time"
+	"errors"
+	"regexp"
+	"slices"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
 
 	"github.com/itisalisas/avito-backend/internal/generated/dto"
 	"github.com/itisalisas/avito-backend/internal/models"
@@ -11,67 +14,163 @@ import (
 )
 
 type Service struct {
-	pvzRepo storage.PvzRepositoryInterface
+	txManager      storage.TransactionManager
+	pvzRepo        storage.PvzRepositoryInterface
+	cityRepo       storage.CityRepositoryInterface
+	assignmentRepo storage.AssignmentRepositoryInterface
 }
 
-func NewPvzService(pvzRepo storage.PvzRepositoryInterface) *Service {
-	return &Service{pvzRepo: pvzRepo}
+func NewPvzService(txManager storage.TransactionManager, pvzRepo storage.PvzRepositoryInterface,
+	cityRepo storage.CityRepositoryInterface, assignmentRepo storage.AssignmentRepositoryInterface) *Service {
+	return &Service{txManager: txManager, pvzRepo: pvzRepo, cityRepo: cityRepo, assignmentRepo: assignmentRepo}
 }
 
 func (s *Service) AddPvz(ctx context.Context, request *dto.PostPvzJSONRequestBody) (*dto.PVZ, error) {
-	_, err := s.pvzRepo.BeginTx(ctx, nil)
-	if err != nil {
-		return nil, err
+	if request.City == "" {
+		return nil, models.ErrIncorrectCity
 	}
-	defer func() {
-		err := s.pvzRepo.Rollback()
-		if err != nil {
-			log.Fatalf("Error while rolling back transaction: %s", err)
-		}
-	}()
 
-	if !isValidCity(request.City) {
-		return nil, models.ErrIncorrectCity
+	if err := validateProfile(request.Latitude, request.Longitude, request.Phone); err != nil {
+		return nil, err
 	}
 
 	pvz := dto.PVZ{
-		City: request.City,
+		City:         request.City,
+		Name:         request.Name,
+		Address:      request.Address,
+		Latitude:     request.Latitude,
+		Longitude:    request.Longitude,
+		Phone:        request.Phone,
+		OpeningHours: request.OpeningHours,
+		CreatedBy:    models.ActorID(ctx),
 	}
 
-	if err := s.pvzRepo.CreatePvz(ctx, &pvz); err != nil {
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		city, err := s.cityRepo.GetCityByName(ctx, request.City)
+		switch {
+		case errors.Is(err, models.ErrCityNotFound):
+			return models.ErrIncorrectCity
+		case err != nil:
+			return err
+		case !city.Active:
+			return models.ErrIncorrectCity
+		}
+
+		return s.pvzRepo.CreatePvz(ctx, &pvz)
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return &pvz, s.pvzRepo.Commit()
+	return &pvz, nil
 }
 
-func isValidCity(city dto.PVZCity) bool {
-	return city == dto.Москва || city == dto.Казань || city == dto.СанктПетербург
+func (s *Service) GetPvz(ctx context.Context, id openapi_types.UUID) (*dto.PVZ, error) {
+	var pvz *dto.PVZ
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		pvz, err = s.pvzRepo.GetPvzById(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pvz, nil
 }
 
-func (s *Service) GetPvzList(ctx context.Context, startTime *time.Time, endTime *time.Time, page uint64, limit uint64) ([]*models.ExtendedPvz, error) {
-	_, err := s.pvzRepo.BeginTx(ctx, nil)
+func (s *Service) UpdatePvz(ctx context.Context, id openapi_types.UUID, update *dto.PVZUpdate) (*dto.PVZ, error) {
+	if err := validateProfile(update.Latitude, update.Longitude, update.Phone); err != nil {
+		return nil, err
+	}
+
+	var pvz *dto.PVZ
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		pvz, err = s.pvzRepo.UpdatePvz(ctx, id, update)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		err := s.pvzRepo.Rollback()
-		if err != nil {
-			log.Fatalf("Error while rolling back transaction: %s", err)
-		}
-	}()
+	return pvz, nil
+}
 
-	pvzList, err := s.pvzRepo.GetPvzList(ctx, startTime, endTime, page, limit)
+// DecommissionPvz takes the PVZ out of service. Its history stays available,
+// but no new receptions can be opened in it.
+func (s *Service) DecommissionPvz(ctx context.Context, id openapi_types.UUID) (*dto.PVZ, error) {
+	var pvz *dto.PVZ
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		pvz, err = s.pvzRepo.DecommissionPvz(ctx, id)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
+	return pvz, nil
+}
 
-	if err := s.pvzRepo.Commit(); err != nil {
+// GetPvzList returns a page of the PVZs the caller may work with. Employees
+// only see the PVZs they are assigned to.
+func (s *Service) GetPvzList(ctx context.Context, params models.PvzListParams) (*models.PvzPage, error) {
+	var page *models.PvzPage
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if params.PvzIds, err = s.pvzScope(ctx); err != nil {
+			return err
+		}
+		page, err = s.pvzRepo.GetPvzList(ctx, params)
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
-	return pvzList, nil
+	return page, nil
 }
 
+// GetAllPVZ returns every PVZ the caller may work with, like GetPvzList.
 func (s *Service) GetAllPVZ(ctx context.Context) ([]dto.PVZ, error) {
-	return s.pvzRepo.GetAllPVZs(ctx)
+	scope, err := s.pvzScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pvzs, err := s.pvzRepo.GetAllPVZs(ctx)
+	if err != nil || scope == nil {
+		return pvzs, err
+	}
+	return slices.DeleteFunc(pvzs, func(pvz dto.PVZ) bool {
+		return !slices.Contains(scope, *pvz.Id)
+	}), nil
+}
+
+// pvzScope returns the PVZs the caller is limited to, or nil if they may see
+// all of them. It draws the same line as middleware.CheckPvzAccess: employees
+// are limited to their assignments and service accounts to their own scope.
+func (s *Service) pvzScope(ctx context.Context) ([]openapi_types.UUID, error) {
+	principal, _ := models.PrincipalFromContext(ctx)
+	switch {
+	case principal.ServiceAccount && len(principal.PvzIds) > 0:
+		return principal.PvzIds, nil
+	case principal.ServiceAccount || principal.Role != dto.UserRoleEmployee:
+		return nil, nil
+	default:
+		return s.assignmentRepo.GetAssignedPvzIds(ctx, principal.UserID)
+	}
+}
+
+var phonePattern = regexp.MustCompile(`^\+?[0-9][0-9 ()-]{4,19}$`)
+
+// validateProfile checks the optional profile fields. Coordinates are only
+// accepted as a pair.
+func validateProfile(latitude, longitude *float64, phone *string) error {
+	if (latitude == nil) != (longitude == nil) {
+		return models.ErrIncorrectCoordinates
+	}
+	if latitude != nil && (*latitude < -90 || *latitude > 90 || *longitude < -180 || *longitude > 180) {
+		return models.ErrIncorrectCoordinates
+	}
+	if phone != nil && !phonePattern.MatchString(*phone) {
+		return models.ErrIncorrectPhone
+	}
+	return nil
 }
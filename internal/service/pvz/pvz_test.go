@@ -3,7 +3,6 @@ package pvz
 import (
 	"context"
 	"testing"
-	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -18,9 +17,16 @@ func TestPvzService(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	mockTxManager := mocks.NewMockTransactionManager(ctrl)
 	mockPvzRepo := mocks.NewMockPvzRepositoryInterface(ctrl)
-	service := NewPvzService(mockPvzRepo)
+	mockCityRepo := mocks.NewMockCityRepositoryInterface(ctrl)
+	service := NewPvzService(mockTxManager, mockPvzRepo, mockCityRepo, nil)
 	pvzId := uuid.New()
+	moderatorId := uuid.New()
+	latitude, longitude := 55.7558, 37.6173
+	outOfRange := 120.0
+	phone := "+7 (495) 123-45-67"
+	badPhone := "call me"
 
 	tests := []struct {
 		name            string
@@ -35,17 +41,21 @@ func TestPvzService(t *testing.T) {
 			name:   "add pvz success",
 			method: "AddPvz",
 			request: &dto.PVZ{
-				City: dto.Москва,
+				City: "Москва",
 			},
 			mockActions: func() {
-				mockPvzRepo.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockCityRepo.EXPECT().GetCityByName(gomock.Any(), "Москва").Return(&dto.City{
+					Id:     uuid.New(),
+					Name:   "Москва",
+					Active: true,
+				}, nil).Times(1)
 				mockPvzRepo.EXPECT().CreatePvz(gomock.Any(), gomock.Any()).Return(nil).Times(1)
-				mockPvzRepo.EXPECT().Commit().Return(nil).Times(1)
-				mockPvzRepo.EXPECT().Rollback().Return(nil).Times(1)
 			},
 			expectedErr: nil,
 			expectedPvz: &dto.PVZ{
-				City: dto.Москва,
+				City:      "Москва",
+				CreatedBy: &moderatorId,
 			},
 		},
 		{
@@ -55,46 +65,163 @@ func TestPvzService(t *testing.T) {
 				City: "InvalidCity",
 			},
 			mockActions: func() {
-				mockPvzRepo.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
-				mockPvzRepo.EXPECT().Rollback().Return(nil).Times(1)
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockCityRepo.EXPECT().GetCityByName(gomock.Any(), "InvalidCity").Return(nil, models.ErrCityNotFound).Times(1)
+			},
+			expectedErr: models.ErrIncorrectCity,
+			expectedPvz: nil,
+		},
+		{
+			name:    "add pvz empty city",
+			method:  "AddPvz",
+			request: &dto.PVZ{},
+			mockActions: func() {
+			},
+			expectedErr: models.ErrIncorrectCity,
+			expectedPvz: nil,
+		},
+		{
+			name:   "add pvz disabled city",
+			method: "AddPvz",
+			request: &dto.PVZ{
+				City: "Казань",
+			},
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockCityRepo.EXPECT().GetCityByName(gomock.Any(), "Казань").Return(&dto.City{
+					Id:     uuid.New(),
+					Name:   "Казань",
+					Active: false,
+				}, nil).Times(1)
 			},
 			expectedErr: models.ErrIncorrectCity,
 			expectedPvz: nil,
 		},
+		{
+			name:   "add pvz latitude without longitude",
+			method: "AddPvz",
+			request: &dto.PVZ{
+				City:     "Москва",
+				Latitude: &latitude,
+			},
+			mockActions: func() {
+			},
+			expectedErr: models.ErrIncorrectCoordinates,
+			expectedPvz: nil,
+		},
+		{
+			name:   "add pvz incorrect phone",
+			method: "AddPvz",
+			request: &dto.PVZ{
+				City:  "Москва",
+				Phone: &badPhone,
+			},
+			mockActions: func() {
+			},
+			expectedErr: models.ErrIncorrectPhone,
+			expectedPvz: nil,
+		},
+		{
+			name:   "update pvz success",
+			method: "UpdatePvz",
+			request: &dto.PVZUpdate{
+				Latitude:  &latitude,
+				Longitude: &longitude,
+				Phone:     &phone,
+			},
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockPvzRepo.EXPECT().UpdatePvz(gomock.Any(), pvzId, gomock.Any()).Return(&dto.PVZ{
+					Id:        &pvzId,
+					City:      "Москва",
+					Latitude:  &latitude,
+					Longitude: &longitude,
+					Phone:     &phone,
+				}, nil).Times(1)
+			},
+			expectedErr: nil,
+			expectedPvz: &dto.PVZ{
+				City: "Москва",
+			},
+		},
+		{
+			name:   "update pvz coordinates out of range",
+			method: "UpdatePvz",
+			request: &dto.PVZUpdate{
+				Latitude:  &outOfRange,
+				Longitude: &longitude,
+			},
+			mockActions: func() {
+			},
+			expectedErr: models.ErrIncorrectCoordinates,
+			expectedPvz: nil,
+		},
+		{
+			name:    "update pvz not found",
+			method:  "UpdatePvz",
+			request: &dto.PVZUpdate{},
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockPvzRepo.EXPECT().UpdatePvz(gomock.Any(), pvzId, gomock.Any()).Return(nil, models.ErrPvzNotFound).Times(1)
+			},
+			expectedErr: models.ErrPvzNotFound,
+			expectedPvz: nil,
+		},
+		{
+			name:    "get pvz success",
+			method:  "GetPvz",
+			request: pvzId,
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockPvzRepo.EXPECT().GetPvzById(gomock.Any(), pvzId).Return(&dto.PVZ{
+					Id:   &pvzId,
+					City: "Москва",
+				}, nil).Times(1)
+			},
+			expectedErr: nil,
+			expectedPvz: &dto.PVZ{
+				City: "Москва",
+			},
+		},
+		{
+			name:    "decommission pvz twice",
+			method:  "DecommissionPvz",
+			request: pvzId,
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockPvzRepo.EXPECT().DecommissionPvz(gomock.Any(), pvzId).Return(nil, models.ErrPvzDecommissioned).Times(1)
+			},
+			expectedErr: models.ErrPvzDecommissioned,
+			expectedPvz: nil,
+		},
 		{
 			name:   "get pvz list success",
 			method: "GetPvzList",
-			request: struct {
-				startTime *time.Time
-				endTime   *time.Time
-				page      uint64
-				limit     uint64
-			}{
-				startTime: nil,
-				endTime:   nil,
-				page:      1,
-				limit:     10,
+			request: models.PvzListParams{
+				Page:  1,
+				Limit: 10,
 			},
 			mockActions: func() {
-				mockPvzRepo.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
-				mockPvzRepo.EXPECT().GetPvzList(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return([]*models.ExtendedPvz{
-					{
-						PVZ: dto.PVZ{
-							Id:   &pvzId,
-							City: dto.Москва,
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockPvzRepo.EXPECT().GetPvzList(gomock.Any(), models.PvzListParams{Page: 1, Limit: 10}).Return(&models.PvzPage{
+					Items: []*models.ExtendedPvz{
+						{
+							PVZ: dto.PVZ{
+								Id:   &pvzId,
+								City: "Москва",
+							},
+							Receptions: []models.ExtendedReception{},
 						},
-						Receptions: []models.ExtendedReception{},
 					},
+					TotalCount: 1,
 				}, nil).Times(1)
-				mockPvzRepo.EXPECT().Commit().Return(nil).Times(1)
-				mockPvzRepo.EXPECT().Rollback().Return(nil).Times(1)
 			},
 			expectedErr: nil,
 			expectedPvzList: []*models.ExtendedPvz{
 				{
 					PVZ: dto.PVZ{
 						Id:   &pvzId,
-						City: dto.Москва,
+						City: "Москва",
 					},
 					Receptions: []models.ExtendedReception{},
 				},
@@ -108,19 +235,20 @@ func TestPvzService(t *testing.T) {
 
 			var err error
 			var pvz *dto.PVZ
-			var pvzList []*models.ExtendedPvz
+			var pvzPage *models.PvzPage
 
 			switch tt.method {
 			case "AddPvz":
-				pvz, err = service.AddPvz(context.Background(), tt.request.(*dto.PVZ))
+				ctx := models.WithPrincipal(context.Background(), models.Principal{UserID: moderatorId, Role: dto.UserRoleModerator})
+				pvz, err = service.AddPvz(ctx, tt.request.(*dto.PVZ))
+			case "GetPvz":
+				pvz, err = service.GetPvz(context.Background(), tt.request.(uuid.UUID))
+			case "UpdatePvz":
+				pvz, err = service.UpdatePvz(context.Background(), pvzId, tt.request.(*dto.PVZUpdate))
+			case "DecommissionPvz":
+				pvz, err = service.DecommissionPvz(context.Background(), tt.request.(uuid.UUID))
 			case "GetPvzList":
-				request := tt.request.(struct {
-					startTime *time.Time
-					endTime   *time.Time
-					page      uint64
-					limit     uint64
-				})
-				pvzList, err = service.GetPvzList(context.Background(), request.startTime, request.endTime, request.page, request.limit)
+				pvzPage, err = service.GetPvzList(context.Background(), tt.request.(models.PvzListParams))
 			}
 
 			if tt.expectedErr != nil {
@@ -132,14 +260,95 @@ func TestPvzService(t *testing.T) {
 
 			if tt.expectedPvz != nil {
 				assert.Equal(t, tt.expectedPvz.City, pvz.City)
+				if tt.expectedPvz.CreatedBy != nil {
+					assert.Equal(t, tt.expectedPvz.CreatedBy, pvz.CreatedBy)
+				}
 			}
 
 			if tt.expectedPvzList != nil {
-				assert.Len(t, pvzList, len(tt.expectedPvzList))
+				assert.Len(t, pvzPage.Items, len(tt.expectedPvzList))
 				for i, item := range tt.expectedPvzList {
-					assert.Equal(t, item.PVZ.City, pvzList[i].PVZ.City)
+					assert.Equal(t, item.PVZ.City, pvzPage.Items[i].PVZ.City)
 				}
 			}
 		})
 	}
 }
+
+func TestPvzService_ListScope(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mocks.NewMockTransactionManager(ctrl)
+	mockPvzRepo := mocks.NewMockPvzRepositoryInterface(ctrl)
+	mockAssignmentRepo := mocks.NewMockAssignmentRepositoryInterface(ctrl)
+	service := NewPvzService(mockTxManager, mockPvzRepo, nil, mockAssignmentRepo)
+	employeeId := uuid.New()
+	assigned, other := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name        string
+		principal   models.Principal
+		mockActions func()
+		wantScope   []uuid.UUID
+	}{
+		{
+			name:      "moderator",
+			principal: models.Principal{UserID: uuid.New(), Role: dto.UserRoleModerator},
+			wantScope: nil,
+		},
+		{
+			name:      "employee",
+			principal: models.Principal{UserID: employeeId, Role: dto.UserRoleEmployee},
+			mockActions: func() {
+				mockAssignmentRepo.EXPECT().GetAssignedPvzIds(gomock.Any(), employeeId).Return([]uuid.UUID{assigned}, nil).Times(2)
+			},
+			wantScope: []uuid.UUID{assigned},
+		},
+		{
+			name:      "scoped service account",
+			principal: models.Principal{UserID: uuid.New(), Role: dto.UserRoleModerator, ServiceAccount: true, PvzIds: []uuid.UUID{assigned}},
+			wantScope: []uuid.UUID{assigned},
+		},
+		{
+			name:      "unscoped employee service account",
+			principal: models.Principal{UserID: uuid.New(), Role: dto.UserRoleEmployee, ServiceAccount: true},
+			wantScope: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockActions != nil {
+				tt.mockActions()
+			}
+			ctx := models.WithPrincipal(context.Background(), tt.principal)
+
+			mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+			mockPvzRepo.EXPECT().GetPvzList(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, params models.PvzListParams) (*models.PvzPage, error) {
+					assert.Equal(t, tt.wantScope, params.PvzIds)
+					return &models.PvzPage{}, nil
+				})
+			_, err := service.GetPvzList(ctx, models.PvzListParams{Page: 1, Limit: 10})
+			assert.NoError(t, err)
+
+			mockPvzRepo.EXPECT().GetAllPVZs(gomock.Any()).Return([]dto.PVZ{{Id: &assigned}, {Id: &other}}, nil)
+			pvzs, err := service.GetAllPVZ(ctx)
+			assert.NoError(t, err)
+			want := []uuid.UUID{assigned, other}
+			if tt.wantScope != nil {
+				want = tt.wantScope
+			}
+			var got []uuid.UUID
+			for _, pvz := range pvzs {
+				got = append(got, *pvz.Id)
+			}
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func runInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
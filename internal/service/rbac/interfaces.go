@@ -0,0 +1,15 @@
+package rbac
+
+import (
+	"context"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type ServiceInterface interface {
+	RolePermissions(ctx context.Context, role dto.UserRole) ([]models.Permission, error)
+	ListPermissions(ctx context.Context) []string
+	ListRoles(ctx context.Context) ([]*dto.Role, error)
+	SetRolePermissions(ctx context.Context, role string, request dto.PutRolesRolePermissionsJSONRequestBody) (*dto.Role, error)
+}
@@ -0,0 +1,94 @@
+package rbac
+
+import (
+	"context"
+	"slices"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+	"github.com/itisalisas/avito-backend/internal/storage"
+)
+
+type Service struct {
+	txManager storage.TransactionManager
+	roleRepo  storage.RoleRepositoryInterface
+}
+
+func NewRBACService(txManager storage.TransactionManager, roleRepo storage.RoleRepositoryInterface) *Service {
+	return &Service{
+		txManager: txManager,
+		roleRepo:  roleRepo,
+	}
+}
+
+// RolePermissions returns what the role is allowed to do. It is read on every
+// authenticated request, so changes apply without reissuing tokens.
+func (s *Service) RolePermissions(ctx context.Context, role dto.UserRole) ([]models.Permission, error) {
+	return s.roleRepo.GetRolePermissions(ctx, role)
+}
+
+func (s *Service) ListPermissions(_ context.Context) []string {
+	permissions := make([]string, 0, len(models.Permissions))
+	for _, permission := range models.Permissions {
+		permissions = append(permissions, string(permission))
+	}
+	return permissions
+}
+
+func (s *Service) ListRoles(ctx context.Context) ([]*dto.Role, error) {
+	rolePermissions, err := s.roleRepo.ListRolePermissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make([]*dto.Role, 0, len(models.Roles))
+	for _, role := range models.Roles {
+		roles = append(roles, roleDTO(role, rolePermissions[role]))
+	}
+	return roles, nil
+}
+
+// SetRolePermissions replaces the permissions of the role. The admin role
+// always keeps role:manage, otherwise nobody could undo the change.
+func (s *Service) SetRolePermissions(ctx context.Context, role string,
+	request dto.PutRolesRolePermissionsJSONRequestBody) (*dto.Role, error) {
+	userRole := dto.UserRole(role)
+	if !slices.Contains(models.Roles, userRole) {
+		return nil, models.ErrIncorrectUserRole
+	}
+
+	permissions := make([]models.Permission, 0, len(request.Permissions))
+	for _, name := range request.Permissions {
+		permission := models.Permission(name)
+		if !slices.Contains(models.Permissions, permission) {
+			return nil, models.ErrUnknownPermission
+		}
+		permissions = append(permissions, permission)
+	}
+	if userRole == dto.UserRoleAdmin && !slices.Contains(permissions, models.PermRoleManage) {
+		return nil, models.ErrRoleLockout
+	}
+
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		if err := s.roleRepo.SetRolePermissions(ctx, userRole, permissions); err != nil {
+			return err
+		}
+
+		var err error
+		permissions, err = s.roleRepo.GetRolePermissions(ctx, userRole)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return roleDTO(userRole, permissions), nil
+}
+
+func roleDTO(role dto.UserRole, permissions []models.Permission) *dto.Role {
+	names := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		names = append(names, string(permission))
+	}
+	return &dto.Role{Role: string(role), Permissions: names}
+}
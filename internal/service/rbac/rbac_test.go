@@ -0,0 +1,127 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/generated/mocks"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+func TestRBACService_SetRolePermissions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mocks.NewMockTransactionManager(ctrl)
+	mockRoleRepo := mocks.NewMockRoleRepositoryInterface(ctrl)
+	service := NewRBACService(mockTxManager, mockRoleRepo)
+
+	tests := []struct {
+		name         string
+		role         string
+		permissions  []string
+		mockActions  func()
+		expectedErr  error
+		expectedRole *dto.Role
+	}{
+		{
+			name:        "unknown role",
+			role:        "owner",
+			permissions: []string{"pvz:read"},
+			expectedErr: models.ErrIncorrectUserRole,
+		},
+		{
+			name:        "unknown permission",
+			role:        "employee",
+			permissions: []string{"pvz:read", "pvz:burn"},
+			expectedErr: models.ErrUnknownPermission,
+		},
+		{
+			name:        "admin keeps role management",
+			role:        "admin",
+			permissions: []string{"pvz:read"},
+			expectedErr: models.ErrRoleLockout,
+		},
+		{
+			name:        "repository error",
+			role:        "employee",
+			permissions: []string{"pvz:read"},
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				mockRoleRepo.EXPECT().SetRolePermissions(gomock.Any(), dto.UserRoleEmployee,
+					[]models.Permission{models.PermPvzRead}).Return(errors.New("db error"))
+			},
+			expectedErr: errors.New("db error"),
+		},
+		{
+			name:        "success",
+			role:        "moderator",
+			permissions: []string{"pvz:read", "pvz:create"},
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				mockRoleRepo.EXPECT().SetRolePermissions(gomock.Any(), dto.UserRoleModerator,
+					[]models.Permission{models.PermPvzRead, models.PermPvzCreate}).Return(nil)
+				mockRoleRepo.EXPECT().GetRolePermissions(gomock.Any(), dto.UserRoleModerator).
+					Return([]models.Permission{models.PermPvzCreate, models.PermPvzRead}, nil)
+			},
+			expectedRole: &dto.Role{Role: "moderator", Permissions: []string{"pvz:create", "pvz:read"}},
+		},
+		{
+			name:        "clear permissions",
+			role:        "employee",
+			permissions: []string{},
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				mockRoleRepo.EXPECT().SetRolePermissions(gomock.Any(), dto.UserRoleEmployee, []models.Permission{}).Return(nil)
+				mockRoleRepo.EXPECT().GetRolePermissions(gomock.Any(), dto.UserRoleEmployee).Return([]models.Permission{}, nil)
+			},
+			expectedRole: &dto.Role{Role: "employee", Permissions: []string{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockActions != nil {
+				tt.mockActions()
+			}
+
+			role, err := service.SetRolePermissions(context.Background(), tt.role,
+				dto.PutRolesRolePermissionsJSONRequestBody{Permissions: tt.permissions})
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedRole, role)
+		})
+	}
+}
+
+func TestRBACService_ListRoles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRoleRepo := mocks.NewMockRoleRepositoryInterface(ctrl)
+	service := NewRBACService(nil, mockRoleRepo)
+
+	mockRoleRepo.EXPECT().ListRolePermissions(gomock.Any()).Return(map[dto.UserRole][]models.Permission{
+		dto.UserRoleEmployee: {models.PermPvzRead},
+		dto.UserRoleAdmin:    {models.PermRoleManage},
+	}, nil)
+
+	roles, err := service.ListRoles(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []*dto.Role{
+		{Role: "employee", Permissions: []string{"pvz:read"}},
+		{Role: "moderator", Permissions: []string{}},
+		{Role: "admin", Permissions: []string{"role:manage"}},
+	}, roles)
+
+	assert.Len(t, service.ListPermissions(context.Background()), len(models.Permissions))
+}
+
+func runInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
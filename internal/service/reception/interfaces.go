@@ -11,4 +11,5 @@ import (
 type ServiceInterface interface {
 	AddReception(ctx context.Context, request dto.PostReceptionsJSONRequestBody) (*dto.Reception, error)
 	CloseLastReception(ctx context.Context, pvzId openapi_types.UUID) (*dto.Reception, error)
+	ChangeStatus(ctx context.Context, receptionId openapi_types.UUID, action Action) (*dto.Reception, error)
 }
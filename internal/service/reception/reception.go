@@ -3,7 +3,6 @@ package reception
 import (
 	"context"
 	"errors"
-	"log"
 
 	openapi_types "github.com/oapi-codegen/runtime/types"
 
@@ -13,43 +12,46 @@ import (
 )
 
 type Service struct {
+	txManager     storage.TransactionManager
 	receptionRepo storage.ReceptionRepositoryInterface
+	pvzRepo       storage.PvzRepositoryInterface
 }
 
-func NewReceptionService(receptionRepo storage.ReceptionRepositoryInterface) *Service {
-	return &Service{receptionRepo: receptionRepo}
+func NewReceptionService(txManager storage.TransactionManager, receptionRepo storage.ReceptionRepositoryInterface,
+	pvzRepo storage.PvzRepositoryInterface) *Service {
+	return &Service{txManager: txManager, receptionRepo: receptionRepo, pvzRepo: pvzRepo}
 }
 
 func (s *Service) AddReception(ctx context.Context, request dto.PostReceptionsJSONRequestBody) (*dto.Reception, error) {
-	_, err := s.receptionRepo.BeginTx(ctx, nil)
-	if err != nil {
-		return nil, err
+	reception := dto.Reception{
+		PvzId:     request.PvzId,
+		CreatedBy: models.ActorID(ctx),
 	}
-	defer func() {
-		err := s.receptionRepo.Rollback()
+
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		pvz, err := s.pvzRepo.GetPvzById(ctx, request.PvzId)
 		if err != nil {
-			log.Fatalf("Error while rolling back transaction: %s", err)
+			return err
+		}
+		if pvz.DecommissionedAt != nil {
+			return models.ErrPvzDecommissioned
 		}
-	}()
-
-	lastReception, err := s.receptionRepo.GetLastReceptionByPvzId(ctx, request.PvzId)
-	if err != nil && !errors.Is(err, models.ErrReceptionNotFound) {
-		return nil, err
-	}
-
-	if lastReception != nil && lastReception.Status != dto.Close {
-		return nil, models.ErrReceptionNotClosed
-	}
 
-	reception := dto.Reception{
-		PvzId: request.PvzId,
-	}
+		lastReception, err := s.receptionRepo.GetLastReceptionByPvzId(ctx, request.PvzId)
+		if err != nil && !errors.Is(err, models.ErrReceptionNotFound) {
+			return err
+		}
 
-	if err := s.receptionRepo.AddReception(ctx, &reception); err != nil {
-		return nil, err
-	}
+		if lastReception != nil && models.IsOpenReception(lastReception.Status) {
+			return models.ErrReceptionNotClosed
+		}
 
-	if err := s.receptionRepo.Commit(); err != nil {
+		// The check above only fails fast. A concurrent request may still open a
+		// reception first, in which case the storage rejects this insert with
+		// ErrReceptionNotClosed.
+		return s.receptionRepo.AddReception(ctx, &reception)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -57,31 +59,65 @@ func (s *Service) AddReception(ctx context.Context, request dto.PostReceptionsJS
 }
 
 func (s *Service) CloseLastReception(ctx context.Context, pvzId openapi_types.UUID) (*dto.Reception, error) {
-	_, err := s.receptionRepo.BeginTx(ctx, nil)
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		err := s.receptionRepo.Rollback()
+	var updReception *dto.Reception
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		reception, err := s.receptionRepo.GetLastReceptionByPvzId(ctx, pvzId)
 		if err != nil {
-			log.Fatalf("Error while rolling back transaction: %s", err)
+			return err
+		}
+
+		if !transitions[Close].allowedFrom(reception.Status) {
+			return models.ErrReceptionClosed
 		}
-	}()
 
-	reception, err := s.receptionRepo.GetLastReceptionByPvzId(ctx, pvzId)
+		updReception, err = s.receptionRepo.CloseLastReception(ctx, *reception.Id)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if reception.Status == dto.Close {
-		return nil, models.ErrReceptionClosed
+	return updReception, nil
+}
+
+// ChangeStatus moves the reception through its lifecycle on behalf of the
+// authenticated user.
+func (s *Service) ChangeStatus(ctx context.Context, receptionId openapi_types.UUID, action Action) (*dto.Reception, error) {
+	principal, _ := models.PrincipalFromContext(ctx)
+	t, err := transitionFor(action, principal)
+	if err != nil {
+		return nil, err
 	}
 
-	updReception, err := s.receptionRepo.CloseLastReception(ctx, *reception.Id)
+	var updReception *dto.Reception
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		reception, err := s.receptionRepo.GetReceptionById(ctx, receptionId)
+		if err != nil {
+			return err
+		}
+
+		if !t.allowedFrom(reception.Status) {
+			return models.ErrInvalidTransition
+		}
+
+		if action == Reopen {
+			// Products always go to the latest reception of the PVZ, so an
+			// older one would stay unusable after reopening.
+			lastReception, err := s.receptionRepo.GetLastReceptionByPvzId(ctx, reception.PvzId)
+			if err != nil {
+				return err
+			}
+			if *lastReception.Id != receptionId {
+				return models.ErrInvalidTransition
+			}
+		}
 
-	if err := s.receptionRepo.Commit(); err != nil {
+		updReception, err = s.receptionRepo.UpdateReceptionStatus(ctx, receptionId, t.to)
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return updReception, err
+	return updReception, nil
 }
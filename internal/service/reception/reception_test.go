@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/oapi-codegen/runtime/types"
@@ -19,10 +20,13 @@ func TestReceptionService(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	mockTxManager := mocks.NewMockTransactionManager(ctrl)
 	mockReceptionRepo := mocks.NewMockReceptionRepositoryInterface(ctrl)
-	service := NewReceptionService(mockReceptionRepo)
+	mockPvzRepo := mocks.NewMockPvzRepositoryInterface(ctrl)
+	service := NewReceptionService(mockTxManager, mockReceptionRepo, mockPvzRepo)
 	pvzId := uuid.New()
 	receptionId := uuid.New()
+	decommissionedAt := time.Now()
 
 	tests := []struct {
 		name              string
@@ -39,11 +43,10 @@ func TestReceptionService(t *testing.T) {
 				PvzId: pvzId,
 			},
 			mockActions: func() {
-				mockReceptionRepo.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockPvzRepo.EXPECT().GetPvzById(gomock.Any(), pvzId).Return(&dto.PVZ{Id: &pvzId}, nil).Times(1)
 				mockReceptionRepo.EXPECT().GetLastReceptionByPvzId(gomock.Any(), gomock.Any()).Return(nil, models.ErrReceptionNotFound).Times(1)
 				mockReceptionRepo.EXPECT().AddReception(gomock.Any(), gomock.Any()).Return(nil).Times(1)
-				mockReceptionRepo.EXPECT().Commit().Return(nil).Times(1)
-				mockReceptionRepo.EXPECT().Rollback().Return(nil).Times(1)
 			},
 			expectedErr: nil,
 			expectedReception: &dto.Reception{
@@ -57,21 +60,84 @@ func TestReceptionService(t *testing.T) {
 				PvzId: pvzId,
 			},
 			mockActions: func() {
-				mockReceptionRepo.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockPvzRepo.EXPECT().GetPvzById(gomock.Any(), pvzId).Return(&dto.PVZ{Id: &pvzId}, nil).Times(1)
 				mockReceptionRepo.EXPECT().GetLastReceptionByPvzId(gomock.Any(), gomock.Any()).Return(&dto.Reception{
 					Status: dto.InProgress,
 				}, nil).Times(1)
-				mockReceptionRepo.EXPECT().Rollback().Return(nil).Times(1)
 			},
 			expectedErr:       models.ErrReceptionNotClosed,
 			expectedReception: nil,
 		},
+		{
+			name:   "add reception after cancelled one",
+			method: "AddReception",
+			request: dto.PostReceptionsJSONRequestBody{
+				PvzId: pvzId,
+			},
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockPvzRepo.EXPECT().GetPvzById(gomock.Any(), pvzId).Return(&dto.PVZ{Id: &pvzId}, nil).Times(1)
+				mockReceptionRepo.EXPECT().GetLastReceptionByPvzId(gomock.Any(), gomock.Any()).Return(&dto.Reception{
+					Status: dto.Cancelled,
+				}, nil).Times(1)
+				mockReceptionRepo.EXPECT().AddReception(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+			},
+			expectedErr: nil,
+			expectedReception: &dto.Reception{
+				PvzId: pvzId,
+			},
+		},
+		{
+			name:   "add reception loses race to concurrent one",
+			method: "AddReception",
+			request: dto.PostReceptionsJSONRequestBody{
+				PvzId: pvzId,
+			},
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockPvzRepo.EXPECT().GetPvzById(gomock.Any(), pvzId).Return(&dto.PVZ{Id: &pvzId}, nil).Times(1)
+				mockReceptionRepo.EXPECT().GetLastReceptionByPvzId(gomock.Any(), gomock.Any()).Return(nil, models.ErrReceptionNotFound).Times(1)
+				mockReceptionRepo.EXPECT().AddReception(gomock.Any(), gomock.Any()).Return(models.ErrReceptionNotClosed).Times(1)
+			},
+			expectedErr:       models.ErrReceptionNotClosed,
+			expectedReception: nil,
+		},
+		{
+			name:   "add reception to missing pvz",
+			method: "AddReception",
+			request: dto.PostReceptionsJSONRequestBody{
+				PvzId: pvzId,
+			},
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockPvzRepo.EXPECT().GetPvzById(gomock.Any(), pvzId).Return(nil, models.ErrPvzNotFound).Times(1)
+			},
+			expectedErr:       models.ErrPvzNotFound,
+			expectedReception: nil,
+		},
+		{
+			name:   "add reception to decommissioned pvz",
+			method: "AddReception",
+			request: dto.PostReceptionsJSONRequestBody{
+				PvzId: pvzId,
+			},
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockPvzRepo.EXPECT().GetPvzById(gomock.Any(), pvzId).Return(&dto.PVZ{
+					Id:               &pvzId,
+					DecommissionedAt: &decommissionedAt,
+				}, nil).Times(1)
+			},
+			expectedErr:       models.ErrPvzDecommissioned,
+			expectedReception: nil,
+		},
 		{
 			name:    "close reception success",
 			method:  "CloseLastReception",
 			request: pvzId,
 			mockActions: func() {
-				mockReceptionRepo.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
 				mockReceptionRepo.EXPECT().GetLastReceptionByPvzId(gomock.Any(), gomock.Any()).Return(&dto.Reception{
 					Id:     &receptionId,
 					Status: dto.InProgress,
@@ -80,8 +146,6 @@ func TestReceptionService(t *testing.T) {
 					Id:     &receptionId,
 					Status: dto.Close,
 				}, nil).Times(1)
-				mockReceptionRepo.EXPECT().Commit().Return(nil).Times(1)
-				mockReceptionRepo.EXPECT().Rollback().Return(nil).Times(1)
 			},
 			expectedErr: nil,
 			expectedReception: &dto.Reception{
@@ -94,12 +158,11 @@ func TestReceptionService(t *testing.T) {
 			method:  "CloseLastReception",
 			request: pvzId,
 			mockActions: func() {
-				mockReceptionRepo.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
 				mockReceptionRepo.EXPECT().GetLastReceptionByPvzId(gomock.Any(), gomock.Any()).Return(&dto.Reception{
 					Id:     &receptionId,
 					Status: dto.Close,
 				}, nil).Times(1)
-				mockReceptionRepo.EXPECT().Rollback().Return(nil).Times(1)
 			},
 			expectedErr:       models.ErrReceptionClosed,
 			expectedReception: nil,
@@ -111,7 +174,7 @@ func TestReceptionService(t *testing.T) {
 				PvzId: pvzId,
 			},
 			mockActions: func() {
-				mockReceptionRepo.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, errors.New("tx error")).Times(1)
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).Return(errors.New("tx error")).Times(1)
 			},
 			expectedErr:       errors.New("tx error"),
 			expectedReception: nil,
@@ -145,3 +208,155 @@ func TestReceptionService(t *testing.T) {
 		})
 	}
 }
+
+func runInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestReceptionService_ChangeStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mocks.NewMockTransactionManager(ctrl)
+	mockReceptionRepo := mocks.NewMockReceptionRepositoryInterface(ctrl)
+	mockPvzRepo := mocks.NewMockPvzRepositoryInterface(ctrl)
+	service := NewReceptionService(mockTxManager, mockReceptionRepo, mockPvzRepo)
+	pvzId := uuid.New()
+	receptionId := uuid.New()
+	newerReceptionId := uuid.New()
+
+	receptionWith := func(status dto.ReceptionStatus) *dto.Reception {
+		return &dto.Reception{Id: &receptionId, PvzId: pvzId, Status: status}
+	}
+
+	tests := []struct {
+		name        string
+		action      Action
+		role        dto.UserRole
+		mockActions func()
+		expectedErr error
+		expected    dto.ReceptionStatus
+	}{
+		{
+			name:   "employee pauses reception in progress",
+			action: Pause,
+			role:   dto.UserRoleEmployee,
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockReceptionRepo.EXPECT().GetReceptionById(gomock.Any(), receptionId).Return(receptionWith(dto.InProgress), nil).Times(1)
+				mockReceptionRepo.EXPECT().UpdateReceptionStatus(gomock.Any(), receptionId, dto.Paused).Return(receptionWith(dto.Paused), nil).Times(1)
+			},
+			expected: dto.Paused,
+		},
+		{
+			name:   "employee resumes paused reception",
+			action: Resume,
+			role:   dto.UserRoleEmployee,
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockReceptionRepo.EXPECT().GetReceptionById(gomock.Any(), receptionId).Return(receptionWith(dto.Paused), nil).Times(1)
+				mockReceptionRepo.EXPECT().UpdateReceptionStatus(gomock.Any(), receptionId, dto.InProgress).Return(receptionWith(dto.InProgress), nil).Times(1)
+			},
+			expected: dto.InProgress,
+		},
+		{
+			name:   "resume reception in progress",
+			action: Resume,
+			role:   dto.UserRoleEmployee,
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockReceptionRepo.EXPECT().GetReceptionById(gomock.Any(), receptionId).Return(receptionWith(dto.InProgress), nil).Times(1)
+			},
+			expectedErr: models.ErrInvalidTransition,
+		},
+		{
+			name:   "moderator cancels paused reception",
+			action: Cancel,
+			role:   dto.UserRoleModerator,
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockReceptionRepo.EXPECT().GetReceptionById(gomock.Any(), receptionId).Return(receptionWith(dto.Paused), nil).Times(1)
+				mockReceptionRepo.EXPECT().UpdateReceptionStatus(gomock.Any(), receptionId, dto.Cancelled).Return(receptionWith(dto.Cancelled), nil).Times(1)
+			},
+			expected: dto.Cancelled,
+		},
+		{
+			name:   "cancel closed reception",
+			action: Cancel,
+			role:   dto.UserRoleEmployee,
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockReceptionRepo.EXPECT().GetReceptionById(gomock.Any(), receptionId).Return(receptionWith(dto.Close), nil).Times(1)
+			},
+			expectedErr: models.ErrInvalidTransition,
+		},
+		{
+			name:        "employee cannot reopen",
+			action:      Reopen,
+			role:        dto.UserRoleEmployee,
+			mockActions: func() {},
+			expectedErr: models.ErrTransitionForbidden,
+		},
+		{
+			name:        "moderator cannot pause",
+			action:      Pause,
+			role:        dto.UserRoleModerator,
+			mockActions: func() {},
+			expectedErr: models.ErrTransitionForbidden,
+		},
+		{
+			name:   "moderator reopens last closed reception",
+			action: Reopen,
+			role:   dto.UserRoleModerator,
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockReceptionRepo.EXPECT().GetReceptionById(gomock.Any(), receptionId).Return(receptionWith(dto.Close), nil).Times(1)
+				mockReceptionRepo.EXPECT().GetLastReceptionByPvzId(gomock.Any(), pvzId).Return(receptionWith(dto.Close), nil).Times(1)
+				mockReceptionRepo.EXPECT().UpdateReceptionStatus(gomock.Any(), receptionId, dto.InProgress).Return(receptionWith(dto.InProgress), nil).Times(1)
+			},
+			expected: dto.InProgress,
+		},
+		{
+			name:   "reopen older reception",
+			action: Reopen,
+			role:   dto.UserRoleModerator,
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockReceptionRepo.EXPECT().GetReceptionById(gomock.Any(), receptionId).Return(receptionWith(dto.Close), nil).Times(1)
+				mockReceptionRepo.EXPECT().GetLastReceptionByPvzId(gomock.Any(), pvzId).Return(&dto.Reception{
+					Id:     &newerReceptionId,
+					PvzId:  pvzId,
+					Status: dto.Close,
+				}, nil).Times(1)
+			},
+			expectedErr: models.ErrInvalidTransition,
+		},
+		{
+			name:   "reception not found",
+			action: Close,
+			role:   dto.UserRoleEmployee,
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(1)
+				mockReceptionRepo.EXPECT().GetReceptionById(gomock.Any(), receptionId).Return(nil, models.ErrReceptionNotFound).Times(1)
+			},
+			expectedErr: models.ErrReceptionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockActions()
+
+			principal := models.Principal{Role: tt.role, Permissions: models.DefaultRolePermissions[tt.role]}
+			reception, err := service.ChangeStatus(models.WithPrincipal(context.Background(), principal), receptionId, tt.action)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, reception)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, reception.Status)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package reception
+
+import (
+	"slices"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+// Action is a user request to move a reception to another status.
+type Action string
+
+const (
+	Pause  Action = "pause"
+	Resume Action = "resume"
+	Close  Action = "close"
+	Cancel Action = "cancel"
+	Reopen Action = "reopen"
+)
+
+type transition struct {
+	from       []dto.ReceptionStatus
+	to         dto.ReceptionStatus
+	permission models.Permission
+}
+
+// transitions is the reception lifecycle. Closed and cancelled receptions are
+// final, except that a closed one may be reopened by whoever holds
+// reception:reopen (moderators by default).
+var transitions = map[Action]transition{
+	Pause: {
+		from:       []dto.ReceptionStatus{dto.InProgress},
+		to:         dto.Paused,
+		permission: models.PermReceptionPause,
+	},
+	Resume: {
+		from:       []dto.ReceptionStatus{dto.Paused},
+		to:         dto.InProgress,
+		permission: models.PermReceptionResume,
+	},
+	Close: {
+		from:       []dto.ReceptionStatus{dto.InProgress, dto.Paused},
+		to:         dto.Close,
+		permission: models.PermReceptionClose,
+	},
+	Cancel: {
+		from:       []dto.ReceptionStatus{dto.InProgress, dto.Paused},
+		to:         dto.Cancelled,
+		permission: models.PermReceptionCancel,
+	},
+	Reopen: {
+		from:       []dto.ReceptionStatus{dto.Close},
+		to:         dto.InProgress,
+		permission: models.PermReceptionReopen,
+	},
+}
+
+// transitionFor returns the transition of action if principal may perform it.
+func transitionFor(action Action, principal models.Principal) (transition, error) {
+	t, ok := transitions[action]
+	switch {
+	case !ok:
+		return transition{}, models.ErrInvalidTransition
+	case !principal.Can(t.permission):
+		return transition{}, models.ErrTransitionForbidden
+	default:
+		return t, nil
+	}
+}
+
+func (t transition) allowedFrom(status dto.ReceptionStatus) bool {
+	return slices.Contains(t.from, status)
+}
@@ -0,0 +1,20 @@
+package serviceaccount
+
+import (
+	"context"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type ServiceInterface interface {
+	CreateServiceAccount(ctx context.Context, request dto.PostServiceAccountsJSONRequestBody) (*dto.ServiceAccount, error)
+	ListServiceAccounts(ctx context.Context) ([]*dto.ServiceAccount, error)
+	CreateAPIKey(ctx context.Context, accountId openapi_types.UUID,
+		request dto.PostServiceAccountsServiceAccountIdApiKeysJSONRequestBody) (*dto.ApiKey, error)
+	ListAPIKeys(ctx context.Context, accountId openapi_types.UUID) ([]*dto.ApiKey, error)
+	RevokeAPIKey(ctx context.Context, id openapi_types.UUID) error
+	AuthenticateAPIKey(ctx context.Context, key string) (models.Principal, error)
+}
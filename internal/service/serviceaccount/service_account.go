@@ -0,0 +1,216 @@
+package serviceaccount
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+	"github.com/itisalisas/avito-backend/internal/storage"
+)
+
+const (
+	// keyPrefix marks API keys so that they are easy to tell apart from
+	// other secrets, e.g. by secret scanners.
+	keyPrefix = "pvz_"
+	// displayPrefixLength is how much of a key is kept in clear to let
+	// moderators recognise it in the listing.
+	displayPrefixLength = 12
+	maxKeyTTLDays       = 365
+	// lastUsedResolution limits how often the last use of a key is written.
+	lastUsedResolution = time.Minute
+)
+
+type Service struct {
+	txManager          storage.TransactionManager
+	serviceAccountRepo storage.ServiceAccountRepositoryInterface
+}
+
+func NewServiceAccountService(txManager storage.TransactionManager,
+	serviceAccountRepo storage.ServiceAccountRepositoryInterface) *Service {
+	return &Service{
+		txManager:          txManager,
+		serviceAccountRepo: serviceAccountRepo,
+	}
+}
+
+// CreateServiceAccount creates an account whose keys act with the given role,
+// limited to the given PVZs if there are any.
+func (s *Service) CreateServiceAccount(ctx context.Context,
+	request dto.PostServiceAccountsJSONRequestBody) (*dto.ServiceAccount, error) {
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		return nil, models.ErrEmptyName
+	}
+	role := dto.UserRole(request.Role)
+	if role != dto.UserRoleEmployee && role != dto.UserRoleModerator {
+		return nil, models.ErrIncorrectUserRole
+	}
+
+	account := &models.ServiceAccount{
+		Name:      name,
+		Role:      role,
+		CreatedBy: models.ActorID(ctx),
+	}
+	if request.PvzIds != nil {
+		account.PvzIds = *request.PvzIds
+	}
+
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		return s.serviceAccountRepo.CreateServiceAccount(ctx, account)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return account.DTO(), nil
+}
+
+func (s *Service) ListServiceAccounts(ctx context.Context) ([]*dto.ServiceAccount, error) {
+	accounts, err := s.serviceAccountRepo.ListServiceAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.ServiceAccount, 0, len(accounts))
+	for _, account := range accounts {
+		result = append(result, account.DTO())
+	}
+	return result, nil
+}
+
+// CreateAPIKey issues a key for the service account. The key is returned
+// only here; afterwards just its hash is known.
+func (s *Service) CreateAPIKey(ctx context.Context, accountId openapi_types.UUID,
+	request dto.PostServiceAccountsServiceAccountIdApiKeysJSONRequestBody) (*dto.ApiKey, error) {
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		return nil, models.ErrEmptyName
+	}
+
+	var expiresAt *time.Time
+	if request.TtlDays != nil {
+		if *request.TtlDays < 1 || *request.TtlDays > maxKeyTTLDays {
+			return nil, models.ErrInvalidAPIKeyTTL
+		}
+		at := time.Now().UTC().AddDate(0, 0, *request.TtlDays)
+		expiresAt = &at
+	}
+
+	key, err := generateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey := &models.APIKey{
+		ServiceAccountID: accountId,
+		Name:             name,
+		KeyHash:          hashKey(key),
+		Prefix:           key[:displayPrefixLength],
+		CreatedBy:        models.ActorID(ctx),
+		ExpiresAt:        expiresAt,
+	}
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		return s.serviceAccountRepo.CreateAPIKey(ctx, apiKey)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := apiKey.DTO()
+	result.Key = &key
+	return result, nil
+}
+
+func (s *Service) ListAPIKeys(ctx context.Context, accountId openapi_types.UUID) ([]*dto.ApiKey, error) {
+	var keys []*models.APIKey
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		if _, err := s.serviceAccountRepo.GetServiceAccountById(ctx, accountId); err != nil {
+			return err
+		}
+
+		var err error
+		keys, err = s.serviceAccountRepo.ListAPIKeys(ctx, accountId)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.ApiKey, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, key.DTO())
+	}
+	return result, nil
+}
+
+func (s *Service) RevokeAPIKey(ctx context.Context, id openapi_types.UUID) error {
+	return s.txManager.Do(ctx, func(ctx context.Context) error {
+		return s.serviceAccountRepo.RevokeAPIKey(ctx, id)
+	})
+}
+
+// AuthenticateAPIKey returns the principal of the service account the key
+// belongs to. Unknown, expired and revoked keys give ErrInvalidAPIKey.
+func (s *Service) AuthenticateAPIKey(ctx context.Context, key string) (models.Principal, error) {
+	if !strings.HasPrefix(key, keyPrefix) {
+		return models.Principal{}, models.ErrInvalidAPIKey
+	}
+
+	apiKey, err := s.serviceAccountRepo.GetAPIKeyByHash(ctx, hashKey(key))
+	switch {
+	case errors.Is(err, models.ErrAPIKeyNotFound):
+		return models.Principal{}, models.ErrInvalidAPIKey
+	case err != nil:
+		return models.Principal{}, err
+	}
+
+	now := time.Now().UTC()
+	if apiKey.RevokedAt != nil || (apiKey.ExpiresAt != nil && !now.Before(*apiKey.ExpiresAt)) {
+		return models.Principal{}, models.ErrInvalidAPIKey
+	}
+
+	account, err := s.serviceAccountRepo.GetServiceAccountById(ctx, apiKey.ServiceAccountID)
+	switch {
+	case errors.Is(err, models.ErrServiceAccountNotFound):
+		return models.Principal{}, models.ErrInvalidAPIKey
+	case err != nil:
+		return models.Principal{}, err
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= lastUsedResolution {
+		if err := s.serviceAccountRepo.UpdateAPIKeyLastUsed(ctx, apiKey.ID, now); err != nil {
+			return models.Principal{}, err
+		}
+	}
+
+	return models.Principal{
+		UserID:         account.ID,
+		Role:           account.Role,
+		ServiceAccount: true,
+		PvzIds:         account.PvzIds,
+	}, nil
+}
+
+func generateKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return keyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashKey returns the form API keys are stored in. Keys are random, so a
+// plain hash is enough.
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,304 @@
+package serviceaccount
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/generated/mocks"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+func TestServiceAccountService_CreateServiceAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mocks.NewMockTransactionManager(ctrl)
+	mockRepo := mocks.NewMockServiceAccountRepositoryInterface(ctrl)
+	service := NewServiceAccountService(mockTxManager, mockRepo)
+
+	moderatorId := uuid.New()
+	ctx := models.WithPrincipal(context.Background(), models.Principal{UserID: moderatorId, Role: dto.UserRoleModerator})
+	pvzIds := []uuid.UUID{uuid.New()}
+
+	tests := []struct {
+		name        string
+		request     dto.PostServiceAccountsJSONRequestBody
+		mockActions func()
+		expectedErr error
+	}{
+		{
+			name:        "empty name",
+			request:     dto.PostServiceAccountsJSONRequestBody{Name: "  ", Role: "employee"},
+			expectedErr: models.ErrEmptyName,
+		},
+		{
+			name:        "invalid role",
+			request:     dto.PostServiceAccountsJSONRequestBody{Name: "warehouse", Role: "admin"},
+			expectedErr: models.ErrIncorrectUserRole,
+		},
+		{
+			name:    "name taken",
+			request: dto.PostServiceAccountsJSONRequestBody{Name: "warehouse", Role: "employee"},
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				mockRepo.EXPECT().CreateServiceAccount(gomock.Any(), gomock.Any()).Return(models.ErrServiceAccountExists)
+			},
+			expectedErr: models.ErrServiceAccountExists,
+		},
+		{
+			name:    "success",
+			request: dto.PostServiceAccountsJSONRequestBody{Name: " warehouse ", Role: "employee", PvzIds: &pvzIds},
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				mockRepo.EXPECT().CreateServiceAccount(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, account *models.ServiceAccount) error {
+						assert.Equal(t, "warehouse", account.Name)
+						assert.Equal(t, dto.UserRoleEmployee, account.Role)
+						assert.Equal(t, pvzIds, account.PvzIds)
+						assert.Equal(t, &moderatorId, account.CreatedBy)
+						account.ID = uuid.New()
+						return nil
+					})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockActions != nil {
+				tt.mockActions()
+			}
+
+			account, err := service.CreateServiceAccount(ctx, tt.request)
+			assert.Equal(t, tt.expectedErr, err)
+			if tt.expectedErr == nil {
+				assert.Equal(t, "warehouse", account.Name)
+				assert.Equal(t, "employee", account.Role)
+				assert.NotEqual(t, uuid.Nil, account.Id)
+			}
+		})
+	}
+}
+
+func TestServiceAccountService_CreateAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mocks.NewMockTransactionManager(ctrl)
+	mockRepo := mocks.NewMockServiceAccountRepositoryInterface(ctrl)
+	service := NewServiceAccountService(mockTxManager, mockRepo)
+
+	accountId := uuid.New()
+	ttl := 30
+	zeroTTL := 0
+
+	tests := []struct {
+		name          string
+		request       dto.PostServiceAccountsServiceAccountIdApiKeysJSONRequestBody
+		mockActions   func()
+		expectedErr   error
+		wantExpiresIn time.Duration
+	}{
+		{
+			name:        "empty name",
+			request:     dto.PostServiceAccountsServiceAccountIdApiKeysJSONRequestBody{},
+			expectedErr: models.ErrEmptyName,
+		},
+		{
+			name:        "invalid ttl",
+			request:     dto.PostServiceAccountsServiceAccountIdApiKeysJSONRequestBody{Name: "sync", TtlDays: &zeroTTL},
+			expectedErr: models.ErrInvalidAPIKeyTTL,
+		},
+		{
+			name:    "unknown account",
+			request: dto.PostServiceAccountsServiceAccountIdApiKeysJSONRequestBody{Name: "sync"},
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				mockRepo.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).Return(models.ErrServiceAccountNotFound)
+			},
+			expectedErr: models.ErrServiceAccountNotFound,
+		},
+		{
+			name:    "without expiry",
+			request: dto.PostServiceAccountsServiceAccountIdApiKeysJSONRequestBody{Name: "sync"},
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				mockRepo.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:    "with expiry",
+			request: dto.PostServiceAccountsServiceAccountIdApiKeysJSONRequestBody{Name: "sync", TtlDays: &ttl},
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				mockRepo.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wantExpiresIn: 30 * 24 * time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockActions != nil {
+				tt.mockActions()
+			}
+
+			key, err := service.CreateAPIKey(context.Background(), accountId, tt.request)
+			assert.Equal(t, tt.expectedErr, err)
+			if tt.expectedErr != nil {
+				return
+			}
+
+			require.NotNil(t, key.Key)
+			assert.True(t, strings.HasPrefix(*key.Key, keyPrefix))
+			assert.True(t, strings.HasPrefix(*key.Key, key.Prefix))
+			assert.Len(t, key.Prefix, displayPrefixLength)
+			assert.Equal(t, accountId, key.ServiceAccountId)
+			if tt.wantExpiresIn == 0 {
+				assert.Nil(t, key.ExpiresAt)
+			} else {
+				require.NotNil(t, key.ExpiresAt)
+				assert.WithinDuration(t, time.Now().Add(tt.wantExpiresIn), *key.ExpiresAt, time.Minute)
+			}
+		})
+	}
+}
+
+func TestServiceAccountService_ListAPIKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mocks.NewMockTransactionManager(ctrl)
+	mockRepo := mocks.NewMockServiceAccountRepositoryInterface(ctrl)
+	service := NewServiceAccountService(mockTxManager, mockRepo)
+	accountId := uuid.New()
+
+	mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(2)
+
+	mockRepo.EXPECT().GetServiceAccountById(gomock.Any(), accountId).Return(nil, models.ErrServiceAccountNotFound)
+	_, err := service.ListAPIKeys(context.Background(), accountId)
+	assert.Equal(t, models.ErrServiceAccountNotFound, err)
+
+	key := &models.APIKey{ID: uuid.New(), ServiceAccountID: accountId, Name: "sync", KeyHash: "hash", Prefix: "pvz_abcdefgh"}
+	mockRepo.EXPECT().GetServiceAccountById(gomock.Any(), accountId).Return(&models.ServiceAccount{ID: accountId}, nil)
+	mockRepo.EXPECT().ListAPIKeys(gomock.Any(), accountId).Return([]*models.APIKey{key}, nil)
+	keys, err := service.ListAPIKeys(context.Background(), accountId)
+	assert.NoError(t, err)
+	assert.Equal(t, []*dto.ApiKey{key.DTO()}, keys)
+	assert.Nil(t, keys[0].Key)
+}
+
+func TestServiceAccountService_AuthenticateAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockServiceAccountRepositoryInterface(ctrl)
+	service := NewServiceAccountService(nil, mockRepo)
+
+	const key = "pvz_secret"
+	account := &models.ServiceAccount{ID: uuid.New(), Name: "warehouse", Role: dto.UserRoleEmployee, PvzIds: []uuid.UUID{uuid.New()}}
+	past := time.Now().UTC().Add(-time.Hour)
+	future := time.Now().UTC().Add(time.Hour)
+	recent := time.Now().UTC().Add(-time.Second)
+	apiKey := func(modify func(key *models.APIKey)) *models.APIKey {
+		k := &models.APIKey{ID: uuid.New(), ServiceAccountID: account.ID, KeyHash: hashKey(key)}
+		if modify != nil {
+			modify(k)
+		}
+		return k
+	}
+
+	tests := []struct {
+		name          string
+		key           string
+		mockActions   func()
+		expectedErr   error
+		wantPrincipal models.Principal
+	}{
+		{
+			name:        "foreign format",
+			key:         "Bearer token",
+			expectedErr: models.ErrInvalidAPIKey,
+		},
+		{
+			name: "unknown key",
+			key:  key,
+			mockActions: func() {
+				mockRepo.EXPECT().GetAPIKeyByHash(gomock.Any(), hashKey(key)).Return(nil, models.ErrAPIKeyNotFound)
+			},
+			expectedErr: models.ErrInvalidAPIKey,
+		},
+		{
+			name: "lookup failure",
+			key:  key,
+			mockActions: func() {
+				mockRepo.EXPECT().GetAPIKeyByHash(gomock.Any(), hashKey(key)).Return(nil, errors.New("db error"))
+			},
+			expectedErr: errors.New("db error"),
+		},
+		{
+			name: "revoked key",
+			key:  key,
+			mockActions: func() {
+				mockRepo.EXPECT().GetAPIKeyByHash(gomock.Any(), hashKey(key)).
+					Return(apiKey(func(k *models.APIKey) { k.RevokedAt = &past }), nil)
+			},
+			expectedErr: models.ErrInvalidAPIKey,
+		},
+		{
+			name: "expired key",
+			key:  key,
+			mockActions: func() {
+				mockRepo.EXPECT().GetAPIKeyByHash(gomock.Any(), hashKey(key)).
+					Return(apiKey(func(k *models.APIKey) { k.ExpiresAt = &past }), nil)
+			},
+			expectedErr: models.ErrInvalidAPIKey,
+		},
+		{
+			name: "first use records last use",
+			key:  key,
+			mockActions: func() {
+				k := apiKey(func(k *models.APIKey) { k.ExpiresAt = &future })
+				mockRepo.EXPECT().GetAPIKeyByHash(gomock.Any(), hashKey(key)).Return(k, nil)
+				mockRepo.EXPECT().GetServiceAccountById(gomock.Any(), account.ID).Return(account, nil)
+				mockRepo.EXPECT().UpdateAPIKeyLastUsed(gomock.Any(), k.ID, gomock.Any()).Return(nil)
+			},
+			wantPrincipal: models.Principal{UserID: account.ID, Role: dto.UserRoleEmployee, ServiceAccount: true, PvzIds: account.PvzIds},
+		},
+		{
+			name: "recent use is not rewritten",
+			key:  key,
+			mockActions: func() {
+				mockRepo.EXPECT().GetAPIKeyByHash(gomock.Any(), hashKey(key)).
+					Return(apiKey(func(k *models.APIKey) { k.LastUsedAt = &recent }), nil)
+				mockRepo.EXPECT().GetServiceAccountById(gomock.Any(), account.ID).Return(account, nil)
+			},
+			wantPrincipal: models.Principal{UserID: account.ID, Role: dto.UserRoleEmployee, ServiceAccount: true, PvzIds: account.PvzIds},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockActions != nil {
+				tt.mockActions()
+			}
+
+			principal, err := service.AuthenticateAPIKey(context.Background(), tt.key)
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.wantPrincipal, principal)
+		})
+	}
+}
+
+func runInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
@@ -0,0 +1,18 @@
+package user
+
+import (
+	"context"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type ServiceInterface interface {
+	ListUsers(ctx context.Context, params models.UserListParams) (*models.UserPage, error)
+	ChangeRole(ctx context.Context, id openapi_types.UUID, request dto.PatchUsersUserIdJSONRequestBody) (*dto.User, error)
+	DeactivateUser(ctx context.Context, id openapi_types.UUID) (*dto.User, error)
+	ReactivateUser(ctx context.Context, id openapi_types.UUID) (*dto.User, error)
+	ActiveUserRole(ctx context.Context, id openapi_types.UUID) (dto.UserRole, error)
+}
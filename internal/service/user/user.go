@@ -0,0 +1,148 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+	"github.com/itisalisas/avito-backend/internal/storage"
+)
+
+type Service struct {
+	txManager storage.TransactionManager
+	userRepo  storage.UserRepositoryInterface
+	tokenRepo storage.TokenRepositoryInterface
+}
+
+func NewUserService(txManager storage.TransactionManager, userRepo storage.UserRepositoryInterface,
+	tokenRepo storage.TokenRepositoryInterface) *Service {
+	return &Service{
+		txManager: txManager,
+		userRepo:  userRepo,
+		tokenRepo: tokenRepo,
+	}
+}
+
+func (s *Service) ListUsers(ctx context.Context, params models.UserListParams) (*models.UserPage, error) {
+	return s.userRepo.ListUsers(ctx, params)
+}
+
+// ChangeRole sets the role of another user. The new role applies to their
+// next request, access tokens already issued included. Only callers that may manage
+// roles can make someone an admin or take the admin role away.
+func (s *Service) ChangeRole(ctx context.Context, id openapi_types.UUID,
+	request dto.PatchUsersUserIdJSONRequestBody) (*dto.User, error) {
+	role := dto.UserRole(request.Role)
+	if !slices.Contains(models.Roles, role) {
+		return nil, models.ErrIncorrectUserRole
+	}
+	if isSelf(ctx, id) {
+		return nil, models.ErrSelfModification
+	}
+
+	principal, _ := models.PrincipalFromContext(ctx)
+	if !principal.CanManageUser(role) {
+		return nil, models.ErrPermissionDenied
+	}
+
+	return s.update(ctx, id, func(ctx context.Context) error {
+		if err := s.checkManageable(ctx, id); err != nil {
+			return err
+		}
+		return s.userRepo.UpdateRole(ctx, id, role)
+	})
+}
+
+// DeactivateUser blocks another user from logging in and ends all of their
+// sessions. Access tokens already issued are refused by the auth middleware.
+// Like role changes, admins can only be deactivated by those who may manage
+// roles.
+func (s *Service) DeactivateUser(ctx context.Context, id openapi_types.UUID) (*dto.User, error) {
+	if isSelf(ctx, id) {
+		return nil, models.ErrSelfModification
+	}
+
+	return s.update(ctx, id, func(ctx context.Context) error {
+		if err := s.checkManageable(ctx, id); err != nil {
+			return err
+		}
+		if err := s.userRepo.SetActive(ctx, id, false); err != nil {
+			return err
+		}
+		return s.tokenRepo.RevokeUserRefreshTokens(ctx, id)
+	})
+}
+
+func (s *Service) ReactivateUser(ctx context.Context, id openapi_types.UUID) (*dto.User, error) {
+	return s.update(ctx, id, func(ctx context.Context) error {
+		if err := s.checkManageable(ctx, id); err != nil {
+			return err
+		}
+		return s.userRepo.SetActive(ctx, id, true)
+	})
+}
+
+// ActiveUserRole returns the stored role of a user who may still use their
+// tokens. Deactivated and deleted users get ErrUserDeactivated.
+func (s *Service) ActiveUserRole(ctx context.Context, id openapi_types.UUID) (dto.UserRole, error) {
+	user, err := s.userRepo.GetUserById(ctx, id)
+	switch {
+	case errors.Is(err, models.ErrUserNotFound):
+		return "", models.ErrUserDeactivated
+	case err != nil:
+		return "", err
+	case !user.Active:
+		return "", models.ErrUserDeactivated
+	default:
+		return user.Role, nil
+	}
+}
+
+// checkManageable refuses changes to the user that the caller may not make,
+// see models.Principal.CanManageUser. Callers that may manage roles can
+// change anyone, so the user is only loaded for the others.
+func (s *Service) checkManageable(ctx context.Context, id openapi_types.UUID) error {
+	principal, _ := models.PrincipalFromContext(ctx)
+	if principal.Can(models.PermRoleManage) {
+		return nil
+	}
+
+	user, err := s.userRepo.GetUserById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !principal.CanManageUser(user.Role) {
+		return models.ErrPermissionDenied
+	}
+	return nil
+}
+
+// update applies the change in a transaction and returns the updated user.
+func (s *Service) update(ctx context.Context, id openapi_types.UUID, change func(ctx context.Context) error) (*dto.User, error) {
+	var user *models.User
+	err := s.txManager.Do(ctx, func(ctx context.Context) error {
+		if err := change(ctx); err != nil {
+			return err
+		}
+
+		var err error
+		user, err = s.userRepo.GetUserById(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user.DTO(), nil
+}
+
+// isSelf reports whether the caller targets their own account, which would
+// let the last moderator lock everyone out.
+func isSelf(ctx context.Context, id openapi_types.UUID) bool {
+	actorId := models.ActorID(ctx)
+	return actorId != nil && *actorId == id
+}
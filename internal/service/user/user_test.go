@@ -0,0 +1,255 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/generated/mocks"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+func TestUserService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTxManager := mocks.NewMockTransactionManager(ctrl)
+	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	mockTokenRepo := mocks.NewMockTokenRepositoryInterface(ctrl)
+	service := NewUserService(mockTxManager, mockUserRepo, mockTokenRepo)
+
+	moderatorId := uuid.New()
+	ctx := models.WithPrincipal(context.Background(), models.Principal{
+		UserID:      moderatorId,
+		Role:        dto.UserRoleModerator,
+		Permissions: models.DefaultRolePermissions[dto.UserRoleModerator],
+	})
+	adminCtx := models.WithPrincipal(context.Background(), models.Principal{
+		UserID:      uuid.New(),
+		Role:        dto.UserRoleAdmin,
+		Permissions: models.DefaultRolePermissions[dto.UserRoleAdmin],
+	})
+	userId := uuid.New()
+	createdAt := time.Now().UTC()
+	user := func(role dto.UserRole, active bool) *models.User {
+		return &models.User{ID: userId, Email: "user@example.com", Role: role, Active: active, CreatedAt: createdAt}
+	}
+
+	tests := []struct {
+		name         string
+		method       string
+		ctx          context.Context
+		id           uuid.UUID
+		role         string
+		mockActions  func()
+		expectedErr  error
+		expectedUser *dto.User
+	}{
+		{
+			name:        "change role invalid",
+			method:      "ChangeRole",
+			id:          userId,
+			role:        "owner",
+			expectedErr: models.ErrIncorrectUserRole,
+		},
+		{
+			name:        "change own role",
+			method:      "ChangeRole",
+			id:          moderatorId,
+			role:        "employee",
+			expectedErr: models.ErrSelfModification,
+		},
+		{
+			name:   "change role user not found",
+			method: "ChangeRole",
+			id:     userId,
+			role:   "moderator",
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				mockUserRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(nil, models.ErrUserNotFound)
+			},
+			expectedErr: models.ErrUserNotFound,
+		},
+		{
+			name:        "moderator cannot grant admin",
+			method:      "ChangeRole",
+			id:          userId,
+			role:        "admin",
+			expectedErr: models.ErrPermissionDenied,
+		},
+		{
+			name:   "moderator cannot demote admin",
+			method: "ChangeRole",
+			id:     userId,
+			role:   "employee",
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				mockUserRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(user(dto.UserRoleAdmin, true), nil)
+			},
+			expectedErr: models.ErrPermissionDenied,
+		},
+		{
+			name:   "admin grants admin",
+			method: "ChangeRole",
+			ctx:    adminCtx,
+			id:     userId,
+			role:   "admin",
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				mockUserRepo.EXPECT().UpdateRole(gomock.Any(), userId, dto.UserRoleAdmin).Return(nil)
+				mockUserRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(user(dto.UserRoleAdmin, true), nil)
+			},
+			expectedUser: user(dto.UserRoleAdmin, true).DTO(),
+		},
+		{
+			name:   "change role success",
+			method: "ChangeRole",
+			id:     userId,
+			role:   "moderator",
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				mockUserRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(user(dto.UserRoleEmployee, true), nil)
+				mockUserRepo.EXPECT().UpdateRole(gomock.Any(), userId, dto.UserRoleModerator).Return(nil)
+				mockUserRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(user(dto.UserRoleModerator, true), nil)
+			},
+			expectedUser: user(dto.UserRoleModerator, true).DTO(),
+		},
+		{
+			name:        "deactivate self",
+			method:      "DeactivateUser",
+			id:          moderatorId,
+			expectedErr: models.ErrSelfModification,
+		},
+		{
+			name:   "deactivate revokes sessions",
+			method: "DeactivateUser",
+			id:     userId,
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				mockUserRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(user(dto.UserRoleEmployee, true), nil)
+				mockUserRepo.EXPECT().SetActive(gomock.Any(), userId, false).Return(nil)
+				mockTokenRepo.EXPECT().RevokeUserRefreshTokens(gomock.Any(), userId).Return(nil)
+				mockUserRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(user(dto.UserRoleEmployee, false), nil)
+			},
+			expectedUser: user(dto.UserRoleEmployee, false).DTO(),
+		},
+		{
+			name:   "deactivate user not found",
+			method: "DeactivateUser",
+			id:     userId,
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				mockUserRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(nil, models.ErrUserNotFound)
+			},
+			expectedErr: models.ErrUserNotFound,
+		},
+		{
+			name:   "moderator cannot deactivate admin",
+			method: "DeactivateUser",
+			id:     userId,
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				mockUserRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(user(dto.UserRoleAdmin, true), nil)
+			},
+			expectedErr: models.ErrPermissionDenied,
+		},
+		{
+			name:   "admin deactivates admin",
+			method: "DeactivateUser",
+			ctx:    adminCtx,
+			id:     userId,
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				mockUserRepo.EXPECT().SetActive(gomock.Any(), userId, false).Return(nil)
+				mockTokenRepo.EXPECT().RevokeUserRefreshTokens(gomock.Any(), userId).Return(nil)
+				mockUserRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(user(dto.UserRoleAdmin, false), nil)
+			},
+			expectedUser: user(dto.UserRoleAdmin, false).DTO(),
+		},
+		{
+			name:   "moderator cannot reactivate admin",
+			method: "ReactivateUser",
+			id:     userId,
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				mockUserRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(user(dto.UserRoleAdmin, false), nil)
+			},
+			expectedErr: models.ErrPermissionDenied,
+		},
+		{
+			name:   "reactivate success",
+			method: "ReactivateUser",
+			id:     userId,
+			mockActions: func() {
+				mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+				mockUserRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(user(dto.UserRoleEmployee, false), nil)
+				mockUserRepo.EXPECT().SetActive(gomock.Any(), userId, true).Return(nil)
+				mockUserRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(user(dto.UserRoleEmployee, true), nil)
+			},
+			expectedUser: user(dto.UserRoleEmployee, true).DTO(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockActions != nil {
+				tt.mockActions()
+			}
+			if tt.ctx == nil {
+				tt.ctx = ctx
+			}
+
+			var (
+				result *dto.User
+				err    error
+			)
+			switch tt.method {
+			case "ChangeRole":
+				result, err = service.ChangeRole(tt.ctx, tt.id, dto.PatchUsersUserIdJSONRequestBody{Role: tt.role})
+			case "DeactivateUser":
+				result, err = service.DeactivateUser(tt.ctx, tt.id)
+			case "ReactivateUser":
+				result, err = service.ReactivateUser(tt.ctx, tt.id)
+			}
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedUser, result)
+		})
+	}
+}
+
+func TestUserService_ActiveUserRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	service := NewUserService(nil, mockUserRepo, nil)
+	userId := uuid.New()
+
+	mockUserRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(&models.User{ID: userId, Role: dto.UserRoleModerator, Active: true}, nil)
+	role, err := service.ActiveUserRole(context.Background(), userId)
+	assert.NoError(t, err)
+	assert.Equal(t, dto.UserRoleModerator, role)
+
+	mockUserRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(&models.User{ID: userId, Role: dto.UserRoleModerator}, nil)
+	_, err = service.ActiveUserRole(context.Background(), userId)
+	assert.ErrorIs(t, err, models.ErrUserDeactivated)
+
+	mockUserRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(nil, models.ErrUserNotFound)
+	_, err = service.ActiveUserRole(context.Background(), userId)
+	assert.ErrorIs(t, err, models.ErrUserDeactivated)
+
+	mockUserRepo.EXPECT().GetUserById(gomock.Any(), userId).Return(nil, errors.New("db error"))
+	_, err = service.ActiveUserRole(context.Background(), userId)
+	assert.EqualError(t, err, "db error")
+}
+
+func runInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
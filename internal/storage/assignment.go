@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type AssignmentRepository struct {
+	*BaseRepository
+}
+
+func NewAssignmentRepository(db *sql.DB) *AssignmentRepository {
+	return &AssignmentRepository{BaseRepository: NewBaseRepository(db)}
+}
+
+func (r *AssignmentRepository) AssignPvz(ctx context.Context, userId, pvzId openapi_types.UUID) error {
+	query, args, err := squirrel.Insert("pvz_service.employee_pvz").
+		Columns("user_id", "pvz_id").
+		Values(userId, pvzId).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	_, err = r.querier(ctx).ExecContext(ctx, query, args...)
+	switch {
+	case isConstraintViolation(err, uniqueViolation, employeePvzConstraint):
+		return models.ErrAlreadyAssigned
+	case isConstraintViolation(err, foreignKeyViolation, employeeUserFKConstraint):
+		return models.ErrUserNotFound
+	case isConstraintViolation(err, foreignKeyViolation, employeePvzFKConstraint):
+		return models.ErrPvzNotFound
+	case err != nil:
+		return fmt.Errorf("failed to assign pvz: %w", err)
+	default:
+		return nil
+	}
+}
+
+func (r *AssignmentRepository) UnassignPvz(ctx context.Context, userId, pvzId openapi_types.UUID) error {
+	query, args, err := squirrel.Delete("pvz_service.employee_pvz").
+		Where(squirrel.Eq{"user_id": userId, "pvz_id": pvzId}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := r.querier(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to unassign pvz: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to unassign pvz: %w", err)
+	}
+	if affected == 0 {
+		return models.ErrAssignmentNotFound
+	}
+	return nil
+}
+
+func (r *AssignmentRepository) GetAssignedPvzIds(ctx context.Context, userId openapi_types.UUID) ([]openapi_types.UUID, error) {
+	query, args, err := squirrel.Select("pvz_id").
+		From("pvz_service.employee_pvz").
+		Where(squirrel.Eq{"user_id": userId}).
+		OrderBy("assigned_at", "pvz_id").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.querier(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assigned pvzs: %w", err)
+	}
+	defer rows.Close()
+
+	pvzIds := []openapi_types.UUID{}
+	for rows.Next() {
+		var pvzId openapi_types.UUID
+		if err := rows.Scan(&pvzId); err != nil {
+			return nil, fmt.Errorf("failed to scan pvz id: %w", err)
+		}
+		pvzIds = append(pvzIds, pvzId)
+	}
+
+	return pvzIds, rows.Err()
+}
+
+func (r *AssignmentRepository) IsAssigned(ctx context.Context, userId, pvzId openapi_types.UUID) (bool, error) {
+	query, args, err := squirrel.Select("1").
+		Prefix("select exists (").
+		From("pvz_service.employee_pvz").
+		Where(squirrel.Eq{"user_id": userId, "pvz_id": pvzId}).
+		Suffix(")").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return false, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var assigned bool
+	if err := r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(&assigned); err != nil {
+		return false, fmt.Errorf("failed to check assignment: %w", err)
+	}
+	return assigned, nil
+}
@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type AssignmentRepositoryTestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	cleanup func()
+	repo    *AssignmentRepository
+	tx      *sql.Tx
+	ctx     context.Context
+	userID  uuid.UUID
+	pvzID   uuid.UUID
+}
+
+func TestAssignmentRepositorySuite(t *testing.T) {
+	suite.Run(t, new(AssignmentRepositoryTestSuite))
+}
+
+func (s *AssignmentRepositoryTestSuite) SetupSuite() {
+	s.ctx = context.Background()
+	db := DBTestSetup()
+	if db == nil {
+		s.T().Skip("test database is not configured")
+	}
+	log.Println("migrations applied")
+	s.db = db
+	s.repo = NewAssignmentRepository(s.db)
+}
+
+func (s *AssignmentRepositoryTestSuite) TearDownSuite() {
+	err := s.db.Close()
+	if err != nil {
+		log.Fatalf("failed to close database connection: %v", err)
+	}
+	if s.cleanup != nil {
+		s.cleanup()
+	}
+}
+
+func (s *AssignmentRepositoryTestSuite) SetupTest() {
+	tx, err := s.db.BeginTx(s.ctx, nil)
+	require.NoError(s.T(), err)
+	s.tx = tx
+	s.ctx = withTx(context.Background(), tx)
+
+	s.userID = uuid.New()
+	_, err = s.tx.ExecContext(s.ctx, `
+		insert into pvz_service.user (user_id, email, password, role)
+		values ($1, $2, 'hash', 'employee')`, s.userID, s.userID.String()+"@example.com")
+	require.NoError(s.T(), err)
+
+	s.pvzID = uuid.New()
+	_, err = s.tx.ExecContext(s.ctx, `
+		insert into pvz_service.pvz (pvz_id, registration_date, city)
+		values ($1, current_date, 'Москва')`, s.pvzID)
+	require.NoError(s.T(), err)
+}
+
+func (s *AssignmentRepositoryTestSuite) TearDownTest() {
+	if s.tx != nil {
+		err := s.tx.Rollback()
+		require.NoError(s.T(), err)
+	}
+}
+
+func (s *AssignmentRepositoryTestSuite) TestAssignAndUnassign() {
+	assigned, err := s.repo.IsAssigned(s.ctx, s.userID, s.pvzID)
+	require.NoError(s.T(), err)
+	assert.False(s.T(), assigned)
+
+	require.NoError(s.T(), s.repo.AssignPvz(s.ctx, s.userID, s.pvzID))
+	assert.Equal(s.T(), models.ErrAlreadyAssigned, s.repo.AssignPvz(s.ctx, s.userID, s.pvzID))
+
+	assigned, err = s.repo.IsAssigned(s.ctx, s.userID, s.pvzID)
+	require.NoError(s.T(), err)
+	assert.True(s.T(), assigned)
+
+	pvzIDs, err := s.repo.GetAssignedPvzIds(s.ctx, s.userID)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), []uuid.UUID{s.pvzID}, pvzIDs)
+
+	require.NoError(s.T(), s.repo.UnassignPvz(s.ctx, s.userID, s.pvzID))
+	assert.Equal(s.T(), models.ErrAssignmentNotFound, s.repo.UnassignPvz(s.ctx, s.userID, s.pvzID))
+}
+
+func (s *AssignmentRepositoryTestSuite) TestAssignUnknownPvz() {
+	err := s.repo.AssignPvz(s.ctx, s.userID, uuid.New())
+	assert.Equal(s.T(), models.ErrPvzNotFound, err)
+}
@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type CityRepository struct {
+	*BaseRepository
+}
+
+func NewCityRepository(db *sql.DB) *CityRepository {
+	return &CityRepository{BaseRepository: NewBaseRepository(db)}
+}
+
+func (r *CityRepository) CreateCity(ctx context.Context, city *dto.City) error {
+	query, args, err := squirrel.Insert("pvz_service.city").
+		Columns("name").
+		Values(city.Name).
+		Suffix("returning city_id, active").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	err = r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(&city.Id, &city.Active)
+	switch {
+	case isConstraintViolation(err, uniqueViolation, cityNameConstraint):
+		return models.ErrCityExists
+	case err != nil:
+		return fmt.Errorf("failed to create city: %w", err)
+	default:
+		return nil
+	}
+}
+
+func (r *CityRepository) GetCities(ctx context.Context) ([]dto.City, error) {
+	query, args, err := squirrel.Select("city_id", "name", "active").
+		From("pvz_service.city").
+		OrderBy("name").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.querier(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cities: %w", err)
+	}
+	defer rows.Close()
+
+	cities := []dto.City{}
+	for rows.Next() {
+		var city dto.City
+		if err := rows.Scan(&city.Id, &city.Name, &city.Active); err != nil {
+			return nil, fmt.Errorf("failed to scan city: %w", err)
+		}
+		cities = append(cities, city)
+	}
+
+	return cities, rows.Err()
+}
+
+func (r *CityRepository) GetCityByName(ctx context.Context, name string) (*dto.City, error) {
+	query, args, err := squirrel.Select("city_id", "name", "active").
+		From("pvz_service.city").
+		Where(squirrel.Eq{"name": name}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	return r.scanCity(r.querier(ctx).QueryRowContext(ctx, query, args...))
+}
+
+func (r *CityRepository) DisableCity(ctx context.Context, id openapi_types.UUID) (*dto.City, error) {
+	query, args, err := squirrel.Update("pvz_service.city").
+		Set("active", false).
+		Where(squirrel.Eq{"city_id": id}).
+		Suffix("returning city_id, name, active").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	return r.scanCity(r.querier(ctx).QueryRowContext(ctx, query, args...))
+}
+
+func (r *CityRepository) scanCity(row *sql.Row) (*dto.City, error) {
+	var city dto.City
+	err := row.Scan(&city.Id, &city.Name, &city.Active)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, models.ErrCityNotFound
+	case err != nil:
+		return nil, fmt.Errorf("failed to get city: %w", err)
+	default:
+		return &city, nil
+	}
+}
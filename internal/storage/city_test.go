@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type CityRepositoryTestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	cleanup func()
+	repo    *CityRepository
+	tx      *sql.Tx
+	ctx     context.Context
+}
+
+func TestCityRepositorySuite(t *testing.T) {
+	suite.Run(t, new(CityRepositoryTestSuite))
+}
+
+func (s *CityRepositoryTestSuite) SetupSuite() {
+	s.ctx = context.Background()
+	db := DBTestSetup()
+	if db == nil {
+		s.T().Skip("test database is not configured")
+	}
+	log.Println("migrations applied")
+	s.db = db
+	s.repo = NewCityRepository(s.db)
+}
+
+func (s *CityRepositoryTestSuite) TearDownSuite() {
+	err := s.db.Close()
+	if err != nil {
+		log.Fatalf("failed to close database connection: %v", err)
+	}
+	if s.cleanup != nil {
+		s.cleanup()
+	}
+}
+
+func (s *CityRepositoryTestSuite) SetupTest() {
+	tx, err := s.db.BeginTx(s.ctx, nil)
+	require.NoError(s.T(), err)
+	s.tx = tx
+	s.ctx = withTx(context.Background(), tx)
+}
+
+func (s *CityRepositoryTestSuite) TearDownTest() {
+	if s.tx != nil {
+		err := s.tx.Rollback()
+		require.NoError(s.T(), err)
+	}
+}
+
+func (s *CityRepositoryTestSuite) TestSeededCities() {
+	cities, err := s.repo.GetCities(s.ctx)
+	require.NoError(s.T(), err)
+
+	var names []string
+	for _, city := range cities {
+		names = append(names, city.Name)
+	}
+	assert.Subset(s.T(), names, models.DefaultCities)
+}
+
+func (s *CityRepositoryTestSuite) TestCreateCity() {
+	city := &dto.City{Name: "Новосибирск"}
+	require.NoError(s.T(), s.repo.CreateCity(s.ctx, city))
+	assert.NotEqual(s.T(), uuid.Nil, city.Id)
+	assert.True(s.T(), city.Active)
+
+	err := s.repo.CreateCity(s.ctx, &dto.City{Name: "Новосибирск"})
+	assert.Equal(s.T(), models.ErrCityExists, err)
+}
+
+func (s *CityRepositoryTestSuite) TestDisableCity() {
+	city := &dto.City{Name: "Новосибирск"}
+	require.NoError(s.T(), s.repo.CreateCity(s.ctx, city))
+
+	disabled, err := s.repo.DisableCity(s.ctx, city.Id)
+	require.NoError(s.T(), err)
+	assert.False(s.T(), disabled.Active)
+
+	_, err = s.repo.DisableCity(s.ctx, uuid.New())
+	assert.Equal(s.T(), models.ErrCityNotFound, err)
+}
+
+func (s *CityRepositoryTestSuite) TestPvzRequiresKnownCity() {
+	err := NewPvzRepository(s.db).CreatePvz(s.ctx, &dto.PVZ{City: "Тверь"})
+	assert.Equal(s.T(), models.ErrIncorrectCity, err)
+}
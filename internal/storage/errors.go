@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+
+	openReceptionConstraint      = "uq_reception_open_pvz_id"
+	productTypeNameConstraint    = "product_type_name_key"
+	productTypeFKConstraint      = "fk_product_type"
+	cityNameConstraint           = "city_name_key"
+	pvzCityFKConstraint          = "fk_pvz_city"
+	employeePvzConstraint        = "pk_employee_pvz"
+	employeeUserFKConstraint     = "fk_employee_pvz_user"
+	employeePvzFKConstraint      = "fk_employee_pvz_pvz"
+	refreshTokenUserFKConstraint = "fk_refresh_token_user"
+	invitePvzFKConstraint        = "fk_invite_pvz_pvz"
+	resetTokenUserFKConstraint   = "fk_password_reset_token_user"
+	inviteUsedByFKConstraint     = "fk_invite_used_by"
+
+	serviceAccountNameConstraint     = "uq_service_account_name"
+	serviceAccountPvzFKConstraint    = "fk_service_account_pvz_pvz"
+	apiKeyServiceAccountFKConstraint = "fk_api_key_service_account"
+	rolePermissionFKConstraint       = "fk_role_permission_permission"
+
+	userTOTPUserFKConstraint       = "fk_user_totp_user"
+	recoveryCodeUserFKConstraint   = "fk_recovery_code_user"
+	loginChallengeUserFKConstraint = "fk_login_challenge_user"
+
+	userIdentityUserFKConstraint = "fk_user_identity_user"
+	userIdentityUniqueConstraint = "uq_user_identity_issuer_subject"
+)
+
+// isConstraintViolation reports whether err was raised by postgres for the
+// named constraint with the given SQLSTATE code.
+func isConstraintViolation(err error, code, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code && pgErr.ConstraintName == constraint
+}
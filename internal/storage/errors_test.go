@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsConstraintViolation(t *testing.T) {
+	violation := &pgconn.PgError{Code: uniqueViolation, ConstraintName: openReceptionConstraint}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil error", err: nil, want: false},
+		{name: "plain error", err: errors.New("boom"), want: false},
+		{name: "matching violation", err: violation, want: true},
+		{name: "wrapped violation", err: fmt.Errorf("failed to insert: %w", violation), want: true},
+		{
+			name: "other constraint",
+			err:  &pgconn.PgError{Code: uniqueViolation, ConstraintName: "user_email_key"},
+			want: false,
+		},
+		{
+			name: "other code",
+			err:  &pgconn.PgError{Code: "23503", ConstraintName: openReceptionConstraint},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isConstraintViolation(tt.err, uniqueViolation, openReceptionConstraint))
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type IdentityRepository struct {
+	*BaseRepository
+}
+
+func NewIdentityRepository(db *sql.DB) *IdentityRepository {
+	return &IdentityRepository{BaseRepository: NewBaseRepository(db)}
+}
+
+func (r *IdentityRepository) GetUserIdentity(ctx context.Context, issuer, subject string) (*models.UserIdentity, error) {
+	query, args, err := squirrel.Select("user_identity_id", "user_id", "issuer", "subject", "created_at").
+		From("pvz_service.user_identity").
+		Where(squirrel.Eq{"issuer": issuer, "subject": subject}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var identity models.UserIdentity
+	err = r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(
+		&identity.ID,
+		&identity.UserID,
+		&identity.Issuer,
+		&identity.Subject,
+		&identity.CreatedAt,
+	)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, models.ErrUserIdentityNotFound
+	case err != nil:
+		return nil, fmt.Errorf("failed to get user identity: %w", err)
+	default:
+		return &identity, nil
+	}
+}
+
+func (r *IdentityRepository) CreateUserIdentity(ctx context.Context, identity *models.UserIdentity) error {
+	query, args, err := squirrel.Insert("pvz_service.user_identity").
+		Columns("user_id", "issuer", "subject").
+		Values(identity.UserID, identity.Issuer, identity.Subject).
+		Suffix("returning user_identity_id, created_at").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	err = r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(&identity.ID, &identity.CreatedAt)
+	switch {
+	case isConstraintViolation(err, foreignKeyViolation, userIdentityUserFKConstraint):
+		return models.ErrUserNotFound
+	case isConstraintViolation(err, uniqueViolation, userIdentityUniqueConstraint):
+		return models.ErrUserIdentityExists
+	case err != nil:
+		return fmt.Errorf("failed to create user identity: %w", err)
+	default:
+		return nil
+	}
+}
+
+func (r *IdentityRepository) CreateOIDCLoginState(ctx context.Context, state *models.OIDCLoginState) error {
+	query, args, err := squirrel.Insert("pvz_service.oidc_login_state").
+		Columns("state_hash", "nonce", "code_verifier", "expires_at").
+		Values(state.StateHash, state.Nonce, state.CodeVerifier, state.ExpiresAt).
+		Suffix("returning oidc_login_state_id").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if err := r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(&state.ID); err != nil {
+		return fmt.Errorf("failed to create oidc login state: %w", err)
+	}
+	return nil
+}
+
+// UseOIDCLoginState deletes the state, so that every state works once.
+func (r *IdentityRepository) UseOIDCLoginState(ctx context.Context, stateHash string) (*models.OIDCLoginState, error) {
+	query, args, err := squirrel.Delete("pvz_service.oidc_login_state").
+		Where(squirrel.Eq{"state_hash": stateHash}).
+		Suffix("returning oidc_login_state_id, state_hash, nonce, code_verifier, expires_at").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var state models.OIDCLoginState
+	err = r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(
+		&state.ID,
+		&state.StateHash,
+		&state.Nonce,
+		&state.CodeVerifier,
+		&state.ExpiresAt,
+	)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, models.ErrOIDCStateNotFound
+	case err != nil:
+		return nil, fmt.Errorf("failed to use oidc login state: %w", err)
+	default:
+		return &state, nil
+	}
+}
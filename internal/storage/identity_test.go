@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type IdentityRepositoryTestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	cleanup func()
+	repo    *IdentityRepository
+	tx      *sql.Tx
+	ctx     context.Context
+	userID  uuid.UUID
+}
+
+func TestIdentityRepositorySuite(t *testing.T) {
+	suite.Run(t, new(IdentityRepositoryTestSuite))
+}
+
+func (s *IdentityRepositoryTestSuite) SetupSuite() {
+	s.ctx = context.Background()
+	db := DBTestSetup()
+	if db == nil {
+		s.T().Skip("test database is not configured")
+	}
+	log.Println("migrations applied")
+	s.db = db
+	s.repo = NewIdentityRepository(s.db)
+}
+
+func (s *IdentityRepositoryTestSuite) TearDownSuite() {
+	err := s.db.Close()
+	if err != nil {
+		log.Fatalf("failed to close database connection: %v", err)
+	}
+	if s.cleanup != nil {
+		s.cleanup()
+	}
+}
+
+func (s *IdentityRepositoryTestSuite) SetupTest() {
+	tx, err := s.db.BeginTx(s.ctx, nil)
+	require.NoError(s.T(), err)
+	s.tx = tx
+	s.ctx = withTx(context.Background(), tx)
+
+	s.userID = uuid.New()
+	_, err = s.tx.ExecContext(s.ctx, `
+		insert into pvz_service.user (user_id, email, password, role)
+		values ($1, $2, 'hash', 'employee')`, s.userID, s.userID.String()+"@example.com")
+	require.NoError(s.T(), err)
+}
+
+func (s *IdentityRepositoryTestSuite) TearDownTest() {
+	if s.tx != nil {
+		err := s.tx.Rollback()
+		require.NoError(s.T(), err)
+	}
+}
+
+func (s *IdentityRepositoryTestSuite) TestUserIdentity() {
+	_, err := s.repo.GetUserIdentity(s.ctx, "https://idp.example.com", "staff-1")
+	assert.ErrorIs(s.T(), err, models.ErrUserIdentityNotFound)
+
+	identity := &models.UserIdentity{UserID: s.userID, Issuer: "https://idp.example.com", Subject: "staff-1"}
+	require.NoError(s.T(), s.repo.CreateUserIdentity(s.ctx, identity))
+	assert.NotEqual(s.T(), uuid.Nil, identity.ID)
+
+	found, err := s.repo.GetUserIdentity(s.ctx, "https://idp.example.com", "staff-1")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), identity.ID, found.ID)
+	assert.Equal(s.T(), s.userID, found.UserID)
+
+	_, err = s.repo.GetUserIdentity(s.ctx, "https://other.example.com", "staff-1")
+	assert.ErrorIs(s.T(), err, models.ErrUserIdentityNotFound)
+
+	// A violated constraint aborts the transaction, so it is checked last.
+	err = s.repo.CreateUserIdentity(s.ctx, &models.UserIdentity{UserID: s.userID, Issuer: "https://idp.example.com", Subject: "staff-1"})
+	assert.ErrorIs(s.T(), err, models.ErrUserIdentityExists)
+}
+
+func (s *IdentityRepositoryTestSuite) TestCreateUserIdentity_UnknownUser() {
+	err := s.repo.CreateUserIdentity(s.ctx, &models.UserIdentity{UserID: uuid.New(), Issuer: "https://idp.example.com", Subject: "staff-2"})
+	assert.ErrorIs(s.T(), err, models.ErrUserNotFound)
+}
+
+func (s *IdentityRepositoryTestSuite) TestOIDCLoginState() {
+	state := &models.OIDCLoginState{
+		StateHash:    uuid.NewString(),
+		Nonce:        "nonce",
+		CodeVerifier: "verifier",
+		ExpiresAt:    time.Now().UTC().Add(time.Minute).Truncate(time.Microsecond),
+	}
+	require.NoError(s.T(), s.repo.CreateOIDCLoginState(s.ctx, state))
+
+	found, err := s.repo.UseOIDCLoginState(s.ctx, state.StateHash)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), state.ID, found.ID)
+	assert.Equal(s.T(), "nonce", found.Nonce)
+	assert.Equal(s.T(), "verifier", found.CodeVerifier)
+	assert.True(s.T(), state.ExpiresAt.Equal(found.ExpiresAt))
+
+	_, err = s.repo.UseOIDCLoginState(s.ctx, state.StateHash)
+	assert.ErrorIs(s.T(), err, models.ErrOIDCStateNotFound)
+}
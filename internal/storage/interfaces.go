@@ -2,7 +2,6 @@ package storage
 
 import (
 	"context"
-	"database/sql"
 	"time"
 
 	openapi_types "github.com/oapi-codegen/runtime/types"
@@ -12,34 +11,126 @@ import (
 )
 
 type ProductRepositoryInterface interface {
-	TransactionStorage
 	AddProduct(ctx context.Context, product *dto.Product) error
 	GetLastProduct(ctx context.Context, receptionId openapi_types.UUID) (*dto.Product, error)
 	DeleteProductById(ctx context.Context, productId openapi_types.UUID) error
 }
 
+type ProductTypeRepositoryInterface interface {
+	CreateProductType(ctx context.Context, productType *dto.ProductType) error
+	GetProductTypes(ctx context.Context) ([]dto.ProductType, error)
+	GetProductTypeByName(ctx context.Context, name string) (*dto.ProductType, error)
+	RenameProductType(ctx context.Context, id openapi_types.UUID, name string) (*dto.ProductType, error)
+	DeactivateProductType(ctx context.Context, id openapi_types.UUID) (*dto.ProductType, error)
+}
+
 type ReceptionRepositoryInterface interface {
-	TransactionStorage
 	GetLastReceptionByPvzId(ctx context.Context, pvzId openapi_types.UUID) (*dto.Reception, error)
 	AddReception(ctx context.Context, reception *dto.Reception) error
 	CloseLastReception(ctx context.Context, receptionId openapi_types.UUID) (*dto.Reception, error)
+	GetReceptionById(ctx context.Context, receptionId openapi_types.UUID) (*dto.Reception, error)
+	UpdateReceptionStatus(ctx context.Context, receptionId openapi_types.UUID, status dto.ReceptionStatus) (*dto.Reception, error)
 }
 
 type PvzRepositoryInterface interface {
-	TransactionStorage
 	CreatePvz(ctx context.Context, pvz *dto.PVZ) error
-	GetPvzList(ctx context.Context, startTime *time.Time, endTime *time.Time, page uint64, limit uint64) ([]*models.ExtendedPvz, error)
+	GetPvzList(ctx context.Context, params models.PvzListParams) (*models.PvzPage, error)
 	GetAllPVZs(ctx context.Context) ([]dto.PVZ, error)
+	GetPvzById(ctx context.Context, id openapi_types.UUID) (*dto.PVZ, error)
+	UpdatePvz(ctx context.Context, id openapi_types.UUID, update *dto.PVZUpdate) (*dto.PVZ, error)
+	DecommissionPvz(ctx context.Context, id openapi_types.UUID) (*dto.PVZ, error)
+}
+
+type CityRepositoryInterface interface {
+	CreateCity(ctx context.Context, city *dto.City) error
+	GetCities(ctx context.Context) ([]dto.City, error)
+	GetCityByName(ctx context.Context, name string) (*dto.City, error)
+	DisableCity(ctx context.Context, id openapi_types.UUID) (*dto.City, error)
 }
 
 type UserRepositoryInterface interface {
-	TransactionStorage
 	CreateUser(ctx context.Context, user *models.User) error
 	GetUserByEmail(ctx context.Context, email openapi_types.Email) (*models.User, error)
+	GetUserById(ctx context.Context, id openapi_types.UUID) (*models.User, error)
+	UpdatePassword(ctx context.Context, id openapi_types.UUID, passwordHash string) error
+	RehashPassword(ctx context.Context, id openapi_types.UUID, oldHash, newHash string) (bool, error)
+	ListUsers(ctx context.Context, params models.UserListParams) (*models.UserPage, error)
+	UpdateRole(ctx context.Context, id openapi_types.UUID, role dto.UserRole) error
+	SetActive(ctx context.Context, id openapi_types.UUID, active bool) error
+	UpdateLastLogin(ctx context.Context, id openapi_types.UUID, at time.Time) error
+}
+
+type AssignmentRepositoryInterface interface {
+	AssignPvz(ctx context.Context, userId, pvzId openapi_types.UUID) error
+	UnassignPvz(ctx context.Context, userId, pvzId openapi_types.UUID) error
+	GetAssignedPvzIds(ctx context.Context, userId openapi_types.UUID) ([]openapi_types.UUID, error)
+	IsAssigned(ctx context.Context, userId, pvzId openapi_types.UUID) (bool, error)
+}
+
+type TokenRepositoryInterface interface {
+	CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error
+	GetRefreshTokenByHash(ctx context.Context, hash string) (*models.RefreshToken, error)
+	RevokeRefreshToken(ctx context.Context, id openapi_types.UUID) error
+	RevokeUserRefreshTokens(ctx context.Context, userId openapi_types.UUID) error
+	RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error
+	IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error)
+	CreatePasswordResetToken(ctx context.Context, token *models.PasswordResetToken) error
+	GetPasswordResetTokenByHash(ctx context.Context, hash string) (*models.PasswordResetToken, error)
+	UsePasswordResetToken(ctx context.Context, id openapi_types.UUID) error
+	UseUserPasswordResetTokens(ctx context.Context, userId openapi_types.UUID) error
+}
+
+type InviteRepositoryInterface interface {
+	CreateInvite(ctx context.Context, invite *models.Invite) error
+	GetInviteByHash(ctx context.Context, hash string) (*models.Invite, error)
+	UseInvite(ctx context.Context, id, userId openapi_types.UUID) error
+}
+
+type LoginAttemptRepositoryInterface interface {
+	GetLoginFailures(ctx context.Context, subject string) (*models.LoginFailures, error)
+	RecordLoginFailure(ctx context.Context, subject string, at, windowStart time.Time) (*models.LoginFailures, error)
+	BlockLogin(ctx context.Context, subject string, until time.Time) error
+	ResetLoginFailures(ctx context.Context, subject string) error
+}
+
+type ServiceAccountRepositoryInterface interface {
+	CreateServiceAccount(ctx context.Context, account *models.ServiceAccount) error
+	GetServiceAccountById(ctx context.Context, id openapi_types.UUID) (*models.ServiceAccount, error)
+	ListServiceAccounts(ctx context.Context) ([]*models.ServiceAccount, error)
+	CreateAPIKey(ctx context.Context, key *models.APIKey) error
+	GetAPIKeyByHash(ctx context.Context, hash string) (*models.APIKey, error)
+	ListAPIKeys(ctx context.Context, accountId openapi_types.UUID) ([]*models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id openapi_types.UUID) error
+	UpdateAPIKeyLastUsed(ctx context.Context, id openapi_types.UUID, at time.Time) error
+}
+
+type RoleRepositoryInterface interface {
+	GetRolePermissions(ctx context.Context, role dto.UserRole) ([]models.Permission, error)
+	ListRolePermissions(ctx context.Context) (map[dto.UserRole][]models.Permission, error)
+	SetRolePermissions(ctx context.Context, role dto.UserRole, permissions []models.Permission) error
+}
+
+type TwoFactorRepositoryInterface interface {
+	GetTOTP(ctx context.Context, userId openapi_types.UUID) (*models.TOTP, error)
+	SaveTOTP(ctx context.Context, totp *models.TOTP) error
+	ConfirmTOTP(ctx context.Context, userId openapi_types.UUID, at time.Time) error
+	UseTOTPStep(ctx context.Context, userId openapi_types.UUID, step int64) error
+	DeleteTOTP(ctx context.Context, userId openapi_types.UUID) error
+	ReplaceRecoveryCodes(ctx context.Context, userId openapi_types.UUID, hashes []string) error
+	UseRecoveryCode(ctx context.Context, userId openapi_types.UUID, hash string) error
+	CreateLoginChallenge(ctx context.Context, challenge *models.LoginChallenge) error
+	GetLoginChallengeByHash(ctx context.Context, hash string) (*models.LoginChallenge, error)
+	RecordLoginChallengeAttempt(ctx context.Context, id openapi_types.UUID) (int, error)
+	UseLoginChallenge(ctx context.Context, id openapi_types.UUID) error
+}
+
+type IdentityRepositoryInterface interface {
+	GetUserIdentity(ctx context.Context, issuer, subject string) (*models.UserIdentity, error)
+	CreateUserIdentity(ctx context.Context, identity *models.UserIdentity) error
+	CreateOIDCLoginState(ctx context.Context, state *models.OIDCLoginState) error
+	UseOIDCLoginState(ctx context.Context, stateHash string) (*models.OIDCLoginState, error)
 }
 
-type TransactionStorage interface {
-	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
-	Commit() error
-	Rollback() error
+type TransactionManager interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
 }
@@ -0,0 +1,163 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type InviteRepository struct {
+	*BaseRepository
+}
+
+func NewInviteRepository(db *sql.DB) *InviteRepository {
+	return &InviteRepository{BaseRepository: NewBaseRepository(db)}
+}
+
+// CreateInvite stores the invite together with its PVZ bindings; call it
+// inside a transaction so both are written atomically.
+func (r *InviteRepository) CreateInvite(ctx context.Context, invite *models.Invite) error {
+	query, args, err := squirrel.Insert("pvz_service.invite").
+		Columns("code_hash", "role", "created_by", "expires_at").
+		Values(invite.CodeHash, invite.Role, invite.CreatedBy, invite.ExpiresAt).
+		Suffix("returning invite_id").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if err := r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(&invite.ID); err != nil {
+		return fmt.Errorf("failed to create invite: %w", err)
+	}
+
+	if len(invite.PvzIds) == 0 {
+		return nil
+	}
+
+	insert := squirrel.Insert("pvz_service.invite_pvz").
+		Columns("invite_id", "pvz_id").
+		Suffix("on conflict do nothing").
+		PlaceholderFormat(squirrel.Dollar)
+	for _, pvzId := range invite.PvzIds {
+		insert = insert.Values(invite.ID, pvzId)
+	}
+
+	query, args, err = insert.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	_, err = r.querier(ctx).ExecContext(ctx, query, args...)
+	switch {
+	case isConstraintViolation(err, foreignKeyViolation, invitePvzFKConstraint):
+		return models.ErrPvzNotFound
+	case err != nil:
+		return fmt.Errorf("failed to bind invite pvzs: %w", err)
+	default:
+		return nil
+	}
+}
+
+func (r *InviteRepository) GetInviteByHash(ctx context.Context, hash string) (*models.Invite, error) {
+	query, args, err := squirrel.Select("invite_id", "code_hash", "role", "created_by", "expires_at", "used_at").
+		From("pvz_service.invite").
+		Where(squirrel.Eq{"code_hash": hash}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var invite models.Invite
+	err = r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(
+		&invite.ID,
+		&invite.CodeHash,
+		&invite.Role,
+		&invite.CreatedBy,
+		&invite.ExpiresAt,
+		&invite.UsedAt,
+	)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, models.ErrInviteNotFound
+	case err != nil:
+		return nil, fmt.Errorf("failed to get invite: %w", err)
+	}
+
+	invite.PvzIds, err = r.getInvitePvzIds(ctx, invite.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+func (r *InviteRepository) getInvitePvzIds(ctx context.Context, inviteId openapi_types.UUID) ([]openapi_types.UUID, error) {
+	query, args, err := squirrel.Select("pvz_id").
+		From("pvz_service.invite_pvz").
+		Where(squirrel.Eq{"invite_id": inviteId}).
+		OrderBy("pvz_id").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.querier(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invite pvzs: %w", err)
+	}
+	defer rows.Close()
+
+	pvzIds := []openapi_types.UUID{}
+	for rows.Next() {
+		var pvzId openapi_types.UUID
+		if err := rows.Scan(&pvzId); err != nil {
+			return nil, fmt.Errorf("failed to scan pvz id: %w", err)
+		}
+		pvzIds = append(pvzIds, pvzId)
+	}
+
+	return pvzIds, rows.Err()
+}
+
+// UseInvite marks the invite as redeemed by the user. It returns
+// ErrInviteNotFound if the invite does not exist or was already used.
+func (r *InviteRepository) UseInvite(ctx context.Context, id, userId openapi_types.UUID) error {
+	query, args, err := squirrel.Update("pvz_service.invite").
+		Set("used_at", squirrel.Expr("current_timestamp")).
+		Set("used_by", userId).
+		Where(squirrel.Eq{"invite_id": id, "used_at": nil}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := r.querier(ctx).ExecContext(ctx, query, args...)
+	switch {
+	case isConstraintViolation(err, foreignKeyViolation, inviteUsedByFKConstraint):
+		return models.ErrUserNotFound
+	case err != nil:
+		return fmt.Errorf("failed to use invite: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to use invite: %w", err)
+	}
+	if affected == 0 {
+		return models.ErrInviteNotFound
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type InviteRepositoryTestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	cleanup func()
+	repo    *InviteRepository
+	tx      *sql.Tx
+	ctx     context.Context
+	userID  uuid.UUID
+	pvzID   uuid.UUID
+}
+
+func TestInviteRepositorySuite(t *testing.T) {
+	suite.Run(t, new(InviteRepositoryTestSuite))
+}
+
+func (s *InviteRepositoryTestSuite) SetupSuite() {
+	s.ctx = context.Background()
+	db := DBTestSetup()
+	if db == nil {
+		s.T().Skip("test database is not configured")
+	}
+	log.Println("migrations applied")
+	s.db = db
+	s.repo = NewInviteRepository(s.db)
+}
+
+func (s *InviteRepositoryTestSuite) TearDownSuite() {
+	err := s.db.Close()
+	if err != nil {
+		log.Fatalf("failed to close database connection: %v", err)
+	}
+	if s.cleanup != nil {
+		s.cleanup()
+	}
+}
+
+func (s *InviteRepositoryTestSuite) SetupTest() {
+	tx, err := s.db.BeginTx(s.ctx, nil)
+	require.NoError(s.T(), err)
+	s.tx = tx
+	s.ctx = withTx(context.Background(), tx)
+
+	s.userID = uuid.New()
+	_, err = s.tx.ExecContext(s.ctx, `
+		insert into pvz_service.user (user_id, email, password, role)
+		values ($1, $2, 'hash', 'employee')`, s.userID, s.userID.String()+"@example.com")
+	require.NoError(s.T(), err)
+
+	s.pvzID = uuid.New()
+	_, err = s.tx.ExecContext(s.ctx, `
+		insert into pvz_service.pvz (pvz_id, registration_date, city)
+		values ($1, current_date, 'Москва')`, s.pvzID)
+	require.NoError(s.T(), err)
+}
+
+func (s *InviteRepositoryTestSuite) TearDownTest() {
+	if s.tx != nil {
+		err := s.tx.Rollback()
+		require.NoError(s.T(), err)
+	}
+}
+
+func (s *InviteRepositoryTestSuite) TestInviteRedemption() {
+	invite := &models.Invite{
+		CodeHash:  uuid.NewString(),
+		Role:      dto.UserRoleEmployee,
+		PvzIds:    []uuid.UUID{s.pvzID},
+		ExpiresAt: time.Now().UTC().Add(time.Hour),
+	}
+	require.NoError(s.T(), s.repo.CreateInvite(s.ctx, invite))
+
+	found, err := s.repo.GetInviteByHash(s.ctx, invite.CodeHash)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), invite.ID, found.ID)
+	assert.Equal(s.T(), dto.UserRoleEmployee, found.Role)
+	assert.Equal(s.T(), []uuid.UUID{s.pvzID}, found.PvzIds)
+	assert.Nil(s.T(), found.UsedAt)
+
+	require.NoError(s.T(), s.repo.UseInvite(s.ctx, invite.ID, s.userID))
+	assert.ErrorIs(s.T(), s.repo.UseInvite(s.ctx, invite.ID, s.userID), models.ErrInviteNotFound)
+
+	found, err = s.repo.GetInviteByHash(s.ctx, invite.CodeHash)
+	require.NoError(s.T(), err)
+	assert.NotNil(s.T(), found.UsedAt)
+}
+
+func (s *InviteRepositoryTestSuite) TestInviteNotFound() {
+	_, err := s.repo.GetInviteByHash(s.ctx, uuid.NewString())
+	assert.ErrorIs(s.T(), err, models.ErrInviteNotFound)
+}
+
+func (s *InviteRepositoryTestSuite) TestInviteUnknownPvz() {
+	err := s.repo.CreateInvite(s.ctx, &models.Invite{
+		CodeHash:  uuid.NewString(),
+		Role:      dto.UserRoleEmployee,
+		PvzIds:    []uuid.UUID{uuid.New()},
+		ExpiresAt: time.Now().UTC().Add(time.Hour),
+	})
+	assert.ErrorIs(s.T(), err, models.ErrPvzNotFound)
+}
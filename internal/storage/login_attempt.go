@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type LoginAttemptRepository struct {
+	*BaseRepository
+}
+
+func NewLoginAttemptRepository(db *sql.DB) *LoginAttemptRepository {
+	return &LoginAttemptRepository{BaseRepository: NewBaseRepository(db)}
+}
+
+// GetLoginFailures returns a zero record if the subject has no failures.
+func (r *LoginAttemptRepository) GetLoginFailures(ctx context.Context, subject string) (*models.LoginFailures, error) {
+	query, args, err := squirrel.Select("subject", "failures", "last_failure_at", "blocked_until").
+		From("pvz_service.login_failure").
+		Where(squirrel.Eq{"subject": subject}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	failures, err := scanLoginFailures(r.querier(ctx).QueryRowContext(ctx, query, args...))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return &models.LoginFailures{Subject: subject}, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to get login failures: %w", err)
+	default:
+		return failures, nil
+	}
+}
+
+// RecordLoginFailure atomically counts a failed attempt at the given time.
+// Failures recorded before windowStart are forgotten first.
+func (r *LoginAttemptRepository) RecordLoginFailure(ctx context.Context, subject string,
+	at, windowStart time.Time) (*models.LoginFailures, error) {
+	query, args, err := squirrel.Insert("pvz_service.login_failure").
+		Columns("subject", "failures", "last_failure_at").
+		Values(subject, 1, at).
+		Suffix(`on conflict (subject) do update set
+			failures = case when login_failure.last_failure_at < ? then 1 else login_failure.failures + 1 end,
+			last_failure_at = excluded.last_failure_at
+			returning subject, failures, last_failure_at, blocked_until`, windowStart).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	failures, err := scanLoginFailures(r.querier(ctx).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to record login failure: %w", err)
+	}
+	return failures, nil
+}
+
+func (r *LoginAttemptRepository) BlockLogin(ctx context.Context, subject string, until time.Time) error {
+	query, args, err := squirrel.Update("pvz_service.login_failure").
+		Set("blocked_until", until).
+		Where(squirrel.Eq{"subject": subject}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := r.querier(ctx).ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to block login: %w", err)
+	}
+	return nil
+}
+
+func (r *LoginAttemptRepository) ResetLoginFailures(ctx context.Context, subject string) error {
+	query, args, err := squirrel.Delete("pvz_service.login_failure").
+		Where(squirrel.Eq{"subject": subject}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := r.querier(ctx).ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to reset login failures: %w", err)
+	}
+	return nil
+}
+
+func scanLoginFailures(row *sql.Row) (*models.LoginFailures, error) {
+	var failures models.LoginFailures
+	err := row.Scan(
+		&failures.Subject,
+		&failures.Failures,
+		&failures.LastFailureAt,
+		&failures.BlockedUntil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &failures, nil
+}
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type LoginAttemptRepositoryTestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	cleanup func()
+	repo    *LoginAttemptRepository
+	tx      *sql.Tx
+	ctx     context.Context
+	subject string
+}
+
+func TestLoginAttemptRepositorySuite(t *testing.T) {
+	suite.Run(t, new(LoginAttemptRepositoryTestSuite))
+}
+
+func (s *LoginAttemptRepositoryTestSuite) SetupSuite() {
+	s.ctx = context.Background()
+	db := DBTestSetup()
+	if db == nil {
+		s.T().Skip("test database is not configured")
+	}
+	log.Println("migrations applied")
+	s.db = db
+	s.repo = NewLoginAttemptRepository(s.db)
+}
+
+func (s *LoginAttemptRepositoryTestSuite) TearDownSuite() {
+	err := s.db.Close()
+	if err != nil {
+		log.Fatalf("failed to close database connection: %v", err)
+	}
+	if s.cleanup != nil {
+		s.cleanup()
+	}
+}
+
+func (s *LoginAttemptRepositoryTestSuite) SetupTest() {
+	tx, err := s.db.BeginTx(s.ctx, nil)
+	require.NoError(s.T(), err)
+	s.tx = tx
+	s.ctx = withTx(context.Background(), tx)
+	s.subject = "email:" + uuid.NewString()
+}
+
+func (s *LoginAttemptRepositoryTestSuite) TearDownTest() {
+	if s.tx != nil {
+		err := s.tx.Rollback()
+		require.NoError(s.T(), err)
+	}
+}
+
+func (s *LoginAttemptRepositoryTestSuite) TestFailuresWithinWindow() {
+	failures, err := s.repo.GetLoginFailures(s.ctx, s.subject)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 0, failures.Failures)
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	for i := 1; i <= 3; i++ {
+		failures, err = s.repo.RecordLoginFailure(s.ctx, s.subject, now, now.Add(-time.Minute))
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), i, failures.Failures)
+	}
+
+	until := now.Add(time.Minute)
+	require.NoError(s.T(), s.repo.BlockLogin(s.ctx, s.subject, until))
+	failures, err = s.repo.GetLoginFailures(s.ctx, s.subject)
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), failures.BlockedUntil)
+	assert.True(s.T(), until.Equal(*failures.BlockedUntil))
+
+	later := now.Add(time.Hour)
+	failures, err = s.repo.RecordLoginFailure(s.ctx, s.subject, later, later.Add(-time.Minute))
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, failures.Failures)
+}
+
+func (s *LoginAttemptRepositoryTestSuite) TestResetLoginFailures() {
+	now := time.Now().UTC()
+	_, err := s.repo.RecordLoginFailure(s.ctx, s.subject, now, now.Add(-time.Minute))
+	require.NoError(s.T(), err)
+
+	require.NoError(s.T(), s.repo.ResetLoginFailures(s.ctx, s.subject))
+	failures, err := s.repo.GetLoginFailures(s.ctx, s.subject)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 0, failures.Failures)
+	assert.Nil(s.T(), failures.BlockedUntil)
+}
@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type AssignmentRepository struct {
+	storage *Storage
+}
+
+func NewAssignmentRepository(storage *Storage) *AssignmentRepository {
+	return &AssignmentRepository{storage: storage}
+}
+
+func (r *AssignmentRepository) AssignPvz(ctx context.Context, userId, pvzId openapi_types.UUID) error {
+	return r.storage.run(ctx, func(st *state) error {
+		if _, ok := st.users[userId]; !ok {
+			return models.ErrUserNotFound
+		}
+		if st.findPvz(pvzId) < 0 {
+			return models.ErrPvzNotFound
+		}
+		if st.findAssignment(userId, pvzId) >= 0 {
+			return models.ErrAlreadyAssigned
+		}
+
+		st.assignments = append(st.assignments, dto.PvzAssignment{UserId: userId, PvzId: pvzId})
+		return nil
+	})
+}
+
+func (r *AssignmentRepository) UnassignPvz(ctx context.Context, userId, pvzId openapi_types.UUID) error {
+	return r.storage.run(ctx, func(st *state) error {
+		i := st.findAssignment(userId, pvzId)
+		if i < 0 {
+			return models.ErrAssignmentNotFound
+		}
+		st.assignments = slices.Delete(st.assignments, i, i+1)
+		return nil
+	})
+}
+
+func (r *AssignmentRepository) GetAssignedPvzIds(ctx context.Context, userId openapi_types.UUID) ([]openapi_types.UUID, error) {
+	pvzIds := []openapi_types.UUID{}
+	err := r.storage.run(ctx, func(st *state) error {
+		for _, assignment := range st.assignments {
+			if assignment.UserId == userId {
+				pvzIds = append(pvzIds, assignment.PvzId)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pvzIds, nil
+}
+
+func (r *AssignmentRepository) IsAssigned(ctx context.Context, userId, pvzId openapi_types.UUID) (bool, error) {
+	var assigned bool
+	err := r.storage.run(ctx, func(st *state) error {
+		assigned = st.findAssignment(userId, pvzId) >= 0
+		return nil
+	})
+	return assigned, err
+}
+
+func (st *state) findAssignment(userId, pvzId openapi_types.UUID) int {
+	return slices.IndexFunc(st.assignments, func(assignment dto.PvzAssignment) bool {
+		return assignment.UserId == userId && assignment.PvzId == pvzId
+	})
+}
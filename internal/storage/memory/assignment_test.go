@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+func TestAssignmentRepository(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	repo := NewAssignmentRepository(s)
+
+	user := &models.User{Email: "employee@example.com", Role: dto.UserRoleEmployee}
+	require.NoError(t, NewUserRepository(s).CreateUser(ctx, user))
+	pvz := &dto.PVZ{City: "Москва"}
+	require.NoError(t, NewPvzRepository(s).CreatePvz(ctx, pvz))
+
+	assert.ErrorIs(t, repo.AssignPvz(ctx, uuid.New(), *pvz.Id), models.ErrUserNotFound)
+	assert.ErrorIs(t, repo.AssignPvz(ctx, user.ID, uuid.New()), models.ErrPvzNotFound)
+
+	require.NoError(t, repo.AssignPvz(ctx, user.ID, *pvz.Id))
+	assert.ErrorIs(t, repo.AssignPvz(ctx, user.ID, *pvz.Id), models.ErrAlreadyAssigned)
+
+	assigned, err := repo.IsAssigned(ctx, user.ID, *pvz.Id)
+	require.NoError(t, err)
+	assert.True(t, assigned)
+
+	pvzIds, err := repo.GetAssignedPvzIds(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{*pvz.Id}, pvzIds)
+
+	require.NoError(t, repo.UnassignPvz(ctx, user.ID, *pvz.Id))
+	assert.ErrorIs(t, repo.UnassignPvz(ctx, user.ID, *pvz.Id), models.ErrAssignmentNotFound)
+
+	assigned, err = repo.IsAssigned(ctx, user.ID, *pvz.Id)
+	require.NoError(t, err)
+	assert.False(t, assigned)
+}
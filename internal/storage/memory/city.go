@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type CityRepository struct {
+	storage *Storage
+}
+
+func NewCityRepository(storage *Storage) *CityRepository {
+	return &CityRepository{storage: storage}
+}
+
+func (r *CityRepository) CreateCity(ctx context.Context, city *dto.City) error {
+	return r.storage.run(ctx, func(st *state) error {
+		if st.findCity(city.Name) >= 0 {
+			return models.ErrCityExists
+		}
+
+		city.Id = uuid.New()
+		city.Active = true
+		st.cities = append(st.cities, *city)
+		return nil
+	})
+}
+
+func (r *CityRepository) GetCities(ctx context.Context) ([]dto.City, error) {
+	cities := []dto.City{}
+	err := r.storage.run(ctx, func(st *state) error {
+		cities = append(cities, st.cities...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(cities, func(a, b dto.City) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return cities, nil
+}
+
+func (r *CityRepository) GetCityByName(ctx context.Context, name string) (*dto.City, error) {
+	var city dto.City
+	err := r.storage.run(ctx, func(st *state) error {
+		i := st.findCity(name)
+		if i < 0 {
+			return models.ErrCityNotFound
+		}
+		city = st.cities[i]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &city, nil
+}
+
+func (r *CityRepository) DisableCity(ctx context.Context, id openapi_types.UUID) (*dto.City, error) {
+	var city dto.City
+	err := r.storage.run(ctx, func(st *state) error {
+		i := slices.IndexFunc(st.cities, func(city dto.City) bool {
+			return city.Id == id
+		})
+		if i < 0 {
+			return models.ErrCityNotFound
+		}
+		st.cities[i].Active = false
+		city = st.cities[i]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &city, nil
+}
+
+func (st *state) findCity(name string) int {
+	return slices.IndexFunc(st.cities, func(city dto.City) bool {
+		return city.Name == name
+	})
+}
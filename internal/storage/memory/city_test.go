@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+func TestCityRepository(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	repo := NewCityRepository(s)
+
+	t.Run("default cities are seeded", func(t *testing.T) {
+		cities, err := repo.GetCities(ctx)
+		require.NoError(t, err)
+		assert.Len(t, cities, len(models.DefaultCities))
+	})
+
+	novosibirsk := &dto.City{Name: "Новосибирск"}
+	require.NoError(t, repo.CreateCity(ctx, novosibirsk))
+	assert.NotEqual(t, uuid.Nil, novosibirsk.Id)
+	assert.True(t, novosibirsk.Active)
+
+	t.Run("duplicate name", func(t *testing.T) {
+		err := repo.CreateCity(ctx, &dto.City{Name: "Новосибирск"})
+		assert.Equal(t, models.ErrCityExists, err)
+	})
+
+	t.Run("pvz in new city", func(t *testing.T) {
+		err := NewPvzRepository(s).CreatePvz(ctx, &dto.PVZ{City: "Новосибирск"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("pvz in unknown city", func(t *testing.T) {
+		err := NewPvzRepository(s).CreatePvz(ctx, &dto.PVZ{City: "Тверь"})
+		assert.Equal(t, models.ErrIncorrectCity, err)
+	})
+
+	t.Run("disable", func(t *testing.T) {
+		disabled, err := repo.DisableCity(ctx, novosibirsk.Id)
+		require.NoError(t, err)
+		assert.False(t, disabled.Active)
+
+		found, err := repo.GetCityByName(ctx, "Новосибирск")
+		require.NoError(t, err)
+		assert.False(t, found.Active)
+	})
+
+	t.Run("disable unknown", func(t *testing.T) {
+		_, err := repo.DisableCity(ctx, uuid.New())
+		assert.Equal(t, models.ErrCityNotFound, err)
+	})
+}
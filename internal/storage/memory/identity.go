@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type IdentityRepository struct {
+	storage *Storage
+}
+
+func NewIdentityRepository(storage *Storage) *IdentityRepository {
+	return &IdentityRepository{storage: storage}
+}
+
+func (r *IdentityRepository) GetUserIdentity(ctx context.Context, issuer, subject string) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	err := r.storage.run(ctx, func(st *state) error {
+		i := slices.IndexFunc(st.userIdentities, func(identity models.UserIdentity) bool {
+			return identity.Issuer == issuer && identity.Subject == subject
+		})
+		if i < 0 {
+			return models.ErrUserIdentityNotFound
+		}
+		identity = st.userIdentities[i]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *IdentityRepository) CreateUserIdentity(ctx context.Context, identity *models.UserIdentity) error {
+	return r.storage.run(ctx, func(st *state) error {
+		if _, ok := st.users[identity.UserID]; !ok {
+			return models.ErrUserNotFound
+		}
+		if slices.ContainsFunc(st.userIdentities, func(existing models.UserIdentity) bool {
+			return existing.Issuer == identity.Issuer && existing.Subject == identity.Subject
+		}) {
+			return models.ErrUserIdentityExists
+		}
+
+		identity.ID = uuid.New()
+		identity.CreatedAt = time.Now().UTC()
+		st.userIdentities = append(st.userIdentities, *identity)
+		return nil
+	})
+}
+
+func (r *IdentityRepository) CreateOIDCLoginState(ctx context.Context, loginState *models.OIDCLoginState) error {
+	return r.storage.run(ctx, func(st *state) error {
+		loginState.ID = uuid.New()
+		st.oidcStates = append(st.oidcStates, *loginState)
+		return nil
+	})
+}
+
+// UseOIDCLoginState removes the state, so that every state works once.
+func (r *IdentityRepository) UseOIDCLoginState(ctx context.Context, stateHash string) (*models.OIDCLoginState, error) {
+	var loginState models.OIDCLoginState
+	err := r.storage.run(ctx, func(st *state) error {
+		i := slices.IndexFunc(st.oidcStates, func(loginState models.OIDCLoginState) bool {
+			return loginState.StateHash == stateHash
+		})
+		if i < 0 {
+			return models.ErrOIDCStateNotFound
+		}
+		loginState = st.oidcStates[i]
+		st.oidcStates = slices.Delete(st.oidcStates, i, i+1)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &loginState, nil
+}
@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+func TestIdentityRepository_UserIdentity(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	repo := NewIdentityRepository(s)
+
+	user := &models.User{Email: "staff@example.com", Role: dto.UserRoleEmployee}
+	require.NoError(t, NewUserRepository(s).CreateUser(ctx, user))
+
+	_, err := repo.GetUserIdentity(ctx, "https://idp.example.com", "staff-1")
+	assert.ErrorIs(t, err, models.ErrUserIdentityNotFound)
+
+	identity := &models.UserIdentity{UserID: user.ID, Issuer: "https://idp.example.com", Subject: "staff-1"}
+	require.NoError(t, repo.CreateUserIdentity(ctx, identity))
+
+	found, err := repo.GetUserIdentity(ctx, "https://idp.example.com", "staff-1")
+	require.NoError(t, err)
+	assert.Equal(t, identity.ID, found.ID)
+	assert.Equal(t, user.ID, found.UserID)
+
+	err = repo.CreateUserIdentity(ctx, &models.UserIdentity{UserID: user.ID, Issuer: "https://idp.example.com", Subject: "staff-1"})
+	assert.ErrorIs(t, err, models.ErrUserIdentityExists)
+	err = repo.CreateUserIdentity(ctx, &models.UserIdentity{UserID: uuid.New(), Issuer: "https://idp.example.com", Subject: "staff-2"})
+	assert.ErrorIs(t, err, models.ErrUserNotFound)
+}
+
+func TestIdentityRepository_OIDCLoginState(t *testing.T) {
+	ctx := context.Background()
+	repo := NewIdentityRepository(New())
+
+	state := &models.OIDCLoginState{StateHash: "hash", Nonce: "nonce", CodeVerifier: "verifier", ExpiresAt: time.Now().Add(time.Minute)}
+	require.NoError(t, repo.CreateOIDCLoginState(ctx, state))
+
+	found, err := repo.UseOIDCLoginState(ctx, "hash")
+	require.NoError(t, err)
+	assert.Equal(t, state.ID, found.ID)
+	assert.Equal(t, "verifier", found.CodeVerifier)
+
+	_, err = repo.UseOIDCLoginState(ctx, "hash")
+	assert.ErrorIs(t, err, models.ErrOIDCStateNotFound)
+}
@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type InviteRepository struct {
+	storage *Storage
+}
+
+func NewInviteRepository(storage *Storage) *InviteRepository {
+	return &InviteRepository{storage: storage}
+}
+
+func (r *InviteRepository) CreateInvite(ctx context.Context, invite *models.Invite) error {
+	return r.storage.run(ctx, func(st *state) error {
+		pvzIds := []uuid.UUID{}
+		for _, pvzId := range invite.PvzIds {
+			if st.findPvz(pvzId) < 0 {
+				return models.ErrPvzNotFound
+			}
+			if !slices.Contains(pvzIds, pvzId) {
+				pvzIds = append(pvzIds, pvzId)
+			}
+		}
+
+		invite.ID = uuid.New()
+		stored := *invite
+		stored.PvzIds = pvzIds
+		st.invites = append(st.invites, stored)
+		return nil
+	})
+}
+
+func (r *InviteRepository) GetInviteByHash(ctx context.Context, hash string) (*models.Invite, error) {
+	var invite models.Invite
+	err := r.storage.run(ctx, func(st *state) error {
+		i := slices.IndexFunc(st.invites, func(invite models.Invite) bool {
+			return invite.CodeHash == hash
+		})
+		if i < 0 {
+			return models.ErrInviteNotFound
+		}
+		invite = st.invites[i]
+		invite.PvzIds = slices.Clone(invite.PvzIds)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+func (r *InviteRepository) UseInvite(ctx context.Context, id, userId openapi_types.UUID) error {
+	return r.storage.run(ctx, func(st *state) error {
+		if _, ok := st.users[userId]; !ok {
+			return models.ErrUserNotFound
+		}
+
+		i := slices.IndexFunc(st.invites, func(invite models.Invite) bool {
+			return invite.ID == id && invite.UsedAt == nil
+		})
+		if i < 0 {
+			return models.ErrInviteNotFound
+		}
+
+		usedAt := time.Now()
+		st.invites[i].UsedAt = &usedAt
+		return nil
+	})
+}
@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+func TestInviteRepository(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	repo := NewInviteRepository(s)
+
+	user := &models.User{Email: "employee@example.com", Role: dto.UserRoleEmployee}
+	require.NoError(t, NewUserRepository(s).CreateUser(ctx, user))
+	pvz := &dto.PVZ{City: "Москва"}
+	require.NoError(t, NewPvzRepository(s).CreatePvz(ctx, pvz))
+
+	assert.ErrorIs(t, repo.CreateInvite(ctx, &models.Invite{CodeHash: "unknown-pvz", PvzIds: []uuid.UUID{uuid.New()}}),
+		models.ErrPvzNotFound)
+
+	invite := &models.Invite{
+		CodeHash:  "hash",
+		Role:      dto.UserRoleEmployee,
+		PvzIds:    []uuid.UUID{*pvz.Id, *pvz.Id},
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	require.NoError(t, repo.CreateInvite(ctx, invite))
+
+	found, err := repo.GetInviteByHash(ctx, "hash")
+	require.NoError(t, err)
+	assert.Equal(t, invite.ID, found.ID)
+	assert.Equal(t, []uuid.UUID{*pvz.Id}, found.PvzIds)
+	assert.Nil(t, found.UsedAt)
+
+	_, err = repo.GetInviteByHash(ctx, "missing")
+	assert.ErrorIs(t, err, models.ErrInviteNotFound)
+
+	assert.ErrorIs(t, repo.UseInvite(ctx, invite.ID, uuid.New()), models.ErrUserNotFound)
+	require.NoError(t, repo.UseInvite(ctx, invite.ID, user.ID))
+	assert.ErrorIs(t, repo.UseInvite(ctx, invite.ID, user.ID), models.ErrInviteNotFound)
+
+	found, err = repo.GetInviteByHash(ctx, "hash")
+	require.NoError(t, err)
+	assert.NotNil(t, found.UsedAt)
+}
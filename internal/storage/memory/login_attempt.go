@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type LoginAttemptRepository struct {
+	storage *Storage
+}
+
+func NewLoginAttemptRepository(storage *Storage) *LoginAttemptRepository {
+	return &LoginAttemptRepository{storage: storage}
+}
+
+func (r *LoginAttemptRepository) GetLoginFailures(ctx context.Context, subject string) (*models.LoginFailures, error) {
+	failures := models.LoginFailures{Subject: subject}
+	err := r.storage.run(ctx, func(st *state) error {
+		if stored, ok := st.loginFailures[subject]; ok {
+			failures = stored
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &failures, nil
+}
+
+func (r *LoginAttemptRepository) RecordLoginFailure(ctx context.Context, subject string,
+	at, windowStart time.Time) (*models.LoginFailures, error) {
+	var failures models.LoginFailures
+	err := r.storage.run(ctx, func(st *state) error {
+		failures = st.loginFailures[subject]
+		if failures.LastFailureAt.Before(windowStart) {
+			failures.Failures = 0
+		}
+		failures.Subject = subject
+		failures.Failures++
+		failures.LastFailureAt = at
+		st.loginFailures[subject] = failures
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &failures, nil
+}
+
+func (r *LoginAttemptRepository) BlockLogin(ctx context.Context, subject string, until time.Time) error {
+	return r.storage.run(ctx, func(st *state) error {
+		if failures, ok := st.loginFailures[subject]; ok {
+			failures.BlockedUntil = &until
+			st.loginFailures[subject] = failures
+		}
+		return nil
+	})
+}
+
+func (r *LoginAttemptRepository) ResetLoginFailures(ctx context.Context, subject string) error {
+	return r.storage.run(ctx, func(st *state) error {
+		delete(st.loginFailures, subject)
+		return nil
+	})
+}
@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginAttemptRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewLoginAttemptRepository(New())
+	now := time.Now().UTC()
+
+	failures, err := repo.GetLoginFailures(ctx, "email:user@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 0, failures.Failures)
+	assert.Nil(t, failures.BlockedUntil)
+
+	for i := 1; i <= 3; i++ {
+		failures, err = repo.RecordLoginFailure(ctx, "email:user@example.com", now, now.Add(-time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, i, failures.Failures)
+	}
+
+	until := now.Add(time.Minute)
+	require.NoError(t, repo.BlockLogin(ctx, "email:user@example.com", until))
+	failures, err = repo.GetLoginFailures(ctx, "email:user@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 3, failures.Failures)
+	assert.Equal(t, &until, failures.BlockedUntil)
+
+	later := now.Add(time.Hour)
+	failures, err = repo.RecordLoginFailure(ctx, "email:user@example.com", later, later.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, failures.Failures, "failures outside the window are forgotten")
+
+	require.NoError(t, repo.ResetLoginFailures(ctx, "email:user@example.com"))
+	failures, err = repo.GetLoginFailures(ctx, "email:user@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 0, failures.Failures)
+}
@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type ProductRepository struct {
+	storage *Storage
+}
+
+func NewProductRepository(storage *Storage) *ProductRepository {
+	return &ProductRepository{storage: storage}
+}
+
+func (r *ProductRepository) AddProduct(ctx context.Context, product *dto.Product) error {
+	return r.storage.run(ctx, func(st *state) error {
+		if st.findReception(product.ReceptionId) < 0 {
+			return fmt.Errorf("failed to insert product: reception %s does not exist", product.ReceptionId)
+		}
+		if st.findProductType(product.Type) < 0 {
+			return models.ErrIncorrectProductType
+		}
+
+		id := uuid.New()
+		addedAt := time.Now()
+		product.Id = &id
+		product.DateTime = &addedAt
+		st.products = append(st.products, *product)
+		return nil
+	})
+}
+
+func (r *ProductRepository) GetLastProduct(ctx context.Context, receptionId uuid.UUID) (*dto.Product, error) {
+	var product dto.Product
+	err := r.storage.run(ctx, func(st *state) error {
+		last := -1
+		for i := range st.products {
+			if st.products[i].ReceptionId != receptionId {
+				continue
+			}
+			if last < 0 || !st.products[i].DateTime.Before(*st.products[last].DateTime) {
+				last = i
+			}
+		}
+		if last < 0 {
+			return sql.ErrNoRows
+		}
+		product = st.products[last]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (r *ProductRepository) DeleteProductById(ctx context.Context, productID openapi_types.UUID) error {
+	return r.storage.run(ctx, func(st *state) error {
+		for i := range st.products {
+			if *st.products[i].Id == productID {
+				st.products = slices.Delete(st.products, i, i+1)
+				return nil
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+func TestProductRepository(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	repo := NewProductRepository(s)
+
+	pvz := &dto.PVZ{City: "Москва"}
+	require.NoError(t, NewPvzRepository(s).CreatePvz(ctx, pvz))
+	reception := &dto.Reception{PvzId: *pvz.Id}
+	require.NoError(t, NewReceptionRepository(s).AddReception(ctx, reception))
+
+	t.Run("unknown reception", func(t *testing.T) {
+		err := repo.AddProduct(ctx, &dto.Product{ReceptionId: uuid.New(), Type: "обувь"})
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown product type", func(t *testing.T) {
+		err := repo.AddProduct(ctx, &dto.Product{ReceptionId: *reception.Id, Type: "книги"})
+		assert.ErrorIs(t, err, models.ErrIncorrectProductType)
+	})
+
+	t.Run("empty reception", func(t *testing.T) {
+		_, err := repo.GetLastProduct(ctx, *reception.Id)
+		assert.Equal(t, sql.ErrNoRows, err)
+	})
+
+	first := &dto.Product{ReceptionId: *reception.Id, Type: "обувь"}
+	second := &dto.Product{ReceptionId: *reception.Id, Type: "одежда"}
+	require.NoError(t, repo.AddProduct(ctx, first))
+	require.NoError(t, repo.AddProduct(ctx, second))
+
+	t.Run("last product", func(t *testing.T) {
+		last, err := repo.GetLastProduct(ctx, *reception.Id)
+		require.NoError(t, err)
+		assert.Equal(t, *second.Id, *last.Id)
+	})
+
+	t.Run("delete product", func(t *testing.T) {
+		require.NoError(t, repo.DeleteProductById(ctx, *second.Id))
+
+		last, err := repo.GetLastProduct(ctx, *reception.Id)
+		require.NoError(t, err)
+		assert.Equal(t, *first.Id, *last.Id)
+	})
+}
@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type ProductTypeRepository struct {
+	storage *Storage
+}
+
+func NewProductTypeRepository(storage *Storage) *ProductTypeRepository {
+	return &ProductTypeRepository{storage: storage}
+}
+
+func (r *ProductTypeRepository) CreateProductType(ctx context.Context, productType *dto.ProductType) error {
+	return r.storage.run(ctx, func(st *state) error {
+		if st.findProductType(productType.Name) >= 0 {
+			return models.ErrProductTypeExists
+		}
+
+		productType.Id = uuid.New()
+		productType.Active = true
+		st.productTypes = append(st.productTypes, *productType)
+		return nil
+	})
+}
+
+func (r *ProductTypeRepository) GetProductTypes(ctx context.Context) ([]dto.ProductType, error) {
+	productTypes := []dto.ProductType{}
+	err := r.storage.run(ctx, func(st *state) error {
+		productTypes = append(productTypes, st.productTypes...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(productTypes, func(a, b dto.ProductType) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return productTypes, nil
+}
+
+func (r *ProductTypeRepository) GetProductTypeByName(ctx context.Context, name string) (*dto.ProductType, error) {
+	var productType dto.ProductType
+	err := r.storage.run(ctx, func(st *state) error {
+		i := st.findProductType(name)
+		if i < 0 {
+			return models.ErrProductTypeNotFound
+		}
+		productType = st.productTypes[i]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &productType, nil
+}
+
+// RenameProductType also renames the type on stored products, like the
+// cascading foreign key does in Postgres.
+func (r *ProductTypeRepository) RenameProductType(ctx context.Context, id openapi_types.UUID, name string) (*dto.ProductType, error) {
+	var productType dto.ProductType
+	err := r.storage.run(ctx, func(st *state) error {
+		i := st.findProductTypeById(id)
+		if i < 0 {
+			return models.ErrProductTypeNotFound
+		}
+		if j := st.findProductType(name); j >= 0 && j != i {
+			return models.ErrProductTypeExists
+		}
+
+		oldName := st.productTypes[i].Name
+		for j := range st.products {
+			if st.products[j].Type == oldName {
+				st.products[j].Type = name
+			}
+		}
+		st.productTypes[i].Name = name
+		productType = st.productTypes[i]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &productType, nil
+}
+
+func (r *ProductTypeRepository) DeactivateProductType(ctx context.Context, id openapi_types.UUID) (*dto.ProductType, error) {
+	var productType dto.ProductType
+	err := r.storage.run(ctx, func(st *state) error {
+		i := st.findProductTypeById(id)
+		if i < 0 {
+			return models.ErrProductTypeNotFound
+		}
+		st.productTypes[i].Active = false
+		productType = st.productTypes[i]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &productType, nil
+}
+
+func (st *state) findProductType(name string) int {
+	return slices.IndexFunc(st.productTypes, func(productType dto.ProductType) bool {
+		return productType.Name == name
+	})
+}
+
+func (st *state) findProductTypeById(id uuid.UUID) int {
+	return slices.IndexFunc(st.productTypes, func(productType dto.ProductType) bool {
+		return productType.Id == id
+	})
+}
@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+func TestProductTypeRepository(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	repo := NewProductTypeRepository(s)
+
+	t.Run("default types are seeded", func(t *testing.T) {
+		productTypes, err := repo.GetProductTypes(ctx)
+		require.NoError(t, err)
+		require.Len(t, productTypes, len(models.DefaultProductTypes))
+		for _, productType := range productTypes {
+			assert.True(t, productType.Active)
+		}
+	})
+
+	books := &dto.ProductType{Name: "книги"}
+	require.NoError(t, repo.CreateProductType(ctx, books))
+	assert.NotEqual(t, uuid.Nil, books.Id)
+	assert.True(t, books.Active)
+
+	t.Run("duplicate name", func(t *testing.T) {
+		err := repo.CreateProductType(ctx, &dto.ProductType{Name: "книги"})
+		assert.Equal(t, models.ErrProductTypeExists, err)
+	})
+
+	t.Run("get by name", func(t *testing.T) {
+		productType, err := repo.GetProductTypeByName(ctx, "книги")
+		require.NoError(t, err)
+		assert.Equal(t, books.Id, productType.Id)
+
+		_, err = repo.GetProductTypeByName(ctx, "игрушки")
+		assert.Equal(t, models.ErrProductTypeNotFound, err)
+	})
+
+	t.Run("rename follows stored products", func(t *testing.T) {
+		pvz := &dto.PVZ{City: "Москва"}
+		require.NoError(t, NewPvzRepository(s).CreatePvz(ctx, pvz))
+		reception := &dto.Reception{PvzId: *pvz.Id}
+		require.NoError(t, NewReceptionRepository(s).AddReception(ctx, reception))
+		require.NoError(t, NewProductRepository(s).AddProduct(ctx, &dto.Product{ReceptionId: *reception.Id, Type: "книги"}))
+
+		renamed, err := repo.RenameProductType(ctx, books.Id, "литература")
+		require.NoError(t, err)
+		assert.Equal(t, "литература", renamed.Name)
+
+		product, err := NewProductRepository(s).GetLastProduct(ctx, *reception.Id)
+		require.NoError(t, err)
+		assert.Equal(t, "литература", product.Type)
+	})
+
+	t.Run("rename to taken name", func(t *testing.T) {
+		_, err := repo.RenameProductType(ctx, books.Id, "обувь")
+		assert.Equal(t, models.ErrProductTypeExists, err)
+	})
+
+	t.Run("rename unknown", func(t *testing.T) {
+		_, err := repo.RenameProductType(ctx, uuid.New(), "игрушки")
+		assert.Equal(t, models.ErrProductTypeNotFound, err)
+	})
+
+	t.Run("deactivate", func(t *testing.T) {
+		deactivated, err := repo.DeactivateProductType(ctx, books.Id)
+		require.NoError(t, err)
+		assert.False(t, deactivated.Active)
+
+		_, err = repo.DeactivateProductType(ctx, uuid.New())
+		assert.Equal(t, models.ErrProductTypeNotFound, err)
+	})
+}
@@ -0,0 +1,235 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type PvzRepository struct {
+	storage *Storage
+}
+
+func NewPvzRepository(storage *Storage) *PvzRepository {
+	return &PvzRepository{storage: storage}
+}
+
+func (r *PvzRepository) GetAllPVZs(ctx context.Context) ([]dto.PVZ, error) {
+	var pvzs []dto.PVZ
+	err := r.storage.run(ctx, func(st *state) error {
+		pvzs = append(pvzs, st.pvzs...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pvzs, nil
+}
+
+func (r *PvzRepository) CreatePvz(ctx context.Context, pvz *dto.PVZ) error {
+	return r.storage.run(ctx, func(st *state) error {
+		if st.findCity(pvz.City) < 0 {
+			return models.ErrIncorrectCity
+		}
+
+		id := uuid.New()
+		now := time.Now().UTC()
+		registrationDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		pvz.Id = &id
+		pvz.RegistrationDate = &registrationDate
+		st.pvzs = append(st.pvzs, *pvz)
+		return nil
+	})
+}
+
+func (r *PvzRepository) GetPvzById(ctx context.Context, id openapi_types.UUID) (*dto.PVZ, error) {
+	var pvz dto.PVZ
+	err := r.storage.run(ctx, func(st *state) error {
+		i := st.findPvz(id)
+		if i < 0 {
+			return models.ErrPvzNotFound
+		}
+		pvz = st.pvzs[i]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pvz, nil
+}
+
+func (r *PvzRepository) UpdatePvz(ctx context.Context, id openapi_types.UUID, update *dto.PVZUpdate) (*dto.PVZ, error) {
+	var pvz dto.PVZ
+	err := r.storage.run(ctx, func(st *state) error {
+		i := st.findPvz(id)
+		if i < 0 {
+			return models.ErrPvzNotFound
+		}
+
+		stored := &st.pvzs[i]
+		if update.Name != nil {
+			stored.Name = update.Name
+		}
+		if update.Address != nil {
+			stored.Address = update.Address
+		}
+		if update.Latitude != nil {
+			stored.Latitude = update.Latitude
+		}
+		if update.Longitude != nil {
+			stored.Longitude = update.Longitude
+		}
+		if update.Phone != nil {
+			stored.Phone = update.Phone
+		}
+		if update.OpeningHours != nil {
+			stored.OpeningHours = update.OpeningHours
+		}
+		pvz = *stored
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pvz, nil
+}
+
+func (r *PvzRepository) DecommissionPvz(ctx context.Context, id openapi_types.UUID) (*dto.PVZ, error) {
+	var pvz dto.PVZ
+	err := r.storage.run(ctx, func(st *state) error {
+		i := st.findPvz(id)
+		if i < 0 {
+			return models.ErrPvzNotFound
+		}
+		if st.pvzs[i].DecommissionedAt != nil {
+			return models.ErrPvzDecommissioned
+		}
+
+		now := time.Now().UTC()
+		st.pvzs[i].DecommissionedAt = &now
+		pvz = st.pvzs[i]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pvz, nil
+}
+
+func (r *PvzRepository) GetPvzList(ctx context.Context, params models.PvzListParams) (*models.PvzPage, error) {
+	var page *models.PvzPage
+	err := r.storage.run(ctx, func(st *state) error {
+		page = st.pvzPage(params)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+func (st *state) pvzPage(params models.PvzListParams) *models.PvzPage {
+	var pvzs []*models.ExtendedPvz
+	for _, pvz := range st.pvzs {
+		if params.PvzIds != nil && !slices.Contains(params.PvzIds, *pvz.Id) {
+			continue
+		}
+		if len(params.Cities) > 0 && !slices.Contains(params.Cities, pvz.City) {
+			continue
+		}
+		if params.HasOpenReception && !st.hasOpenReception(*pvz.Id) {
+			continue
+		}
+		receptions := st.pvzReceptions(*pvz.Id, params.PvzFilter)
+		if len(receptions) == 0 && params.FiltersReceptions() {
+			continue
+		}
+		if params.SkipReceptions {
+			receptions = []models.ExtendedReception{}
+		}
+		pvzs = append(pvzs, &models.ExtendedPvz{PVZ: pvz, Receptions: receptions})
+	}
+
+	sort.Slice(pvzs, func(i, j int) bool {
+		a, b := pvzs[i].PVZ, pvzs[j].PVZ
+		if !a.RegistrationDate.Equal(*b.RegistrationDate) {
+			return a.RegistrationDate.After(*b.RegistrationDate)
+		}
+		return bytes.Compare(a.Id[:], b.Id[:]) > 0
+	})
+
+	page := &models.PvzPage{Items: []*models.ExtendedPvz{}}
+	if !params.SkipCount {
+		page.TotalCount = uint64(len(pvzs))
+	}
+
+	var start uint64
+	if params.Cursor != nil {
+		start = uint64(sort.Search(len(pvzs), func(i int) bool {
+			return params.Cursor.After(pvzs[i].PVZ)
+		}))
+	} else {
+		start = min(params.Offset(), uint64(len(pvzs)))
+	}
+	end := min(start+params.Limit, uint64(len(pvzs)))
+
+	page.Items = append(page.Items, pvzs[start:end]...)
+	if end < uint64(len(pvzs)) && len(page.Items) > 0 {
+		page.NextCursor = models.NewPvzCursor(page.Items[len(page.Items)-1].PVZ)
+	}
+	return page
+}
+
+func (st *state) hasOpenReception(pvzId uuid.UUID) bool {
+	return slices.ContainsFunc(st.receptions, func(reception dto.Reception) bool {
+		return reception.PvzId == pvzId && models.IsOpenReception(reception.Status)
+	})
+}
+
+// pvzReceptions returns the receptions of the pvz that match the filter
+// together with their matching products, both in chronological order.
+func (st *state) pvzReceptions(pvzId uuid.UUID, filter models.PvzFilter) []models.ExtendedReception {
+	receptions := []models.ExtendedReception{}
+	for _, reception := range st.receptions {
+		if reception.PvzId != pvzId || !filter.MatchReception(reception) {
+			continue
+		}
+
+		products := []dto.Product{}
+		for _, product := range st.products {
+			if product.ReceptionId == *reception.Id && filter.MatchProduct(product) {
+				products = append(products, product)
+			}
+		}
+		if len(products) == 0 && filter.FiltersProducts() {
+			continue
+		}
+		sort.SliceStable(products, func(i, j int) bool {
+			return products[i].DateTime.Before(*products[j].DateTime)
+		})
+
+		receptions = append(receptions, models.ExtendedReception{Reception: reception, Products: products})
+	}
+
+	sort.SliceStable(receptions, func(i, j int) bool {
+		return receptions[i].Reception.DateTime.Before(receptions[j].Reception.DateTime)
+	})
+	return receptions
+}
+
+func (st *state) findPvz(id uuid.UUID) int {
+	for i := range st.pvzs {
+		if *st.pvzs[i].Id == id {
+			return i
+		}
+	}
+	return -1
+}
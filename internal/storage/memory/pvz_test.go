@@ -0,0 +1,284 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+func TestPvzRepository(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	repo := NewPvzRepository(s)
+
+	pvz := &dto.PVZ{City: "Москва"}
+	require.NoError(t, repo.CreatePvz(ctx, pvz))
+	require.NotNil(t, pvz.Id)
+	require.NotNil(t, pvz.RegistrationDate)
+
+	empty := &dto.PVZ{City: "Казань"}
+	require.NoError(t, repo.CreatePvz(ctx, empty))
+
+	reception := &dto.Reception{PvzId: *pvz.Id}
+	require.NoError(t, NewReceptionRepository(s).AddReception(ctx, reception))
+	for i := 0; i < 3; i++ {
+		require.NoError(t, NewProductRepository(s).AddProduct(ctx, &dto.Product{ReceptionId: *reception.Id, Type: "одежда"}))
+	}
+
+	t.Run("get all pvzs", func(t *testing.T) {
+		pvzs, err := repo.GetAllPVZs(ctx)
+		require.NoError(t, err)
+		assert.Len(t, pvzs, 2)
+	})
+
+	t.Run("list with nested receptions and products", func(t *testing.T) {
+		page, err := repo.GetPvzList(ctx, models.PvzListParams{Page: 1, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, page.Items, 2)
+		assert.Equal(t, uint64(2), page.TotalCount)
+		assert.Nil(t, page.NextCursor)
+
+		for _, item := range page.Items {
+			if *item.PVZ.Id == *pvz.Id {
+				require.Len(t, item.Receptions, 1)
+				assert.Len(t, item.Receptions[0].Products, 3)
+			} else {
+				assert.Empty(t, item.Receptions)
+			}
+		}
+	})
+
+	t.Run("filter by reception date", func(t *testing.T) {
+		start := reception.DateTime.Add(-time.Minute)
+		end := reception.DateTime.Add(time.Minute)
+
+		page, err := repo.GetPvzList(ctx, models.PvzListParams{PvzFilter: models.PvzFilter{StartDate: &start, EndDate: &end}, Page: 1, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, page.Items, 1)
+		assert.Equal(t, *pvz.Id, *page.Items[0].PVZ.Id)
+		assert.Equal(t, uint64(1), page.TotalCount)
+	})
+
+	t.Run("skip receptions and count", func(t *testing.T) {
+		start := reception.DateTime.Add(-time.Minute)
+		page, err := repo.GetPvzList(ctx, models.PvzListParams{
+			PvzFilter:      models.PvzFilter{StartDate: &start},
+			Limit:          10,
+			SkipReceptions: true,
+			SkipCount:      true,
+		})
+		require.NoError(t, err)
+		require.Len(t, page.Items, 1, "filters still apply")
+		assert.Empty(t, page.Items[0].Receptions)
+		assert.Zero(t, page.TotalCount)
+	})
+
+	t.Run("page beyond the end", func(t *testing.T) {
+		page, err := repo.GetPvzList(ctx, models.PvzListParams{Page: 5, Limit: 10})
+		require.NoError(t, err)
+		assert.Empty(t, page.Items)
+		assert.Equal(t, uint64(2), page.TotalCount)
+	})
+}
+
+func TestPvzRepository_Pagination(t *testing.T) {
+	const pvzCount = 7
+
+	ctx := context.Background()
+	s := New()
+	repo := NewPvzRepository(s)
+
+	for i := 0; i < pvzCount; i++ {
+		pvz := &dto.PVZ{City: "Москва"}
+		require.NoError(t, repo.CreatePvz(ctx, pvz))
+		reception := &dto.Reception{PvzId: *pvz.Id}
+		require.NoError(t, NewReceptionRepository(s).AddReception(ctx, reception))
+		for j := 0; j < 5; j++ {
+			require.NoError(t, NewProductRepository(s).AddProduct(ctx, &dto.Product{ReceptionId: *reception.Id, Type: "обувь"}))
+		}
+	}
+
+	var byPage []uuid.UUID
+	for p := uint64(1); ; p++ {
+		page, err := repo.GetPvzList(ctx, models.PvzListParams{Page: p, Limit: 3})
+		require.NoError(t, err)
+		if len(page.Items) == 0 {
+			break
+		}
+		for _, item := range page.Items {
+			require.Len(t, item.Receptions, 1)
+			assert.Len(t, item.Receptions[0].Products, 5)
+			byPage = append(byPage, *item.PVZ.Id)
+		}
+	}
+
+	var byCursor []uuid.UUID
+	params := models.PvzListParams{Limit: 3}
+	for {
+		page, err := repo.GetPvzList(ctx, params)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(pvzCount), page.TotalCount)
+		for _, item := range page.Items {
+			byCursor = append(byCursor, *item.PVZ.Id)
+		}
+		if page.NextCursor == nil {
+			break
+		}
+		cursor, err := models.DecodePvzCursor(page.NextCursor.Encode())
+		require.NoError(t, err)
+		params.Cursor = cursor
+	}
+
+	assert.Len(t, byPage, pvzCount)
+	assert.Equal(t, byPage, byCursor)
+}
+
+func TestPvzRepository_Filters(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	repo := NewPvzRepository(s)
+	receptionRepo := NewReceptionRepository(s)
+	productRepo := NewProductRepository(s)
+
+	moscow := &dto.PVZ{City: "Москва"}
+	require.NoError(t, repo.CreatePvz(ctx, moscow))
+	closed := &dto.Reception{PvzId: *moscow.Id}
+	require.NoError(t, receptionRepo.AddReception(ctx, closed))
+	shoes := &dto.Product{ReceptionId: *closed.Id, Type: "обувь"}
+	require.NoError(t, productRepo.AddProduct(ctx, shoes))
+	require.NoError(t, productRepo.AddProduct(ctx, &dto.Product{ReceptionId: *closed.Id, Type: "одежда"}))
+	_, err := receptionRepo.CloseLastReception(ctx, *closed.Id)
+	require.NoError(t, err)
+
+	kazan := &dto.PVZ{City: "Казань"}
+	require.NoError(t, repo.CreatePvz(ctx, kazan))
+	open := &dto.Reception{PvzId: *kazan.Id}
+	require.NoError(t, receptionRepo.AddReception(ctx, open))
+	require.NoError(t, productRepo.AddProduct(ctx, &dto.Product{ReceptionId: *open.Id, Type: "электроника"}))
+
+	require.NoError(t, repo.CreatePvz(ctx, &dto.PVZ{City: "Санкт-Петербург"}))
+
+	status := dto.Close
+	productType := "обувь"
+	future := time.Now().Add(time.Hour)
+	shoesAddedAt := *shoes.DateTime
+
+	tests := []struct {
+		name   string
+		filter models.PvzFilter
+		want   []uuid.UUID
+		check  func(t *testing.T, items []*models.ExtendedPvz)
+	}{
+		{
+			name:   "several cities",
+			filter: models.PvzFilter{Cities: []string{"Москва", "Казань"}},
+			want:   []uuid.UUID{*moscow.Id, *kazan.Id},
+		},
+		{
+			name:   "pvz ids",
+			filter: models.PvzFilter{PvzIds: []uuid.UUID{*kazan.Id}},
+			want:   []uuid.UUID{*kazan.Id},
+		},
+		{
+			name:   "no pvz ids",
+			filter: models.PvzFilter{PvzIds: []uuid.UUID{}},
+			want:   nil,
+		},
+		{
+			name:   "has open reception",
+			filter: models.PvzFilter{HasOpenReception: true},
+			want:   []uuid.UUID{*kazan.Id},
+		},
+		{
+			name:   "reception status",
+			filter: models.PvzFilter{ReceptionStatus: &status},
+			want:   []uuid.UUID{*moscow.Id},
+		},
+		{
+			name:   "open-ended reception range",
+			filter: models.PvzFilter{StartDate: &future},
+			want:   nil,
+		},
+		{
+			name:   "product type keeps only matching products",
+			filter: models.PvzFilter{ProductType: &productType},
+			want:   []uuid.UUID{*moscow.Id},
+			check: func(t *testing.T, items []*models.ExtendedPvz) {
+				require.Len(t, items[0].Receptions, 1)
+				require.Len(t, items[0].Receptions[0].Products, 1)
+				assert.Equal(t, *shoes.Id, *items[0].Receptions[0].Products[0].Id)
+			},
+		},
+		{
+			name:   "product added range",
+			filter: models.PvzFilter{ProductStartDate: &shoesAddedAt, ProductEndDate: &shoesAddedAt},
+			want:   []uuid.UUID{*moscow.Id},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := repo.GetPvzList(ctx, models.PvzListParams{PvzFilter: tt.filter, Page: 1, Limit: 10})
+			require.NoError(t, err)
+
+			var got []uuid.UUID
+			for _, item := range page.Items {
+				got = append(got, *item.PVZ.Id)
+			}
+			assert.ElementsMatch(t, tt.want, got)
+			assert.Equal(t, uint64(len(tt.want)), page.TotalCount)
+			if tt.check != nil {
+				tt.check(t, page.Items)
+			}
+		})
+	}
+}
+
+func TestPvzRepository_Profile(t *testing.T) {
+	ctx := context.Background()
+	repo := NewPvzRepository(New())
+
+	name := "ПВЗ на Тверской"
+	pvz := &dto.PVZ{City: "Москва", Name: &name}
+	require.NoError(t, repo.CreatePvz(ctx, pvz))
+
+	t.Run("get by id", func(t *testing.T) {
+		found, err := repo.GetPvzById(ctx, *pvz.Id)
+		require.NoError(t, err)
+		assert.Equal(t, name, *found.Name)
+		assert.Nil(t, found.DecommissionedAt)
+
+		_, err = repo.GetPvzById(ctx, uuid.New())
+		assert.ErrorIs(t, err, models.ErrPvzNotFound)
+	})
+
+	t.Run("update keeps absent fields", func(t *testing.T) {
+		phone := "+7 (495) 123-45-67"
+		updated, err := repo.UpdatePvz(ctx, *pvz.Id, &dto.PVZUpdate{Phone: &phone})
+		require.NoError(t, err)
+		assert.Equal(t, name, *updated.Name)
+		assert.Equal(t, phone, *updated.Phone)
+
+		_, err = repo.UpdatePvz(ctx, uuid.New(), &dto.PVZUpdate{Phone: &phone})
+		assert.ErrorIs(t, err, models.ErrPvzNotFound)
+	})
+
+	t.Run("decommission once", func(t *testing.T) {
+		decommissioned, err := repo.DecommissionPvz(ctx, *pvz.Id)
+		require.NoError(t, err)
+		assert.NotNil(t, decommissioned.DecommissionedAt)
+
+		_, err = repo.DecommissionPvz(ctx, *pvz.Id)
+		assert.ErrorIs(t, err, models.ErrPvzDecommissioned)
+
+		_, err = repo.DecommissionPvz(ctx, uuid.New())
+		assert.ErrorIs(t, err, models.ErrPvzNotFound)
+	})
+}
@@ -0,0 +1,133 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type ReceptionRepository struct {
+	storage *Storage
+}
+
+func NewReceptionRepository(storage *Storage) *ReceptionRepository {
+	return &ReceptionRepository{storage: storage}
+}
+
+func (r *ReceptionRepository) AddReception(ctx context.Context, reception *dto.Reception) error {
+	return r.storage.run(ctx, func(st *state) error {
+		if st.findPvz(reception.PvzId) < 0 {
+			return fmt.Errorf("failed to insert reception: pvz %s does not exist", reception.PvzId)
+		}
+		if st.hasOpenReception(reception.PvzId) {
+			return models.ErrReceptionNotClosed
+		}
+
+		id := uuid.New()
+		reception.Id = &id
+		reception.DateTime = time.Now()
+		reception.Status = dto.InProgress
+		st.receptions = append(st.receptions, *reception)
+		return nil
+	})
+}
+
+func (r *ReceptionRepository) GetLastReceptionByPvzId(ctx context.Context, pvzId openapi_types.UUID) (*dto.Reception, error) {
+	var reception dto.Reception
+	err := r.storage.run(ctx, func(st *state) error {
+		i := st.lastReception(pvzId)
+		if i < 0 {
+			return models.ErrReceptionNotFound
+		}
+		reception = st.receptions[i]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &reception, nil
+}
+
+func (r *ReceptionRepository) CloseLastReception(ctx context.Context, receptionId openapi_types.UUID) (*dto.Reception, error) {
+	var reception dto.Reception
+	err := r.storage.run(ctx, func(st *state) error {
+		i := st.findReception(receptionId)
+		if i < 0 {
+			return models.ErrReceptionNotFound
+		}
+		st.receptions[i].Status = dto.Close
+		reception = st.receptions[i]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &reception, nil
+}
+
+func (r *ReceptionRepository) GetReceptionById(ctx context.Context, receptionId openapi_types.UUID) (*dto.Reception, error) {
+	var reception dto.Reception
+	err := r.storage.run(ctx, func(st *state) error {
+		i := st.findReception(receptionId)
+		if i < 0 {
+			return models.ErrReceptionNotFound
+		}
+		reception = st.receptions[i]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &reception, nil
+}
+
+func (r *ReceptionRepository) UpdateReceptionStatus(ctx context.Context, receptionId openapi_types.UUID, status dto.ReceptionStatus) (*dto.Reception, error) {
+	var reception dto.Reception
+	err := r.storage.run(ctx, func(st *state) error {
+		i := st.findReception(receptionId)
+		if i < 0 {
+			return models.ErrReceptionNotFound
+		}
+		if models.IsOpenReception(status) && !models.IsOpenReception(st.receptions[i].Status) &&
+			st.hasOpenReception(st.receptions[i].PvzId) {
+			return models.ErrReceptionNotClosed
+		}
+		st.receptions[i].Status = status
+		reception = st.receptions[i]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &reception, nil
+}
+
+func (st *state) findReception(id uuid.UUID) int {
+	for i := range st.receptions {
+		if *st.receptions[i].Id == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// lastReception returns the index of the most recently started reception of
+// the pvz. Receptions are stored in insertion order, which breaks ties.
+func (st *state) lastReception(pvzId uuid.UUID) int {
+	last := -1
+	for i := range st.receptions {
+		if st.receptions[i].PvzId != pvzId {
+			continue
+		}
+		if last < 0 || !st.receptions[i].DateTime.Before(st.receptions[last].DateTime) {
+			last = i
+		}
+	}
+	return last
+}
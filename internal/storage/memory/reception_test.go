@@ -0,0 +1,127 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+func TestReceptionRepository(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	repo := NewReceptionRepository(s)
+
+	pvz := &dto.PVZ{City: "Москва"}
+	require.NoError(t, NewPvzRepository(s).CreatePvz(ctx, pvz))
+
+	t.Run("unknown pvz", func(t *testing.T) {
+		err := repo.AddReception(ctx, &dto.Reception{PvzId: uuid.New()})
+		assert.Error(t, err)
+	})
+
+	t.Run("no receptions yet", func(t *testing.T) {
+		_, err := repo.GetLastReceptionByPvzId(ctx, *pvz.Id)
+		assert.Equal(t, models.ErrReceptionNotFound, err)
+	})
+
+	first := &dto.Reception{PvzId: *pvz.Id}
+	require.NoError(t, repo.AddReception(ctx, first))
+	assert.Equal(t, dto.InProgress, first.Status)
+	assert.NotNil(t, first.Id)
+
+	t.Run("close reception", func(t *testing.T) {
+		closed, err := repo.CloseLastReception(ctx, *first.Id)
+		require.NoError(t, err)
+		assert.Equal(t, dto.Close, closed.Status)
+	})
+
+	t.Run("close unknown reception", func(t *testing.T) {
+		_, err := repo.CloseLastReception(ctx, uuid.New())
+		assert.Equal(t, models.ErrReceptionNotFound, err)
+	})
+
+	t.Run("last reception", func(t *testing.T) {
+		second := &dto.Reception{PvzId: *pvz.Id}
+		require.NoError(t, repo.AddReception(ctx, second))
+
+		last, err := repo.GetLastReceptionByPvzId(ctx, *pvz.Id)
+		require.NoError(t, err)
+		assert.Equal(t, *second.Id, *last.Id)
+		assert.Equal(t, dto.InProgress, last.Status)
+	})
+}
+
+func TestReceptionRepository_SingleOpenReception(t *testing.T) {
+	const workers = 20
+
+	ctx := context.Background()
+	s := New()
+	repo := NewReceptionRepository(s)
+	txManager := NewTxManager(s)
+
+	pvz := &dto.PVZ{City: "Москва"}
+	require.NoError(t, NewPvzRepository(s).CreatePvz(ctx, pvz))
+
+	errs := make(chan error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- txManager.Do(ctx, func(ctx context.Context) error {
+				return repo.AddReception(ctx, &dto.Reception{PvzId: *pvz.Id})
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	var created int
+	for err := range errs {
+		if err == nil {
+			created++
+			continue
+		}
+		assert.ErrorIs(t, err, models.ErrReceptionNotClosed)
+	}
+	assert.Equal(t, 1, created)
+}
+
+func TestReceptionRepository_UpdateStatus(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	repo := NewReceptionRepository(s)
+
+	pvz := &dto.PVZ{City: "Москва"}
+	require.NoError(t, NewPvzRepository(s).CreatePvz(ctx, pvz))
+
+	first := &dto.Reception{PvzId: *pvz.Id}
+	require.NoError(t, repo.AddReception(ctx, first))
+
+	paused, err := repo.UpdateReceptionStatus(ctx, *first.Id, dto.Paused)
+	require.NoError(t, err)
+	assert.Equal(t, dto.Paused, paused.Status)
+	assert.ErrorIs(t, repo.AddReception(ctx, &dto.Reception{PvzId: *pvz.Id}), models.ErrReceptionNotClosed)
+
+	_, err = repo.UpdateReceptionStatus(ctx, *first.Id, dto.Close)
+	require.NoError(t, err)
+	second := &dto.Reception{PvzId: *pvz.Id}
+	require.NoError(t, repo.AddReception(ctx, second))
+
+	_, err = repo.UpdateReceptionStatus(ctx, *first.Id, dto.InProgress)
+	assert.ErrorIs(t, err, models.ErrReceptionNotClosed)
+
+	found, err := repo.GetReceptionById(ctx, *first.Id)
+	require.NoError(t, err)
+	assert.Equal(t, dto.Close, found.Status)
+
+	_, err = repo.UpdateReceptionStatus(ctx, uuid.New(), dto.Cancelled)
+	assert.ErrorIs(t, err, models.ErrReceptionNotFound)
+}
@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type RoleRepository struct {
+	storage *Storage
+}
+
+func NewRoleRepository(storage *Storage) *RoleRepository {
+	return &RoleRepository{storage: storage}
+}
+
+func (r *RoleRepository) GetRolePermissions(ctx context.Context, role dto.UserRole) ([]models.Permission, error) {
+	permissions := []models.Permission{}
+	err := r.storage.run(ctx, func(st *state) error {
+		permissions = append(permissions, st.rolePermissions[role]...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+func (r *RoleRepository) ListRolePermissions(ctx context.Context) (map[dto.UserRole][]models.Permission, error) {
+	rolePermissions := map[dto.UserRole][]models.Permission{}
+	err := r.storage.run(ctx, func(st *state) error {
+		for role, permissions := range st.rolePermissions {
+			if len(permissions) > 0 {
+				rolePermissions[role] = slices.Clone(permissions)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rolePermissions, nil
+}
+
+func (r *RoleRepository) SetRolePermissions(ctx context.Context, role dto.UserRole, permissions []models.Permission) error {
+	return r.storage.run(ctx, func(st *state) error {
+		for _, permission := range permissions {
+			if !slices.Contains(models.Permissions, permission) {
+				return models.ErrUnknownPermission
+			}
+		}
+
+		st.rolePermissions[role] = sortedPermissions(permissions)
+		return nil
+	})
+}
+
+// sortedPermissions returns a sorted copy without duplicates, matching the
+// order the Postgres repository reads them in.
+func sortedPermissions(permissions []models.Permission) []models.Permission {
+	sorted := slices.Clone(permissions)
+	slices.Sort(sorted)
+	return slices.Compact(sorted)
+}
@@ -0,0 +1,39 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+func TestRoleRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRoleRepository(New())
+
+	t.Run("default permissions are seeded", func(t *testing.T) {
+		rolePermissions, err := repo.ListRolePermissions(ctx)
+		require.NoError(t, err)
+		for _, role := range models.Roles {
+			assert.ElementsMatch(t, models.DefaultRolePermissions[role], rolePermissions[role], role)
+		}
+	})
+
+	t.Run("set", func(t *testing.T) {
+		permissions := []models.Permission{models.PermPvzRead, models.PermProductCreate, models.PermPvzRead}
+		require.NoError(t, repo.SetRolePermissions(ctx, dto.UserRoleEmployee, permissions))
+
+		found, err := repo.GetRolePermissions(ctx, dto.UserRoleEmployee)
+		require.NoError(t, err)
+		assert.Equal(t, []models.Permission{models.PermProductCreate, models.PermPvzRead}, found)
+	})
+
+	t.Run("unknown permission", func(t *testing.T) {
+		err := repo.SetRolePermissions(ctx, dto.UserRoleEmployee, []models.Permission{"pvz:burn"})
+		assert.Equal(t, models.ErrUnknownPermission, err)
+	})
+}
@@ -0,0 +1,175 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type ServiceAccountRepository struct {
+	storage *Storage
+}
+
+func NewServiceAccountRepository(storage *Storage) *ServiceAccountRepository {
+	return &ServiceAccountRepository{storage: storage}
+}
+
+func (r *ServiceAccountRepository) CreateServiceAccount(ctx context.Context, account *models.ServiceAccount) error {
+	return r.storage.run(ctx, func(st *state) error {
+		if slices.ContainsFunc(st.serviceAccounts, func(existing models.ServiceAccount) bool {
+			return existing.Name == account.Name
+		}) {
+			return models.ErrServiceAccountExists
+		}
+
+		pvzIds := []uuid.UUID{}
+		for _, pvzId := range account.PvzIds {
+			if st.findPvz(pvzId) < 0 {
+				return models.ErrPvzNotFound
+			}
+			if !slices.Contains(pvzIds, pvzId) {
+				pvzIds = append(pvzIds, pvzId)
+			}
+		}
+		slices.SortFunc(pvzIds, func(a, b uuid.UUID) int {
+			return cmp.Compare(a.String(), b.String())
+		})
+
+		account.ID = uuid.New()
+		account.CreatedAt = time.Now().UTC()
+		account.PvzIds = pvzIds
+		stored := *account
+		stored.PvzIds = slices.Clone(pvzIds)
+		st.serviceAccounts = append(st.serviceAccounts, stored)
+		return nil
+	})
+}
+
+func (r *ServiceAccountRepository) GetServiceAccountById(ctx context.Context, id openapi_types.UUID) (*models.ServiceAccount, error) {
+	var account models.ServiceAccount
+	err := r.storage.run(ctx, func(st *state) error {
+		i := slices.IndexFunc(st.serviceAccounts, func(account models.ServiceAccount) bool {
+			return account.ID == id
+		})
+		if i < 0 {
+			return models.ErrServiceAccountNotFound
+		}
+		account = st.serviceAccounts[i]
+		account.PvzIds = slices.Clone(account.PvzIds)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *ServiceAccountRepository) ListServiceAccounts(ctx context.Context) ([]*models.ServiceAccount, error) {
+	accounts := []*models.ServiceAccount{}
+	err := r.storage.run(ctx, func(st *state) error {
+		for _, account := range st.serviceAccounts {
+			account.PvzIds = slices.Clone(account.PvzIds)
+			accounts = append(accounts, &account)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(accounts, func(a, b *models.ServiceAccount) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return accounts, nil
+}
+
+func (r *ServiceAccountRepository) CreateAPIKey(ctx context.Context, key *models.APIKey) error {
+	return r.storage.run(ctx, func(st *state) error {
+		if !slices.ContainsFunc(st.serviceAccounts, func(account models.ServiceAccount) bool {
+			return account.ID == key.ServiceAccountID
+		}) {
+			return models.ErrServiceAccountNotFound
+		}
+
+		key.ID = uuid.New()
+		key.CreatedAt = time.Now().UTC()
+		st.apiKeys = append(st.apiKeys, *key)
+		return nil
+	})
+}
+
+func (r *ServiceAccountRepository) GetAPIKeyByHash(ctx context.Context, hash string) (*models.APIKey, error) {
+	var key models.APIKey
+	err := r.storage.run(ctx, func(st *state) error {
+		i := slices.IndexFunc(st.apiKeys, func(key models.APIKey) bool {
+			return key.KeyHash == hash
+		})
+		if i < 0 {
+			return models.ErrAPIKeyNotFound
+		}
+		key = st.apiKeys[i]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *ServiceAccountRepository) ListAPIKeys(ctx context.Context, accountId openapi_types.UUID) ([]*models.APIKey, error) {
+	keys := []*models.APIKey{}
+	err := r.storage.run(ctx, func(st *state) error {
+		for _, key := range st.apiKeys {
+			if key.ServiceAccountID == accountId {
+				keys = append(keys, &key)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(keys, func(a, b *models.APIKey) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.ID.String(), a.ID.String())
+	})
+	return keys, nil
+}
+
+func (r *ServiceAccountRepository) RevokeAPIKey(ctx context.Context, id openapi_types.UUID) error {
+	return r.storage.run(ctx, func(st *state) error {
+		i := slices.IndexFunc(st.apiKeys, func(key models.APIKey) bool {
+			return key.ID == id && key.RevokedAt == nil
+		})
+		if i < 0 {
+			return models.ErrAPIKeyNotFound
+		}
+
+		now := time.Now().UTC()
+		st.apiKeys[i].RevokedAt = &now
+		return nil
+	})
+}
+
+func (r *ServiceAccountRepository) UpdateAPIKeyLastUsed(ctx context.Context, id openapi_types.UUID, at time.Time) error {
+	return r.storage.run(ctx, func(st *state) error {
+		i := slices.IndexFunc(st.apiKeys, func(key models.APIKey) bool {
+			return key.ID == id
+		})
+		if i < 0 {
+			return models.ErrAPIKeyNotFound
+		}
+
+		st.apiKeys[i].LastUsedAt = &at
+		return nil
+	})
+}
@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+func TestServiceAccountRepository(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	repo := NewServiceAccountRepository(s)
+
+	pvz := &dto.PVZ{City: "Москва"}
+	require.NoError(t, NewPvzRepository(s).CreatePvz(ctx, pvz))
+
+	assert.ErrorIs(t, repo.CreateServiceAccount(ctx, &models.ServiceAccount{Name: "unknown-pvz", PvzIds: []uuid.UUID{uuid.New()}}),
+		models.ErrPvzNotFound)
+
+	account := &models.ServiceAccount{Name: "warehouse", Role: dto.UserRoleEmployee, PvzIds: []uuid.UUID{*pvz.Id, *pvz.Id}}
+	require.NoError(t, repo.CreateServiceAccount(ctx, account))
+	assert.Equal(t, []uuid.UUID{*pvz.Id}, account.PvzIds)
+
+	assert.ErrorIs(t, repo.CreateServiceAccount(ctx, &models.ServiceAccount{Name: "warehouse"}), models.ErrServiceAccountExists)
+	require.NoError(t, repo.CreateServiceAccount(ctx, &models.ServiceAccount{Name: "audit", Role: dto.UserRoleModerator}))
+
+	found, err := repo.GetServiceAccountById(ctx, account.ID)
+	require.NoError(t, err)
+	assert.Equal(t, account, found)
+
+	_, err = repo.GetServiceAccountById(ctx, uuid.New())
+	assert.ErrorIs(t, err, models.ErrServiceAccountNotFound)
+
+	accounts, err := repo.ListServiceAccounts(ctx)
+	require.NoError(t, err)
+	require.Len(t, accounts, 2)
+	assert.Equal(t, "audit", accounts[0].Name)
+	assert.Equal(t, "warehouse", accounts[1].Name)
+
+	assert.ErrorIs(t, repo.CreateAPIKey(ctx, &models.APIKey{ServiceAccountID: uuid.New(), KeyHash: "orphan"}),
+		models.ErrServiceAccountNotFound)
+
+	older := &models.APIKey{ServiceAccountID: account.ID, Name: "old", KeyHash: "old-hash"}
+	require.NoError(t, repo.CreateAPIKey(ctx, older))
+	newer := &models.APIKey{ServiceAccountID: account.ID, Name: "new", KeyHash: "new-hash"}
+	require.NoError(t, repo.CreateAPIKey(ctx, newer))
+
+	key, err := repo.GetAPIKeyByHash(ctx, "old-hash")
+	require.NoError(t, err)
+	assert.Equal(t, older.ID, key.ID)
+
+	_, err = repo.GetAPIKeyByHash(ctx, "missing")
+	assert.ErrorIs(t, err, models.ErrAPIKeyNotFound)
+
+	usedAt := time.Now().UTC()
+	require.NoError(t, repo.UpdateAPIKeyLastUsed(ctx, older.ID, usedAt))
+	assert.ErrorIs(t, repo.UpdateAPIKeyLastUsed(ctx, uuid.New(), usedAt), models.ErrAPIKeyNotFound)
+
+	require.NoError(t, repo.RevokeAPIKey(ctx, older.ID))
+	assert.ErrorIs(t, repo.RevokeAPIKey(ctx, older.ID), models.ErrAPIKeyNotFound)
+
+	keys, err := repo.ListAPIKeys(ctx, account.ID)
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	assert.Equal(t, newer.ID, keys[0].ID)
+	assert.Equal(t, older.ID, keys[1].ID)
+	assert.Equal(t, &usedAt, keys[1].LastUsedAt)
+	assert.NotNil(t, keys[1].RevokedAt)
+}
@@ -0,0 +1,136 @@
+// Package memory implements the storage repository interfaces on top of
+// in-process maps and slices, so the service can run without Postgres.
+package memory
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+	"github.com/itisalisas/avito-backend/internal/storage"
+)
+
+type state struct {
+	users           map[uuid.UUID]models.User
+	pvzs            []dto.PVZ
+	receptions      []dto.Reception
+	products        []dto.Product
+	productTypes    []dto.ProductType
+	cities          []dto.City
+	assignments     []dto.PvzAssignment
+	refreshTokens   []models.RefreshToken
+	revokedTokens   map[string]time.Time
+	resetTokens     []models.PasswordResetToken
+	invites         []models.Invite
+	loginFailures   map[string]models.LoginFailures
+	serviceAccounts []models.ServiceAccount
+	apiKeys         []models.APIKey
+	rolePermissions map[dto.UserRole][]models.Permission
+	totps           map[uuid.UUID]models.TOTP
+	recoveryCodes   []recoveryCode
+	loginChallenges []models.LoginChallenge
+	userIdentities  []models.UserIdentity
+	oidcStates      []models.OIDCLoginState
+}
+
+func (s state) clone() state {
+	return state{
+		users:           maps.Clone(s.users),
+		pvzs:            slices.Clone(s.pvzs),
+		receptions:      slices.Clone(s.receptions),
+		products:        slices.Clone(s.products),
+		productTypes:    slices.Clone(s.productTypes),
+		cities:          slices.Clone(s.cities),
+		assignments:     slices.Clone(s.assignments),
+		refreshTokens:   slices.Clone(s.refreshTokens),
+		revokedTokens:   maps.Clone(s.revokedTokens),
+		resetTokens:     slices.Clone(s.resetTokens),
+		invites:         slices.Clone(s.invites),
+		loginFailures:   maps.Clone(s.loginFailures),
+		serviceAccounts: slices.Clone(s.serviceAccounts),
+		apiKeys:         slices.Clone(s.apiKeys),
+		rolePermissions: maps.Clone(s.rolePermissions),
+		totps:           maps.Clone(s.totps),
+		recoveryCodes:   slices.Clone(s.recoveryCodes),
+		loginChallenges: slices.Clone(s.loginChallenges),
+		userIdentities:  slices.Clone(s.userIdentities),
+		oidcStates:      slices.Clone(s.oidcStates),
+	}
+}
+
+// Storage holds the whole dataset. Transactions are serialized with a single
+// mutex, which gives them the same all-or-nothing behavior as Postgres.
+type Storage struct {
+	mu    sync.Mutex
+	state state
+}
+
+func New() *Storage {
+	st := state{
+		users:           make(map[uuid.UUID]models.User),
+		revokedTokens:   make(map[string]time.Time),
+		loginFailures:   make(map[string]models.LoginFailures),
+		rolePermissions: make(map[dto.UserRole][]models.Permission),
+		totps:           make(map[uuid.UUID]models.TOTP),
+	}
+	for _, name := range models.DefaultProductTypes {
+		st.productTypes = append(st.productTypes, dto.ProductType{Id: uuid.New(), Name: name, Active: true})
+	}
+	for _, name := range models.DefaultCities {
+		st.cities = append(st.cities, dto.City{Id: uuid.New(), Name: name, Active: true})
+	}
+	for role, permissions := range models.DefaultRolePermissions {
+		st.rolePermissions[role] = sortedPermissions(permissions)
+	}
+	return &Storage{state: st}
+}
+
+// NewRepositories builds every repository over a fresh in-memory dataset.
+func NewRepositories() *storage.Repositories {
+	s := New()
+	return &storage.Repositories{
+		TxManager:      NewTxManager(s),
+		User:           NewUserRepository(s),
+		Pvz:            NewPvzRepository(s),
+		Reception:      NewReceptionRepository(s),
+		Product:        NewProductRepository(s),
+		ProductType:    NewProductTypeRepository(s),
+		City:           NewCityRepository(s),
+		Assignment:     NewAssignmentRepository(s),
+		Token:          NewTokenRepository(s),
+		Invite:         NewInviteRepository(s),
+		LoginAttempt:   NewLoginAttemptRepository(s),
+		ServiceAccount: NewServiceAccountRepository(s),
+		Role:           NewRoleRepository(s),
+		TwoFactor:      NewTwoFactorRepository(s),
+		Identity:       NewIdentityRepository(s),
+	}
+}
+
+type txKey struct{}
+
+func (s *Storage) inTx(ctx context.Context) bool {
+	owner, _ := ctx.Value(txKey{}).(*Storage)
+	return owner == s
+}
+
+// run gives fn access to the dataset. Inside a transaction the lock is
+// already held by TxManager.Do, otherwise it is taken for this single call.
+func (s *Storage) run(ctx context.Context, fn func(st *state) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if s.inTx(ctx) {
+		return fn(&s.state)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fn(&s.state)
+}
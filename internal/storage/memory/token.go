@@ -0,0 +1,160 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type TokenRepository struct {
+	storage *Storage
+}
+
+func NewTokenRepository(storage *Storage) *TokenRepository {
+	return &TokenRepository{storage: storage}
+}
+
+func (r *TokenRepository) CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error {
+	return r.storage.run(ctx, func(st *state) error {
+		if _, ok := st.users[token.UserID]; !ok {
+			return models.ErrUserNotFound
+		}
+
+		token.ID = uuid.New()
+		st.refreshTokens = append(st.refreshTokens, *token)
+		return nil
+	})
+}
+
+func (r *TokenRepository) GetRefreshTokenByHash(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	err := r.storage.run(ctx, func(st *state) error {
+		i := slices.IndexFunc(st.refreshTokens, func(token models.RefreshToken) bool {
+			return token.TokenHash == hash
+		})
+		if i < 0 {
+			return models.ErrRefreshTokenNotFound
+		}
+		token = st.refreshTokens[i]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *TokenRepository) RevokeRefreshToken(ctx context.Context, id openapi_types.UUID) error {
+	revoked, err := r.revokeRefreshTokens(ctx, func(token models.RefreshToken) bool {
+		return token.ID == id
+	})
+	if err == nil && revoked == 0 {
+		return models.ErrRefreshTokenNotFound
+	}
+	return err
+}
+
+func (r *TokenRepository) RevokeUserRefreshTokens(ctx context.Context, userId openapi_types.UUID) error {
+	_, err := r.revokeRefreshTokens(ctx, func(token models.RefreshToken) bool {
+		return token.UserID == userId
+	})
+	return err
+}
+
+func (r *TokenRepository) revokeRefreshTokens(ctx context.Context, match func(models.RefreshToken) bool) (int, error) {
+	var revoked int
+	err := r.storage.run(ctx, func(st *state) error {
+		now := time.Now().UTC()
+		for i, token := range st.refreshTokens {
+			if token.RevokedAt == nil && match(token) {
+				st.refreshTokens[i].RevokedAt = &now
+				revoked++
+			}
+		}
+		return nil
+	})
+	return revoked, err
+}
+
+func (r *TokenRepository) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	return r.storage.run(ctx, func(st *state) error {
+		st.revokedTokens[jti] = expiresAt
+		return nil
+	})
+}
+
+func (r *TokenRepository) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	var revoked bool
+	err := r.storage.run(ctx, func(st *state) error {
+		_, revoked = st.revokedTokens[jti]
+		return nil
+	})
+	return revoked, err
+}
+
+func (r *TokenRepository) CreatePasswordResetToken(ctx context.Context, token *models.PasswordResetToken) error {
+	return r.storage.run(ctx, func(st *state) error {
+		if _, ok := st.users[token.UserID]; !ok {
+			return models.ErrUserNotFound
+		}
+
+		token.ID = uuid.New()
+		st.resetTokens = append(st.resetTokens, *token)
+		return nil
+	})
+}
+
+func (r *TokenRepository) GetPasswordResetTokenByHash(ctx context.Context, hash string) (*models.PasswordResetToken, error) {
+	var token models.PasswordResetToken
+	err := r.storage.run(ctx, func(st *state) error {
+		i := slices.IndexFunc(st.resetTokens, func(token models.PasswordResetToken) bool {
+			return token.TokenHash == hash
+		})
+		if i < 0 {
+			return models.ErrResetTokenNotFound
+		}
+		token = st.resetTokens[i]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *TokenRepository) UsePasswordResetToken(ctx context.Context, id openapi_types.UUID) error {
+	used, err := r.useResetTokens(ctx, func(token models.PasswordResetToken) bool {
+		return token.ID == id
+	})
+	if err == nil && used == 0 {
+		return models.ErrResetTokenNotFound
+	}
+	return err
+}
+
+func (r *TokenRepository) UseUserPasswordResetTokens(ctx context.Context, userId openapi_types.UUID) error {
+	_, err := r.useResetTokens(ctx, func(token models.PasswordResetToken) bool {
+		return token.UserID == userId
+	})
+	return err
+}
+
+func (r *TokenRepository) useResetTokens(ctx context.Context, match func(models.PasswordResetToken) bool) (int, error) {
+	var used int
+	err := r.storage.run(ctx, func(st *state) error {
+		now := time.Now().UTC()
+		for i, token := range st.resetTokens {
+			if token.UsedAt == nil && match(token) {
+				st.resetTokens[i].UsedAt = &now
+				used++
+			}
+		}
+		return nil
+	})
+	return used, err
+}
@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+func TestTokenRepository_RefreshTokens(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	repo := NewTokenRepository(s)
+
+	user := &models.User{Email: "employee@example.com", Role: dto.UserRoleEmployee}
+	require.NoError(t, NewUserRepository(s).CreateUser(ctx, user))
+
+	assert.ErrorIs(t, repo.CreateRefreshToken(ctx, &models.RefreshToken{UserID: uuid.New(), TokenHash: "unknown"}),
+		models.ErrUserNotFound)
+
+	first := &models.RefreshToken{UserID: user.ID, TokenHash: "first", ExpiresAt: time.Now().Add(time.Hour)}
+	second := &models.RefreshToken{UserID: user.ID, TokenHash: "second", ExpiresAt: time.Now().Add(time.Hour)}
+	require.NoError(t, repo.CreateRefreshToken(ctx, first))
+	require.NoError(t, repo.CreateRefreshToken(ctx, second))
+
+	found, err := repo.GetRefreshTokenByHash(ctx, "first")
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, found.ID)
+	assert.Nil(t, found.RevokedAt)
+
+	_, err = repo.GetRefreshTokenByHash(ctx, "missing")
+	assert.ErrorIs(t, err, models.ErrRefreshTokenNotFound)
+
+	require.NoError(t, repo.RevokeRefreshToken(ctx, first.ID))
+	assert.ErrorIs(t, repo.RevokeRefreshToken(ctx, first.ID), models.ErrRefreshTokenNotFound)
+
+	require.NoError(t, repo.RevokeUserRefreshTokens(ctx, user.ID))
+	found, err = repo.GetRefreshTokenByHash(ctx, "second")
+	require.NoError(t, err)
+	assert.NotNil(t, found.RevokedAt)
+}
+
+func TestTokenRepository_AccessTokens(t *testing.T) {
+	ctx := context.Background()
+	repo := NewTokenRepository(New())
+
+	revoked, err := repo.IsAccessTokenRevoked(ctx, "jti")
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	require.NoError(t, repo.RevokeAccessToken(ctx, "jti", time.Now().Add(time.Minute)))
+	require.NoError(t, repo.RevokeAccessToken(ctx, "jti", time.Now().Add(time.Minute)))
+
+	revoked, err = repo.IsAccessTokenRevoked(ctx, "jti")
+	require.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func TestTokenRepository_PasswordResetTokens(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	repo := NewTokenRepository(s)
+
+	user := &models.User{Email: "employee@example.com", Role: dto.UserRoleEmployee}
+	require.NoError(t, NewUserRepository(s).CreateUser(ctx, user))
+
+	assert.ErrorIs(t, repo.CreatePasswordResetToken(ctx, &models.PasswordResetToken{UserID: uuid.New(), TokenHash: "unknown"}),
+		models.ErrUserNotFound)
+
+	first := &models.PasswordResetToken{UserID: user.ID, TokenHash: "first", ExpiresAt: time.Now().Add(time.Hour)}
+	second := &models.PasswordResetToken{UserID: user.ID, TokenHash: "second", ExpiresAt: time.Now().Add(time.Hour)}
+	require.NoError(t, repo.CreatePasswordResetToken(ctx, first))
+	require.NoError(t, repo.CreatePasswordResetToken(ctx, second))
+
+	found, err := repo.GetPasswordResetTokenByHash(ctx, "first")
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, found.ID)
+	assert.Nil(t, found.UsedAt)
+
+	_, err = repo.GetPasswordResetTokenByHash(ctx, "missing")
+	assert.ErrorIs(t, err, models.ErrResetTokenNotFound)
+
+	require.NoError(t, repo.UsePasswordResetToken(ctx, first.ID))
+	assert.ErrorIs(t, repo.UsePasswordResetToken(ctx, first.ID), models.ErrResetTokenNotFound)
+
+	require.NoError(t, repo.UseUserPasswordResetTokens(ctx, user.ID))
+	found, err = repo.GetPasswordResetTokenByHash(ctx, "second")
+	require.NoError(t, err)
+	assert.NotNil(t, found.UsedAt)
+}
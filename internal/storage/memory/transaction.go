@@ -0,0 +1,42 @@
+package memory
+
+import (
+	"context"
+)
+
+type TxManager struct {
+	storage *Storage
+}
+
+func NewTxManager(storage *Storage) *TxManager {
+	return &TxManager{storage: storage}
+}
+
+// Do runs fn with exclusive access to the storage. Changes made by fn are
+// discarded when it returns an error or panics. Nested calls join the outer one.
+func (m *TxManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if m.storage.inTx(ctx) {
+		return fn(ctx)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.storage.mu.Lock()
+	defer m.storage.mu.Unlock()
+
+	snapshot := m.storage.state.clone()
+	committed := false
+	defer func() {
+		if !committed {
+			m.storage.state = snapshot
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, m.storage)); err != nil {
+		return err
+	}
+
+	committed = true
+	return nil
+}
@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+)
+
+func TestTxManager(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should keep changes when fn succeeds", func(t *testing.T) {
+		s := New()
+		pvzRepo := NewPvzRepository(s)
+
+		err := NewTxManager(s).Do(ctx, func(ctx context.Context) error {
+			return pvzRepo.CreatePvz(ctx, &dto.PVZ{City: "Москва"})
+		})
+		require.NoError(t, err)
+
+		pvzs, err := pvzRepo.GetAllPVZs(ctx)
+		require.NoError(t, err)
+		assert.Len(t, pvzs, 1)
+	})
+
+	t.Run("should discard changes when fn fails", func(t *testing.T) {
+		s := New()
+		pvzRepo := NewPvzRepository(s)
+		fnErr := errors.New("fn error")
+
+		err := NewTxManager(s).Do(ctx, func(ctx context.Context) error {
+			require.NoError(t, pvzRepo.CreatePvz(ctx, &dto.PVZ{City: "Москва"}))
+			return fnErr
+		})
+		assert.ErrorIs(t, err, fnErr)
+
+		pvzs, err := pvzRepo.GetAllPVZs(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, pvzs)
+	})
+
+	t.Run("should discard changes when fn panics", func(t *testing.T) {
+		s := New()
+		pvzRepo := NewPvzRepository(s)
+
+		assert.Panics(t, func() {
+			_ = NewTxManager(s).Do(ctx, func(ctx context.Context) error {
+				require.NoError(t, pvzRepo.CreatePvz(ctx, &dto.PVZ{City: "Москва"}))
+				panic("boom")
+			})
+		})
+
+		pvzs, err := pvzRepo.GetAllPVZs(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, pvzs)
+	})
+
+	t.Run("should join outer transaction", func(t *testing.T) {
+		s := New()
+		manager := NewTxManager(s)
+		pvzRepo := NewPvzRepository(s)
+
+		err := manager.Do(ctx, func(ctx context.Context) error {
+			return manager.Do(ctx, func(ctx context.Context) error {
+				return pvzRepo.CreatePvz(ctx, &dto.PVZ{City: "Казань"})
+			})
+		})
+		require.NoError(t, err)
+
+		pvzs, err := pvzRepo.GetAllPVZs(ctx)
+		require.NoError(t, err)
+		assert.Len(t, pvzs, 1)
+	})
+
+	t.Run("should reject cancelled context", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		err := NewTxManager(New()).Do(cancelled, func(ctx context.Context) error {
+			return nil
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestTxManager_ParallelCallsAreIsolated(t *testing.T) {
+	const workers = 50
+
+	ctx := context.Background()
+	repos := NewRepositories()
+
+	pvz := &dto.PVZ{City: "Москва"}
+	require.NoError(t, repos.Pvz.CreatePvz(ctx, pvz))
+
+	var wg sync.WaitGroup
+	var opened int
+	var mu sync.Mutex
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := repos.TxManager.Do(ctx, func(ctx context.Context) error {
+				last, err := repos.Reception.GetLastReceptionByPvzId(ctx, *pvz.Id)
+				if err == nil && last.Status != dto.Close {
+					return nil
+				}
+				mu.Lock()
+				opened++
+				mu.Unlock()
+				return repos.Reception.AddReception(ctx, &dto.Reception{PvzId: *pvz.Id})
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, opened)
+}
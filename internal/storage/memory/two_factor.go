@@ -0,0 +1,183 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type recoveryCode struct {
+	userID   uuid.UUID
+	codeHash string
+	usedAt   *time.Time
+}
+
+type TwoFactorRepository struct {
+	storage *Storage
+}
+
+func NewTwoFactorRepository(storage *Storage) *TwoFactorRepository {
+	return &TwoFactorRepository{storage: storage}
+}
+
+func (r *TwoFactorRepository) GetTOTP(ctx context.Context, userId openapi_types.UUID) (*models.TOTP, error) {
+	var totp models.TOTP
+	err := r.storage.run(ctx, func(st *state) error {
+		stored, ok := st.totps[userId]
+		if !ok {
+			return models.ErrTOTPNotFound
+		}
+		totp = stored
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &totp, nil
+}
+
+func (r *TwoFactorRepository) SaveTOTP(ctx context.Context, totp *models.TOTP) error {
+	return r.storage.run(ctx, func(st *state) error {
+		if _, ok := st.users[totp.UserID]; !ok {
+			return models.ErrUserNotFound
+		}
+
+		totp.ConfirmedAt = nil
+		totp.LastUsedStep = 0
+		totp.CreatedAt = time.Now().UTC()
+		st.totps[totp.UserID] = *totp
+		return nil
+	})
+}
+
+func (r *TwoFactorRepository) ConfirmTOTP(ctx context.Context, userId openapi_types.UUID, at time.Time) error {
+	return r.storage.run(ctx, func(st *state) error {
+		totp, ok := st.totps[userId]
+		if !ok {
+			return models.ErrTOTPNotFound
+		}
+		totp.ConfirmedAt = &at
+		st.totps[userId] = totp
+		return nil
+	})
+}
+
+func (r *TwoFactorRepository) UseTOTPStep(ctx context.Context, userId openapi_types.UUID, step int64) error {
+	return r.storage.run(ctx, func(st *state) error {
+		totp, ok := st.totps[userId]
+		if !ok || totp.LastUsedStep >= step {
+			return models.ErrInvalidTOTPCode
+		}
+		totp.LastUsedStep = step
+		st.totps[userId] = totp
+		return nil
+	})
+}
+
+func (r *TwoFactorRepository) DeleteTOTP(ctx context.Context, userId openapi_types.UUID) error {
+	return r.storage.run(ctx, func(st *state) error {
+		if _, ok := st.totps[userId]; !ok {
+			return models.ErrTOTPNotFound
+		}
+		delete(st.totps, userId)
+		st.recoveryCodes = slices.DeleteFunc(st.recoveryCodes, func(code recoveryCode) bool {
+			return code.userID == userId
+		})
+		return nil
+	})
+}
+
+func (r *TwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userId openapi_types.UUID, hashes []string) error {
+	return r.storage.run(ctx, func(st *state) error {
+		if _, ok := st.users[userId]; !ok {
+			return models.ErrUserNotFound
+		}
+
+		st.recoveryCodes = slices.DeleteFunc(st.recoveryCodes, func(code recoveryCode) bool {
+			return code.userID == userId
+		})
+		for _, hash := range hashes {
+			st.recoveryCodes = append(st.recoveryCodes, recoveryCode{userID: userId, codeHash: hash})
+		}
+		return nil
+	})
+}
+
+func (r *TwoFactorRepository) UseRecoveryCode(ctx context.Context, userId openapi_types.UUID, hash string) error {
+	return r.storage.run(ctx, func(st *state) error {
+		i := slices.IndexFunc(st.recoveryCodes, func(code recoveryCode) bool {
+			return code.userID == userId && code.codeHash == hash && code.usedAt == nil
+		})
+		if i < 0 {
+			return models.ErrRecoveryCodeNotFound
+		}
+		now := time.Now().UTC()
+		st.recoveryCodes[i].usedAt = &now
+		return nil
+	})
+}
+
+func (r *TwoFactorRepository) CreateLoginChallenge(ctx context.Context, challenge *models.LoginChallenge) error {
+	return r.storage.run(ctx, func(st *state) error {
+		if _, ok := st.users[challenge.UserID]; !ok {
+			return models.ErrUserNotFound
+		}
+
+		challenge.ID = uuid.New()
+		st.loginChallenges = append(st.loginChallenges, *challenge)
+		return nil
+	})
+}
+
+func (r *TwoFactorRepository) GetLoginChallengeByHash(ctx context.Context, hash string) (*models.LoginChallenge, error) {
+	var challenge models.LoginChallenge
+	err := r.storage.run(ctx, func(st *state) error {
+		i := slices.IndexFunc(st.loginChallenges, func(challenge models.LoginChallenge) bool {
+			return challenge.TokenHash == hash
+		})
+		if i < 0 {
+			return models.ErrLoginChallengeNotFound
+		}
+		challenge = st.loginChallenges[i]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+func (r *TwoFactorRepository) RecordLoginChallengeAttempt(ctx context.Context, id openapi_types.UUID) (int, error) {
+	var attempts int
+	err := r.storage.run(ctx, func(st *state) error {
+		i := slices.IndexFunc(st.loginChallenges, func(challenge models.LoginChallenge) bool {
+			return challenge.ID == id
+		})
+		if i < 0 {
+			return models.ErrLoginChallengeNotFound
+		}
+		st.loginChallenges[i].Attempts++
+		attempts = st.loginChallenges[i].Attempts
+		return nil
+	})
+	return attempts, err
+}
+
+func (r *TwoFactorRepository) UseLoginChallenge(ctx context.Context, id openapi_types.UUID) error {
+	return r.storage.run(ctx, func(st *state) error {
+		i := slices.IndexFunc(st.loginChallenges, func(challenge models.LoginChallenge) bool {
+			return challenge.ID == id && challenge.UsedAt == nil
+		})
+		if i < 0 {
+			return models.ErrLoginChallengeNotFound
+		}
+		now := time.Now().UTC()
+		st.loginChallenges[i].UsedAt = &now
+		return nil
+	})
+}
@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+func TestTwoFactorRepository_TOTP(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	repo := NewTwoFactorRepository(s)
+
+	user := &models.User{Email: "moderator@example.com", Role: dto.UserRoleModerator}
+	require.NoError(t, NewUserRepository(s).CreateUser(ctx, user))
+
+	_, err := repo.GetTOTP(ctx, user.ID)
+	assert.ErrorIs(t, err, models.ErrTOTPNotFound)
+	assert.ErrorIs(t, repo.SaveTOTP(ctx, &models.TOTP{UserID: uuid.New(), Secret: "SECRET"}), models.ErrUserNotFound)
+
+	require.NoError(t, repo.SaveTOTP(ctx, &models.TOTP{UserID: user.ID, Secret: "SECRET"}))
+	require.NoError(t, repo.ConfirmTOTP(ctx, user.ID, time.Now()))
+	require.NoError(t, repo.UseTOTPStep(ctx, user.ID, 10))
+	assert.ErrorIs(t, repo.UseTOTPStep(ctx, user.ID, 10), models.ErrInvalidTOTPCode)
+
+	require.NoError(t, repo.SaveTOTP(ctx, &models.TOTP{UserID: user.ID, Secret: "OTHER"}))
+	totp, err := repo.GetTOTP(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "OTHER", totp.Secret)
+	assert.False(t, totp.Confirmed(), "a new secret has to be confirmed again")
+	assert.Zero(t, totp.LastUsedStep)
+
+	require.NoError(t, repo.ReplaceRecoveryCodes(ctx, user.ID, []string{"a", "b"}))
+	require.NoError(t, repo.UseRecoveryCode(ctx, user.ID, "a"))
+	assert.ErrorIs(t, repo.UseRecoveryCode(ctx, user.ID, "a"), models.ErrRecoveryCodeNotFound)
+
+	require.NoError(t, repo.DeleteTOTP(ctx, user.ID))
+	assert.ErrorIs(t, repo.DeleteTOTP(ctx, user.ID), models.ErrTOTPNotFound)
+	assert.ErrorIs(t, repo.UseRecoveryCode(ctx, user.ID, "b"), models.ErrRecoveryCodeNotFound)
+}
+
+func TestTwoFactorRepository_LoginChallenges(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	repo := NewTwoFactorRepository(s)
+
+	user := &models.User{Email: "moderator@example.com", Role: dto.UserRoleModerator}
+	require.NoError(t, NewUserRepository(s).CreateUser(ctx, user))
+
+	assert.ErrorIs(t, repo.CreateLoginChallenge(ctx, &models.LoginChallenge{UserID: uuid.New()}), models.ErrUserNotFound)
+
+	challenge := &models.LoginChallenge{UserID: user.ID, TokenHash: "hash", ExpiresAt: time.Now().Add(time.Minute)}
+	require.NoError(t, repo.CreateLoginChallenge(ctx, challenge))
+
+	attempts, err := repo.RecordLoginChallengeAttempt(ctx, challenge.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempts)
+
+	found, err := repo.GetLoginChallengeByHash(ctx, "hash")
+	require.NoError(t, err)
+	assert.Equal(t, challenge.ID, found.ID)
+	assert.Equal(t, 1, found.Attempts)
+
+	require.NoError(t, repo.UseLoginChallenge(ctx, challenge.ID))
+	assert.ErrorIs(t, repo.UseLoginChallenge(ctx, challenge.ID), models.ErrLoginChallengeNotFound)
+
+	_, err = repo.GetLoginChallengeByHash(ctx, "missing")
+	assert.ErrorIs(t, err, models.ErrLoginChallengeNotFound)
+}
@@ -0,0 +1,149 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type UserRepository struct {
+	storage *Storage
+}
+
+func NewUserRepository(storage *Storage) *UserRepository {
+	return &UserRepository{storage: storage}
+}
+
+func (r *UserRepository) CreateUser(ctx context.Context, user *models.User) error {
+	return r.storage.run(ctx, func(st *state) error {
+		for _, u := range st.users {
+			if u.Email == user.Email {
+				return models.ErrEmailAlreadyInUse
+			}
+		}
+
+		user.ID = uuid.New()
+		user.Active = true
+		user.CreatedAt = time.Now().UTC()
+		st.users[user.ID] = *user
+		return nil
+	})
+}
+
+func (r *UserRepository) GetUserByEmail(ctx context.Context, email openapi_types.Email) (*models.User, error) {
+	var user *models.User
+	err := r.storage.run(ctx, func(st *state) error {
+		for _, u := range st.users {
+			if u.Email == email {
+				user = &u
+				return nil
+			}
+		}
+		return models.ErrUserNotFound
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (r *UserRepository) GetUserById(ctx context.Context, id openapi_types.UUID) (*models.User, error) {
+	var user models.User
+	err := r.storage.run(ctx, func(st *state) error {
+		u, ok := st.users[id]
+		if !ok {
+			return models.ErrUserNotFound
+		}
+		user = u
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *UserRepository) UpdatePassword(ctx context.Context, id openapi_types.UUID, passwordHash string) error {
+	return r.updateUser(ctx, id, func(user *models.User) { user.Password = passwordHash })
+}
+
+func (r *UserRepository) RehashPassword(ctx context.Context, id openapi_types.UUID, oldHash, newHash string) (bool, error) {
+	replaced := false
+	err := r.storage.run(ctx, func(st *state) error {
+		user, ok := st.users[id]
+		if !ok || user.Password != oldHash {
+			return nil
+		}
+		user.Password = newHash
+		st.users[id] = user
+		replaced = true
+		return nil
+	})
+	return replaced, err
+}
+
+func (r *UserRepository) ListUsers(ctx context.Context, params models.UserListParams) (*models.UserPage, error) {
+	var users []*models.User
+	err := r.storage.run(ctx, func(st *state) error {
+		email := strings.ToLower(params.Email)
+		for _, u := range st.users {
+			switch {
+			case !strings.Contains(strings.ToLower(string(u.Email)), email):
+			case params.Role != nil && u.Role != *params.Role:
+			case params.Active != nil && u.Active != *params.Active:
+			default:
+				users = append(users, &u)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		a, b := users[i], users[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return bytes.Compare(a.ID[:], b.ID[:]) > 0
+	})
+
+	start := min(params.Offset(), uint64(len(users)))
+	end := min(start+params.Limit, uint64(len(users)))
+	page := &models.UserPage{Items: []*models.User{}, TotalCount: uint64(len(users))}
+	page.Items = append(page.Items, users[start:end]...)
+	return page, nil
+}
+
+func (r *UserRepository) UpdateRole(ctx context.Context, id openapi_types.UUID, role dto.UserRole) error {
+	return r.updateUser(ctx, id, func(user *models.User) { user.Role = role })
+}
+
+func (r *UserRepository) SetActive(ctx context.Context, id openapi_types.UUID, active bool) error {
+	return r.updateUser(ctx, id, func(user *models.User) { user.Active = active })
+}
+
+func (r *UserRepository) UpdateLastLogin(ctx context.Context, id openapi_types.UUID, at time.Time) error {
+	return r.updateUser(ctx, id, func(user *models.User) { user.LastLoginAt = &at })
+}
+
+func (r *UserRepository) updateUser(ctx context.Context, id openapi_types.UUID, update func(user *models.User)) error {
+	return r.storage.run(ctx, func(st *state) error {
+		user, ok := st.users[id]
+		if !ok {
+			return models.ErrUserNotFound
+		}
+		update(&user)
+		st.users[id] = user
+		return nil
+	})
+}
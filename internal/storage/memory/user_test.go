@@ -0,0 +1,144 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/oapi-codegen/runtime/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+func TestUserRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewUserRepository(New())
+
+	user := &models.User{Email: "test@example.com", Password: "hash", Role: dto.UserRoleEmployee}
+	require.NoError(t, repo.CreateUser(ctx, user))
+	assert.NotEqual(t, uuid.Nil, user.ID)
+	assert.True(t, user.Active)
+	assert.False(t, user.CreatedAt.IsZero())
+
+	t.Run("email already in use", func(t *testing.T) {
+		err := repo.CreateUser(ctx, &models.User{Email: user.Email, Password: "hash", Role: dto.UserRoleModerator})
+		assert.Equal(t, models.ErrEmailAlreadyInUse, err)
+	})
+
+	t.Run("get user by email", func(t *testing.T) {
+		result, err := repo.GetUserByEmail(ctx, user.Email)
+		require.NoError(t, err)
+		assert.Equal(t, *user, *result)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		_, err := repo.GetUserByEmail(ctx, "nonexistent@example.com")
+		assert.Equal(t, models.ErrUserNotFound, err)
+	})
+
+	t.Run("update password", func(t *testing.T) {
+		require.NoError(t, repo.UpdatePassword(ctx, user.ID, "new hash"))
+		result, err := repo.GetUserById(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "new hash", result.Password)
+
+		assert.Equal(t, models.ErrUserNotFound, repo.UpdatePassword(ctx, uuid.New(), "hash"))
+	})
+
+	t.Run("rehash password", func(t *testing.T) {
+		replaced, err := repo.RehashPassword(ctx, user.ID, "stale hash", "rehashed")
+		require.NoError(t, err)
+		assert.False(t, replaced)
+
+		replaced, err = repo.RehashPassword(ctx, user.ID, "new hash", "rehashed")
+		require.NoError(t, err)
+		assert.True(t, replaced)
+		result, err := repo.GetUserById(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "rehashed", result.Password)
+	})
+}
+
+func TestUserRepository_Admin(t *testing.T) {
+	ctx := context.Background()
+	repo := NewUserRepository(New())
+
+	var users []*models.User
+	for _, email := range []string{"alice@example.com", "bob@example.com", "carol@test.org"} {
+		user := &models.User{Email: types.Email(email), Password: "hash", Role: dto.UserRoleEmployee}
+		require.NoError(t, repo.CreateUser(ctx, user))
+		users = append(users, user)
+	}
+	alice, bob, carol := users[0], users[1], users[2]
+
+	require.NoError(t, repo.UpdateRole(ctx, bob.ID, dto.UserRoleModerator))
+	require.NoError(t, repo.SetActive(ctx, carol.ID, false))
+	loginAt := time.Now().UTC()
+	require.NoError(t, repo.UpdateLastLogin(ctx, alice.ID, loginAt))
+
+	assert.Equal(t, models.ErrUserNotFound, repo.UpdateRole(ctx, uuid.New(), dto.UserRoleModerator))
+	assert.Equal(t, models.ErrUserNotFound, repo.SetActive(ctx, uuid.New(), false))
+	assert.Equal(t, models.ErrUserNotFound, repo.UpdateLastLogin(ctx, uuid.New(), loginAt))
+
+	found, err := repo.GetUserById(ctx, alice.ID)
+	require.NoError(t, err)
+	assert.Equal(t, loginAt, *found.LastLoginAt)
+
+	moderator := dto.UserRoleModerator
+	active := false
+	tests := []struct {
+		name      string
+		params    models.UserListParams
+		wantIds   []uuid.UUID
+		wantTotal uint64
+	}{
+		{
+			name:      "all newest first",
+			params:    models.UserListParams{Page: 1, Limit: 10},
+			wantIds:   []uuid.UUID{carol.ID, bob.ID, alice.ID},
+			wantTotal: 3,
+		},
+		{
+			name:      "second page",
+			params:    models.UserListParams{Page: 2, Limit: 2},
+			wantIds:   []uuid.UUID{alice.ID},
+			wantTotal: 3,
+		},
+		{
+			name:      "email substring ignores case",
+			params:    models.UserListParams{UserFilter: models.UserFilter{Email: "EXAMPLE"}, Page: 1, Limit: 10},
+			wantIds:   []uuid.UUID{bob.ID, alice.ID},
+			wantTotal: 2,
+		},
+		{
+			name:      "role",
+			params:    models.UserListParams{UserFilter: models.UserFilter{Role: &moderator}, Page: 1, Limit: 10},
+			wantIds:   []uuid.UUID{bob.ID},
+			wantTotal: 1,
+		},
+		{
+			name:      "inactive",
+			params:    models.UserListParams{UserFilter: models.UserFilter{Active: &active}, Page: 1, Limit: 10},
+			wantIds:   []uuid.UUID{carol.ID},
+			wantTotal: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := repo.ListUsers(ctx, tt.params)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTotal, page.TotalCount)
+
+			ids := make([]uuid.UUID, 0, len(page.Items))
+			for _, u := range page.Items {
+				ids = append(ids, u.ID)
+			}
+			assert.Equal(t, tt.wantIds, ids)
+		})
+	}
+}
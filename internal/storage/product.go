@@ -9,6 +9,7 @@ import (
 	openapi_types "github.com/oapi-codegen/runtime/types"
 
 	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
 )
 
 type ProductRepository struct {
@@ -21,8 +22,8 @@ func NewProductRepository(db *sql.DB) *ProductRepository {
 
 func (r *ProductRepository) AddProduct(ctx context.Context, product *dto.Product) error {
 	query, args, err := squirrel.Insert("pvz_service.product").
-		Columns("product_type", "reception_id").
-		Values(product.Type, product.ReceptionId).
+		Columns("product_type", "reception_id", "created_by").
+		Values(product.Type, product.ReceptionId, product.CreatedBy).
 		Suffix("returning product_id, added_at").
 		PlaceholderFormat(squirrel.Dollar).
 		ToSql()
@@ -30,7 +31,10 @@ func (r *ProductRepository) AddProduct(ctx context.Context, product *dto.Product
 	if err != nil {
 		return err
 	}
-	err = r.tx.QueryRowContext(ctx, query, args...).Scan(&product.Id, &product.DateTime)
+	err = r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(&product.Id, &product.DateTime)
+	if isConstraintViolation(err, foreignKeyViolation, productTypeFKConstraint) {
+		return models.ErrIncorrectProductType
+	}
 
 	if err != nil {
 		return err
@@ -40,7 +44,7 @@ func (r *ProductRepository) AddProduct(ctx context.Context, product *dto.Product
 }
 
 func (r *ProductRepository) GetLastProduct(ctx context.Context, receptionId uuid.UUID) (*dto.Product, error) {
-	query, args, err := squirrel.Select("product_id", "product_type", "reception_id", "added_at").
+	query, args, err := squirrel.Select("product_id", "product_type", "reception_id", "added_at", "created_by").
 		From("pvz_service.product").
 		Where(squirrel.Eq{"reception_id": receptionId}).
 		OrderBy("added_at DESC").
@@ -54,7 +58,7 @@ func (r *ProductRepository) GetLastProduct(ctx context.Context, receptionId uuid
 
 	product := &dto.Product{}
 
-	err = r.tx.QueryRowContext(ctx, query, args...).Scan(&product.Id, &product.Type, &product.ReceptionId, &product.DateTime)
+	err = r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(&product.Id, &product.Type, &product.ReceptionId, &product.DateTime, &product.CreatedBy)
 	switch {
 	case err != nil:
 		return nil, err
@@ -73,6 +77,6 @@ func (r *ProductRepository) DeleteProductById(ctx context.Context, productID ope
 		return err
 	}
 
-	_, err = r.tx.ExecContext(ctx, query, args...)
+	_, err = r.querier(ctx).ExecContext(ctx, query, args...)
 	return err
 }
@@ -20,6 +20,7 @@ type ProductRepositoryTestSuite struct {
 	db      *sql.DB
 	cleanup func()
 	repo    *ProductRepository
+	tx      *sql.Tx
 	ctx     context.Context
 	pvzID   uuid.UUID
 }
@@ -31,6 +32,9 @@ func TestProductRepositorySuite(t *testing.T) {
 func (s *ProductRepositoryTestSuite) SetupSuite() {
 	s.ctx = context.Background()
 	db := DBTestSetup()
+	if db == nil {
+		s.T().Skip("test database is not configured")
+	}
 	log.Println("migrations applied")
 	s.db = db
 
@@ -50,19 +54,20 @@ func (s *ProductRepositoryTestSuite) TearDownSuite() {
 func (s *ProductRepositoryTestSuite) SetupTest() {
 	tx, err := s.db.BeginTx(s.ctx, nil)
 	require.NoError(s.T(), err)
-	s.repo.tx = tx
+	s.tx = tx
+	s.ctx = withTx(context.Background(), tx)
 }
 
 func (s *ProductRepositoryTestSuite) TearDownTest() {
-	if s.repo.tx != nil {
-		err := s.repo.tx.Rollback()
+	if s.tx != nil {
+		err := s.tx.Rollback()
 		require.NoError(s.T(), err)
 	}
 }
 
 func (s *ProductRepositoryTestSuite) createPVZ(t *testing.T) uuid.UUID {
 	pvzID := uuid.New()
-	_, err := s.repo.tx.ExecContext(s.ctx, `
+	_, err := s.tx.ExecContext(s.ctx, `
         insert into pvz_service.pvz (pvz_id, registration_date, city)
         values ($1, current_date, 'Москва')`, pvzID)
 	require.NoError(t, err)
@@ -71,7 +76,7 @@ func (s *ProductRepositoryTestSuite) createPVZ(t *testing.T) uuid.UUID {
 
 func (s *ProductRepositoryTestSuite) createReception(t *testing.T) uuid.UUID {
 	receptionID := uuid.New()
-	_, err := s.repo.tx.ExecContext(s.ctx, `
+	_, err := s.tx.ExecContext(s.ctx, `
 		insert into pvz_service.reception (reception_id, started_at, pvz_id, status)
 		values ($1, current_timestamp, $2, 'in_progress')`,
 		receptionID, s.pvzID,
@@ -104,7 +109,7 @@ func (s *ProductRepositoryTestSuite) TestAddProduct() {
 				assert.False(t, p.DateTime.IsZero())
 
 				var count int
-				err := s.repo.tx.QueryRowContext(
+				err := s.tx.QueryRowContext(
 					s.ctx,
 					"select count(*) from pvz_service.product where product_id = $1",
 					p.Id,
@@ -135,16 +140,16 @@ func (s *ProductRepositoryTestSuite) TestGetLastProduct() {
 	s.pvzID = s.createPVZ(s.T())
 	receptionID := s.createReception(s.T())
 
-	types := []dto.ProductType{
-		dto.ProductTypeОбувь,
-		dto.ProductTypeОдежда,
-		dto.ProductTypeЭлектроника,
+	types := []string{
+		"обувь",
+		"одежда",
+		"электроника",
 	}
 
 	for i, ptype := range types {
 		date := time.Now().Add(time.Duration(i) * time.Second)
 		productID := uuid.New()
-		_, err := s.repo.tx.ExecContext(s.ctx, `
+		_, err := s.tx.ExecContext(s.ctx, `
 insert into pvz_service.product (product_id, product_type, reception_id, added_at)
 values ($1, $2, $3, $4)
 		`, productID, ptype, receptionID, date)
@@ -154,7 +159,7 @@ values ($1, $2, $3, $4)
 	s.T().Run("should return last added product", func(t *testing.T) {
 		result, err := s.repo.GetLastProduct(s.ctx, receptionID)
 		require.NoError(t, err)
-		assert.Equal(t, dto.ProductTypeЭлектроника, result.Type)
+		assert.Equal(t, "электроника", result.Type)
 	})
 
 	s.T().Run("should return error for non-existent reception", func(t *testing.T) {
@@ -169,7 +174,7 @@ func (s *ProductRepositoryTestSuite) TestDeleteProductById() {
 	receptionID := s.createReception(s.T())
 
 	p := &dto.Product{
-		Type:        dto.ProductTypeОдежда,
+		Type:        "одежда",
 		ReceptionId: receptionID,
 	}
 	err := s.repo.AddProduct(s.ctx, p)
@@ -180,7 +185,7 @@ func (s *ProductRepositoryTestSuite) TestDeleteProductById() {
 		require.NoError(t, err)
 
 		var exists bool
-		err = s.repo.tx.QueryRowContext(
+		err = s.tx.QueryRowContext(
 			s.ctx,
 			"select exists(select 1 from pvz_service.product where product_id = $1)",
 			p.Id,
@@ -0,0 +1,135 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type ProductTypeRepository struct {
+	*BaseRepository
+}
+
+func NewProductTypeRepository(db *sql.DB) *ProductTypeRepository {
+	return &ProductTypeRepository{BaseRepository: NewBaseRepository(db)}
+}
+
+func (r *ProductTypeRepository) CreateProductType(ctx context.Context, productType *dto.ProductType) error {
+	query, args, err := squirrel.Insert("pvz_service.product_type").
+		Columns("name").
+		Values(productType.Name).
+		Suffix("returning product_type_id, active").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	err = r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(&productType.Id, &productType.Active)
+	switch {
+	case isConstraintViolation(err, uniqueViolation, productTypeNameConstraint):
+		return models.ErrProductTypeExists
+	case err != nil:
+		return fmt.Errorf("failed to create product type: %w", err)
+	default:
+		return nil
+	}
+}
+
+func (r *ProductTypeRepository) GetProductTypes(ctx context.Context) ([]dto.ProductType, error) {
+	query, args, err := squirrel.Select("product_type_id", "name", "active").
+		From("pvz_service.product_type").
+		OrderBy("name").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.querier(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product types: %w", err)
+	}
+	defer rows.Close()
+
+	productTypes := []dto.ProductType{}
+	for rows.Next() {
+		var productType dto.ProductType
+		if err := rows.Scan(&productType.Id, &productType.Name, &productType.Active); err != nil {
+			return nil, fmt.Errorf("failed to scan product type: %w", err)
+		}
+		productTypes = append(productTypes, productType)
+	}
+
+	return productTypes, rows.Err()
+}
+
+func (r *ProductTypeRepository) GetProductTypeByName(ctx context.Context, name string) (*dto.ProductType, error) {
+	query, args, err := squirrel.Select("product_type_id", "name", "active").
+		From("pvz_service.product_type").
+		Where(squirrel.Eq{"name": name}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	return r.scanProductType(r.querier(ctx).QueryRowContext(ctx, query, args...))
+}
+
+func (r *ProductTypeRepository) RenameProductType(ctx context.Context, id openapi_types.UUID, name string) (*dto.ProductType, error) {
+	query, args, err := squirrel.Update("pvz_service.product_type").
+		Set("name", name).
+		Where(squirrel.Eq{"product_type_id": id}).
+		Suffix("returning product_type_id, name, active").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	productType, err := r.scanProductType(r.querier(ctx).QueryRowContext(ctx, query, args...))
+	if isConstraintViolation(err, uniqueViolation, productTypeNameConstraint) {
+		return nil, models.ErrProductTypeExists
+	}
+	return productType, err
+}
+
+func (r *ProductTypeRepository) DeactivateProductType(ctx context.Context, id openapi_types.UUID) (*dto.ProductType, error) {
+	query, args, err := squirrel.Update("pvz_service.product_type").
+		Set("active", false).
+		Where(squirrel.Eq{"product_type_id": id}).
+		Suffix("returning product_type_id, name, active").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	return r.scanProductType(r.querier(ctx).QueryRowContext(ctx, query, args...))
+}
+
+func (r *ProductTypeRepository) scanProductType(row *sql.Row) (*dto.ProductType, error) {
+	var productType dto.ProductType
+	err := row.Scan(&productType.Id, &productType.Name, &productType.Active)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, models.ErrProductTypeNotFound
+	case err != nil:
+		return nil, fmt.Errorf("failed to get product type: %w", err)
+	default:
+		return &productType, nil
+	}
+}
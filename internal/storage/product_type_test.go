@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type ProductTypeRepositoryTestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	cleanup func()
+	repo    *ProductTypeRepository
+	tx      *sql.Tx
+	ctx     context.Context
+}
+
+func TestProductTypeRepositorySuite(t *testing.T) {
+	suite.Run(t, new(ProductTypeRepositoryTestSuite))
+}
+
+func (s *ProductTypeRepositoryTestSuite) SetupSuite() {
+	s.ctx = context.Background()
+	db := DBTestSetup()
+	if db == nil {
+		s.T().Skip("test database is not configured")
+	}
+	log.Println("migrations applied")
+	s.db = db
+	s.repo = NewProductTypeRepository(s.db)
+}
+
+func (s *ProductTypeRepositoryTestSuite) TearDownSuite() {
+	err := s.db.Close()
+	if err != nil {
+		log.Fatalf("failed to close database connection: %v", err)
+	}
+	if s.cleanup != nil {
+		s.cleanup()
+	}
+}
+
+func (s *ProductTypeRepositoryTestSuite) SetupTest() {
+	tx, err := s.db.BeginTx(s.ctx, nil)
+	require.NoError(s.T(), err)
+	s.tx = tx
+	s.ctx = withTx(context.Background(), tx)
+}
+
+func (s *ProductTypeRepositoryTestSuite) TearDownTest() {
+	if s.tx != nil {
+		err := s.tx.Rollback()
+		require.NoError(s.T(), err)
+	}
+}
+
+func (s *ProductTypeRepositoryTestSuite) TestSeededTypes() {
+	productTypes, err := s.repo.GetProductTypes(s.ctx)
+	require.NoError(s.T(), err)
+
+	var names []string
+	for _, productType := range productTypes {
+		names = append(names, productType.Name)
+	}
+	assert.Subset(s.T(), names, models.DefaultProductTypes)
+}
+
+func (s *ProductTypeRepositoryTestSuite) TestCreateProductType() {
+	productType := &dto.ProductType{Name: "книги"}
+	require.NoError(s.T(), s.repo.CreateProductType(s.ctx, productType))
+	assert.NotEqual(s.T(), uuid.Nil, productType.Id)
+	assert.True(s.T(), productType.Active)
+
+	found, err := s.repo.GetProductTypeByName(s.ctx, "книги")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), productType.Id, found.Id)
+}
+
+func (s *ProductTypeRepositoryTestSuite) TestCreateDuplicateProductType() {
+	err := s.repo.CreateProductType(s.ctx, &dto.ProductType{Name: "обувь"})
+	assert.Equal(s.T(), models.ErrProductTypeExists, err)
+}
+
+func (s *ProductTypeRepositoryTestSuite) TestRenameCascadesToProducts() {
+	productType := &dto.ProductType{Name: "книги"}
+	require.NoError(s.T(), s.repo.CreateProductType(s.ctx, productType))
+
+	pvzID, receptionID, productID := uuid.New(), uuid.New(), uuid.New()
+	_, err := s.tx.ExecContext(s.ctx, `
+		insert into pvz_service.pvz (pvz_id, registration_date, city)
+		values ($1, current_date, 'Москва')`, pvzID)
+	require.NoError(s.T(), err)
+	_, err = s.tx.ExecContext(s.ctx, `
+		insert into pvz_service.reception (reception_id, pvz_id)
+		values ($1, $2)`, receptionID, pvzID)
+	require.NoError(s.T(), err)
+	_, err = s.tx.ExecContext(s.ctx, `
+		insert into pvz_service.product (product_id, product_type, reception_id)
+		values ($1, 'книги', $2)`, productID, receptionID)
+	require.NoError(s.T(), err)
+
+	renamed, err := s.repo.RenameProductType(s.ctx, productType.Id, "литература")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "литература", renamed.Name)
+
+	var stored string
+	err = s.tx.QueryRowContext(s.ctx,
+		"select product_type from pvz_service.product where product_id = $1", productID,
+	).Scan(&stored)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "литература", stored)
+}
+
+func (s *ProductTypeRepositoryTestSuite) TestRenameUnknownProductType() {
+	_, err := s.repo.RenameProductType(s.ctx, uuid.New(), "литература")
+	assert.Equal(s.T(), models.ErrProductTypeNotFound, err)
+}
+
+func (s *ProductTypeRepositoryTestSuite) TestDeactivateProductType() {
+	productType := &dto.ProductType{Name: "книги"}
+	require.NoError(s.T(), s.repo.CreateProductType(s.ctx, productType))
+
+	deactivated, err := s.repo.DeactivateProductType(s.ctx, productType.Id)
+	require.NoError(s.T(), err)
+	assert.False(s.T(), deactivated.Active)
+}
+
+func (s *ProductTypeRepositoryTestSuite) TestProductRequiresKnownType() {
+	pvzID, receptionID := uuid.New(), uuid.New()
+	_, err := s.tx.ExecContext(s.ctx, `
+		insert into pvz_service.pvz (pvz_id, registration_date, city)
+		values ($1, current_date, 'Москва')`, pvzID)
+	require.NoError(s.T(), err)
+	_, err = s.tx.ExecContext(s.ctx, `
+		insert into pvz_service.reception (reception_id, pvz_id)
+		values ($1, $2)`, receptionID, pvzID)
+	require.NoError(s.T(), err)
+
+	err = NewProductRepository(s.db).AddProduct(s.ctx, &dto.Product{ReceptionId: receptionID, Type: "игрушки"})
+	assert.Equal(s.T(), models.ErrIncorrectProductType, err)
+}
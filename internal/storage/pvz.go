@@ -3,8 +3,10 @@ package storage
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/Masterminds/squirrel"
@@ -19,8 +21,39 @@ type PvzRepository struct {
 	*BaseRepository
 }
 
+var pvzColumns = []string{
+	"pvz_id",
+	"city",
+	"registration_date",
+	"name",
+	"address",
+	"latitude",
+	"longitude",
+	"phone",
+	"opening_hours",
+	"decommissioned_at",
+	"created_by",
+}
+
+// pvzFields returns scan destinations matching pvzColumns.
+func pvzFields(pvz *dto.PVZ) []any {
+	return []any{
+		&pvz.Id,
+		&pvz.City,
+		&pvz.RegistrationDate,
+		&pvz.Name,
+		&pvz.Address,
+		&pvz.Latitude,
+		&pvz.Longitude,
+		&pvz.Phone,
+		&pvz.OpeningHours,
+		&pvz.DecommissionedAt,
+		&pvz.CreatedBy,
+	}
+}
+
 func (r *PvzRepository) GetAllPVZs(ctx context.Context) ([]dto.PVZ, error) {
-	query, _, err := squirrel.Select("pvz_id", "city", "registration_date").
+	query, _, err := squirrel.Select(pvzColumns...).
 		From("pvz_service.pvz").
 		PlaceholderFormat(squirrel.Dollar).
 		ToSql()
@@ -29,7 +62,7 @@ func (r *PvzRepository) GetAllPVZs(ctx context.Context) ([]dto.PVZ, error) {
 		return nil, fmt.Errorf("failed to build query: %w", err)
 	}
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := r.querier(ctx).QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -43,7 +76,7 @@ func (r *PvzRepository) GetAllPVZs(ctx context.Context) ([]dto.PVZ, error) {
 	var pvzs []dto.PVZ
 	for rows.Next() {
 		var p dto.PVZ
-		if err := rows.Scan(&p.Id, &p.City, &p.RegistrationDate); err != nil {
+		if err := rows.Scan(pvzFields(&p)...); err != nil {
 			return nil, err
 		}
 		pvzs = append(pvzs, p)
@@ -57,8 +90,8 @@ func NewPvzRepository(db *sql.DB) *PvzRepository {
 
 func (r *PvzRepository) CreatePvz(ctx context.Context, pvz *dto.PVZ) error {
 	query, args, err := squirrel.Insert("pvz_service.pvz").
-		Columns("city").
-		Values(pvz.City).
+		Columns("city", "name", "address", "latitude", "longitude", "phone", "opening_hours", "created_by").
+		Values(pvz.City, pvz.Name, pvz.Address, pvz.Latitude, pvz.Longitude, pvz.Phone, pvz.OpeningHours, pvz.CreatedBy).
 		Suffix("returning pvz_id, registration_date").
 		PlaceholderFormat(squirrel.Dollar).
 		ToSql()
@@ -67,7 +100,10 @@ func (r *PvzRepository) CreatePvz(ctx context.Context, pvz *dto.PVZ) error {
 		return fmt.Errorf("failed to build query: %w", err)
 	}
 
-	err = r.tx.QueryRowContext(ctx, query, args...).Scan(&pvz.Id, &pvz.RegistrationDate)
+	err = r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(&pvz.Id, &pvz.RegistrationDate)
+	if isConstraintViolation(err, foreignKeyViolation, pvzCityFKConstraint) {
+		return models.ErrIncorrectCity
+	}
 
 	if err != nil {
 		return fmt.Errorf("failed to insert pvz: %w", err)
@@ -76,49 +112,298 @@ func (r *PvzRepository) CreatePvz(ctx context.Context, pvz *dto.PVZ) error {
 	return nil
 }
 
-func (r *PvzRepository) GetPvzList(ctx context.Context, startTime *time.Time, endTime *time.Time, page uint64, limit uint64) ([]*models.ExtendedPvz, error) {
+func (r *PvzRepository) GetPvzById(ctx context.Context, id openapi_types.UUID) (*dto.PVZ, error) {
+	query, args, err := squirrel.Select(pvzColumns...).
+		From("pvz_service.pvz").
+		Where(squirrel.Eq{"pvz_id": id}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	return scanPvz(r.querier(ctx).QueryRowContext(ctx, query, args...))
+}
+
+func (r *PvzRepository) UpdatePvz(ctx context.Context, id openapi_types.UUID, update *dto.PVZUpdate) (*dto.PVZ, error) {
+	values := map[string]interface{}{}
+	if update.Name != nil {
+		values["name"] = *update.Name
+	}
+	if update.Address != nil {
+		values["address"] = *update.Address
+	}
+	if update.Latitude != nil {
+		values["latitude"] = *update.Latitude
+	}
+	if update.Longitude != nil {
+		values["longitude"] = *update.Longitude
+	}
+	if update.Phone != nil {
+		values["phone"] = *update.Phone
+	}
+	if update.OpeningHours != nil {
+		values["opening_hours"] = *update.OpeningHours
+	}
+	if len(values) == 0 {
+		return r.GetPvzById(ctx, id)
+	}
+
+	query, args, err := squirrel.Update("pvz_service.pvz").
+		SetMap(values).
+		Where(squirrel.Eq{"pvz_id": id}).
+		Suffix("returning " + strings.Join(pvzColumns, ", ")).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	return scanPvz(r.querier(ctx).QueryRowContext(ctx, query, args...))
+}
+
+func (r *PvzRepository) DecommissionPvz(ctx context.Context, id openapi_types.UUID) (*dto.PVZ, error) {
+	query, args, err := squirrel.Update("pvz_service.pvz").
+		Set("decommissioned_at", squirrel.Expr("current_timestamp")).
+		Where(squirrel.Eq{"pvz_id": id, "decommissioned_at": nil}).
+		Suffix("returning " + strings.Join(pvzColumns, ", ")).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	pvz, err := scanPvz(r.querier(ctx).QueryRowContext(ctx, query, args...))
+	if !errors.Is(err, models.ErrPvzNotFound) {
+		return pvz, err
+	}
+
+	// Nothing was updated: either the PVZ does not exist or it is already
+	// decommissioned.
+	if _, err := r.GetPvzById(ctx, id); err != nil {
+		return nil, err
+	}
+	return nil, models.ErrPvzDecommissioned
+}
+
+func scanPvz(row *sql.Row) (*dto.PVZ, error) {
+	var pvz dto.PVZ
+	err := row.Scan(pvzFields(&pvz)...)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, models.ErrPvzNotFound
+	case err != nil:
+		return nil, fmt.Errorf("failed to get pvz: %w", err)
+	default:
+		return &pvz, nil
+	}
+}
+
+func (r *PvzRepository) GetPvzList(ctx context.Context, params models.PvzListParams) (*models.PvzPage, error) {
+	filter := pvzListFilter(params)
+
+	var totalCount uint64
+	if !params.SkipCount {
+		var err error
+		totalCount, err = r.countPvz(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	columns := make([]string, len(pvzColumns))
+	for i, column := range pvzColumns {
+		columns[i] = "p." + column
+	}
+
+	pageQuery := squirrel.Select(columns...).
+		From("pvz_service.pvz p").
+		Where(filter).
+		OrderBy("p.registration_date DESC", "p.pvz_id DESC").
+		Limit(params.Limit + 1).
+		PlaceholderFormat(squirrel.Dollar)
+
+	if params.Cursor != nil {
+		pageQuery = pageQuery.Where("(p.registration_date, p.pvz_id) < (?, ?)",
+			params.Cursor.RegistrationDate, params.Cursor.ID)
+	} else {
+		pageQuery = pageQuery.Offset(params.Offset())
+	}
+
+	query, args, err := pageQuery.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.querier(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pvzs: %w", err)
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			log.Fatalf("failed to close rows: %v", err)
+		}
+	}(rows)
+
+	page := &models.PvzPage{Items: []*models.ExtendedPvz{}, TotalCount: totalCount}
+	pvzIndex := make(map[uuid.UUID]*models.ExtendedPvz)
+	for rows.Next() {
+		var pvz models.ExtendedPvz
+		if err := rows.Scan(pvzFields(&pvz.PVZ)...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		pvz.Receptions = []models.ExtendedReception{}
+		page.Items = append(page.Items, &pvz)
+		pvzIndex[*pvz.PVZ.Id] = &pvz
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read pvzs: %w", err)
+	}
+
+	if uint64(len(page.Items)) > params.Limit {
+		last := page.Items[params.Limit-1]
+		delete(pvzIndex, *page.Items[params.Limit].PVZ.Id)
+		page.Items = page.Items[:params.Limit]
+		page.NextCursor = models.NewPvzCursor(last.PVZ)
+	}
+
+	if params.SkipReceptions {
+		return page, nil
+	}
+	if err := r.fillReceptions(ctx, params, pvzIndex); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// pvzListFilter selects the PVZs that take part in the listing.
+func pvzListFilter(params models.PvzListParams) squirrel.Sqlizer {
+	filter := squirrel.And{}
+	if params.PvzIds != nil {
+		filter = append(filter, squirrel.Eq{"p.pvz_id": params.PvzIds})
+	}
+	if len(params.Cities) > 0 {
+		filter = append(filter, squirrel.Eq{"p.city": params.Cities})
+	}
+	if params.HasOpenReception {
+		filter = append(filter, exists(squirrel.Select("1").
+			From("pvz_service.reception ro").
+			Where("ro.pvz_id = p.pvz_id").
+			Where(squirrel.Eq{"ro.status": dto.InProgress})))
+	}
+	if params.FiltersReceptions() {
+		filter = append(filter, exists(squirrel.Select("1").
+			From("pvz_service.reception r").
+			Where("r.pvz_id = p.pvz_id").
+			Where(receptionConditions(params.PvzFilter))))
+	}
+	return filter
+}
+
+// receptionConditions matches rows of pvz_service.reception aliased as r.
+func receptionConditions(filter models.PvzFilter) squirrel.And {
+	conditions := squirrel.And{}
+	if filter.StartDate != nil {
+		conditions = append(conditions, squirrel.GtOrEq{"r.started_at": *filter.StartDate})
+	}
+	if filter.EndDate != nil {
+		conditions = append(conditions, squirrel.LtOrEq{"r.started_at": *filter.EndDate})
+	}
+	if filter.ReceptionStatus != nil {
+		conditions = append(conditions, squirrel.Eq{"r.status": *filter.ReceptionStatus})
+	}
+	if filter.FiltersProducts() {
+		conditions = append(conditions, exists(squirrel.Select("1").
+			From("pvz_service.product pr").
+			Where("pr.reception_id = r.reception_id").
+			Where(productConditions(filter))))
+	}
+	return conditions
+}
+
+// productConditions matches rows of pvz_service.product aliased as pr.
+func productConditions(filter models.PvzFilter) squirrel.And {
+	conditions := squirrel.And{}
+	if filter.ProductType != nil {
+		conditions = append(conditions, squirrel.Eq{"pr.product_type": *filter.ProductType})
+	}
+	if filter.ProductStartDate != nil {
+		conditions = append(conditions, squirrel.GtOrEq{"pr.added_at": *filter.ProductStartDate})
+	}
+	if filter.ProductEndDate != nil {
+		conditions = append(conditions, squirrel.LtOrEq{"pr.added_at": *filter.ProductEndDate})
+	}
+	return conditions
+}
+
+func exists(subQuery squirrel.SelectBuilder) squirrel.Sqlizer {
+	return squirrel.Expr("exists (?)", subQuery)
+}
+
+func (r *PvzRepository) countPvz(ctx context.Context, filter squirrel.Sqlizer) (uint64, error) {
+	query, args, err := squirrel.Select("count(*)").
+		From("pvz_service.pvz p").
+		Where(filter).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var count uint64
+	if err := r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count pvzs: %w", err)
+	}
+	return count, nil
+}
+
+// fillReceptions loads the receptions and products of every PVZ on the page.
+func (r *PvzRepository) fillReceptions(ctx context.Context, params models.PvzListParams, pvzIndex map[uuid.UUID]*models.ExtendedPvz) error {
+	if len(pvzIndex) == 0 {
+		return nil
+	}
+
+	pvzIDs := make([]uuid.UUID, 0, len(pvzIndex))
+	for id := range pvzIndex {
+		pvzIDs = append(pvzIDs, id)
+	}
+
+	productJoin, productArgs, err := productConditions(params.PvzFilter).ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
 	baseQuery := squirrel.Select(
-		"p.pvz_id",
-		"p.city",
-		"p.registration_date",
+		"r.pvz_id",
 		"r.reception_id",
 		"r.started_at",
 		"r.status",
+		"r.created_by",
 		"pr.product_id",
 		"pr.added_at",
 		"pr.product_type",
+		"pr.created_by",
 	).
-		From("pvz_service.pvz p").
-		LeftJoin("pvz_service.reception r ON p.pvz_id = r.pvz_id").
-		LeftJoin("pvz_service.product pr ON r.reception_id = pr.reception_id").
+		From("pvz_service.reception r").
+		LeftJoin("pvz_service.product pr ON r.reception_id = pr.reception_id AND "+productJoin, productArgs...).
+		Where(squirrel.Eq{"r.pvz_id": pvzIDs}).
+		Where(receptionConditions(params.PvzFilter)).
+		OrderBy("r.started_at", "r.reception_id", "pr.added_at", "pr.product_id").
 		PlaceholderFormat(squirrel.Dollar)
 
-	if startTime != nil && endTime != nil {
-		baseQuery = baseQuery.Where("r.started_at BETWEEN ? AND ?", *startTime, *endTime)
-	}
-
-	subQuery := baseQuery.
-		GroupBy(
-			"p.pvz_id",
-			"r.reception_id",
-			"r.started_at",
-			"r.status",
-			"pr.product_id",
-			"pr.added_at",
-			"pr.product_type",
-		).
-		OrderBy("p.registration_date DESC").
-		Limit(limit).
-		Offset((page - 1) * limit)
-
-	query, args, err := subQuery.ToSql()
+	query, args, err := baseQuery.ToSql()
 	if err != nil {
-		return nil, fmt.Errorf("failed to build query: %w", err)
+		return fmt.Errorf("failed to build query: %w", err)
 	}
 
-	rows, err := r.tx.QueryContext(ctx, query, args...)
+	rows, err := r.querier(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query pvzs: %w", err)
+		return fmt.Errorf("failed to query receptions: %w", err)
 	}
 	defer func(rows *sql.Rows) {
 		err := rows.Close()
@@ -127,78 +412,62 @@ func (r *PvzRepository) GetPvzList(ctx context.Context, startTime *time.Time, en
 		}
 	}(rows)
 
-	pvzMap := make(map[uuid.UUID]*models.ExtendedPvz)
-	receptionMap := make(map[uuid.UUID]*models.ExtendedReception)
-
+	receptionIndex := make(map[uuid.UUID]int)
 	for rows.Next() {
 		var (
-			pvzID          openapi_types.UUID
-			city           dto.PVZCity
-			regDate        time.Time
-			receptionID    *uuid.UUID
-			startedAt      *time.Time
-			status         *dto.ReceptionStatus
-			productID      *uuid.UUID
-			productAddedAt *time.Time
-			productType    *dto.ProductType
+			pvzID            openapi_types.UUID
+			receptionID      uuid.UUID
+			startedAt        time.Time
+			status           dto.ReceptionStatus
+			createdBy        *uuid.UUID
+			productID        *uuid.UUID
+			productAddedAt   *time.Time
+			productType      *string
+			productCreatedBy *uuid.UUID
 		)
 
 		err := rows.Scan(
 			&pvzID,
-			&city,
-			&regDate,
 			&receptionID,
 			&startedAt,
 			&status,
+			&createdBy,
 			&productID,
 			&productAddedAt,
 			&productType,
+			&productCreatedBy,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan row: %w", err)
+			return fmt.Errorf("failed to scan row: %w", err)
 		}
 
-		if _, exists := pvzMap[pvzID]; !exists {
-			pvzMap[pvzID] = &models.ExtendedPvz{
-				PVZ: dto.PVZ{
-					Id:               &pvzID,
-					City:             city,
-					RegistrationDate: &regDate,
+		pvz := pvzIndex[pvzID]
+		i, exists := receptionIndex[receptionID]
+		if !exists {
+			pvz.Receptions = append(pvz.Receptions, models.ExtendedReception{
+				Reception: dto.Reception{
+					Id:        &receptionID,
+					PvzId:     pvzID,
+					DateTime:  startedAt,
+					Status:    status,
+					CreatedBy: createdBy,
 				},
-				Receptions: []models.ExtendedReception{},
-			}
-		}
-		pvz := pvzMap[pvzID]
-
-		if receptionID != nil {
-			if _, exists := receptionMap[*receptionID]; !exists {
-				receptionMap[*receptionID] = &models.ExtendedReception{
-					Reception: dto.Reception{
-						Id:       receptionID,
-						DateTime: *startedAt,
-						Status:   *status,
-					},
-					Products: []dto.Product{},
-				}
-				pvz.Receptions = append(pvz.Receptions, *receptionMap[*receptionID])
-			}
-			reception := receptionMap[*receptionID]
-
-			if productID != nil {
-				reception.Products = append(reception.Products, dto.Product{
-					Id:       productID,
-					DateTime: productAddedAt,
-					Type:     *productType,
-				})
-			}
+				Products: []dto.Product{},
+			})
+			i = len(pvz.Receptions) - 1
+			receptionIndex[receptionID] = i
 		}
-	}
 
-	result := make([]*models.ExtendedPvz, 0, len(pvzMap))
-	for _, pvz := range pvzMap {
-		log.Println(pvz.PVZ.City)
-		result = append(result, pvz)
+		if productID != nil {
+			pvz.Receptions[i].Products = append(pvz.Receptions[i].Products, dto.Product{
+				Id:          productID,
+				ReceptionId: receptionID,
+				DateTime:    productAddedAt,
+				Type:        *productType,
+				CreatedBy:   productCreatedBy,
+			})
+		}
 	}
 
-	return result, nil
+	return rows.Err()
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
 )
 
 type PvzRepositoryTestSuite struct {
@@ -20,6 +21,7 @@ type PvzRepositoryTestSuite struct {
 	db      *sql.DB
 	cleanup func()
 	repo    *PvzRepository
+	tx      *sql.Tx
 	ctx     context.Context
 	pvzID   uuid.UUID
 }
@@ -31,6 +33,9 @@ func TestPvzRepositorySuite(t *testing.T) {
 func (s *PvzRepositoryTestSuite) SetupSuite() {
 	s.ctx = context.Background()
 	db := DBTestSetup()
+	if db == nil {
+		s.T().Skip("test database is not configured")
+	}
 	log.Println("migrations applied")
 	s.db = db
 
@@ -38,10 +43,11 @@ func (s *PvzRepositoryTestSuite) SetupSuite() {
 	require.NoError(s.T(), err)
 
 	s.repo = NewPvzRepository(s.db)
-	s.repo.tx = tx
+	s.tx = tx
+	s.ctx = withTx(context.Background(), tx)
 
 	pvzID := uuid.New()
-	_, err = s.repo.tx.ExecContext(s.ctx, `
+	_, err = s.tx.ExecContext(s.ctx, `
         insert into pvz_service.pvz (pvz_id, registration_date, city)
         values ($1, current_date, 'Москва')`, pvzID)
 	require.NoError(s.T(), err)
@@ -61,12 +67,13 @@ func (s *PvzRepositoryTestSuite) TearDownSuite() {
 func (s *PvzRepositoryTestSuite) SetupTest() {
 	tx, err := s.db.BeginTx(s.ctx, nil)
 	require.NoError(s.T(), err)
-	s.repo.tx = tx
+	s.tx = tx
+	s.ctx = withTx(context.Background(), tx)
 }
 
 func (s *PvzRepositoryTestSuite) TearDownTest() {
-	if s.repo.tx != nil {
-		err := s.repo.tx.Rollback()
+	if s.tx != nil {
+		err := s.tx.Rollback()
 		require.NoError(s.T(), err)
 	}
 }
@@ -91,7 +98,7 @@ func (s *PvzRepositoryTestSuite) TestCreatePvz() {
 				assert.False(t, pvz.RegistrationDate.IsZero())
 
 				var count int
-				err := s.repo.tx.QueryRowContext(
+				err := s.tx.QueryRowContext(
 					s.ctx,
 					"select count(*) from pvz_service.pvz where pvz_id = $1",
 					pvz.Id,
@@ -118,6 +125,48 @@ func (s *PvzRepositoryTestSuite) TestCreatePvz() {
 	}
 }
 
+func (s *PvzRepositoryTestSuite) TestPvzProfile() {
+	name := "ПВЗ на Тверской"
+	latitude, longitude := 55.7558, 37.6173
+	pvz := &dto.PVZ{City: "Москва", Name: &name, Latitude: &latitude, Longitude: &longitude}
+	require.NoError(s.T(), s.repo.CreatePvz(s.ctx, pvz))
+
+	s.Run("get by id", func() {
+		found, err := s.repo.GetPvzById(s.ctx, *pvz.Id)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), name, *found.Name)
+		assert.Equal(s.T(), latitude, *found.Latitude)
+		assert.Nil(s.T(), found.Address)
+		assert.Nil(s.T(), found.DecommissionedAt)
+
+		_, err = s.repo.GetPvzById(s.ctx, uuid.New())
+		assert.ErrorIs(s.T(), err, models.ErrPvzNotFound)
+	})
+
+	s.Run("update keeps absent fields", func() {
+		address := "Тверская ул., 1"
+		updated, err := s.repo.UpdatePvz(s.ctx, *pvz.Id, &dto.PVZUpdate{Address: &address})
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), name, *updated.Name)
+		assert.Equal(s.T(), address, *updated.Address)
+
+		_, err = s.repo.UpdatePvz(s.ctx, uuid.New(), &dto.PVZUpdate{Address: &address})
+		assert.ErrorIs(s.T(), err, models.ErrPvzNotFound)
+	})
+
+	s.Run("decommission once", func() {
+		decommissioned, err := s.repo.DecommissionPvz(s.ctx, *pvz.Id)
+		require.NoError(s.T(), err)
+		assert.NotNil(s.T(), decommissioned.DecommissionedAt)
+
+		_, err = s.repo.DecommissionPvz(s.ctx, *pvz.Id)
+		assert.ErrorIs(s.T(), err, models.ErrPvzDecommissioned)
+
+		_, err = s.repo.DecommissionPvz(s.ctx, uuid.New())
+		assert.ErrorIs(s.T(), err, models.ErrPvzNotFound)
+	})
+}
+
 func (s *PvzRepositoryTestSuite) TestGetPvzList() {
 	const timeLayout = "2006-01-02 15:04:05"
 
@@ -127,13 +176,13 @@ func (s *PvzRepositoryTestSuite) TestGetPvzList() {
 	reg1, _ := time.Parse(timeLayout, "2025-01-01 00:00:00")
 	reg2, _ := time.Parse(timeLayout, "2025-01-02 00:00:00")
 
-	_, err := s.repo.tx.ExecContext(s.ctx, `
+	_, err := s.tx.ExecContext(s.ctx, `
 		insert into pvz_service.pvz (pvz_id, registration_date, city)
 		values ($1, $2, $3)
 	`, pvzID1, reg1, "Москва")
 	require.NoError(s.T(), err)
 
-	_, err = s.repo.tx.ExecContext(s.ctx, `
+	_, err = s.tx.ExecContext(s.ctx, `
 		insert into pvz_service.pvz (pvz_id, registration_date, city)
 		values ($1, $2, $3)
 	`, pvzID2, reg2, "Санкт-Петербург")
@@ -157,7 +206,7 @@ func (s *PvzRepositoryTestSuite) TestGetPvzList() {
 		{rec1b, pvzID1, start1b, "close"},
 		{rec2, pvzID2, start2, "in_progress"},
 	} {
-		_, err = s.repo.tx.ExecContext(s.ctx, `
+		_, err = s.tx.ExecContext(s.ctx, `
 			insert into pvz_service.reception (reception_id, pvz_id, started_at, status)
 			values ($1, $2, $3, $4)
 		`, data.id, data.pvz, data.start, data.status)
@@ -178,11 +227,11 @@ func (s *PvzRepositoryTestSuite) TestGetPvzList() {
 		at    time.Time
 		ptype string
 	}{
-		{prod1, rec1a, add1, "TypeA"},
-		{prod2, rec1a, add2, "TypeB"},
-		{prod3, rec1b, add3, "TypeC"},
+		{prod1, rec1a, add1, "электроника"},
+		{prod2, rec1a, add2, "одежда"},
+		{prod3, rec1b, add3, "обувь"},
 	} {
-		_, err = s.repo.tx.ExecContext(s.ctx, `
+		_, err = s.tx.ExecContext(s.ctx, `
 			insert into pvz_service.product (product_id, reception_id, added_at, product_type)
 			values ($1, $2, $3, $4)
 		`, data.id, data.rec, data.at, data.ptype)
@@ -192,19 +241,130 @@ func (s *PvzRepositoryTestSuite) TestGetPvzList() {
 	startF, _ := time.Parse(timeLayout, "2025-01-01 00:00:00")
 	endF, _ := time.Parse(timeLayout, "2025-01-02 12:00:00")
 	s.Run("time filter only pvz1", func() {
-		list, err := s.repo.GetPvzList(s.ctx, &startF, &endF, 1, 10)
+		page, err := s.repo.GetPvzList(s.ctx, models.PvzListParams{PvzFilter: models.PvzFilter{StartDate: &startF, EndDate: &endF}, Page: 1, Limit: 10})
 		require.NoError(s.T(), err)
 
+		list := page.Items
 		require.Len(s.T(), list, 1)
 		assert.Equal(s.T(), pvzID1, *list[0].PVZ.Id)
-		assert.Len(s.T(), list[0].Receptions, 2)
+		require.Len(s.T(), list[0].Receptions, 2)
+		assert.Len(s.T(), list[0].Receptions[0].Products, 2)
+		assert.Len(s.T(), list[0].Receptions[1].Products, 1)
+		assert.Equal(s.T(), uint64(1), page.TotalCount)
+		assert.Nil(s.T(), page.NextCursor)
+	})
+
+	s.Run("city filter", func() {
+		page, err := s.repo.GetPvzList(s.ctx, models.PvzListParams{
+			PvzFilter: models.PvzFilter{Cities: []string{"Санкт-Петербург"}},
+			Page:      1,
+			Limit:     10,
+		})
+		require.NoError(s.T(), err)
+
+		require.Len(s.T(), page.Items, 1)
+		assert.Equal(s.T(), pvzID2, *page.Items[0].PVZ.Id)
+	})
+
+	s.Run("pvz ids", func() {
+		page, err := s.repo.GetPvzList(s.ctx, models.PvzListParams{
+			PvzFilter: models.PvzFilter{PvzIds: []uuid.UUID{pvzID2}},
+			Page:      1,
+			Limit:     10,
+		})
+		require.NoError(s.T(), err)
+		require.Len(s.T(), page.Items, 1)
+		assert.Equal(s.T(), pvzID2, *page.Items[0].PVZ.Id)
+
+		page, err = s.repo.GetPvzList(s.ctx, models.PvzListParams{
+			PvzFilter: models.PvzFilter{PvzIds: []uuid.UUID{}},
+			Page:      1,
+			Limit:     10,
+		})
+		require.NoError(s.T(), err)
+		assert.Empty(s.T(), page.Items)
+	})
+
+	s.Run("reception status keeps only matching receptions", func() {
+		status := dto.Close
+		page, err := s.repo.GetPvzList(s.ctx, models.PvzListParams{
+			PvzFilter: models.PvzFilter{ReceptionStatus: &status},
+			Page:      1,
+			Limit:     10,
+		})
+		require.NoError(s.T(), err)
+
+		require.Len(s.T(), page.Items, 1)
+		assert.Equal(s.T(), pvzID1, *page.Items[0].PVZ.Id)
+		require.Len(s.T(), page.Items[0].Receptions, 1)
+		assert.Equal(s.T(), rec1b, *page.Items[0].Receptions[0].Reception.Id)
+	})
+
+	s.Run("product type and open-ended added range", func() {
+		productType := "одежда"
+		page, err := s.repo.GetPvzList(s.ctx, models.PvzListParams{
+			PvzFilter: models.PvzFilter{ProductType: &productType, ProductStartDate: &add1},
+			Page:      1,
+			Limit:     10,
+		})
+		require.NoError(s.T(), err)
+
+		require.Len(s.T(), page.Items, 1)
+		require.Len(s.T(), page.Items[0].Receptions, 1)
+		require.Len(s.T(), page.Items[0].Receptions[0].Products, 1)
+		assert.Equal(s.T(), prod2, *page.Items[0].Receptions[0].Products[0].Id)
+		assert.Equal(s.T(), uint64(1), page.TotalCount)
+	})
+
+	s.Run("has open reception", func() {
+		page, err := s.repo.GetPvzList(s.ctx, models.PvzListParams{
+			PvzFilter: models.PvzFilter{HasOpenReception: true, Cities: []string{"Москва"}},
+			Page:      1,
+			Limit:     10,
+		})
+		require.NoError(s.T(), err)
+
+		require.Len(s.T(), page.Items, 1)
+		assert.Equal(s.T(), pvzID1, *page.Items[0].PVZ.Id)
+		assert.Len(s.T(), page.Items[0].Receptions, 2)
 	})
 
 	s.Run("pagination page2 limit1 gives second pvz", func() {
-		list, err := s.repo.GetPvzList(s.ctx, nil, nil, 2, 1)
+		page, err := s.repo.GetPvzList(s.ctx, models.PvzListParams{Page: 2, Limit: 1})
 		require.NoError(s.T(), err)
 
+		list := page.Items
 		require.Len(s.T(), list, 1)
 		assert.Equal(s.T(), pvzID1, *list[0].PVZ.Id)
+		assert.Len(s.T(), list[0].Receptions, 2)
+	})
+
+	s.Run("cursor walks pvzs without gaps", func() {
+		first, err := s.repo.GetPvzList(s.ctx, models.PvzListParams{Page: 1, Limit: 1})
+		require.NoError(s.T(), err)
+		require.Len(s.T(), first.Items, 1)
+		require.NotNil(s.T(), first.NextCursor)
+		assert.Equal(s.T(), pvzID2, *first.Items[0].PVZ.Id)
+
+		second, err := s.repo.GetPvzList(s.ctx, models.PvzListParams{Limit: 1, Cursor: first.NextCursor})
+		require.NoError(s.T(), err)
+		require.NotEmpty(s.T(), second.Items)
+		assert.Equal(s.T(), pvzID1, *second.Items[0].PVZ.Id)
+		assert.Equal(s.T(), first.TotalCount, second.TotalCount)
+	})
+
+	s.Run("skip receptions and count", func() {
+		page, err := s.repo.GetPvzList(s.ctx, models.PvzListParams{
+			PvzFilter:      models.PvzFilter{HasOpenReception: true, Cities: []string{"Москва"}},
+			Limit:          10,
+			SkipReceptions: true,
+			SkipCount:      true,
+		})
+		require.NoError(s.T(), err)
+
+		require.Len(s.T(), page.Items, 1)
+		assert.Equal(s.T(), pvzID1, *page.Items[0].PVZ.Id)
+		assert.Empty(s.T(), page.Items[0].Receptions)
+		assert.Zero(s.T(), page.TotalCount)
 	})
 }
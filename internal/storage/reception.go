@@ -23,8 +23,8 @@ func NewReceptionRepository(db *sql.DB) *ReceptionRepository {
 
 func (r *ReceptionRepository) AddReception(ctx context.Context, reception *dto.Reception) error {
 	query, args, err := squirrel.Insert("pvz_service.reception").
-		Columns("pvz_id").
-		Values(reception.PvzId).
+		Columns("pvz_id", "created_by").
+		Values(reception.PvzId, reception.CreatedBy).
 		Suffix("returning reception_id, started_at, status").
 		PlaceholderFormat(squirrel.Dollar).
 		ToSql()
@@ -35,13 +35,16 @@ func (r *ReceptionRepository) AddReception(ctx context.Context, reception *dto.R
 		return err
 	}
 
-	err = r.tx.QueryRowContext(ctx, query, args...).Scan(&reception.Id, &reception.DateTime, &reception.Status)
+	err = r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(&reception.Id, &reception.DateTime, &reception.Status)
+	if isConstraintViolation(err, uniqueViolation, openReceptionConstraint) {
+		return models.ErrReceptionNotClosed
+	}
 
 	return err
 }
 
 func (r *ReceptionRepository) GetLastReceptionByPvzId(ctx context.Context, pvzId openapi_types.UUID) (*dto.Reception, error) {
-	query, args, err := squirrel.Select("reception_id", "started_at", "status", "pvz_id").
+	query, args, err := squirrel.Select("reception_id", "started_at", "status", "pvz_id", "created_by").
 		From("pvz_service.reception").
 		Where("pvz_id = $1", pvzId).
 		OrderBy("started_at DESC").
@@ -55,7 +58,7 @@ func (r *ReceptionRepository) GetLastReceptionByPvzId(ctx context.Context, pvzId
 
 	reception := &dto.Reception{}
 
-	err = r.tx.QueryRowContext(ctx, query, args...).Scan(&reception.Id, &reception.DateTime, &reception.Status, &reception.PvzId)
+	err = r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(&reception.Id, &reception.DateTime, &reception.Status, &reception.PvzId, &reception.CreatedBy)
 	switch {
 	case errors.Is(err, sql.ErrNoRows):
 		return nil, models.ErrReceptionNotFound
@@ -70,7 +73,56 @@ func (r *ReceptionRepository) CloseLastReception(ctx context.Context, receptionI
 	query, args, err := squirrel.Update("pvz_service.reception").
 		Set("status", string(dto.Close)).
 		Where("reception_id = $2", receptionId).
-		Suffix("returning reception_id, started_at, status, pvz_id").
+		Suffix("returning reception_id, started_at, status, pvz_id, created_by").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	reception := &dto.Reception{}
+
+	err = r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(&reception.Id, &reception.DateTime, &reception.Status, &reception.PvzId, &reception.CreatedBy)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, models.ErrReceptionNotFound
+	case err != nil:
+		return nil, err
+	default:
+		return reception, nil
+	}
+}
+
+func (r *ReceptionRepository) GetReceptionById(ctx context.Context, receptionId openapi_types.UUID) (*dto.Reception, error) {
+	query, args, err := squirrel.Select("reception_id", "started_at", "status", "pvz_id", "created_by").
+		From("pvz_service.reception").
+		Where(squirrel.Eq{"reception_id": receptionId}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	reception := &dto.Reception{}
+
+	err = r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(&reception.Id, &reception.DateTime, &reception.Status, &reception.PvzId, &reception.CreatedBy)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, models.ErrReceptionNotFound
+	case err != nil:
+		return nil, err
+	default:
+		return reception, nil
+	}
+}
+
+func (r *ReceptionRepository) UpdateReceptionStatus(ctx context.Context, receptionId openapi_types.UUID, status dto.ReceptionStatus) (*dto.Reception, error) {
+	query, args, err := squirrel.Update("pvz_service.reception").
+		Set("status", string(status)).
+		Where(squirrel.Eq{"reception_id": receptionId}).
+		Suffix("returning reception_id, started_at, status, pvz_id, created_by").
 		PlaceholderFormat(squirrel.Dollar).
 		ToSql()
 
@@ -80,8 +132,10 @@ func (r *ReceptionRepository) CloseLastReception(ctx context.Context, receptionI
 
 	reception := &dto.Reception{}
 
-	err = r.tx.QueryRowContext(ctx, query, args...).Scan(&reception.Id, &reception.DateTime, &reception.Status, &reception.PvzId)
+	err = r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(&reception.Id, &reception.DateTime, &reception.Status, &reception.PvzId, &reception.CreatedBy)
 	switch {
+	case isConstraintViolation(err, uniqueViolation, openReceptionConstraint):
+		return nil, models.ErrReceptionNotClosed
 	case errors.Is(err, sql.ErrNoRows):
 		return nil, models.ErrReceptionNotFound
 	case err != nil:
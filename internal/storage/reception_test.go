@@ -3,10 +3,14 @@ package storage
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"log"
+	"sync"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -20,6 +24,7 @@ type ReceptionRepositoryTestSuite struct {
 	db      *sql.DB
 	cleanup func()
 	repo    *ReceptionRepository
+	tx      *sql.Tx
 	ctx     context.Context
 	pvzID   uuid.UUID
 }
@@ -31,6 +36,9 @@ func TestReceptionRepositorySuite(t *testing.T) {
 func (s *ReceptionRepositoryTestSuite) SetupSuite() {
 	s.ctx = context.Background()
 	db := DBTestSetup()
+	if db == nil {
+		s.T().Skip("test database is not configured")
+	}
 	log.Println("migrations applied")
 	s.db = db
 
@@ -51,11 +59,12 @@ func (s *ReceptionRepositoryTestSuite) TearDownSuite() {
 func (s *ReceptionRepositoryTestSuite) SetupTest() {
 	tx, err := s.db.BeginTx(s.ctx, nil)
 	require.NoError(s.T(), err)
-	s.repo.tx = tx
+	s.tx = tx
+	s.ctx = withTx(context.Background(), tx)
 
 	// Создание PVZ для каждого теста
 	pvzID := uuid.New()
-	_, err = s.repo.tx.ExecContext(s.ctx, `
+	_, err = s.tx.ExecContext(s.ctx, `
         insert into pvz_service.pvz (pvz_id, registration_date, city)
         values ($1, current_date, 'Москва')`, pvzID)
 	require.NoError(s.T(), err)
@@ -65,15 +74,15 @@ func (s *ReceptionRepositoryTestSuite) SetupTest() {
 }
 
 func (s *ReceptionRepositoryTestSuite) TearDownTest() {
-	if s.repo.tx != nil {
-		err := s.repo.tx.Rollback()
+	if s.tx != nil {
+		err := s.tx.Rollback()
 		require.NoError(s.T(), err)
 	}
 }
 
 func (s *ReceptionRepositoryTestSuite) createReception(t *testing.T) uuid.UUID {
 	receptionID := uuid.New()
-	_, err := s.repo.tx.ExecContext(s.ctx, `
+	_, err := s.tx.ExecContext(s.ctx, `
 		insert into pvz_service.reception (reception_id, started_at, pvz_id, status)
 		values ($1, current_timestamp, $2, 'in_progress')`,
 		receptionID, s.pvzID,
@@ -102,7 +111,7 @@ func (s *ReceptionRepositoryTestSuite) TestAddReception() {
 				assert.False(t, r.DateTime.IsZero())
 
 				var count int
-				err := s.repo.tx.QueryRowContext(
+				err := s.tx.QueryRowContext(
 					s.ctx,
 					"select count(*) from pvz_service.reception where reception_id = $1",
 					r.Id,
@@ -129,6 +138,69 @@ func (s *ReceptionRepositoryTestSuite) TestAddReception() {
 	}
 }
 
+func (s *ReceptionRepositoryTestSuite) TestAddReceptionWhileOpen() {
+	s.createReception(s.T())
+
+	err := s.repo.AddReception(s.ctx, &dto.Reception{PvzId: s.pvzID})
+	require.Error(s.T(), err)
+	assert.Equal(s.T(), models.ErrReceptionNotClosed, err)
+}
+
+func (s *ReceptionRepositoryTestSuite) TestAddReceptionConcurrent() {
+	const workers = 20
+
+	pvzID := uuid.New()
+	_, err := s.db.ExecContext(context.Background(), `
+		insert into pvz_service.pvz (pvz_id, registration_date, city)
+		values ($1, current_date, 'Москва')`, pvzID)
+	require.NoError(s.T(), err)
+	defer func() {
+		_, err := s.db.ExecContext(context.Background(), "delete from pvz_service.reception where pvz_id = $1", pvzID)
+		require.NoError(s.T(), err)
+		_, err = s.db.ExecContext(context.Background(), "delete from pvz_service.pvz where pvz_id = $1", pvzID)
+		require.NoError(s.T(), err)
+	}()
+
+	txManager := NewTxManager(s.db)
+	errs := make(chan error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- txManager.Do(context.Background(), func(ctx context.Context) error {
+				last, err := s.repo.GetLastReceptionByPvzId(ctx, pvzID)
+				if err != nil && !errors.Is(err, models.ErrReceptionNotFound) {
+					return err
+				}
+				if last != nil && last.Status != dto.Close {
+					return models.ErrReceptionNotClosed
+				}
+				return s.repo.AddReception(ctx, &dto.Reception{PvzId: pvzID})
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	var created int
+	for err := range errs {
+		if err == nil {
+			created++
+			continue
+		}
+		assert.ErrorIs(s.T(), err, models.ErrReceptionNotClosed)
+	}
+	assert.Equal(s.T(), 1, created)
+
+	var open int
+	err = s.db.QueryRowContext(context.Background(),
+		"select count(*) from pvz_service.reception where pvz_id = $1 and status = 'in_progress'", pvzID,
+	).Scan(&open)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, open)
+}
+
 func (s *ReceptionRepositoryTestSuite) TestGetLastReception() {
 
 	receptionId := s.createReception(s.T())
@@ -138,9 +210,9 @@ func (s *ReceptionRepositoryTestSuite) TestGetLastReception() {
 	}
 	for i := 0; i < 3; i++ {
 		receptionId := uuid.New()
-		_, err := s.repo.tx.ExecContext(s.ctx, `
+		_, err := s.tx.ExecContext(s.ctx, `
 		insert into pvz_service.reception (reception_id, started_at, pvz_id, status)
-		values ($1, current_timestamp + interval '1 second' * $2, $3, 'in_progress')`,
+		values ($1, current_timestamp + interval '1 second' * $2, $3, 'close')`,
 			receptionId, i, s.pvzID)
 		require.NoError(s.T(), err)
 		receptionIds = append(receptionIds, receptionId)
@@ -161,7 +233,7 @@ func (s *ReceptionRepositoryTestSuite) TestGetLastReception() {
 
 func (s *ReceptionRepositoryTestSuite) TestCloseLastReception() {
 	receptionID := uuid.New()
-	_, err := s.repo.tx.ExecContext(s.ctx, `
+	_, err := s.tx.ExecContext(s.ctx, `
 insert into pvz_service.reception (reception_id, started_at, pvz_id, status)
 values ($1, current_timestamp, $2, 'in_progress')`, receptionID, s.pvzID)
 	require.NoError(s.T(), err)
@@ -178,3 +250,56 @@ values ($1, current_timestamp, $2, 'in_progress')`, receptionID, s.pvzID)
 		assert.Equal(t, models.ErrReceptionNotFound, err)
 	})
 }
+
+func (s *ReceptionRepositoryTestSuite) TestUpdateReceptionStatus() {
+	receptionID := s.createReception(s.T())
+
+	s.T().Run("paused reception is still open", func(t *testing.T) {
+		result, err := s.repo.UpdateReceptionStatus(s.ctx, receptionID, dto.Paused)
+		require.NoError(t, err)
+		assert.Equal(t, dto.Paused, result.Status)
+
+		err = s.repo.AddReception(s.ctx, &dto.Reception{PvzId: s.pvzID})
+		assert.Equal(t, models.ErrReceptionNotClosed, err)
+	})
+
+	s.T().Run("get by id", func(t *testing.T) {
+		result, err := s.repo.GetReceptionById(s.ctx, receptionID)
+		require.NoError(t, err)
+		assert.Equal(t, dto.Paused, result.Status)
+
+		_, err = s.repo.GetReceptionById(s.ctx, uuid.New())
+		assert.Equal(t, models.ErrReceptionNotFound, err)
+	})
+
+	s.T().Run("update non-existent reception", func(t *testing.T) {
+		_, err := s.repo.UpdateReceptionStatus(s.ctx, uuid.New(), dto.Cancelled)
+		assert.Equal(t, models.ErrReceptionNotFound, err)
+	})
+}
+
+func TestReceptionRepository_AddReceptionOpenViolation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("INSERT INTO pvz_service.reception").
+		WillReturnError(&pgconn.PgError{Code: uniqueViolation, ConstraintName: openReceptionConstraint})
+
+	err = NewReceptionRepository(db).AddReception(context.Background(), &dto.Reception{PvzId: uuid.New()})
+	assert.ErrorIs(t, err, models.ErrReceptionNotClosed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReceptionRepository_ReopenViolation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("UPDATE pvz_service.reception").
+		WillReturnError(&pgconn.PgError{Code: uniqueViolation, ConstraintName: openReceptionConstraint})
+
+	_, err = NewReceptionRepository(db).UpdateReceptionStatus(context.Background(), uuid.New(), dto.InProgress)
+	assert.ErrorIs(t, err, models.ErrReceptionNotClosed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"database/sql"
+)
+
+// Repositories groups the repositories of one storage backend together with
+// the transaction manager that coordinates them.
+type Repositories struct {
+	TxManager      TransactionManager
+	User           UserRepositoryInterface
+	Pvz            PvzRepositoryInterface
+	Reception      ReceptionRepositoryInterface
+	Product        ProductRepositoryInterface
+	ProductType    ProductTypeRepositoryInterface
+	City           CityRepositoryInterface
+	Assignment     AssignmentRepositoryInterface
+	Token          TokenRepositoryInterface
+	Invite         InviteRepositoryInterface
+	LoginAttempt   LoginAttemptRepositoryInterface
+	ServiceAccount ServiceAccountRepositoryInterface
+	Role           RoleRepositoryInterface
+	TwoFactor      TwoFactorRepositoryInterface
+	Identity       IdentityRepositoryInterface
+}
+
+func NewRepositories(db *sql.DB) *Repositories {
+	return &Repositories{
+		TxManager:      NewTxManager(db),
+		User:           NewUserRepository(db),
+		Pvz:            NewPvzRepository(db),
+		Reception:      NewReceptionRepository(db),
+		Product:        NewProductRepository(db),
+		ProductType:    NewProductTypeRepository(db),
+		City:           NewCityRepository(db),
+		Assignment:     NewAssignmentRepository(db),
+		Token:          NewTokenRepository(db),
+		Invite:         NewInviteRepository(db),
+		LoginAttempt:   NewLoginAttemptRepository(db),
+		ServiceAccount: NewServiceAccountRepository(db),
+		Role:           NewRoleRepository(db),
+		TwoFactor:      NewTwoFactorRepository(db),
+		Identity:       NewIdentityRepository(db),
+	}
+}
@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type RoleRepository struct {
+	*BaseRepository
+}
+
+func NewRoleRepository(db *sql.DB) *RoleRepository {
+	return &RoleRepository{BaseRepository: NewBaseRepository(db)}
+}
+
+// GetRolePermissions returns the permissions of the role ordered by name.
+func (r *RoleRepository) GetRolePermissions(ctx context.Context, role dto.UserRole) ([]models.Permission, error) {
+	rolePermissions, err := r.getRolePermissions(ctx, squirrel.Eq{"role": role})
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := rolePermissions[role]
+	if permissions == nil {
+		permissions = []models.Permission{}
+	}
+	return permissions, nil
+}
+
+// ListRolePermissions returns the permissions of every role that has any.
+func (r *RoleRepository) ListRolePermissions(ctx context.Context) (map[dto.UserRole][]models.Permission, error) {
+	return r.getRolePermissions(ctx, nil)
+}
+
+func (r *RoleRepository) getRolePermissions(ctx context.Context, where squirrel.Sqlizer) (map[dto.UserRole][]models.Permission, error) {
+	builder := squirrel.Select("role", "permission").
+		From("pvz_service.role_permission").
+		OrderBy("role", "permission").
+		PlaceholderFormat(squirrel.Dollar)
+	if where != nil {
+		builder = builder.Where(where)
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.querier(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role permissions: %w", err)
+	}
+	defer rows.Close()
+
+	rolePermissions := map[dto.UserRole][]models.Permission{}
+	for rows.Next() {
+		var (
+			role       dto.UserRole
+			permission models.Permission
+		)
+		if err := rows.Scan(&role, &permission); err != nil {
+			return nil, fmt.Errorf("failed to scan role permission: %w", err)
+		}
+		rolePermissions[role] = append(rolePermissions[role], permission)
+	}
+
+	return rolePermissions, rows.Err()
+}
+
+// SetRolePermissions replaces the permissions of the role; call it inside a
+// transaction so the role is never left half-updated.
+func (r *RoleRepository) SetRolePermissions(ctx context.Context, role dto.UserRole, permissions []models.Permission) error {
+	query, args, err := squirrel.Delete("pvz_service.role_permission").
+		Where(squirrel.Eq{"role": role}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := r.querier(ctx).ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to clear role permissions: %w", err)
+	}
+
+	if len(permissions) == 0 {
+		return nil
+	}
+
+	insert := squirrel.Insert("pvz_service.role_permission").
+		Columns("role", "permission").
+		Suffix("on conflict do nothing").
+		PlaceholderFormat(squirrel.Dollar)
+	for _, permission := range permissions {
+		insert = insert.Values(role, permission)
+	}
+
+	query, args, err = insert.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	_, err = r.querier(ctx).ExecContext(ctx, query, args...)
+	switch {
+	case isConstraintViolation(err, foreignKeyViolation, rolePermissionFKConstraint):
+		return models.ErrUnknownPermission
+	case err != nil:
+		return fmt.Errorf("failed to set role permissions: %w", err)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type RoleRepositoryTestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	cleanup func()
+	repo    *RoleRepository
+	tx      *sql.Tx
+	ctx     context.Context
+}
+
+func TestRoleRepositorySuite(t *testing.T) {
+	suite.Run(t, new(RoleRepositoryTestSuite))
+}
+
+func (s *RoleRepositoryTestSuite) SetupSuite() {
+	s.ctx = context.Background()
+	db := DBTestSetup()
+	if db == nil {
+		s.T().Skip("test database is not configured")
+	}
+	log.Println("migrations applied")
+	s.db = db
+	s.repo = NewRoleRepository(s.db)
+}
+
+func (s *RoleRepositoryTestSuite) TearDownSuite() {
+	err := s.db.Close()
+	if err != nil {
+		log.Fatalf("failed to close database connection: %v", err)
+	}
+	if s.cleanup != nil {
+		s.cleanup()
+	}
+}
+
+func (s *RoleRepositoryTestSuite) SetupTest() {
+	tx, err := s.db.BeginTx(s.ctx, nil)
+	require.NoError(s.T(), err)
+	s.tx = tx
+	s.ctx = withTx(context.Background(), tx)
+}
+
+func (s *RoleRepositoryTestSuite) TearDownTest() {
+	if s.tx != nil {
+		err := s.tx.Rollback()
+		require.NoError(s.T(), err)
+	}
+}
+
+func (s *RoleRepositoryTestSuite) TestDefaultPermissions() {
+	rolePermissions, err := s.repo.ListRolePermissions(s.ctx)
+	require.NoError(s.T(), err)
+	for _, role := range models.Roles {
+		assert.ElementsMatch(s.T(), models.DefaultRolePermissions[role], rolePermissions[role], role)
+	}
+}
+
+func (s *RoleRepositoryTestSuite) TestSetRolePermissions() {
+	permissions := []models.Permission{models.PermPvzRead, models.PermProductCreate}
+	require.NoError(s.T(), s.repo.SetRolePermissions(s.ctx, dto.UserRoleEmployee, permissions))
+
+	found, err := s.repo.GetRolePermissions(s.ctx, dto.UserRoleEmployee)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), []models.Permission{models.PermProductCreate, models.PermPvzRead}, found)
+
+	require.NoError(s.T(), s.repo.SetRolePermissions(s.ctx, dto.UserRoleEmployee, nil))
+	found, err = s.repo.GetRolePermissions(s.ctx, dto.UserRoleEmployee)
+	require.NoError(s.T(), err)
+	assert.Empty(s.T(), found)
+}
+
+func (s *RoleRepositoryTestSuite) TestSetUnknownPermission() {
+	err := s.repo.SetRolePermissions(s.ctx, dto.UserRoleEmployee, []models.Permission{"pvz:burn"})
+	assert.ErrorIs(s.T(), err, models.ErrUnknownPermission)
+}
@@ -0,0 +1,292 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type ServiceAccountRepository struct {
+	*BaseRepository
+}
+
+func NewServiceAccountRepository(db *sql.DB) *ServiceAccountRepository {
+	return &ServiceAccountRepository{BaseRepository: NewBaseRepository(db)}
+}
+
+// CreateServiceAccount stores the account together with its PVZ scope; call
+// it inside a transaction so both are written atomically.
+func (r *ServiceAccountRepository) CreateServiceAccount(ctx context.Context, account *models.ServiceAccount) error {
+	query, args, err := squirrel.Insert("pvz_service.service_account").
+		Columns("name", "role", "created_by").
+		Values(account.Name, account.Role, account.CreatedBy).
+		Suffix("returning service_account_id, created_at").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	err = r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(&account.ID, &account.CreatedAt)
+	switch {
+	case isConstraintViolation(err, uniqueViolation, serviceAccountNameConstraint):
+		return models.ErrServiceAccountExists
+	case err != nil:
+		return fmt.Errorf("failed to create service account: %w", err)
+	}
+
+	if len(account.PvzIds) == 0 {
+		return nil
+	}
+
+	insert := squirrel.Insert("pvz_service.service_account_pvz").
+		Columns("service_account_id", "pvz_id").
+		Suffix("on conflict do nothing").
+		PlaceholderFormat(squirrel.Dollar)
+	for _, pvzId := range account.PvzIds {
+		insert = insert.Values(account.ID, pvzId)
+	}
+
+	query, args, err = insert.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	_, err = r.querier(ctx).ExecContext(ctx, query, args...)
+	switch {
+	case isConstraintViolation(err, foreignKeyViolation, serviceAccountPvzFKConstraint):
+		return models.ErrPvzNotFound
+	case err != nil:
+		return fmt.Errorf("failed to bind service account pvzs: %w", err)
+	}
+
+	account.PvzIds, err = r.getServiceAccountPvzIds(ctx, account.ID)
+	return err
+}
+
+func (r *ServiceAccountRepository) GetServiceAccountById(ctx context.Context, id openapi_types.UUID) (*models.ServiceAccount, error) {
+	accounts, err := r.getServiceAccounts(ctx, squirrel.Eq{"service_account_id": id})
+	if err != nil {
+		return nil, err
+	}
+	if len(accounts) == 0 {
+		return nil, models.ErrServiceAccountNotFound
+	}
+	return accounts[0], nil
+}
+
+// ListServiceAccounts returns every service account ordered by name.
+func (r *ServiceAccountRepository) ListServiceAccounts(ctx context.Context) ([]*models.ServiceAccount, error) {
+	return r.getServiceAccounts(ctx, nil)
+}
+
+func (r *ServiceAccountRepository) getServiceAccounts(ctx context.Context, where squirrel.Sqlizer) ([]*models.ServiceAccount, error) {
+	builder := squirrel.Select("service_account_id", "name", "role", "created_by", "created_at").
+		From("pvz_service.service_account").
+		OrderBy("name").
+		PlaceholderFormat(squirrel.Dollar)
+	if where != nil {
+		builder = builder.Where(where)
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.querier(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service accounts: %w", err)
+	}
+	defer rows.Close()
+
+	accounts := []*models.ServiceAccount{}
+	for rows.Next() {
+		var account models.ServiceAccount
+		if err := rows.Scan(&account.ID, &account.Name, &account.Role, &account.CreatedBy, &account.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan service account: %w", err)
+		}
+		accounts = append(accounts, &account)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get service accounts: %w", err)
+	}
+
+	for _, account := range accounts {
+		account.PvzIds, err = r.getServiceAccountPvzIds(ctx, account.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return accounts, nil
+}
+
+func (r *ServiceAccountRepository) getServiceAccountPvzIds(ctx context.Context, accountId openapi_types.UUID) ([]openapi_types.UUID, error) {
+	query, args, err := squirrel.Select("pvz_id").
+		From("pvz_service.service_account_pvz").
+		Where(squirrel.Eq{"service_account_id": accountId}).
+		OrderBy("pvz_id").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.querier(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service account pvzs: %w", err)
+	}
+	defer rows.Close()
+
+	pvzIds := []openapi_types.UUID{}
+	for rows.Next() {
+		var pvzId openapi_types.UUID
+		if err := rows.Scan(&pvzId); err != nil {
+			return nil, fmt.Errorf("failed to scan pvz id: %w", err)
+		}
+		pvzIds = append(pvzIds, pvzId)
+	}
+
+	return pvzIds, rows.Err()
+}
+
+func (r *ServiceAccountRepository) CreateAPIKey(ctx context.Context, key *models.APIKey) error {
+	query, args, err := squirrel.Insert("pvz_service.api_key").
+		Columns("service_account_id", "name", "key_hash", "prefix", "created_by", "expires_at").
+		Values(key.ServiceAccountID, key.Name, key.KeyHash, key.Prefix, key.CreatedBy, key.ExpiresAt).
+		Suffix("returning api_key_id, created_at").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	err = r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(&key.ID, &key.CreatedAt)
+	switch {
+	case isConstraintViolation(err, foreignKeyViolation, apiKeyServiceAccountFKConstraint):
+		return models.ErrServiceAccountNotFound
+	case err != nil:
+		return fmt.Errorf("failed to create api key: %w", err)
+	default:
+		return nil
+	}
+}
+
+var apiKeyColumns = []string{"api_key_id", "service_account_id", "name", "key_hash", "prefix", "created_by",
+	"created_at", "expires_at", "last_used_at", "revoked_at"}
+
+// apiKeyFields returns scan destinations matching apiKeyColumns.
+func apiKeyFields(key *models.APIKey) []any {
+	return []any{
+		&key.ID,
+		&key.ServiceAccountID,
+		&key.Name,
+		&key.KeyHash,
+		&key.Prefix,
+		&key.CreatedBy,
+		&key.CreatedAt,
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+	}
+}
+
+func (r *ServiceAccountRepository) GetAPIKeyByHash(ctx context.Context, hash string) (*models.APIKey, error) {
+	query, args, err := squirrel.Select(apiKeyColumns...).
+		From("pvz_service.api_key").
+		Where(squirrel.Eq{"key_hash": hash}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var key models.APIKey
+	err = r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(apiKeyFields(&key)...)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, models.ErrAPIKeyNotFound
+	case err != nil:
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	default:
+		return &key, nil
+	}
+}
+
+// ListAPIKeys returns the keys of the service account, newest first.
+func (r *ServiceAccountRepository) ListAPIKeys(ctx context.Context, accountId openapi_types.UUID) ([]*models.APIKey, error) {
+	query, args, err := squirrel.Select(apiKeyColumns...).
+		From("pvz_service.api_key").
+		Where(squirrel.Eq{"service_account_id": accountId}).
+		OrderBy("created_at desc", "api_key_id desc").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.querier(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []*models.APIKey{}
+	for rows.Next() {
+		var key models.APIKey
+		if err := rows.Scan(apiKeyFields(&key)...); err != nil {
+			return nil, fmt.Errorf("failed to scan api key: %w", err)
+		}
+		keys = append(keys, &key)
+	}
+
+	return keys, rows.Err()
+}
+
+// RevokeAPIKey returns ErrAPIKeyNotFound if the key does not exist or was
+// already revoked.
+func (r *ServiceAccountRepository) RevokeAPIKey(ctx context.Context, id openapi_types.UUID) error {
+	return r.updateAPIKey(ctx, squirrel.Eq{"api_key_id": id, "revoked_at": nil}, "revoked_at", squirrel.Expr("current_timestamp"))
+}
+
+func (r *ServiceAccountRepository) UpdateAPIKeyLastUsed(ctx context.Context, id openapi_types.UUID, at time.Time) error {
+	return r.updateAPIKey(ctx, squirrel.Eq{"api_key_id": id}, "last_used_at", at)
+}
+
+func (r *ServiceAccountRepository) updateAPIKey(ctx context.Context, where squirrel.Eq, column string, value any) error {
+	query, args, err := squirrel.Update("pvz_service.api_key").
+		Set(column, value).
+		Where(where).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := r.querier(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update api key: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update api key: %w", err)
+	}
+	if affected == 0 {
+		return models.ErrAPIKeyNotFound
+	}
+	return nil
+}
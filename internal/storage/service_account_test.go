@@ -0,0 +1,155 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type ServiceAccountRepositoryTestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	cleanup func()
+	repo    *ServiceAccountRepository
+	tx      *sql.Tx
+	ctx     context.Context
+	pvzID   uuid.UUID
+}
+
+func TestServiceAccountRepositorySuite(t *testing.T) {
+	suite.Run(t, new(ServiceAccountRepositoryTestSuite))
+}
+
+func (s *ServiceAccountRepositoryTestSuite) SetupSuite() {
+	s.ctx = context.Background()
+	db := DBTestSetup()
+	if db == nil {
+		s.T().Skip("test database is not configured")
+	}
+	log.Println("migrations applied")
+	s.db = db
+	s.repo = NewServiceAccountRepository(s.db)
+}
+
+func (s *ServiceAccountRepositoryTestSuite) TearDownSuite() {
+	err := s.db.Close()
+	if err != nil {
+		log.Fatalf("failed to close database connection: %v", err)
+	}
+	if s.cleanup != nil {
+		s.cleanup()
+	}
+}
+
+func (s *ServiceAccountRepositoryTestSuite) SetupTest() {
+	tx, err := s.db.BeginTx(s.ctx, nil)
+	require.NoError(s.T(), err)
+	s.tx = tx
+	s.ctx = withTx(context.Background(), tx)
+
+	s.pvzID = uuid.New()
+	_, err = s.tx.ExecContext(s.ctx, `
+		insert into pvz_service.pvz (pvz_id, registration_date, city)
+		values ($1, current_date, 'Москва')`, s.pvzID)
+	require.NoError(s.T(), err)
+}
+
+func (s *ServiceAccountRepositoryTestSuite) TearDownTest() {
+	if s.tx != nil {
+		err := s.tx.Rollback()
+		require.NoError(s.T(), err)
+	}
+}
+
+func (s *ServiceAccountRepositoryTestSuite) TestServiceAccounts() {
+	account := &models.ServiceAccount{
+		Name:   "account-" + uuid.NewString(),
+		Role:   dto.UserRoleEmployee,
+		PvzIds: []uuid.UUID{s.pvzID, s.pvzID},
+	}
+	require.NoError(s.T(), s.repo.CreateServiceAccount(s.ctx, account))
+	assert.NotEqual(s.T(), uuid.Nil, account.ID)
+	assert.Equal(s.T(), []uuid.UUID{s.pvzID}, account.PvzIds)
+
+	found, err := s.repo.GetServiceAccountById(s.ctx, account.ID)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), account.Name, found.Name)
+	assert.Equal(s.T(), dto.UserRoleEmployee, found.Role)
+	assert.Equal(s.T(), []uuid.UUID{s.pvzID}, found.PvzIds)
+
+	accounts, err := s.repo.ListServiceAccounts(s.ctx)
+	require.NoError(s.T(), err)
+	assert.Contains(s.T(), accounts, found)
+
+	_, err = s.repo.GetServiceAccountById(s.ctx, uuid.New())
+	assert.ErrorIs(s.T(), err, models.ErrServiceAccountNotFound)
+
+	err = s.repo.CreateServiceAccount(s.ctx, &models.ServiceAccount{Name: account.Name, Role: dto.UserRoleModerator})
+	assert.ErrorIs(s.T(), err, models.ErrServiceAccountExists)
+}
+
+func (s *ServiceAccountRepositoryTestSuite) TestServiceAccountUnknownPvz() {
+	err := s.repo.CreateServiceAccount(s.ctx, &models.ServiceAccount{
+		Name:   "account-" + uuid.NewString(),
+		Role:   dto.UserRoleEmployee,
+		PvzIds: []uuid.UUID{uuid.New()},
+	})
+	assert.ErrorIs(s.T(), err, models.ErrPvzNotFound)
+}
+
+func (s *ServiceAccountRepositoryTestSuite) TestAPIKeys() {
+	account := &models.ServiceAccount{Name: "account-" + uuid.NewString(), Role: dto.UserRoleModerator}
+	require.NoError(s.T(), s.repo.CreateServiceAccount(s.ctx, account))
+
+	expiresAt := time.Now().UTC().Add(time.Hour).Truncate(time.Microsecond)
+	key := &models.APIKey{
+		ServiceAccountID: account.ID,
+		Name:             "sync",
+		KeyHash:          uuid.NewString(),
+		Prefix:           "pvz_abcdefgh",
+		ExpiresAt:        &expiresAt,
+	}
+	require.NoError(s.T(), s.repo.CreateAPIKey(s.ctx, key))
+
+	found, err := s.repo.GetAPIKeyByHash(s.ctx, key.KeyHash)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), key.ID, found.ID)
+	assert.Equal(s.T(), account.ID, found.ServiceAccountID)
+	assert.True(s.T(), expiresAt.Equal(*found.ExpiresAt))
+	assert.Nil(s.T(), found.LastUsedAt)
+	assert.Nil(s.T(), found.RevokedAt)
+
+	usedAt := time.Now().UTC().Truncate(time.Microsecond)
+	require.NoError(s.T(), s.repo.UpdateAPIKeyLastUsed(s.ctx, key.ID, usedAt))
+	require.NoError(s.T(), s.repo.RevokeAPIKey(s.ctx, key.ID))
+	assert.ErrorIs(s.T(), s.repo.RevokeAPIKey(s.ctx, key.ID), models.ErrAPIKeyNotFound)
+
+	keys, err := s.repo.ListAPIKeys(s.ctx, account.ID)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), keys, 1)
+	assert.True(s.T(), usedAt.Equal(*keys[0].LastUsedAt))
+	assert.NotNil(s.T(), keys[0].RevokedAt)
+
+	_, err = s.repo.GetAPIKeyByHash(s.ctx, uuid.NewString())
+	assert.ErrorIs(s.T(), err, models.ErrAPIKeyNotFound)
+}
+
+func (s *ServiceAccountRepositoryTestSuite) TestAPIKeyUnknownAccount() {
+	err := s.repo.CreateAPIKey(s.ctx, &models.APIKey{
+		ServiceAccountID: uuid.New(),
+		Name:             "sync",
+		KeyHash:          uuid.NewString(),
+		Prefix:           "pvz_abcdefgh",
+	})
+	assert.ErrorIs(s.T(), err, models.ErrServiceAccountNotFound)
+}
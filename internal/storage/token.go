@@ -0,0 +1,230 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type TokenRepository struct {
+	*BaseRepository
+}
+
+func NewTokenRepository(db *sql.DB) *TokenRepository {
+	return &TokenRepository{BaseRepository: NewBaseRepository(db)}
+}
+
+func (r *TokenRepository) CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error {
+	query, args, err := squirrel.Insert("pvz_service.refresh_token").
+		Columns("user_id", "token_hash", "expires_at").
+		Values(token.UserID, token.TokenHash, token.ExpiresAt).
+		Suffix("returning refresh_token_id").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	err = r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(&token.ID)
+	if isConstraintViolation(err, foreignKeyViolation, refreshTokenUserFKConstraint) {
+		return models.ErrUserNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create refresh token: %w", err)
+	}
+	return nil
+}
+
+func (r *TokenRepository) GetRefreshTokenByHash(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	query, args, err := squirrel.Select("refresh_token_id", "user_id", "token_hash", "expires_at", "revoked_at").
+		From("pvz_service.refresh_token").
+		Where(squirrel.Eq{"token_hash": hash}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var token models.RefreshToken
+	err = r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.RevokedAt,
+	)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, models.ErrRefreshTokenNotFound
+	case err != nil:
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	default:
+		return &token, nil
+	}
+}
+
+// RevokeRefreshToken returns ErrRefreshTokenNotFound if the token does not
+// exist or is already revoked.
+func (r *TokenRepository) RevokeRefreshToken(ctx context.Context, id openapi_types.UUID) error {
+	revoked, err := r.revokeRefreshTokens(ctx, squirrel.Eq{"refresh_token_id": id})
+	if err == nil && revoked == 0 {
+		return models.ErrRefreshTokenNotFound
+	}
+	return err
+}
+
+func (r *TokenRepository) RevokeUserRefreshTokens(ctx context.Context, userId openapi_types.UUID) error {
+	_, err := r.revokeRefreshTokens(ctx, squirrel.Eq{"user_id": userId})
+	return err
+}
+
+func (r *TokenRepository) revokeRefreshTokens(ctx context.Context, where squirrel.Eq) (int64, error) {
+	query, args, err := squirrel.Update("pvz_service.refresh_token").
+		Set("revoked_at", squirrel.Expr("current_timestamp")).
+		Where(where).
+		Where(squirrel.Eq{"revoked_at": nil}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := r.querier(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+func (r *TokenRepository) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	query, args, err := squirrel.Insert("pvz_service.revoked_token").
+		Columns("jti", "expires_at").
+		Values(jti, expiresAt).
+		Suffix("on conflict (jti) do nothing").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := r.querier(ctx).ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to revoke access token: %w", err)
+	}
+	return nil
+}
+
+func (r *TokenRepository) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	query, args, err := squirrel.Select("1").
+		Prefix("select exists (").
+		From("pvz_service.revoked_token").
+		Where(squirrel.Eq{"jti": jti}).
+		Suffix(")").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return false, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var revoked bool
+	if err := r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(&revoked); err != nil {
+		return false, fmt.Errorf("failed to check revoked token: %w", err)
+	}
+	return revoked, nil
+}
+
+func (r *TokenRepository) CreatePasswordResetToken(ctx context.Context, token *models.PasswordResetToken) error {
+	query, args, err := squirrel.Insert("pvz_service.password_reset_token").
+		Columns("user_id", "token_hash", "expires_at").
+		Values(token.UserID, token.TokenHash, token.ExpiresAt).
+		Suffix("returning password_reset_token_id").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	err = r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(&token.ID)
+	if isConstraintViolation(err, foreignKeyViolation, resetTokenUserFKConstraint) {
+		return models.ErrUserNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create password reset token: %w", err)
+	}
+	return nil
+}
+
+func (r *TokenRepository) GetPasswordResetTokenByHash(ctx context.Context, hash string) (*models.PasswordResetToken, error) {
+	query, args, err := squirrel.Select("password_reset_token_id", "user_id", "token_hash", "expires_at", "used_at").
+		From("pvz_service.password_reset_token").
+		Where(squirrel.Eq{"token_hash": hash}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var token models.PasswordResetToken
+	err = r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.UsedAt,
+	)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, models.ErrResetTokenNotFound
+	case err != nil:
+		return nil, fmt.Errorf("failed to get password reset token: %w", err)
+	default:
+		return &token, nil
+	}
+}
+
+// UsePasswordResetToken returns ErrResetTokenNotFound if the token does not
+// exist or is already used.
+func (r *TokenRepository) UsePasswordResetToken(ctx context.Context, id openapi_types.UUID) error {
+	used, err := r.useResetTokens(ctx, squirrel.Eq{"password_reset_token_id": id})
+	if err == nil && used == 0 {
+		return models.ErrResetTokenNotFound
+	}
+	return err
+}
+
+func (r *TokenRepository) UseUserPasswordResetTokens(ctx context.Context, userId openapi_types.UUID) error {
+	_, err := r.useResetTokens(ctx, squirrel.Eq{"user_id": userId})
+	return err
+}
+
+func (r *TokenRepository) useResetTokens(ctx context.Context, where squirrel.Eq) (int64, error) {
+	query, args, err := squirrel.Update("pvz_service.password_reset_token").
+		Set("used_at", squirrel.Expr("current_timestamp")).
+		Where(where).
+		Where(squirrel.Eq{"used_at": nil}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := r.querier(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to use password reset tokens: %w", err)
+	}
+	return result.RowsAffected()
+}
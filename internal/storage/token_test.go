@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type TokenRepositoryTestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	cleanup func()
+	repo    *TokenRepository
+	tx      *sql.Tx
+	ctx     context.Context
+	userID  uuid.UUID
+}
+
+func TestTokenRepositorySuite(t *testing.T) {
+	suite.Run(t, new(TokenRepositoryTestSuite))
+}
+
+func (s *TokenRepositoryTestSuite) SetupSuite() {
+	s.ctx = context.Background()
+	db := DBTestSetup()
+	if db == nil {
+		s.T().Skip("test database is not configured")
+	}
+	log.Println("migrations applied")
+	s.db = db
+	s.repo = NewTokenRepository(s.db)
+}
+
+func (s *TokenRepositoryTestSuite) TearDownSuite() {
+	err := s.db.Close()
+	if err != nil {
+		log.Fatalf("failed to close database connection: %v", err)
+	}
+	if s.cleanup != nil {
+		s.cleanup()
+	}
+}
+
+func (s *TokenRepositoryTestSuite) SetupTest() {
+	tx, err := s.db.BeginTx(s.ctx, nil)
+	require.NoError(s.T(), err)
+	s.tx = tx
+	s.ctx = withTx(context.Background(), tx)
+
+	s.userID = uuid.New()
+	_, err = s.tx.ExecContext(s.ctx, `
+		insert into pvz_service.user (user_id, email, password, role)
+		values ($1, $2, 'hash', 'employee')`, s.userID, s.userID.String()+"@example.com")
+	require.NoError(s.T(), err)
+}
+
+func (s *TokenRepositoryTestSuite) TearDownTest() {
+	if s.tx != nil {
+		err := s.tx.Rollback()
+		require.NoError(s.T(), err)
+	}
+}
+
+func (s *TokenRepositoryTestSuite) TestRefreshTokenRotation() {
+	token := &models.RefreshToken{UserID: s.userID, TokenHash: uuid.NewString(), ExpiresAt: time.Now().UTC().Add(time.Hour)}
+	require.NoError(s.T(), s.repo.CreateRefreshToken(s.ctx, token))
+
+	found, err := s.repo.GetRefreshTokenByHash(s.ctx, token.TokenHash)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), token.ID, found.ID)
+	assert.Nil(s.T(), found.RevokedAt)
+
+	require.NoError(s.T(), s.repo.RevokeRefreshToken(s.ctx, token.ID))
+	assert.Equal(s.T(), models.ErrRefreshTokenNotFound, s.repo.RevokeRefreshToken(s.ctx, token.ID))
+
+	found, err = s.repo.GetRefreshTokenByHash(s.ctx, token.TokenHash)
+	require.NoError(s.T(), err)
+	assert.NotNil(s.T(), found.RevokedAt)
+}
+
+func (s *TokenRepositoryTestSuite) TestRefreshTokenUnknownUser() {
+	token := &models.RefreshToken{UserID: uuid.New(), TokenHash: uuid.NewString(), ExpiresAt: time.Now().UTC().Add(time.Hour)}
+	assert.Equal(s.T(), models.ErrUserNotFound, s.repo.CreateRefreshToken(s.ctx, token))
+}
+
+func (s *TokenRepositoryTestSuite) TestRevokeAccessToken() {
+	jti := uuid.NewString()
+
+	revoked, err := s.repo.IsAccessTokenRevoked(s.ctx, jti)
+	require.NoError(s.T(), err)
+	assert.False(s.T(), revoked)
+
+	require.NoError(s.T(), s.repo.RevokeAccessToken(s.ctx, jti, time.Now().UTC().Add(time.Minute)))
+	require.NoError(s.T(), s.repo.RevokeAccessToken(s.ctx, jti, time.Now().UTC().Add(time.Minute)))
+
+	revoked, err = s.repo.IsAccessTokenRevoked(s.ctx, jti)
+	require.NoError(s.T(), err)
+	assert.True(s.T(), revoked)
+}
+
+func (s *TokenRepositoryTestSuite) TestPasswordResetTokens() {
+	first := &models.PasswordResetToken{UserID: s.userID, TokenHash: uuid.NewString(), ExpiresAt: time.Now().UTC().Add(time.Hour)}
+	second := &models.PasswordResetToken{UserID: s.userID, TokenHash: uuid.NewString(), ExpiresAt: time.Now().UTC().Add(time.Hour)}
+	require.NoError(s.T(), s.repo.CreatePasswordResetToken(s.ctx, first))
+	require.NoError(s.T(), s.repo.CreatePasswordResetToken(s.ctx, second))
+
+	found, err := s.repo.GetPasswordResetTokenByHash(s.ctx, first.TokenHash)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), first.ID, found.ID)
+	assert.Nil(s.T(), found.UsedAt)
+
+	_, err = s.repo.GetPasswordResetTokenByHash(s.ctx, uuid.NewString())
+	assert.Equal(s.T(), models.ErrResetTokenNotFound, err)
+
+	require.NoError(s.T(), s.repo.UsePasswordResetToken(s.ctx, first.ID))
+	assert.Equal(s.T(), models.ErrResetTokenNotFound, s.repo.UsePasswordResetToken(s.ctx, first.ID))
+
+	require.NoError(s.T(), s.repo.UseUserPasswordResetTokens(s.ctx, s.userID))
+	found, err = s.repo.GetPasswordResetTokenByHash(s.ctx, second.TokenHash)
+	require.NoError(s.T(), err)
+	assert.NotNil(s.T(), found.UsedAt)
+}
+
+func (s *TokenRepositoryTestSuite) TestPasswordResetTokenUnknownUser() {
+	token := &models.PasswordResetToken{UserID: uuid.New(), TokenHash: uuid.NewString(), ExpiresAt: time.Now().UTC().Add(time.Hour)}
+	assert.Equal(s.T(), models.ErrUserNotFound, s.repo.CreatePasswordResetToken(s.ctx, token))
+}
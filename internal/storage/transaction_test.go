@@ -0,0 +1,199 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxManager(t *testing.T) {
+	t.Run("should commit when fn succeeds", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func(db *sql.DB) {
+			_ = db.Close()
+		}(db)
+
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		err = NewTxManager(db).Do(context.Background(), func(ctx context.Context) error {
+			assert.NotNil(t, txFromContext(ctx))
+			return nil
+		})
+
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should rollback when fn fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func(db *sql.DB) {
+			_ = db.Close()
+		}(db)
+
+		fnErr := errors.New("fn error")
+
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		err = NewTxManager(db).Do(context.Background(), func(ctx context.Context) error {
+			return fnErr
+		})
+
+		assert.ErrorIs(t, err, fnErr)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should return error when begin fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func(db *sql.DB) {
+			_ = db.Close()
+		}(db)
+
+		mock.ExpectBegin().WillReturnError(fmt.Errorf("transaction error"))
+
+		called := false
+		err = NewTxManager(db).Do(context.Background(), func(ctx context.Context) error {
+			called = true
+			return nil
+		})
+
+		assert.Error(t, err)
+		assert.False(t, called)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should return error when commit fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func(db *sql.DB) {
+			_ = db.Close()
+		}(db)
+
+		mock.ExpectBegin()
+		mock.ExpectCommit().WillReturnError(fmt.Errorf("commit error"))
+
+		err = NewTxManager(db).Do(context.Background(), func(ctx context.Context) error {
+			return nil
+		})
+
+		assert.Error(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should join outer transaction", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func(db *sql.DB) {
+			_ = db.Close()
+		}(db)
+
+		manager := NewTxManager(db)
+
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		err = manager.Do(context.Background(), func(outer context.Context) error {
+			return manager.Do(outer, func(inner context.Context) error {
+				assert.Equal(t, txFromContext(outer), txFromContext(inner))
+				return nil
+			})
+		})
+
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should span several repositories", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer func(db *sql.DB) {
+			_ = db.Close()
+		}(db)
+
+		receptionRepo := NewReceptionRepository(db)
+		productRepo := NewProductRepository(db)
+		receptionID := uuid.New()
+		productID := uuid.New()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT reception_id").
+			WillReturnRows(sqlmock.NewRows([]string{"reception_id", "started_at", "status", "pvz_id", "created_by"}).
+				AddRow(receptionID, time.Now(), "in_progress", uuid.New(), nil))
+		mock.ExpectExec("DELETE FROM pvz_service.product").
+			WithArgs(productID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err = NewTxManager(db).Do(context.Background(), func(ctx context.Context) error {
+			if _, err := receptionRepo.GetLastReceptionByPvzId(ctx, uuid.New()); err != nil {
+				return err
+			}
+			return productRepo.DeleteProductById(ctx, productID)
+		})
+
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTxManager_ParallelCallsAreIsolated(t *testing.T) {
+	const workers = 50
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+	mock.MatchExpectationsInOrder(false)
+
+	for i := 0; i < workers; i++ {
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM pvz_service.product").WithArgs(sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+	}
+
+	manager := NewTxManager(db)
+	repo := NewProductRepository(db)
+
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		txs = make(map[*sql.Tx]struct{}, workers)
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := manager.Do(context.Background(), func(ctx context.Context) error {
+				tx := txFromContext(ctx)
+				if repo.querier(ctx) != tx {
+					return errors.New("repository used a foreign transaction")
+				}
+
+				mu.Lock()
+				txs[tx] = struct{}{}
+				mu.Unlock()
+
+				return repo.DeleteProductById(ctx, uuid.New())
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, txs, workers)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
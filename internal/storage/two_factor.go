@@ -0,0 +1,316 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type TwoFactorRepository struct {
+	*BaseRepository
+}
+
+func NewTwoFactorRepository(db *sql.DB) *TwoFactorRepository {
+	return &TwoFactorRepository{BaseRepository: NewBaseRepository(db)}
+}
+
+func (r *TwoFactorRepository) GetTOTP(ctx context.Context, userId openapi_types.UUID) (*models.TOTP, error) {
+	query, args, err := squirrel.Select("user_id", "secret", "confirmed_at", "last_used_step", "created_at").
+		From("pvz_service.user_totp").
+		Where(squirrel.Eq{"user_id": userId}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var totp models.TOTP
+	err = r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(
+		&totp.UserID,
+		&totp.Secret,
+		&totp.ConfirmedAt,
+		&totp.LastUsedStep,
+		&totp.CreatedAt,
+	)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, models.ErrTOTPNotFound
+	case err != nil:
+		return nil, fmt.Errorf("failed to get totp: %w", err)
+	default:
+		return &totp, nil
+	}
+}
+
+// SaveTOTP replaces the secret of the user with a new, unconfirmed one.
+func (r *TwoFactorRepository) SaveTOTP(ctx context.Context, totp *models.TOTP) error {
+	query, args, err := squirrel.Insert("pvz_service.user_totp").
+		Columns("user_id", "secret").
+		Values(totp.UserID, totp.Secret).
+		Suffix(`on conflict (user_id) do update set
+			secret = excluded.secret,
+			confirmed_at = null,
+			last_used_step = 0,
+			created_at = current_timestamp
+			returning created_at`).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	err = r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(&totp.CreatedAt)
+	if isConstraintViolation(err, foreignKeyViolation, userTOTPUserFKConstraint) {
+		return models.ErrUserNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save totp: %w", err)
+	}
+	totp.ConfirmedAt = nil
+	totp.LastUsedStep = 0
+	return nil
+}
+
+func (r *TwoFactorRepository) ConfirmTOTP(ctx context.Context, userId openapi_types.UUID, at time.Time) error {
+	return r.updateTOTP(ctx, squirrel.Update("pvz_service.user_totp").
+		Set("confirmed_at", at).
+		Where(squirrel.Eq{"user_id": userId}))
+}
+
+// UseTOTPStep records that a code of the step was accepted. It returns
+// ErrInvalidTOTPCode if a code of this or a later step was accepted already,
+// which stops a code from being replayed.
+func (r *TwoFactorRepository) UseTOTPStep(ctx context.Context, userId openapi_types.UUID, step int64) error {
+	err := r.updateTOTP(ctx, squirrel.Update("pvz_service.user_totp").
+		Set("last_used_step", step).
+		Where(squirrel.Eq{"user_id": userId}).
+		Where(squirrel.Lt{"last_used_step": step}))
+	if errors.Is(err, models.ErrTOTPNotFound) {
+		return models.ErrInvalidTOTPCode
+	}
+	return err
+}
+
+func (r *TwoFactorRepository) updateTOTP(ctx context.Context, update squirrel.UpdateBuilder) error {
+	query, args, err := update.PlaceholderFormat(squirrel.Dollar).ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := r.querier(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update totp: %w", err)
+	}
+	return requireAffected(result, models.ErrTOTPNotFound)
+}
+
+// DeleteTOTP removes the secret and the recovery codes of the user.
+func (r *TwoFactorRepository) DeleteTOTP(ctx context.Context, userId openapi_types.UUID) error {
+	if err := r.deleteRecoveryCodes(ctx, userId); err != nil {
+		return err
+	}
+
+	query, args, err := squirrel.Delete("pvz_service.user_totp").
+		Where(squirrel.Eq{"user_id": userId}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := r.querier(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to delete totp: %w", err)
+	}
+	return requireAffected(result, models.ErrTOTPNotFound)
+}
+
+// ReplaceRecoveryCodes drops the recovery codes of the user, used or not, and
+// stores the new ones.
+func (r *TwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userId openapi_types.UUID, hashes []string) error {
+	if err := r.deleteRecoveryCodes(ctx, userId); err != nil {
+		return err
+	}
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	insert := squirrel.Insert("pvz_service.recovery_code").
+		Columns("user_id", "code_hash").
+		Suffix("on conflict do nothing").
+		PlaceholderFormat(squirrel.Dollar)
+	for _, hash := range hashes {
+		insert = insert.Values(userId, hash)
+	}
+
+	query, args, err := insert.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	_, err = r.querier(ctx).ExecContext(ctx, query, args...)
+	switch {
+	case isConstraintViolation(err, foreignKeyViolation, recoveryCodeUserFKConstraint):
+		return models.ErrUserNotFound
+	case err != nil:
+		return fmt.Errorf("failed to create recovery codes: %w", err)
+	default:
+		return nil
+	}
+}
+
+func (r *TwoFactorRepository) deleteRecoveryCodes(ctx context.Context, userId openapi_types.UUID) error {
+	query, args, err := squirrel.Delete("pvz_service.recovery_code").
+		Where(squirrel.Eq{"user_id": userId}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	if _, err := r.querier(ctx).ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+	return nil
+}
+
+// UseRecoveryCode returns ErrRecoveryCodeNotFound if the user has no unused
+// code with this hash.
+func (r *TwoFactorRepository) UseRecoveryCode(ctx context.Context, userId openapi_types.UUID, hash string) error {
+	query, args, err := squirrel.Update("pvz_service.recovery_code").
+		Set("used_at", squirrel.Expr("current_timestamp")).
+		Where(squirrel.Eq{"user_id": userId, "code_hash": hash, "used_at": nil}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := r.querier(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to use recovery code: %w", err)
+	}
+	return requireAffected(result, models.ErrRecoveryCodeNotFound)
+}
+
+func (r *TwoFactorRepository) CreateLoginChallenge(ctx context.Context, challenge *models.LoginChallenge) error {
+	query, args, err := squirrel.Insert("pvz_service.login_challenge").
+		Columns("user_id", "token_hash", "expires_at").
+		Values(challenge.UserID, challenge.TokenHash, challenge.ExpiresAt).
+		Suffix("returning login_challenge_id").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	err = r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(&challenge.ID)
+	if isConstraintViolation(err, foreignKeyViolation, loginChallengeUserFKConstraint) {
+		return models.ErrUserNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create login challenge: %w", err)
+	}
+	return nil
+}
+
+func (r *TwoFactorRepository) GetLoginChallengeByHash(ctx context.Context, hash string) (*models.LoginChallenge, error) {
+	query, args, err := squirrel.Select("login_challenge_id", "user_id", "token_hash", "attempts", "expires_at", "used_at").
+		From("pvz_service.login_challenge").
+		Where(squirrel.Eq{"token_hash": hash}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var challenge models.LoginChallenge
+	err = r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(
+		&challenge.ID,
+		&challenge.UserID,
+		&challenge.TokenHash,
+		&challenge.Attempts,
+		&challenge.ExpiresAt,
+		&challenge.UsedAt,
+	)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, models.ErrLoginChallengeNotFound
+	case err != nil:
+		return nil, fmt.Errorf("failed to get login challenge: %w", err)
+	default:
+		return &challenge, nil
+	}
+}
+
+// RecordLoginChallengeAttempt counts a wrong code and returns the attempts
+// made so far.
+func (r *TwoFactorRepository) RecordLoginChallengeAttempt(ctx context.Context, id openapi_types.UUID) (int, error) {
+	query, args, err := squirrel.Update("pvz_service.login_challenge").
+		Set("attempts", squirrel.Expr("attempts + 1")).
+		Where(squirrel.Eq{"login_challenge_id": id}).
+		Suffix("returning attempts").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var attempts int
+	err = r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(&attempts)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return 0, models.ErrLoginChallengeNotFound
+	case err != nil:
+		return 0, fmt.Errorf("failed to record login challenge attempt: %w", err)
+	default:
+		return attempts, nil
+	}
+}
+
+// UseLoginChallenge returns ErrLoginChallengeNotFound if the challenge does
+// not exist or is already used.
+func (r *TwoFactorRepository) UseLoginChallenge(ctx context.Context, id openapi_types.UUID) error {
+	query, args, err := squirrel.Update("pvz_service.login_challenge").
+		Set("used_at", squirrel.Expr("current_timestamp")).
+		Where(squirrel.Eq{"login_challenge_id": id, "used_at": nil}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := r.querier(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to use login challenge: %w", err)
+	}
+	return requireAffected(result, models.ErrLoginChallengeNotFound)
+}
+
+// requireAffected returns notFound if the statement changed no rows.
+func requireAffected(result sql.Result, notFound error) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return notFound
+	}
+	return nil
+}
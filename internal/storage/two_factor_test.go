@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type TwoFactorRepositoryTestSuite struct {
+	suite.Suite
+	db      *sql.DB
+	cleanup func()
+	repo    *TwoFactorRepository
+	tx      *sql.Tx
+	ctx     context.Context
+	userID  uuid.UUID
+}
+
+func TestTwoFactorRepositorySuite(t *testing.T) {
+	suite.Run(t, new(TwoFactorRepositoryTestSuite))
+}
+
+func (s *TwoFactorRepositoryTestSuite) SetupSuite() {
+	s.ctx = context.Background()
+	db := DBTestSetup()
+	if db == nil {
+		s.T().Skip("test database is not configured")
+	}
+	log.Println("migrations applied")
+	s.db = db
+	s.repo = NewTwoFactorRepository(s.db)
+}
+
+func (s *TwoFactorRepositoryTestSuite) TearDownSuite() {
+	err := s.db.Close()
+	if err != nil {
+		log.Fatalf("failed to close database connection: %v", err)
+	}
+	if s.cleanup != nil {
+		s.cleanup()
+	}
+}
+
+func (s *TwoFactorRepositoryTestSuite) SetupTest() {
+	tx, err := s.db.BeginTx(s.ctx, nil)
+	require.NoError(s.T(), err)
+	s.tx = tx
+	s.ctx = withTx(context.Background(), tx)
+
+	s.userID = uuid.New()
+	_, err = s.tx.ExecContext(s.ctx, `
+		insert into pvz_service.user (user_id, email, password, role)
+		values ($1, $2, 'hash', 'moderator')`, s.userID, s.userID.String()+"@example.com")
+	require.NoError(s.T(), err)
+}
+
+func (s *TwoFactorRepositoryTestSuite) TearDownTest() {
+	if s.tx != nil {
+		err := s.tx.Rollback()
+		require.NoError(s.T(), err)
+	}
+}
+
+func (s *TwoFactorRepositoryTestSuite) TestTOTP() {
+	_, err := s.repo.GetTOTP(s.ctx, s.userID)
+	assert.ErrorIs(s.T(), err, models.ErrTOTPNotFound)
+
+	require.NoError(s.T(), s.repo.SaveTOTP(s.ctx, &models.TOTP{UserID: s.userID, Secret: "FIRST"}))
+	totp := &models.TOTP{UserID: s.userID, Secret: "SECOND"}
+	require.NoError(s.T(), s.repo.SaveTOTP(s.ctx, totp))
+
+	found, err := s.repo.GetTOTP(s.ctx, s.userID)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "SECOND", found.Secret)
+	assert.False(s.T(), found.Confirmed())
+
+	confirmedAt := time.Now().UTC().Truncate(time.Microsecond)
+	require.NoError(s.T(), s.repo.ConfirmTOTP(s.ctx, s.userID, confirmedAt))
+	require.NoError(s.T(), s.repo.UseTOTPStep(s.ctx, s.userID, 100))
+	assert.ErrorIs(s.T(), s.repo.UseTOTPStep(s.ctx, s.userID, 100), models.ErrInvalidTOTPCode)
+	assert.ErrorIs(s.T(), s.repo.UseTOTPStep(s.ctx, s.userID, 99), models.ErrInvalidTOTPCode)
+
+	found, err = s.repo.GetTOTP(s.ctx, s.userID)
+	require.NoError(s.T(), err)
+	assert.True(s.T(), confirmedAt.Equal(*found.ConfirmedAt))
+	assert.Equal(s.T(), int64(100), found.LastUsedStep)
+
+	require.NoError(s.T(), s.repo.DeleteTOTP(s.ctx, s.userID))
+	assert.ErrorIs(s.T(), s.repo.DeleteTOTP(s.ctx, s.userID), models.ErrTOTPNotFound)
+	assert.ErrorIs(s.T(), s.repo.ConfirmTOTP(s.ctx, s.userID, confirmedAt), models.ErrTOTPNotFound)
+
+	err = s.repo.SaveTOTP(s.ctx, &models.TOTP{UserID: uuid.New(), Secret: "SECRET"})
+	assert.ErrorIs(s.T(), err, models.ErrUserNotFound)
+}
+
+func (s *TwoFactorRepositoryTestSuite) TestRecoveryCodes() {
+	require.NoError(s.T(), s.repo.ReplaceRecoveryCodes(s.ctx, s.userID, []string{"a", "b"}))
+	require.NoError(s.T(), s.repo.UseRecoveryCode(s.ctx, s.userID, "a"))
+	assert.ErrorIs(s.T(), s.repo.UseRecoveryCode(s.ctx, s.userID, "a"), models.ErrRecoveryCodeNotFound)
+	assert.ErrorIs(s.T(), s.repo.UseRecoveryCode(s.ctx, uuid.New(), "b"), models.ErrRecoveryCodeNotFound)
+
+	require.NoError(s.T(), s.repo.ReplaceRecoveryCodes(s.ctx, s.userID, []string{"c"}))
+	assert.ErrorIs(s.T(), s.repo.UseRecoveryCode(s.ctx, s.userID, "b"), models.ErrRecoveryCodeNotFound)
+	require.NoError(s.T(), s.repo.UseRecoveryCode(s.ctx, s.userID, "c"))
+
+	err := s.repo.ReplaceRecoveryCodes(s.ctx, uuid.New(), []string{"d"})
+	assert.ErrorIs(s.T(), err, models.ErrUserNotFound)
+}
+
+func (s *TwoFactorRepositoryTestSuite) TestLoginChallenges() {
+	challenge := &models.LoginChallenge{
+		UserID:    s.userID,
+		TokenHash: uuid.NewString(),
+		ExpiresAt: time.Now().UTC().Add(time.Minute).Truncate(time.Microsecond),
+	}
+	require.NoError(s.T(), s.repo.CreateLoginChallenge(s.ctx, challenge))
+	assert.NotEqual(s.T(), uuid.Nil, challenge.ID)
+
+	attempts, err := s.repo.RecordLoginChallengeAttempt(s.ctx, challenge.ID)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, attempts)
+
+	found, err := s.repo.GetLoginChallengeByHash(s.ctx, challenge.TokenHash)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), challenge.ID, found.ID)
+	assert.Equal(s.T(), s.userID, found.UserID)
+	assert.Equal(s.T(), 1, found.Attempts)
+	assert.True(s.T(), challenge.ExpiresAt.Equal(found.ExpiresAt))
+	assert.Nil(s.T(), found.UsedAt)
+
+	require.NoError(s.T(), s.repo.UseLoginChallenge(s.ctx, challenge.ID))
+	assert.ErrorIs(s.T(), s.repo.UseLoginChallenge(s.ctx, challenge.ID), models.ErrLoginChallengeNotFound)
+
+	_, err = s.repo.GetLoginChallengeByHash(s.ctx, uuid.NewString())
+	assert.ErrorIs(s.T(), err, models.ErrLoginChallengeNotFound)
+	_, err = s.repo.RecordLoginChallengeAttempt(s.ctx, uuid.New())
+	assert.ErrorIs(s.T(), err, models.ErrLoginChallengeNotFound)
+
+	err = s.repo.CreateLoginChallenge(s.ctx, &models.LoginChallenge{UserID: uuid.New(), TokenHash: uuid.NewString()})
+	assert.ErrorIs(s.T(), err, models.ErrUserNotFound)
+}
@@ -5,10 +5,13 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/Masterminds/squirrel"
 	openapi_types "github.com/oapi-codegen/runtime/types"
 
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
 	"github.com/itisalisas/avito-backend/internal/models"
 )
 
@@ -24,7 +27,7 @@ func (r *UserRepository) CreateUser(ctx context.Context, user *models.User) erro
 	query, args, err := squirrel.Insert("pvz_service.user").
 		Columns("email", "password", "role").
 		Values(user.Email, user.Password, user.Role).
-		Suffix("RETURNING user_id").
+		Suffix("RETURNING user_id, active, created_at").
 		PlaceholderFormat(squirrel.Dollar).
 		ToSql()
 
@@ -32,7 +35,7 @@ func (r *UserRepository) CreateUser(ctx context.Context, user *models.User) erro
 		return fmt.Errorf("failed to build query: %w", err)
 	}
 
-	err = r.tx.QueryRowContext(ctx, query, args...).Scan(&user.ID)
+	err = r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(&user.ID, &user.Active, &user.CreatedAt)
 	if err != nil {
 		return models.ErrEmailAlreadyInUse
 	}
@@ -40,10 +43,33 @@ func (r *UserRepository) CreateUser(ctx context.Context, user *models.User) erro
 	return nil
 }
 
+var userColumns = []string{"user_id", "email", "password", "role", "active", "created_at", "last_login_at"}
+
+// userFields returns scan destinations matching userColumns.
+func userFields(user *models.User) []any {
+	return []any{
+		&user.ID,
+		&user.Email,
+		&user.Password,
+		&user.Role,
+		&user.Active,
+		&user.CreatedAt,
+		&user.LastLoginAt,
+	}
+}
+
 func (r *UserRepository) GetUserByEmail(ctx context.Context, email openapi_types.Email) (*models.User, error) {
-	query, args, err := squirrel.Select("user_id", "email", "password", "role").
+	return r.getUser(ctx, squirrel.Eq{"email": email})
+}
+
+func (r *UserRepository) GetUserById(ctx context.Context, id openapi_types.UUID) (*models.User, error) {
+	return r.getUser(ctx, squirrel.Eq{"user_id": id})
+}
+
+func (r *UserRepository) getUser(ctx context.Context, where squirrel.Eq) (*models.User, error) {
+	query, args, err := squirrel.Select(userColumns...).
 		From("pvz_service.user").
-		Where(squirrel.Eq{"email": email}).
+		Where(where).
 		PlaceholderFormat(squirrel.Dollar).
 		ToSql()
 
@@ -52,19 +78,168 @@ func (r *UserRepository) GetUserByEmail(ctx context.Context, email openapi_types
 	}
 
 	var user models.User
-	err = r.tx.QueryRowContext(ctx, query, args...).Scan(
-		&user.ID,
-		&user.Email,
-		&user.Password,
-		&user.Role,
-	)
+	err = r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(userFields(&user)...)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("user not found")
+			return nil, models.ErrUserNotFound
 		}
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
 	return &user, nil
 }
+
+// likeEscaper escapes the wildcards of a like pattern.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func (r *UserRepository) ListUsers(ctx context.Context, params models.UserListParams) (*models.UserPage, error) {
+	filter := squirrel.And{}
+	if params.Email != "" {
+		filter = append(filter, squirrel.ILike{"email": "%" + likeEscaper.Replace(params.Email) + "%"})
+	}
+	if params.Role != nil {
+		filter = append(filter, squirrel.Eq{"role": *params.Role})
+	}
+	if params.Active != nil {
+		filter = append(filter, squirrel.Eq{"active": *params.Active})
+	}
+
+	query, args, err := squirrel.Select("count(*)").
+		From("pvz_service.user").
+		Where(filter).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	page := &models.UserPage{Items: []*models.User{}}
+	if err := r.querier(ctx).QueryRowContext(ctx, query, args...).Scan(&page.TotalCount); err != nil {
+		return nil, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	query, args, err = squirrel.Select(userColumns...).
+		From("pvz_service.user").
+		Where(filter).
+		OrderBy("created_at DESC", "user_id DESC").
+		Limit(params.Limit).
+		Offset(params.Offset()).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.querier(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query users: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(userFields(&user)...); err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		page.Items = append(page.Items, &user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read users: %w", err)
+	}
+
+	return page, nil
+}
+
+func (r *UserRepository) UpdatePassword(ctx context.Context, id openapi_types.UUID, passwordHash string) error {
+	query, args, err := squirrel.Update("pvz_service.user").
+		Set("password", passwordHash).
+		Where(squirrel.Eq{"user_id": id}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := r.querier(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	if affected == 0 {
+		return models.ErrUserNotFound
+	}
+	return nil
+}
+
+// RehashPassword replaces the password hash only if it is still oldHash, so a
+// password changed in the meantime is kept. It reports whether it replaced it.
+func (r *UserRepository) RehashPassword(ctx context.Context, id openapi_types.UUID, oldHash, newHash string) (bool, error) {
+	query, args, err := squirrel.Update("pvz_service.user").
+		Set("password", newHash).
+		Where(squirrel.Eq{"user_id": id, "password": oldHash}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return false, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := r.querier(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to rehash password: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to rehash password: %w", err)
+	}
+	return affected > 0, nil
+}
+
+func (r *UserRepository) UpdateRole(ctx context.Context, id openapi_types.UUID, role dto.UserRole) error {
+	return r.updateUser(ctx, id, "role", role)
+}
+
+func (r *UserRepository) SetActive(ctx context.Context, id openapi_types.UUID, active bool) error {
+	return r.updateUser(ctx, id, "active", active)
+}
+
+func (r *UserRepository) UpdateLastLogin(ctx context.Context, id openapi_types.UUID, at time.Time) error {
+	return r.updateUser(ctx, id, "last_login_at", at)
+}
+
+// updateUser sets a single column of the user. It returns ErrUserNotFound if
+// there is no such user.
+func (r *UserRepository) updateUser(ctx context.Context, id openapi_types.UUID, column string, value any) error {
+	query, args, err := squirrel.Update("pvz_service.user").
+		Set(column, value).
+		Where(squirrel.Eq{"user_id": id}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := r.querier(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update user %s: %w", column, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update user %s: %w", column, err)
+	}
+	if affected == 0 {
+		return models.ErrUserNotFound
+	}
+	return nil
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"log"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	openapi_types "github.com/oapi-codegen/runtime/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -20,6 +23,7 @@ type UserRepositoryTestSuite struct {
 	db      *sql.DB
 	cleanup func()
 	repo    *UserRepository
+	tx      *sql.Tx
 	ctx     context.Context
 }
 
@@ -30,6 +34,9 @@ func TestUserRepositorySuite(t *testing.T) {
 func (s *UserRepositoryTestSuite) SetupSuite() {
 	s.ctx = context.Background()
 	db := DBTestSetup()
+	if db == nil {
+		s.T().Skip("test database is not configured")
+	}
 	log.Println("migrations applied")
 	s.db = db
 	s.repo = NewUserRepository(s.db)
@@ -48,12 +55,13 @@ func (s *UserRepositoryTestSuite) TearDownSuite() {
 func (s *UserRepositoryTestSuite) SetupTest() {
 	tx, err := s.db.BeginTx(s.ctx, nil)
 	require.NoError(s.T(), err)
-	s.repo.tx = tx
+	s.tx = tx
+	s.ctx = withTx(context.Background(), tx)
 }
 
 func (s *UserRepositoryTestSuite) TearDownTest() {
-	if s.repo.tx != nil {
-		err := s.repo.tx.Rollback()
+	if s.tx != nil {
+		err := s.tx.Rollback()
 		require.NoError(s.T(), err)
 	}
 }
@@ -94,7 +102,7 @@ func (s *UserRepositoryTestSuite) TestCreateUser() {
 				assert.Equal(t, u.Role, dto.UserRole(dto.Employee))
 
 				var count int
-				err := s.repo.tx.QueryRowContext(s.ctx, "select count(*) from pvz_service.user where user_id = $1", u.ID).Scan(&count)
+				err := s.tx.QueryRowContext(s.ctx, "select count(*) from pvz_service.user where user_id = $1", u.ID).Scan(&count)
 				require.NoError(t, err)
 				assert.Equal(t, 1, count)
 			},
@@ -143,3 +151,83 @@ func (s *UserRepositoryTestSuite) TestGetUserByEmail() {
 		assert.Equal(t, "user not found", err.Error())
 	})
 }
+
+func (s *UserRepositoryTestSuite) TestUpdatePassword() {
+	user := s.createUser(s.T(), "update_password@example.com", "password123", dto.UserRole(dto.Employee))
+
+	require.NoError(s.T(), s.repo.UpdatePassword(s.ctx, user.ID, "new hash"))
+	result, err := s.repo.GetUserByEmail(s.ctx, user.Email)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "new hash", result.Password)
+
+	assert.Equal(s.T(), models.ErrUserNotFound, s.repo.UpdatePassword(s.ctx, uuid.New(), "hash"))
+}
+
+func (s *UserRepositoryTestSuite) TestRehashPassword() {
+	user := s.createUser(s.T(), openapi_types.Email("rehash_"+uuid.NewString()[:8]+"@example.com"), "old hash", dto.UserRoleEmployee)
+
+	replaced, err := s.repo.RehashPassword(s.ctx, user.ID, "other hash", "new hash")
+	require.NoError(s.T(), err)
+	assert.False(s.T(), replaced)
+
+	replaced, err = s.repo.RehashPassword(s.ctx, user.ID, "old hash", "new hash")
+	require.NoError(s.T(), err)
+	assert.True(s.T(), replaced)
+	result, err := s.repo.GetUserById(s.ctx, user.ID)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "new hash", result.Password)
+}
+
+func (s *UserRepositoryTestSuite) TestUserAdministration() {
+	suffix := uuid.NewString()[:8]
+	alice := s.createUser(s.T(), openapi_types.Email("alice_"+suffix+"@example.com"), "hash", dto.UserRoleEmployee)
+	bob := s.createUser(s.T(), openapi_types.Email("bob_"+suffix+"@example.com"), "hash", dto.UserRoleEmployee)
+	assert.True(s.T(), alice.Active)
+	assert.False(s.T(), alice.CreatedAt.IsZero())
+
+	require.NoError(s.T(), s.repo.UpdateRole(s.ctx, bob.ID, dto.UserRoleModerator))
+	require.NoError(s.T(), s.repo.SetActive(s.ctx, bob.ID, false))
+	loginAt := time.Now().UTC().Truncate(time.Microsecond)
+	require.NoError(s.T(), s.repo.UpdateLastLogin(s.ctx, alice.ID, loginAt))
+
+	assert.Equal(s.T(), models.ErrUserNotFound, s.repo.UpdateRole(s.ctx, uuid.New(), dto.UserRoleModerator))
+	assert.Equal(s.T(), models.ErrUserNotFound, s.repo.SetActive(s.ctx, uuid.New(), false))
+
+	found, err := s.repo.GetUserById(s.ctx, bob.ID)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), dto.UserRoleModerator, found.Role)
+	assert.False(s.T(), found.Active)
+
+	found, err = s.repo.GetUserById(s.ctx, alice.ID)
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), found.LastLoginAt)
+	assert.True(s.T(), loginAt.Equal(*found.LastLoginAt))
+
+	page, err := s.repo.ListUsers(s.ctx, models.UserListParams{
+		UserFilter: models.UserFilter{Email: "_" + strings.ToUpper(suffix)},
+		Page:       1,
+		Limit:      10,
+	})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), uint64(2), page.TotalCount)
+	assert.Len(s.T(), page.Items, 2)
+
+	inactive := false
+	page, err = s.repo.ListUsers(s.ctx, models.UserListParams{
+		UserFilter: models.UserFilter{Email: suffix, Active: &inactive},
+		Page:       1,
+		Limit:      10,
+	})
+	require.NoError(s.T(), err)
+	require.Len(s.T(), page.Items, 1)
+	assert.Equal(s.T(), bob.ID, page.Items[0].ID)
+
+	// A like wildcard in the search is matched literally.
+	page, err = s.repo.ListUsers(s.ctx, models.UserListParams{
+		UserFilter: models.UserFilter{Email: "%" + suffix},
+		Page:       1,
+		Limit:      10,
+	})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), uint64(0), page.TotalCount)
+}
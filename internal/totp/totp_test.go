@@ -0,0 +1,72 @@
+package totp
+
+import (
+	"encoding/base32"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the SHA1 seed of the RFC 6238 test vectors.
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCode_RFC6238(t *testing.T) {
+	// The RFC lists eight digit codes; six digit codes are their last digits.
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+	for unix, want := range vectors {
+		got, err := Code(rfcSecret, time.Unix(unix, 0))
+		require.NoError(t, err)
+		assert.Equal(t, want, got, unix)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+	now := time.Unix(1_700_000_000, 0)
+
+	current, err := Code(secret, now)
+	require.NoError(t, err)
+	step, ok := Validate(secret, current, now)
+	assert.True(t, ok)
+	assert.Equal(t, Step(now), step)
+
+	previous, err := Code(secret, now.Add(-Period))
+	require.NoError(t, err)
+	step, ok = Validate(secret, previous, now)
+	assert.True(t, ok, "one period of drift is allowed")
+	assert.Equal(t, Step(now)-1, step)
+
+	stale, err := Code(secret, now.Add(-3*Period))
+	require.NoError(t, err)
+	_, ok = Validate(secret, stale, now)
+	assert.False(t, ok)
+
+	_, ok = Validate(secret, "12345", now)
+	assert.False(t, ok)
+	_, ok = Validate("not base32!", current, now)
+	assert.False(t, ok)
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri, err := url.Parse(ProvisioningURI("JBSWY3DPEHPK3PXP", "PVZ Service", "moderator@example.com"))
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/PVZ Service:moderator@example.com", uri.Path)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", uri.Query().Get("secret"))
+	assert.Equal(t, "PVZ Service", uri.Query().Get("issuer"))
+	assert.Equal(t, "6", uri.Query().Get("digits"))
+	assert.False(t, strings.Contains(uri.String(), " "))
+}
@@ -0,0 +1,192 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+const (
+	// apiKeyMetadata is the metadata key clients pass their API key in, the
+	// counterpart of the X-API-Key HTTP header.
+	apiKeyMetadata = "x-api-key"
+	// authorizationMetadata carries the bearer token, as the Authorization
+	// HTTP header does.
+	authorizationMetadata = "authorization"
+)
+
+// Authenticator resolves the principal of a call from its API key or its
+// bearer token.
+type Authenticator interface {
+	Authenticate(ctx context.Context, apiKey, authorization string) (models.Principal, error)
+}
+
+// PvzAccess decides whether an employee may work with a PVZ.
+type PvzAccess interface {
+	CheckAccess(ctx context.Context, userId, pvzId uuid.UUID) error
+}
+
+// methodRule is what a call to a method requires of its principal, the
+// counterpart of the permission and PVZ access middlewares of the HTTP
+// routes.
+type methodRule struct {
+	permission models.Permission
+	// pvzScoped methods are open to employees only for their own PVZs. The
+	// PVZ is taken from the pvz_id field of the request.
+	pvzScoped bool
+}
+
+var methodRules = map[string]methodRule{
+	"/pvz.v1.PVZService/GetPVZList":         {permission: models.PermPvzRead},
+	"/pvz.v1.PVZService/ListPVZ":            {permission: models.PermPvzRead},
+	"/pvz.v1.PVZService/StreamPVZ":          {permission: models.PermPvzRead},
+	"/pvz.v1.PVZService/CreatePVZ":          {permission: models.PermPvzCreate},
+	"/pvz.v1.PVZService/OpenReception":      {permission: models.PermReceptionCreate, pvzScoped: true},
+	"/pvz.v1.PVZService/CloseLastReception": {permission: models.PermReceptionClose, pvzScoped: true},
+	"/pvz.v1.PVZService/AddProduct":         {permission: models.PermProductCreate, pvzScoped: true},
+	"/pvz.v1.PVZService/DeleteLastProduct":  {permission: models.PermProductDelete, pvzScoped: true},
+}
+
+// publicServices are served without authentication. Reflection only
+// describes the API, so tools such as grpcurl work before a token is set.
+var publicServices = []string{
+	"/grpc.reflection.v1.ServerReflection/",
+	"/grpc.reflection.v1alpha.ServerReflection/",
+}
+
+// pvzRequest is implemented by the requests of PVZ scoped methods.
+type pvzRequest interface {
+	GetPvzId() string
+}
+
+// UnaryAuthInterceptor authenticates calls the way the HTTP API does, checks
+// the permission the method requires and puts the principal in the context.
+// Methods without a rule are refused.
+func UnaryAuthInterceptor(authenticator Authenticator, access PvzAccess) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if isPublic(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		ctx, rule, err := authorize(ctx, authenticator, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		if rule.pvzScoped {
+			if err := checkPvzAccess(ctx, access, req); err != nil {
+				return nil, err
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuthInterceptor is the streaming counterpart of
+// UnaryAuthInterceptor. The PVZ access of scoped methods is checked on every
+// message the client sends.
+func StreamAuthInterceptor(authenticator Authenticator, access PvzAccess) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if isPublic(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		ctx, rule, err := authorize(ss.Context(), authenticator, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authorizedStream{ServerStream: ss, ctx: ctx, access: access, pvzScoped: rule.pvzScoped})
+	}
+}
+
+// authorizedStream carries the principal in its context.
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx       context.Context
+	access    PvzAccess
+	pvzScoped bool
+}
+
+func (s *authorizedStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *authorizedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if s.pvzScoped {
+		return checkPvzAccess(s.ctx, s.access, m)
+	}
+	return nil
+}
+
+func isPublic(method string) bool {
+	for _, prefix := range publicServices {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// authorize authenticates the call by its metadata and checks the permission
+// the method requires.
+func authorize(ctx context.Context, authenticator Authenticator, method string) (context.Context, methodRule, error) {
+	rule, ok := methodRules[method]
+	if !ok {
+		return nil, methodRule{}, status.Errorf(codes.PermissionDenied, "no access rule for %s", method)
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	principal, err := authenticator.Authenticate(ctx, firstValue(md, apiKeyMetadata), firstValue(md, authorizationMetadata))
+	if err != nil {
+		return nil, methodRule{}, statusError(err)
+	}
+	if !principal.Can(rule.permission) {
+		return nil, methodRule{}, statusError(models.ErrPermissionDenied)
+	}
+	return models.WithPrincipal(ctx, principal), rule, nil
+}
+
+// checkPvzAccess rejects employees that are not assigned to the PVZ of the
+// request, as CheckPvzAccess does for HTTP. Moderators pass, service accounts
+// are bound only by their own PVZ scope.
+func checkPvzAccess(ctx context.Context, access PvzAccess, req any) error {
+	principal, _ := models.PrincipalFromContext(ctx)
+	scoped := principal.ServiceAccount && len(principal.PvzIds) > 0
+	if principal.Role != dto.UserRoleEmployee && !scoped {
+		return nil
+	}
+
+	r, ok := req.(pvzRequest)
+	if !ok {
+		return status.Errorf(codes.Internal, "%T has no pvz_id", req)
+	}
+	pvzId, err := parseID("pvz_id", r.GetPvzId())
+	if err != nil {
+		return err
+	}
+
+	if principal.ServiceAccount {
+		if !principal.CanAccessPvz(pvzId) {
+			return statusError(models.ErrPvzAccessDenied)
+		}
+		return nil
+	}
+	return statusError(access.CheckAccess(ctx, principal.UserID, pvzId))
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,290 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+// stubAuthenticator knows principals by API key and by authorization value.
+type stubAuthenticator struct {
+	principals map[string]models.Principal
+	err        error
+}
+
+func (s *stubAuthenticator) Authenticate(_ context.Context, apiKey, authorization string) (models.Principal, error) {
+	if s.err != nil {
+		return models.Principal{}, s.err
+	}
+	if apiKey != "" {
+		principal, ok := s.principals[apiKey]
+		if !ok {
+			return models.Principal{}, models.ErrInvalidAPIKey
+		}
+		return principal, nil
+	}
+	if authorization == "" {
+		return models.Principal{}, models.ErrAuthRequired
+	}
+	principal, ok := s.principals[authorization]
+	if !ok {
+		return models.Principal{}, models.ErrMalformedToken
+	}
+	return principal, nil
+}
+
+type stubPvzAccess struct {
+	assigned map[uuid.UUID]uuid.UUID
+	err      error
+}
+
+func (s *stubPvzAccess) CheckAccess(_ context.Context, userId, pvzId uuid.UUID) error {
+	if s.err != nil {
+		return s.err
+	}
+	if s.assigned[userId] != pvzId {
+		return models.ErrPvzAccessDenied
+	}
+	return nil
+}
+
+func TestUnaryAuthInterceptor(t *testing.T) {
+	pvzId, otherPvzId := uuid.New(), uuid.New()
+	employee := models.Principal{
+		UserID:      uuid.New(),
+		Role:        dto.UserRoleEmployee,
+		Permissions: models.DefaultRolePermissions[dto.UserRoleEmployee],
+	}
+	moderator := models.Principal{
+		UserID:      uuid.New(),
+		Role:        dto.UserRoleModerator,
+		Permissions: models.DefaultRolePermissions[dto.UserRoleModerator],
+	}
+	serviceAccount := models.Principal{
+		UserID:         uuid.New(),
+		Role:           dto.UserRoleEmployee,
+		ServiceAccount: true,
+		PvzIds:         []uuid.UUID{pvzId},
+		Permissions:    models.DefaultRolePermissions[dto.UserRoleEmployee],
+	}
+	authenticator := &stubAuthenticator{principals: map[string]models.Principal{
+		"Bearer employee":  employee,
+		"Bearer moderator": moderator,
+		"pvz_valid":        serviceAccount,
+	}}
+	access := &stubPvzAccess{assigned: map[uuid.UUID]uuid.UUID{employee.UserID: pvzId}}
+
+	tests := []struct {
+		name          string
+		method        string
+		md            metadata.MD
+		authenticator Authenticator
+		access        PvzAccess
+		req           any
+		wantCode      codes.Code
+		wantPrincipal *models.Principal
+	}{
+		{
+			name:     "reflection is public",
+			method:   "/grpc.reflection.v1.ServerReflection/ServerReflectionInfo",
+			wantCode: codes.OK,
+		},
+		{
+			name:     "method without rule",
+			method:   "/pvz.v1.PVZService/Unknown",
+			md:       metadata.Pairs(authorizationMetadata, "Bearer moderator"),
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "no credentials",
+			method:   "/pvz.v1.PVZService/GetPVZList",
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "invalid token",
+			method:   "/pvz.v1.PVZService/GetPVZList",
+			md:       metadata.Pairs(authorizationMetadata, "Bearer forged"),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "invalid key",
+			method:   "/pvz.v1.PVZService/GetPVZList",
+			md:       metadata.Pairs(apiKeyMetadata, "pvz_unknown"),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:          "deactivated user",
+			method:        "/pvz.v1.PVZService/GetPVZList",
+			md:            metadata.Pairs(authorizationMetadata, "Bearer employee"),
+			authenticator: &stubAuthenticator{err: models.ErrUserDeactivated},
+			wantCode:      codes.PermissionDenied,
+		},
+		{
+			name:          "lookup failure",
+			method:        "/pvz.v1.PVZService/GetPVZList",
+			md:            metadata.Pairs(apiKeyMetadata, "pvz_valid"),
+			authenticator: &stubAuthenticator{err: errors.New("db error")},
+			wantCode:      codes.Internal,
+		},
+		{
+			name:     "missing permission",
+			method:   "/pvz.v1.PVZService/CreatePVZ",
+			md:       metadata.Pairs(authorizationMetadata, "Bearer employee"),
+			req:      &CreatePVZRequest{},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:          "valid token",
+			method:        "/pvz.v1.PVZService/CreatePVZ",
+			md:            metadata.Pairs(authorizationMetadata, "Bearer moderator"),
+			req:           &CreatePVZRequest{},
+			wantCode:      codes.OK,
+			wantPrincipal: &moderator,
+		},
+		{
+			name:          "employee of the pvz",
+			method:        "/pvz.v1.PVZService/OpenReception",
+			md:            metadata.Pairs(authorizationMetadata, "Bearer employee"),
+			req:           &OpenReceptionRequest{PvzId: pvzId.String()},
+			wantCode:      codes.OK,
+			wantPrincipal: &employee,
+		},
+		{
+			name:     "employee of another pvz",
+			method:   "/pvz.v1.PVZService/OpenReception",
+			md:       metadata.Pairs(authorizationMetadata, "Bearer employee"),
+			req:      &OpenReceptionRequest{PvzId: otherPvzId.String()},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "invalid pvz id",
+			method:   "/pvz.v1.PVZService/AddProduct",
+			md:       metadata.Pairs(authorizationMetadata, "Bearer employee"),
+			req:      &AddProductRequest{PvzId: "not-a-uuid"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "access lookup failure",
+			method:   "/pvz.v1.PVZService/AddProduct",
+			md:       metadata.Pairs(authorizationMetadata, "Bearer employee"),
+			access:   &stubPvzAccess{err: errors.New("db error")},
+			req:      &AddProductRequest{PvzId: pvzId.String()},
+			wantCode: codes.Internal,
+		},
+		{
+			name:     "service account outside its scope",
+			method:   "/pvz.v1.PVZService/DeleteLastProduct",
+			md:       metadata.Pairs(apiKeyMetadata, "pvz_valid"),
+			req:      &DeleteLastProductRequest{PvzId: otherPvzId.String()},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:          "service account within its scope",
+			method:        "/pvz.v1.PVZService/DeleteLastProduct",
+			md:            metadata.Pairs(apiKeyMetadata, "pvz_valid"),
+			req:           &DeleteLastProductRequest{PvzId: pvzId.String()},
+			wantCode:      codes.OK,
+			wantPrincipal: &serviceAccount,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.authenticator == nil {
+				tt.authenticator = authenticator
+			}
+			if tt.access == nil {
+				tt.access = access
+			}
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+
+			var gotPrincipal *models.Principal
+			handler := func(ctx context.Context, req any) (any, error) {
+				if principal, ok := models.PrincipalFromContext(ctx); ok {
+					gotPrincipal = &principal
+				}
+				return req, nil
+			}
+
+			interceptor := UnaryAuthInterceptor(tt.authenticator, tt.access)
+			_, err := interceptor(ctx, tt.req, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			require.Equal(t, tt.wantCode, status.Code(err), "error: %v", err)
+			require.Equal(t, tt.wantPrincipal, gotPrincipal)
+		})
+	}
+}
+
+type stubServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	// pvzIds are received as AddProductRequests.
+	pvzIds []string
+}
+
+func (s *stubServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *stubServerStream) RecvMsg(m any) error {
+	if len(s.pvzIds) == 0 {
+		return errors.New("no messages")
+	}
+	m.(*AddProductRequest).PvzId = s.pvzIds[0]
+	s.pvzIds = s.pvzIds[1:]
+	return nil
+}
+
+func TestStreamAuthInterceptor(t *testing.T) {
+	pvzId := uuid.New()
+	employee := models.Principal{
+		UserID:      uuid.New(),
+		Role:        dto.UserRoleEmployee,
+		Permissions: models.DefaultRolePermissions[dto.UserRoleEmployee],
+	}
+	authenticator := &stubAuthenticator{principals: map[string]models.Principal{"Bearer employee": employee}}
+	access := &stubPvzAccess{assigned: map[uuid.UUID]uuid.UUID{employee.UserID: pvzId}}
+	interceptor := StreamAuthInterceptor(authenticator, access)
+
+	t.Run("principal in context", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(authorizationMetadata, "Bearer employee"))
+		var gotPrincipal models.Principal
+		err := interceptor(nil, &stubServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/pvz.v1.PVZService/ListPVZ"},
+			func(_ any, ss grpc.ServerStream) error {
+				gotPrincipal, _ = models.PrincipalFromContext(ss.Context())
+				return nil
+			})
+		require.NoError(t, err)
+		assert.Equal(t, employee, gotPrincipal)
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		err := interceptor(nil, &stubServerStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: "/pvz.v1.PVZService/ListPVZ"},
+			func(any, grpc.ServerStream) error {
+				t.Fatal("handler called")
+				return nil
+			})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("pvz access checked per message", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(authorizationMetadata, "Bearer employee"))
+		stream := &stubServerStream{ctx: ctx, pvzIds: []string{pvzId.String(), uuid.NewString()}}
+		err := interceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: "/pvz.v1.PVZService/AddProduct"},
+			func(_ any, ss grpc.ServerStream) error {
+				require.NoError(t, ss.RecvMsg(&AddProductRequest{}))
+				return ss.RecvMsg(&AddProductRequest{})
+			})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
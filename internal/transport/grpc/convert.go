@@ -0,0 +1,81 @@
+package grpc
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+func toProtoPVZ(p dto.PVZ) *PVZ {
+	pvz := &PVZ{
+		Id:               p.Id.String(),
+		RegistrationDate: timestamppb.New(*p.RegistrationDate),
+		City:             p.City,
+		Name:             p.Name,
+		Address:          p.Address,
+		Latitude:         p.Latitude,
+		Longitude:        p.Longitude,
+		Phone:            p.Phone,
+		OpeningHours:     p.OpeningHours,
+		CreatedBy:        optionalID(p.CreatedBy),
+	}
+	if p.DecommissionedAt != nil {
+		pvz.DecommissionedAt = timestamppb.New(*p.DecommissionedAt)
+	}
+	return pvz
+}
+
+func toProtoReception(r dto.Reception) *Reception {
+	return &Reception{
+		Id:        r.Id.String(),
+		DateTime:  timestamppb.New(r.DateTime),
+		PvzId:     r.PvzId.String(),
+		Status:    string(r.Status),
+		CreatedBy: optionalID(r.CreatedBy),
+	}
+}
+
+func toProtoProduct(p dto.Product) *Product {
+	product := &Product{
+		Id:          p.Id.String(),
+		Type:        p.Type,
+		ReceptionId: p.ReceptionId.String(),
+		CreatedBy:   optionalID(p.CreatedBy),
+	}
+	if p.DateTime != nil {
+		product.DateTime = timestamppb.New(*p.DateTime)
+	}
+	return product
+}
+
+func toProtoPVZWithReceptions(p *models.ExtendedPvz) *PVZWithReceptions {
+	item := &PVZWithReceptions{Pvz: toProtoPVZ(p.PVZ)}
+	for _, r := range p.Receptions {
+		reception := &ReceptionWithProducts{Reception: toProtoReception(r.Reception)}
+		for _, product := range r.Products {
+			reception.Products = append(reception.Products, toProtoProduct(product))
+		}
+		item.Receptions = append(item.Receptions, reception)
+	}
+	return item
+}
+
+func optionalID(id *uuid.UUID) *string {
+	if id == nil {
+		return nil
+	}
+	s := id.String()
+	return &s
+}
+
+func optionalTime(t *timestamppb.Timestamp) *time.Time {
+	if t == nil {
+		return nil
+	}
+	v := t.AsTime()
+	return &v
+}
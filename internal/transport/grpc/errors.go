@@ -0,0 +1,84 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+// errorCodes maps domain errors to the status codes the HTTP handlers'
+// status codes correspond to.
+var errorCodes = []struct {
+	err  error
+	code codes.Code
+}{
+	{models.ErrIncorrectCity, codes.InvalidArgument},
+	{models.ErrIncorrectCoordinates, codes.InvalidArgument},
+	{models.ErrIncorrectPhone, codes.InvalidArgument},
+	{models.ErrIncorrectProductType, codes.InvalidArgument},
+	{models.ErrInvalidCursor, codes.InvalidArgument},
+	{models.ErrEmptyName, codes.InvalidArgument},
+
+	{models.ErrPvzNotFound, codes.NotFound},
+	{models.ErrReceptionNotFound, codes.NotFound},
+
+	{models.ErrReceptionNotClosed, codes.FailedPrecondition},
+	{models.ErrReceptionClosed, codes.FailedPrecondition},
+	{models.ErrNoProductsInReception, codes.FailedPrecondition},
+	{models.ErrPvzDecommissioned, codes.FailedPrecondition},
+	{models.ErrInvalidTransition, codes.FailedPrecondition},
+
+	{models.ErrAuthRequired, codes.Unauthenticated},
+	{models.ErrMalformedToken, codes.Unauthenticated},
+	{models.ErrInvalidToken, codes.Unauthenticated},
+	{models.ErrTokenRevoked, codes.Unauthenticated},
+	{models.ErrInvalidAPIKey, codes.Unauthenticated},
+
+	{models.ErrUserDeactivated, codes.PermissionDenied},
+	{models.ErrPermissionDenied, codes.PermissionDenied},
+	{models.ErrPvzAccessDenied, codes.PermissionDenied},
+	{models.ErrTransitionForbidden, codes.PermissionDenied},
+
+	{context.Canceled, codes.Canceled},
+	{context.DeadlineExceeded, codes.DeadlineExceeded},
+}
+
+// statusError converts an error returned by a service into a status error.
+// Errors that already carry a status are returned as they are, unknown
+// errors become Internal.
+func statusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	for _, e := range errorCodes {
+		if errors.Is(err, e.err) {
+			return status.Error(e.code, err.Error())
+		}
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// UnaryErrorInterceptor converts the errors of unary calls with statusError.
+// Chain it first so it sees the errors of the other interceptors too.
+func UnaryErrorInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		return resp, statusError(err)
+	}
+}
+
+// StreamErrorInterceptor converts the errors of streaming calls with
+// statusError.
+func StreamErrorInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return statusError(handler(srv, ss))
+	}
+}
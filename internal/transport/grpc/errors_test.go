@@ -0,0 +1,43 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+func TestStatusError(t *testing.T) {
+	tests := []struct {
+		err      error
+		wantCode codes.Code
+	}{
+		{nil, codes.OK},
+		{models.ErrIncorrectCity, codes.InvalidArgument},
+		{fmt.Errorf("add pvz: %w", models.ErrIncorrectPhone), codes.InvalidArgument},
+		{models.ErrPvzNotFound, codes.NotFound},
+		{models.ErrReceptionNotClosed, codes.FailedPrecondition},
+		{models.ErrNoProductsInReception, codes.FailedPrecondition},
+		{models.ErrTokenRevoked, codes.Unauthenticated},
+		{models.ErrPvzAccessDenied, codes.PermissionDenied},
+		{context.DeadlineExceeded, codes.DeadlineExceeded},
+		{status.Error(codes.InvalidArgument, "invalid limit"), codes.InvalidArgument},
+		{errors.New("db error"), codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.err), func(t *testing.T) {
+			err := statusError(tt.err)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			if tt.err != nil {
+				assert.Equal(t, status.Convert(tt.err).Message(), status.Convert(err).Message())
+			}
+		})
+	}
+}
@@ -23,7 +23,18 @@ const _ = grpc.SupportPackageIsVersion7
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type PVZServiceClient interface {
+	// GetPVZList returns every PVZ without receptions. Prefer ListPVZ.
 	GetPVZList(ctx context.Context, in *GetPVZListRequest, opts ...grpc.CallOption) (*GetPVZListResponse, error)
+	ListPVZ(ctx context.Context, in *ListPVZRequest, opts ...grpc.CallOption) (*ListPVZResponse, error)
+	// StreamPVZ sends every PVZ that matches the filters, in the order of
+	// ListPVZ, in chunks. It reads the PVZs chunk by chunk, so it suits
+	// listings too large for a single response.
+	StreamPVZ(ctx context.Context, in *StreamPVZRequest, opts ...grpc.CallOption) (PVZService_StreamPVZClient, error)
+	CreatePVZ(ctx context.Context, in *CreatePVZRequest, opts ...grpc.CallOption) (*PVZ, error)
+	OpenReception(ctx context.Context, in *OpenReceptionRequest, opts ...grpc.CallOption) (*Reception, error)
+	CloseLastReception(ctx context.Context, in *CloseLastReceptionRequest, opts ...grpc.CallOption) (*Reception, error)
+	AddProduct(ctx context.Context, in *AddProductRequest, opts ...grpc.CallOption) (*Product, error)
+	DeleteLastProduct(ctx context.Context, in *DeleteLastProductRequest, opts ...grpc.CallOption) (*DeleteLastProductResponse, error)
 }
 
 type pVZServiceClient struct {
@@ -43,11 +54,108 @@ func (c *pVZServiceClient) GetPVZList(ctx context.Context, in *GetPVZListRequest
 	return out, nil
 }
 
+func (c *pVZServiceClient) ListPVZ(ctx context.Context, in *ListPVZRequest, opts ...grpc.CallOption) (*ListPVZResponse, error) {
+	out := new(ListPVZResponse)
+	err := c.cc.Invoke(ctx, "/pvz.v1.PVZService/ListPVZ", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pVZServiceClient) StreamPVZ(ctx context.Context, in *StreamPVZRequest, opts ...grpc.CallOption) (PVZService_StreamPVZClient, error) {
+	stream, err := c.cc.NewStream(ctx, &PVZService_ServiceDesc.Streams[0], "/pvz.v1.PVZService/StreamPVZ", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &pVZServiceStreamPVZClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PVZService_StreamPVZClient interface {
+	Recv() (*StreamPVZResponse, error)
+	grpc.ClientStream
+}
+
+type pVZServiceStreamPVZClient struct {
+	grpc.ClientStream
+}
+
+func (x *pVZServiceStreamPVZClient) Recv() (*StreamPVZResponse, error) {
+	m := new(StreamPVZResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *pVZServiceClient) CreatePVZ(ctx context.Context, in *CreatePVZRequest, opts ...grpc.CallOption) (*PVZ, error) {
+	out := new(PVZ)
+	err := c.cc.Invoke(ctx, "/pvz.v1.PVZService/CreatePVZ", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pVZServiceClient) OpenReception(ctx context.Context, in *OpenReceptionRequest, opts ...grpc.CallOption) (*Reception, error) {
+	out := new(Reception)
+	err := c.cc.Invoke(ctx, "/pvz.v1.PVZService/OpenReception", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pVZServiceClient) CloseLastReception(ctx context.Context, in *CloseLastReceptionRequest, opts ...grpc.CallOption) (*Reception, error) {
+	out := new(Reception)
+	err := c.cc.Invoke(ctx, "/pvz.v1.PVZService/CloseLastReception", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pVZServiceClient) AddProduct(ctx context.Context, in *AddProductRequest, opts ...grpc.CallOption) (*Product, error) {
+	out := new(Product)
+	err := c.cc.Invoke(ctx, "/pvz.v1.PVZService/AddProduct", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pVZServiceClient) DeleteLastProduct(ctx context.Context, in *DeleteLastProductRequest, opts ...grpc.CallOption) (*DeleteLastProductResponse, error) {
+	out := new(DeleteLastProductResponse)
+	err := c.cc.Invoke(ctx, "/pvz.v1.PVZService/DeleteLastProduct", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PVZServiceServer is the server API for PVZService service.
 // All implementations must embed UnimplementedPVZServiceServer
 // for forward compatibility
 type PVZServiceServer interface {
+	// GetPVZList returns every PVZ without receptions. Prefer ListPVZ.
 	GetPVZList(context.Context, *GetPVZListRequest) (*GetPVZListResponse, error)
+	ListPVZ(context.Context, *ListPVZRequest) (*ListPVZResponse, error)
+	// StreamPVZ sends every PVZ that matches the filters, in the order of
+	// ListPVZ, in chunks. It reads the PVZs chunk by chunk, so it suits
+	// listings too large for a single response.
+	StreamPVZ(*StreamPVZRequest, PVZService_StreamPVZServer) error
+	CreatePVZ(context.Context, *CreatePVZRequest) (*PVZ, error)
+	OpenReception(context.Context, *OpenReceptionRequest) (*Reception, error)
+	CloseLastReception(context.Context, *CloseLastReceptionRequest) (*Reception, error)
+	AddProduct(context.Context, *AddProductRequest) (*Product, error)
+	DeleteLastProduct(context.Context, *DeleteLastProductRequest) (*DeleteLastProductResponse, error)
 	mustEmbedUnimplementedPVZServiceServer()
 }
 
@@ -58,6 +166,27 @@ type UnimplementedPVZServiceServer struct {
 func (UnimplementedPVZServiceServer) GetPVZList(context.Context, *GetPVZListRequest) (*GetPVZListResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetPVZList not implemented")
 }
+func (UnimplementedPVZServiceServer) ListPVZ(context.Context, *ListPVZRequest) (*ListPVZResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPVZ not implemented")
+}
+func (UnimplementedPVZServiceServer) StreamPVZ(*StreamPVZRequest, PVZService_StreamPVZServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamPVZ not implemented")
+}
+func (UnimplementedPVZServiceServer) CreatePVZ(context.Context, *CreatePVZRequest) (*PVZ, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePVZ not implemented")
+}
+func (UnimplementedPVZServiceServer) OpenReception(context.Context, *OpenReceptionRequest) (*Reception, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method OpenReception not implemented")
+}
+func (UnimplementedPVZServiceServer) CloseLastReception(context.Context, *CloseLastReceptionRequest) (*Reception, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloseLastReception not implemented")
+}
+func (UnimplementedPVZServiceServer) AddProduct(context.Context, *AddProductRequest) (*Product, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddProduct not implemented")
+}
+func (UnimplementedPVZServiceServer) DeleteLastProduct(context.Context, *DeleteLastProductRequest) (*DeleteLastProductResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteLastProduct not implemented")
+}
 func (UnimplementedPVZServiceServer) mustEmbedUnimplementedPVZServiceServer() {}
 
 // UnsafePVZServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -89,6 +218,135 @@ func _PVZService_GetPVZList_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PVZService_ListPVZ_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPVZRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PVZServiceServer).ListPVZ(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pvz.v1.PVZService/ListPVZ",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PVZServiceServer).ListPVZ(ctx, req.(*ListPVZRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PVZService_StreamPVZ_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamPVZRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PVZServiceServer).StreamPVZ(m, &pVZServiceStreamPVZServer{stream})
+}
+
+type PVZService_StreamPVZServer interface {
+	Send(*StreamPVZResponse) error
+	grpc.ServerStream
+}
+
+type pVZServiceStreamPVZServer struct {
+	grpc.ServerStream
+}
+
+func (x *pVZServiceStreamPVZServer) Send(m *StreamPVZResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _PVZService_CreatePVZ_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePVZRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PVZServiceServer).CreatePVZ(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pvz.v1.PVZService/CreatePVZ",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PVZServiceServer).CreatePVZ(ctx, req.(*CreatePVZRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PVZService_OpenReception_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OpenReceptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PVZServiceServer).OpenReception(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pvz.v1.PVZService/OpenReception",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PVZServiceServer).OpenReception(ctx, req.(*OpenReceptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PVZService_CloseLastReception_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseLastReceptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PVZServiceServer).CloseLastReception(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pvz.v1.PVZService/CloseLastReception",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PVZServiceServer).CloseLastReception(ctx, req.(*CloseLastReceptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PVZService_AddProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PVZServiceServer).AddProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pvz.v1.PVZService/AddProduct",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PVZServiceServer).AddProduct(ctx, req.(*AddProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PVZService_DeleteLastProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteLastProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PVZServiceServer).DeleteLastProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pvz.v1.PVZService/DeleteLastProduct",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PVZServiceServer).DeleteLastProduct(ctx, req.(*DeleteLastProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // PVZService_ServiceDesc is the grpc.ServiceDesc for PVZService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -100,7 +358,37 @@ var PVZService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetPVZList",
 			Handler:    _PVZService_GetPVZList_Handler,
 		},
+		{
+			MethodName: "ListPVZ",
+			Handler:    _PVZService_ListPVZ_Handler,
+		},
+		{
+			MethodName: "CreatePVZ",
+			Handler:    _PVZService_CreatePVZ_Handler,
+		},
+		{
+			MethodName: "OpenReception",
+			Handler:    _PVZService_OpenReception_Handler,
+		},
+		{
+			MethodName: "CloseLastReception",
+			Handler:    _PVZService_CloseLastReception_Handler,
+		},
+		{
+			MethodName: "AddProduct",
+			Handler:    _PVZService_AddProduct_Handler,
+		},
+		{
+			MethodName: "DeleteLastProduct",
+			Handler:    _PVZService_DeleteLastProduct_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamPVZ",
+			Handler:       _PVZService_StreamPVZ_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "internal/transport/grpc/pvz.proto",
 }
@@ -3,38 +3,338 @@ package grpc
 import (
 	"context"
 
+	"github.com/google/uuid"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
-	"github.com/itisalisas/avito-backend/internal/service/pvz"
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+	"github.com/itisalisas/avito-backend/pkg/metrics"
 )
 
+const (
+	defaultListLimit = 10
+	maxListLimit     = 30
+
+	defaultStreamChunkSize = 30
+	maxStreamChunkSize     = 100
+)
+
+// PvzService is the part of the pvz service the gRPC API exposes.
+type PvzService interface {
+	AddPvz(ctx context.Context, request *dto.PostPvzJSONRequestBody) (*dto.PVZ, error)
+	GetPvzList(ctx context.Context, params models.PvzListParams) (*models.PvzPage, error)
+	GetAllPVZ(ctx context.Context) ([]dto.PVZ, error)
+}
+
+// ReceptionService is the part of the reception service the gRPC API
+// exposes.
+type ReceptionService interface {
+	AddReception(ctx context.Context, request dto.PostReceptionsJSONRequestBody) (*dto.Reception, error)
+	CloseLastReception(ctx context.Context, pvzId uuid.UUID) (*dto.Reception, error)
+}
+
+// ProductService is the part of the product service the gRPC API exposes.
+type ProductService interface {
+	AddProduct(ctx context.Context, request dto.PostProductsJSONRequestBody) (*dto.Product, error)
+	DeleteLastProduct(ctx context.Context, pvzId uuid.UUID) error
+}
+
 type PVZServer struct {
 	UnimplementedPVZServiceServer
-	service *pvz.Service
+	pvzService       PvzService
+	receptionService ReceptionService
+	productService   ProductService
 }
 
-func NewPVZServer(s *pvz.Service) *PVZServer {
-	return &PVZServer{service: s}
+func NewPVZServer(pvzService PvzService, receptionService ReceptionService, productService ProductService) *PVZServer {
+	return &PVZServer{pvzService: pvzService, receptionService: receptionService, productService: productService}
 }
 
 func (s *PVZServer) GetPVZList(ctx context.Context, req *GetPVZListRequest) (*GetPVZListResponse, error) {
-	pvzs, err := s.service.GetAllPVZ(ctx)
+	pvzs, err := s.pvzService.GetAllPVZ(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	resp := &GetPVZListResponse{}
 	for _, p := range pvzs {
-		resp.Pvzs = append(resp.Pvzs, &PVZ{
-			Id:               p.Id.String(),
-			RegistrationDate: timestamppb.New(*p.RegistrationDate),
-			City:             string(p.City),
-		})
+		resp.Pvzs = append(resp.Pvzs, toProtoPVZ(p))
+	}
+	return resp, nil
+}
+
+func (s *PVZServer) ListPVZ(ctx context.Context, req *ListPVZRequest) (*ListPVZResponse, error) {
+	params, err := listParams(req)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := s.pvzService.GetPvzList(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ListPVZResponse{TotalCount: page.TotalCount}
+	for _, item := range page.Items {
+		resp.Items = append(resp.Items, toProtoPVZWithReceptions(item))
+	}
+	if page.NextCursor != nil {
+		resp.NextCursor = page.NextCursor.Encode()
 	}
 	return resp, nil
 }
 
-func RegisterGRPCServer(s *grpc.Server, pvzService *pvz.Service) {
-	RegisterPVZServiceServer(s, NewPVZServer(pvzService))
+// StreamPVZ reads the listing page by page with a cursor and sends every
+// page as a chunk. It stops as soon as the client cancels the call or its
+// deadline passes.
+func (s *PVZServer) StreamPVZ(req *StreamPVZRequest, stream PVZService_StreamPVZServer) error {
+	params, err := streamParams(req)
+	if err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		page, err := s.pvzService.GetPvzList(ctx, params)
+		if err != nil {
+			return err
+		}
+		if len(page.Items) == 0 {
+			return nil
+		}
+
+		chunk := &StreamPVZResponse{}
+		for _, item := range page.Items {
+			chunk.Items = append(chunk.Items, toProtoPVZWithReceptions(item))
+		}
+		if page.NextCursor != nil {
+			chunk.NextCursor = page.NextCursor.Encode()
+		}
+		if err := stream.Send(chunk); err != nil {
+			return err
+		}
+
+		if page.NextCursor == nil {
+			return nil
+		}
+		params.Cursor = page.NextCursor
+	}
+}
+
+func (s *PVZServer) CreatePVZ(ctx context.Context, req *CreatePVZRequest) (*PVZ, error) {
+	created, err := s.pvzService.AddPvz(ctx, &dto.PostPvzJSONRequestBody{
+		City:         req.City,
+		Name:         req.Name,
+		Address:      req.Address,
+		Latitude:     req.Latitude,
+		Longitude:    req.Longitude,
+		Phone:        req.Phone,
+		OpeningHours: req.OpeningHours,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.PVZCreated.Inc()
+	return toProtoPVZ(*created), nil
+}
+
+func (s *PVZServer) OpenReception(ctx context.Context, req *OpenReceptionRequest) (*Reception, error) {
+	pvzId, err := parseID("pvz_id", req.PvzId)
+	if err != nil {
+		return nil, err
+	}
+
+	reception, err := s.receptionService.AddReception(ctx, dto.PostReceptionsJSONRequestBody{PvzId: pvzId})
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.OrderReceptionsCreated.Inc()
+	return toProtoReception(*reception), nil
+}
+
+func (s *PVZServer) CloseLastReception(ctx context.Context, req *CloseLastReceptionRequest) (*Reception, error) {
+	pvzId, err := parseID("pvz_id", req.PvzId)
+	if err != nil {
+		return nil, err
+	}
+
+	reception, err := s.receptionService.CloseLastReception(ctx, pvzId)
+	if err != nil {
+		return nil, err
+	}
+	return toProtoReception(*reception), nil
+}
+
+func (s *PVZServer) AddProduct(ctx context.Context, req *AddProductRequest) (*Product, error) {
+	pvzId, err := parseID("pvz_id", req.PvzId)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := s.productService.AddProduct(ctx, dto.PostProductsJSONRequestBody{PvzId: pvzId, Type: req.Type})
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.ProductsAdded.Inc()
+	return toProtoProduct(*product), nil
+}
+
+func (s *PVZServer) DeleteLastProduct(ctx context.Context, req *DeleteLastProductRequest) (*DeleteLastProductResponse, error) {
+	pvzId, err := parseID("pvz_id", req.PvzId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.productService.DeleteLastProduct(ctx, pvzId); err != nil {
+		return nil, err
+	}
+	return &DeleteLastProductResponse{}, nil
+}
+
+// pvzFilterRequest is implemented by the requests that take the PVZ
+// listing filters.
+type pvzFilterRequest interface {
+	GetCities() []string
+	GetStartDate() *timestamppb.Timestamp
+	GetEndDate() *timestamppb.Timestamp
+	GetReceptionStatus() string
+	GetProductType() string
+	GetProductStartDate() *timestamppb.Timestamp
+	GetProductEndDate() *timestamppb.Timestamp
+	GetHasOpenReception() bool
+}
+
+// pvzFilter validates the filters of a request the way GET /pvz validates
+// its query parameters.
+func pvzFilter(req pvzFilterRequest) (models.PvzFilter, error) {
+	filter := models.PvzFilter{
+		Cities:           req.GetCities(),
+		StartDate:        optionalTime(req.GetStartDate()),
+		EndDate:          optionalTime(req.GetEndDate()),
+		ProductStartDate: optionalTime(req.GetProductStartDate()),
+		ProductEndDate:   optionalTime(req.GetProductEndDate()),
+		HasOpenReception: req.GetHasOpenReception(),
+	}
+
+	if filter.StartDate != nil && filter.EndDate != nil && filter.StartDate.After(*filter.EndDate) {
+		return filter, status.Error(codes.InvalidArgument, "start_date must be before end_date")
+	}
+	if filter.ProductStartDate != nil && filter.ProductEndDate != nil &&
+		filter.ProductStartDate.After(*filter.ProductEndDate) {
+		return filter, status.Error(codes.InvalidArgument, "product_start_date must be before product_end_date")
+	}
+
+	if productType := req.GetProductType(); productType != "" {
+		filter.ProductType = &productType
+	}
+	if value := req.GetReceptionStatus(); value != "" {
+		receptionStatus := dto.ReceptionStatus(value)
+		switch receptionStatus {
+		case dto.InProgress, dto.Paused, dto.Close, dto.Cancelled:
+		default:
+			return filter, status.Error(codes.InvalidArgument, "invalid reception_status")
+		}
+		filter.ReceptionStatus = &receptionStatus
+	}
+
+	return filter, nil
+}
+
+// listParams validates a ListPVZ request the way GET /pvz validates its
+// query parameters.
+func listParams(req *ListPVZRequest) (models.PvzListParams, error) {
+	params := models.PvzListParams{
+		Page:  uint64(req.Page),
+		Limit: uint64(req.Limit),
+	}
+
+	var err error
+	params.PvzFilter, err = pvzFilter(req)
+	if err != nil {
+		return params, err
+	}
+
+	if params.Limit == 0 {
+		params.Limit = defaultListLimit
+	}
+	if params.Limit > maxListLimit {
+		return params, status.Error(codes.InvalidArgument, "invalid limit")
+	}
+
+	if req.Cursor != "" {
+		if req.Page != 0 {
+			return params, status.Error(codes.InvalidArgument, "page and cursor are mutually exclusive")
+		}
+		params.Cursor, err = decodeCursor(req.Cursor)
+		if err != nil {
+			return params, err
+		}
+	} else if params.Page == 0 {
+		params.Page = 1
+	}
+
+	return params, nil
+}
+
+// streamParams validates a StreamPVZ request. Its chunks are read as
+// cursor pages without counting the listing.
+func streamParams(req *StreamPVZRequest) (models.PvzListParams, error) {
+	params := models.PvzListParams{
+		Limit:          uint64(req.ChunkSize),
+		SkipReceptions: !req.IncludeReceptions,
+		SkipCount:      true,
+	}
+
+	var err error
+	params.PvzFilter, err = pvzFilter(req)
+	if err != nil {
+		return params, err
+	}
+
+	if params.Limit == 0 {
+		params.Limit = defaultStreamChunkSize
+	}
+	if params.Limit > maxStreamChunkSize {
+		return params, status.Error(codes.InvalidArgument, "invalid chunk_size")
+	}
+
+	if req.Cursor != "" {
+		params.Cursor, err = decodeCursor(req.Cursor)
+		if err != nil {
+			return params, err
+		}
+	}
+
+	return params, nil
+}
+
+func decodeCursor(value string) (*models.PvzCursor, error) {
+	cursor, err := models.DecodePvzCursor(value)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return cursor, nil
+}
+
+func parseID(field, value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, status.Errorf(codes.InvalidArgument, "invalid %s: %v", field, err)
+	}
+	return id, nil
+}
+
+func RegisterGRPCServer(s *grpc.Server, pvzService PvzService, receptionService ReceptionService,
+	productService ProductService) {
+	RegisterPVZServiceServer(s, NewPVZServer(pvzService, receptionService, productService))
 }
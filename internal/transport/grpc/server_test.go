@@ -0,0 +1,381 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/models"
+)
+
+type stubPvzService struct {
+	params models.PvzListParams
+	page   *models.PvzPage
+	added  *dto.PostPvzJSONRequestBody
+}
+
+func (s *stubPvzService) AddPvz(_ context.Context, request *dto.PostPvzJSONRequestBody) (*dto.PVZ, error) {
+	if request.City == "" {
+		return nil, models.ErrIncorrectCity
+	}
+	s.added = request
+	id := uuid.New()
+	now := time.Now()
+	return &dto.PVZ{Id: &id, RegistrationDate: &now, City: request.City, Name: request.Name}, nil
+}
+
+func (s *stubPvzService) GetPvzList(_ context.Context, params models.PvzListParams) (*models.PvzPage, error) {
+	s.params = params
+	return s.page, nil
+}
+
+func (s *stubPvzService) GetAllPVZ(context.Context) ([]dto.PVZ, error) {
+	return nil, nil
+}
+
+type stubReceptionService struct {
+	pvzId uuid.UUID
+}
+
+func (s *stubReceptionService) AddReception(_ context.Context, request dto.PostReceptionsJSONRequestBody) (*dto.Reception, error) {
+	s.pvzId = request.PvzId
+	id := uuid.New()
+	return &dto.Reception{Id: &id, PvzId: request.PvzId, DateTime: time.Now(), Status: dto.InProgress}, nil
+}
+
+func (s *stubReceptionService) CloseLastReception(_ context.Context, pvzId uuid.UUID) (*dto.Reception, error) {
+	s.pvzId = pvzId
+	id := uuid.New()
+	return &dto.Reception{Id: &id, PvzId: pvzId, DateTime: time.Now(), Status: dto.Close}, nil
+}
+
+type stubProductService struct {
+	added   dto.PostProductsJSONRequestBody
+	deleted uuid.UUID
+}
+
+func (s *stubProductService) AddProduct(_ context.Context, request dto.PostProductsJSONRequestBody) (*dto.Product, error) {
+	s.added = request
+	id := uuid.New()
+	now := time.Now()
+	return &dto.Product{Id: &id, DateTime: &now, ReceptionId: uuid.New(), Type: request.Type}, nil
+}
+
+func (s *stubProductService) DeleteLastProduct(_ context.Context, pvzId uuid.UUID) error {
+	s.deleted = pvzId
+	return nil
+}
+
+// dialServer serves the API over an in-memory connection.
+func dialServer(t *testing.T, server *PVZServer, opts ...grpc.ServerOption) PVZServiceClient {
+	listener := bufconn.Listen(1 << 20)
+	s := grpc.NewServer(opts...)
+	RegisterPVZServiceServer(s, server)
+	go func() {
+		_ = s.Serve(listener)
+	}()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return NewPVZServiceClient(conn)
+}
+
+func TestPVZServer_ListPVZ(t *testing.T) {
+	pvzId, receptionId, productId := uuid.New(), uuid.New(), uuid.New()
+	now := time.Now().UTC()
+	next := &models.PvzCursor{RegistrationDate: now, ID: pvzId}
+	pvzs := &stubPvzService{page: &models.PvzPage{
+		TotalCount: 42,
+		NextCursor: next,
+		Items: []*models.ExtendedPvz{{
+			PVZ: dto.PVZ{Id: &pvzId, RegistrationDate: &now, City: "Москва"},
+			Receptions: []models.ExtendedReception{{
+				Reception: dto.Reception{Id: &receptionId, PvzId: pvzId, DateTime: now, Status: dto.InProgress},
+				Products:  []dto.Product{{Id: &productId, DateTime: &now, ReceptionId: receptionId, Type: "обувь"}},
+			}},
+		}},
+	}}
+	client := dialServer(t, NewPVZServer(pvzs, &stubReceptionService{}, &stubProductService{}))
+
+	receptionStatus := string(dto.InProgress)
+	resp, err := client.ListPVZ(context.Background(), &ListPVZRequest{
+		Page:            2,
+		Cities:          []string{"Москва"},
+		StartDate:       timestamppb.New(now.Add(-time.Hour)),
+		ReceptionStatus: &receptionStatus,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(2), pvzs.params.Page)
+	assert.Equal(t, uint64(defaultListLimit), pvzs.params.Limit)
+	assert.Equal(t, []string{"Москва"}, pvzs.params.Cities)
+	require.NotNil(t, pvzs.params.StartDate)
+	assert.True(t, now.Add(-time.Hour).Equal(*pvzs.params.StartDate))
+	assert.Equal(t, dto.InProgress, *pvzs.params.ReceptionStatus)
+
+	assert.Equal(t, uint64(42), resp.TotalCount)
+	assert.Equal(t, next.Encode(), resp.NextCursor)
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, pvzId.String(), resp.Items[0].Pvz.Id)
+	require.Len(t, resp.Items[0].Receptions, 1)
+	assert.Equal(t, "in_progress", resp.Items[0].Receptions[0].Reception.Status)
+	require.Len(t, resp.Items[0].Receptions[0].Products, 1)
+	assert.Equal(t, productId.String(), resp.Items[0].Receptions[0].Products[0].Id)
+
+	// The cursor of one page starts the next.
+	_, err = client.ListPVZ(context.Background(), &ListPVZRequest{Cursor: resp.NextCursor})
+	require.NoError(t, err)
+	assert.Equal(t, next.ID, pvzs.params.Cursor.ID)
+	assert.Zero(t, pvzs.params.Page)
+}
+
+// pagedPvzService serves a listing of PVZs ordered as the repository
+// orders them. With block it waits for the call to end on every page after
+// the first, as a slow query would.
+type pagedPvzService struct {
+	stubPvzService
+	pvzs  []*models.ExtendedPvz
+	block bool
+
+	mu    sync.Mutex
+	calls []models.PvzListParams
+}
+
+func (s *pagedPvzService) GetPvzList(ctx context.Context, params models.PvzListParams) (*models.PvzPage, error) {
+	s.mu.Lock()
+	s.calls = append(s.calls, params)
+	calls := len(s.calls)
+	s.mu.Unlock()
+
+	if s.block && calls > 1 {
+		<-ctx.Done()
+		return nil, fmt.Errorf("failed to query pvzs: %w", ctx.Err())
+	}
+
+	start := 0
+	if params.Cursor != nil {
+		for start < len(s.pvzs) && !params.Cursor.After(s.pvzs[start].PVZ) {
+			start++
+		}
+	}
+	end := min(start+int(params.Limit), len(s.pvzs))
+
+	page := &models.PvzPage{Items: s.pvzs[start:end]}
+	if end < len(s.pvzs) {
+		page.NextCursor = models.NewPvzCursor(s.pvzs[end-1].PVZ)
+	}
+	return page, nil
+}
+
+func (s *pagedPvzService) callCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.calls)
+}
+
+func newPagedPvzService(n int) *pagedPvzService {
+	service := &pagedPvzService{}
+	now := time.Now().UTC()
+	for i := range n {
+		id := uuid.New()
+		date := now.Add(-time.Duration(i) * time.Minute)
+		service.pvzs = append(service.pvzs, &models.ExtendedPvz{
+			PVZ:        dto.PVZ{Id: &id, RegistrationDate: &date, City: "Москва"},
+			Receptions: []models.ExtendedReception{},
+		})
+	}
+	return service
+}
+
+func TestPVZServer_StreamPVZ(t *testing.T) {
+	pvzs := newPagedPvzService(5)
+	client := dialServer(t, NewPVZServer(pvzs, &stubReceptionService{}, &stubProductService{}),
+		grpc.StreamInterceptor(StreamErrorInterceptor()))
+
+	stream, err := client.StreamPVZ(context.Background(), &StreamPVZRequest{
+		ChunkSize:         2,
+		Cities:            []string{"Москва"},
+		IncludeReceptions: true,
+	})
+	require.NoError(t, err)
+
+	var ids []string
+	var chunks []*StreamPVZResponse
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		chunks = append(chunks, chunk)
+		for _, item := range chunk.Items {
+			ids = append(ids, item.Pvz.Id)
+		}
+	}
+
+	require.Len(t, chunks, 3)
+	assert.Len(t, chunks[0].Items, 2)
+	assert.NotEmpty(t, chunks[1].NextCursor)
+	assert.Empty(t, chunks[2].NextCursor)
+	require.Len(t, ids, 5)
+	for i, pvz := range pvzs.pvzs {
+		assert.Equal(t, pvz.PVZ.Id.String(), ids[i])
+	}
+
+	require.Len(t, pvzs.calls, 3)
+	first := pvzs.calls[0]
+	assert.Nil(t, first.Cursor)
+	assert.Equal(t, uint64(2), first.Limit)
+	assert.Equal(t, []string{"Москва"}, first.Cities)
+	assert.False(t, first.SkipReceptions)
+	assert.True(t, first.SkipCount)
+
+	// A stream resumes from the cursor of the last chunk received.
+	pvzs.calls = nil
+	stream, err = client.StreamPVZ(context.Background(), &StreamPVZRequest{Cursor: chunks[0].NextCursor})
+	require.NoError(t, err)
+	chunk, err := stream.Recv()
+	require.NoError(t, err)
+	require.Len(t, chunk.Items, 3)
+	assert.Equal(t, pvzs.pvzs[2].PVZ.Id.String(), chunk.Items[0].Pvz.Id)
+	assert.Equal(t, uint64(defaultStreamChunkSize), pvzs.calls[0].Limit)
+	assert.True(t, pvzs.calls[0].SkipReceptions)
+}
+
+func TestPVZServer_StreamPVZDeadline(t *testing.T) {
+	pvzs := newPagedPvzService(3)
+	pvzs.block = true
+	client := dialServer(t, NewPVZServer(pvzs, &stubReceptionService{}, &stubProductService{}),
+		grpc.StreamInterceptor(StreamErrorInterceptor()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	stream, err := client.StreamPVZ(ctx, &StreamPVZRequest{ChunkSize: 1})
+	require.NoError(t, err)
+
+	_, err = stream.Recv()
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	assert.Never(t, func() bool { return pvzs.callCount() > 2 }, 100*time.Millisecond, 10*time.Millisecond,
+		"the stream stops reading once the deadline passes")
+}
+
+func TestStreamParams_Invalid(t *testing.T) {
+	now := time.Now()
+	invalidStatus := "open"
+
+	tests := []struct {
+		name string
+		req  *StreamPVZRequest
+	}{
+		{name: "chunk too large", req: &StreamPVZRequest{ChunkSize: maxStreamChunkSize + 1}},
+		{name: "malformed cursor", req: &StreamPVZRequest{Cursor: "%%%"}},
+		{name: "unknown reception status", req: &StreamPVZRequest{ReceptionStatus: &invalidStatus}},
+		{
+			name: "reversed dates",
+			req:  &StreamPVZRequest{StartDate: timestamppb.New(now), EndDate: timestamppb.New(now.Add(-time.Hour))},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := streamParams(tt.req)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
+
+func TestListParams_Invalid(t *testing.T) {
+	now := time.Now()
+	invalidStatus := "open"
+
+	tests := []struct {
+		name string
+		req  *ListPVZRequest
+	}{
+		{name: "limit too large", req: &ListPVZRequest{Limit: maxListLimit + 1}},
+		{name: "page and cursor", req: &ListPVZRequest{Page: 1, Cursor: models.NewPvzCursor(dto.PVZ{Id: &uuid.UUID{1}, RegistrationDate: &now}).Encode()}},
+		{name: "malformed cursor", req: &ListPVZRequest{Cursor: "%%%"}},
+		{name: "unknown reception status", req: &ListPVZRequest{ReceptionStatus: &invalidStatus}},
+		{
+			name: "reversed dates",
+			req:  &ListPVZRequest{StartDate: timestamppb.New(now), EndDate: timestamppb.New(now.Add(-time.Hour))},
+		},
+		{
+			name: "reversed product dates",
+			req:  &ListPVZRequest{ProductStartDate: timestamppb.New(now), ProductEndDate: timestamppb.New(now.Add(-time.Hour))},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := listParams(tt.req)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
+
+func TestPVZServer_Mutations(t *testing.T) {
+	pvzs := &stubPvzService{}
+	receptions := &stubReceptionService{}
+	products := &stubProductService{}
+	client := dialServer(t, NewPVZServer(pvzs, receptions, products), grpc.UnaryInterceptor(UnaryErrorInterceptor()))
+	ctx := context.Background()
+	pvzId := uuid.New()
+
+	name := "На Тверской"
+	created, err := client.CreatePVZ(ctx, &CreatePVZRequest{City: "Москва", Name: &name})
+	require.NoError(t, err)
+	assert.Equal(t, "Москва", created.City)
+	assert.Equal(t, name, created.GetName())
+	assert.Equal(t, &name, pvzs.added.Name)
+
+	_, err = client.CreatePVZ(ctx, &CreatePVZRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "service errors are mapped to status codes")
+
+	opened, err := client.OpenReception(ctx, &OpenReceptionRequest{PvzId: pvzId.String()})
+	require.NoError(t, err)
+	assert.Equal(t, pvzId.String(), opened.PvzId)
+	assert.Equal(t, "in_progress", opened.Status)
+
+	closed, err := client.CloseLastReception(ctx, &CloseLastReceptionRequest{PvzId: pvzId.String()})
+	require.NoError(t, err)
+	assert.Equal(t, "close", closed.Status)
+
+	product, err := client.AddProduct(ctx, &AddProductRequest{PvzId: pvzId.String(), Type: "обувь"})
+	require.NoError(t, err)
+	assert.Equal(t, "обувь", product.Type)
+	assert.Equal(t, dto.PostProductsJSONRequestBody{PvzId: pvzId, Type: "обувь"}, products.added)
+
+	_, err = client.DeleteLastProduct(ctx, &DeleteLastProductRequest{PvzId: pvzId.String()})
+	require.NoError(t, err)
+	assert.Equal(t, pvzId, products.deleted)
+
+	_, err = client.OpenReception(ctx, &OpenReceptionRequest{PvzId: "not-a-uuid"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.DeleteLastProduct(ctx, &DeleteLastProductRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,5 @@
+create index if not exists idx_pvz_registration_date on pvz_service.pvz (registration_date desc, pvz_id desc);
+
+create index if not exists idx_reception_pvz_id on pvz_service.reception (pvz_id, started_at);
+
+create index if not exists idx_product_reception_id on pvz_service.product (reception_id, added_at);
@@ -0,0 +1,12 @@
+update pvz_service.reception r
+set status = 'close'
+where r.status = 'in_progress'
+  and exists (
+    select 1
+    from pvz_service.reception newer
+    where newer.pvz_id = r.pvz_id
+      and newer.status = 'in_progress'
+      and (newer.started_at, newer.reception_id) > (r.started_at, r.reception_id)
+  );
+
+create unique index if not exists uq_reception_open_pvz_id on pvz_service.reception (pvz_id) where status = 'in_progress';
@@ -0,0 +1,17 @@
+create table if not exists pvz_service.product_type (
+    product_type_id uuid primary key default gen_random_uuid(),
+    name varchar(255) unique not null,
+    active boolean not null default true
+);
+
+insert into pvz_service.product_type (name)
+values ('электроника'), ('одежда'), ('обувь')
+on conflict (name) do nothing;
+
+insert into pvz_service.product_type (name)
+select distinct product_type from pvz_service.product
+on conflict (name) do nothing;
+
+alter table pvz_service.product
+    add constraint fk_product_type foreign key (product_type)
+        references pvz_service.product_type (name) on update cascade;
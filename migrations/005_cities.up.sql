@@ -0,0 +1,17 @@
+create table if not exists pvz_service.city (
+    city_id uuid primary key default gen_random_uuid(),
+    name varchar(255) unique not null,
+    active boolean not null default true
+);
+
+insert into pvz_service.city (name)
+values ('Москва'), ('Санкт-Петербург'), ('Казань')
+on conflict (name) do nothing;
+
+insert into pvz_service.city (name)
+select distinct city from pvz_service.pvz
+on conflict (name) do nothing;
+
+alter table pvz_service.pvz
+    add constraint fk_pvz_city foreign key (city)
+        references pvz_service.city (name) on update cascade;
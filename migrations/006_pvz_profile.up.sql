@@ -0,0 +1,15 @@
+alter table pvz_service.pvz
+    add column if not exists name varchar(255),
+    add column if not exists address varchar(512),
+    add column if not exists latitude double precision,
+    add column if not exists longitude double precision,
+    add column if not exists phone varchar(32),
+    add column if not exists opening_hours varchar(255),
+    add column if not exists decommissioned_at timestamp;
+
+alter table pvz_service.pvz
+    add constraint chk_pvz_coordinates check (
+        (latitude is null) = (longitude is null)
+        and latitude between -90 and 90
+        and longitude between -180 and 180
+    );
@@ -0,0 +1,11 @@
+alter table pvz_service.reception drop constraint if exists reception_status_check;
+
+alter table pvz_service.reception
+    add constraint reception_status_check
+        check (status in ('in_progress', 'paused', 'close', 'cancelled'));
+
+-- A paused reception is still unfinished, so it blocks opening a new one.
+drop index if exists pvz_service.uq_reception_open_pvz_id;
+
+create unique index if not exists uq_reception_open_pvz_id
+    on pvz_service.reception (pvz_id) where status in ('in_progress', 'paused');
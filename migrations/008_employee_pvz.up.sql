@@ -0,0 +1,10 @@
+create table if not exists pvz_service.employee_pvz (
+    user_id uuid not null,
+    pvz_id uuid not null,
+    assigned_at timestamp not null default current_timestamp,
+    constraint pk_employee_pvz primary key (user_id, pvz_id),
+    constraint fk_employee_pvz_user foreign key (user_id) references pvz_service.user (user_id) on delete cascade,
+    constraint fk_employee_pvz_pvz foreign key (pvz_id) references pvz_service.pvz (pvz_id) on delete cascade
+);
+
+create index if not exists idx_employee_pvz_pvz_id on pvz_service.employee_pvz (pvz_id);
@@ -0,0 +1,5 @@
+-- No foreign key: dummy-login tokens act as a sentinel user that has no row
+-- in pvz_service.user.
+alter table pvz_service.pvz add column if not exists created_by uuid;
+alter table pvz_service.reception add column if not exists created_by uuid;
+alter table pvz_service.product add column if not exists created_by uuid;
@@ -0,0 +1,19 @@
+create table if not exists pvz_service.refresh_token (
+    refresh_token_id uuid primary key default gen_random_uuid(),
+    user_id uuid not null,
+    token_hash varchar(64) not null,
+    created_at timestamp not null default current_timestamp,
+    expires_at timestamp not null,
+    revoked_at timestamp,
+    constraint uq_refresh_token_hash unique (token_hash),
+    constraint fk_refresh_token_user foreign key (user_id) references pvz_service.user (user_id) on delete cascade
+);
+
+create index if not exists idx_refresh_token_user_id on pvz_service.refresh_token (user_id);
+
+-- Access tokens revoked before they expire, keyed by their jti claim. Rows
+-- are only consulted until expires_at.
+create table if not exists pvz_service.revoked_token (
+    jti varchar(64) primary key,
+    expires_at timestamp not null
+);
@@ -0,0 +1,21 @@
+create table if not exists pvz_service.invite (
+    invite_id uuid primary key default gen_random_uuid(),
+    code_hash varchar(64) not null,
+    role varchar(20) not null check (role in ('employee', 'moderator')),
+    created_by uuid,
+    created_at timestamp not null default current_timestamp,
+    expires_at timestamp not null,
+    used_at timestamp,
+    used_by uuid,
+    constraint uq_invite_code_hash unique (code_hash),
+    constraint fk_invite_used_by foreign key (used_by) references pvz_service.user (user_id) on delete set null
+);
+
+-- PVZs an employee invite assigns its user to on registration.
+create table if not exists pvz_service.invite_pvz (
+    invite_id uuid not null,
+    pvz_id uuid not null,
+    constraint pk_invite_pvz primary key (invite_id, pvz_id),
+    constraint fk_invite_pvz_invite foreign key (invite_id) references pvz_service.invite (invite_id) on delete cascade,
+    constraint fk_invite_pvz_pvz foreign key (pvz_id) references pvz_service.pvz (pvz_id) on delete cascade
+);
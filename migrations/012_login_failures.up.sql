@@ -0,0 +1,9 @@
+-- Failed login attempts per subject: an email ("email:...") or a client IP
+-- ("ip:..."). failures counts the attempts of the current window, which
+-- restarts once last_failure_at is older than the window.
+create table if not exists pvz_service.login_failure (
+    subject varchar(320) primary key,
+    failures integer not null,
+    last_failure_at timestamp not null,
+    blocked_until timestamp
+);
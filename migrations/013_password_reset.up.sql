@@ -0,0 +1,12 @@
+create table if not exists pvz_service.password_reset_token (
+    password_reset_token_id uuid primary key default gen_random_uuid(),
+    user_id uuid not null,
+    token_hash varchar(64) not null,
+    created_at timestamp not null default current_timestamp,
+    expires_at timestamp not null,
+    used_at timestamp,
+    constraint uq_password_reset_token_hash unique (token_hash),
+    constraint fk_password_reset_token_user foreign key (user_id) references pvz_service.user (user_id) on delete cascade
+);
+
+create index if not exists idx_password_reset_token_user_id on pvz_service.password_reset_token (user_id);
@@ -0,0 +1,7 @@
+-- Deactivated users can neither log in nor use tokens issued before.
+alter table pvz_service.user
+    add column if not exists active boolean not null default true,
+    add column if not exists created_at timestamp not null default current_timestamp,
+    add column if not exists last_login_at timestamp;
+
+create index if not exists idx_user_created_at on pvz_service.user (created_at desc, user_id desc);
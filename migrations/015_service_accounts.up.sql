@@ -0,0 +1,36 @@
+-- Non-human clients authenticate with API keys issued to a service account.
+-- The account carries the role and the optional PVZ scope of its keys.
+create table if not exists pvz_service.service_account (
+    service_account_id uuid primary key default gen_random_uuid(),
+    name varchar(255) not null,
+    role varchar(20) not null check (role in ('employee', 'moderator')),
+    created_by uuid,
+    created_at timestamp not null default current_timestamp,
+    constraint uq_service_account_name unique (name)
+);
+
+-- PVZs the service account is limited to; no rows means no limit.
+create table if not exists pvz_service.service_account_pvz (
+    service_account_id uuid not null,
+    pvz_id uuid not null,
+    constraint pk_service_account_pvz primary key (service_account_id, pvz_id),
+    constraint fk_service_account_pvz_account foreign key (service_account_id) references pvz_service.service_account (service_account_id) on delete cascade,
+    constraint fk_service_account_pvz_pvz foreign key (pvz_id) references pvz_service.pvz (pvz_id) on delete cascade
+);
+
+create table if not exists pvz_service.api_key (
+    api_key_id uuid primary key default gen_random_uuid(),
+    service_account_id uuid not null,
+    name varchar(255) not null,
+    key_hash varchar(64) not null,
+    prefix varchar(16) not null,
+    created_by uuid,
+    created_at timestamp not null default current_timestamp,
+    expires_at timestamp,
+    last_used_at timestamp,
+    revoked_at timestamp,
+    constraint uq_api_key_hash unique (key_hash),
+    constraint fk_api_key_service_account foreign key (service_account_id) references pvz_service.service_account (service_account_id) on delete cascade
+);
+
+create index if not exists idx_api_key_service_account_id on pvz_service.api_key (service_account_id);
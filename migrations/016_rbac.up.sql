@@ -0,0 +1,87 @@
+-- Admins manage which permissions each role has.
+alter table pvz_service.user
+    drop constraint if exists user_role_check,
+    add constraint user_role_check check (role in ('employee', 'moderator', 'admin'));
+
+create table if not exists pvz_service.permission (
+    name varchar(64) primary key
+);
+
+create table if not exists pvz_service.role_permission (
+    role varchar(20) not null check (role in ('employee', 'moderator', 'admin')),
+    permission varchar(64) not null,
+    constraint pk_role_permission primary key (role, permission),
+    constraint fk_role_permission_permission foreign key (permission) references pvz_service.permission (name) on delete cascade
+);
+
+insert into pvz_service.permission (name) values
+    ('invite:create'),
+    ('city:manage'),
+    ('pvz:create'),
+    ('pvz:read'),
+    ('pvz:update'),
+    ('pvz:decommission'),
+    ('reception:create'),
+    ('reception:pause'),
+    ('reception:resume'),
+    ('reception:close'),
+    ('reception:cancel'),
+    ('reception:reopen'),
+    ('product:create'),
+    ('product:delete'),
+    ('product_type:read'),
+    ('product_type:manage'),
+    ('user:read'),
+    ('user:manage'),
+    ('assignment:manage'),
+    ('service_account:manage'),
+    ('role:manage')
+on conflict do nothing;
+
+-- The defaults reproduce the role checks the router had before.
+insert into pvz_service.role_permission (role, permission) values
+    ('employee', 'pvz:read'),
+    ('employee', 'reception:create'),
+    ('employee', 'reception:pause'),
+    ('employee', 'reception:resume'),
+    ('employee', 'reception:close'),
+    ('employee', 'reception:cancel'),
+    ('employee', 'product:create'),
+    ('employee', 'product:delete'),
+    ('employee', 'product_type:read'),
+    ('moderator', 'invite:create'),
+    ('moderator', 'city:manage'),
+    ('moderator', 'pvz:create'),
+    ('moderator', 'pvz:read'),
+    ('moderator', 'pvz:update'),
+    ('moderator', 'pvz:decommission'),
+    ('moderator', 'reception:cancel'),
+    ('moderator', 'reception:reopen'),
+    ('moderator', 'product_type:read'),
+    ('moderator', 'product_type:manage'),
+    ('moderator', 'user:read'),
+    ('moderator', 'user:manage'),
+    ('moderator', 'assignment:manage'),
+    ('moderator', 'service_account:manage'),
+    ('admin', 'invite:create'),
+    ('admin', 'city:manage'),
+    ('admin', 'pvz:create'),
+    ('admin', 'pvz:read'),
+    ('admin', 'pvz:update'),
+    ('admin', 'pvz:decommission'),
+    ('admin', 'reception:create'),
+    ('admin', 'reception:pause'),
+    ('admin', 'reception:resume'),
+    ('admin', 'reception:close'),
+    ('admin', 'reception:cancel'),
+    ('admin', 'reception:reopen'),
+    ('admin', 'product:create'),
+    ('admin', 'product:delete'),
+    ('admin', 'product_type:read'),
+    ('admin', 'product_type:manage'),
+    ('admin', 'user:read'),
+    ('admin', 'user:manage'),
+    ('admin', 'assignment:manage'),
+    ('admin', 'service_account:manage'),
+    ('admin', 'role:manage')
+on conflict do nothing;
@@ -0,0 +1,33 @@
+-- Authenticator app secrets. A secret protects logins only once confirmed.
+create table if not exists pvz_service.user_totp (
+    user_id uuid primary key,
+    secret varchar(64) not null,
+    confirmed_at timestamp,
+    last_used_step bigint not null default 0,
+    created_at timestamp not null default current_timestamp,
+    constraint fk_user_totp_user foreign key (user_id) references pvz_service.user (user_id) on delete cascade
+);
+
+create table if not exists pvz_service.recovery_code (
+    recovery_code_id uuid primary key default gen_random_uuid(),
+    user_id uuid not null,
+    code_hash varchar(64) not null,
+    used_at timestamp,
+    constraint uq_recovery_code_hash unique (user_id, code_hash),
+    constraint fk_recovery_code_user foreign key (user_id) references pvz_service.user (user_id) on delete cascade
+);
+
+-- Logins that passed the password check and wait for the second factor.
+create table if not exists pvz_service.login_challenge (
+    login_challenge_id uuid primary key default gen_random_uuid(),
+    user_id uuid not null,
+    token_hash varchar(64) not null,
+    attempts integer not null default 0,
+    created_at timestamp not null default current_timestamp,
+    expires_at timestamp not null,
+    used_at timestamp,
+    constraint uq_login_challenge_token_hash unique (token_hash),
+    constraint fk_login_challenge_user foreign key (user_id) references pvz_service.user (user_id) on delete cascade
+);
+
+create index if not exists idx_login_challenge_user_id on pvz_service.login_challenge (user_id);
@@ -0,0 +1,23 @@
+-- Accounts at external OpenID Connect providers linked to users.
+create table if not exists pvz_service.user_identity (
+    user_identity_id uuid primary key default gen_random_uuid(),
+    user_id uuid not null,
+    issuer varchar(255) not null,
+    subject varchar(255) not null,
+    created_at timestamp not null default current_timestamp,
+    constraint uq_user_identity_issuer_subject unique (issuer, subject),
+    constraint fk_user_identity_user foreign key (user_id) references pvz_service.user (user_id) on delete cascade
+);
+
+create index if not exists idx_user_identity_user_id on pvz_service.user_identity (user_id);
+
+-- Single sign-on logins waiting for the identity provider to redirect back.
+create table if not exists pvz_service.oidc_login_state (
+    oidc_login_state_id uuid primary key default gen_random_uuid(),
+    state_hash varchar(64) not null,
+    nonce varchar(64) not null,
+    code_verifier varchar(128) not null,
+    created_at timestamp not null default current_timestamp,
+    expires_at timestamp not null,
+    constraint uq_oidc_login_state_hash unique (state_hash)
+);
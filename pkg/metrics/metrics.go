@@ -33,6 +33,11 @@ var (
 		Name: "products_added_total",
 		Help: "Total number of products added",
 	})
+
+	LoginLockouts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "login_lockouts_total",
+		Help: "Total number of login lockouts after repeated failed attempts",
+	}, []string{"scope"})
 )
 
 func init() {
@@ -42,5 +47,6 @@ func init() {
 		PVZCreated,
 		OrderReceptionsCreated,
 		ProductsAdded,
+		LoginLockouts,
 	)
 }
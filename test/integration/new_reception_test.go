@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 
 	"github.com/go-chi/chi/v5"
@@ -19,18 +20,18 @@ import (
 	"github.com/itisalisas/avito-backend/internal/service/pvz"
 	"github.com/itisalisas/avito-backend/internal/service/reception"
 	"github.com/itisalisas/avito-backend/internal/storage"
+	"github.com/itisalisas/avito-backend/internal/storage/memory"
 )
 
 func setupTestRouter() *chi.Mux {
-	db := storage.DBTestSetup()
-
-	pvzRepo := storage.NewPvzRepository(db)
-	receptionRepo := storage.NewReceptionRepository(db)
-	productRepo := storage.NewProductRepository(db)
+	repos := memory.NewRepositories()
+	if db := storage.DBTestSetup(); db != nil {
+		repos = storage.NewRepositories(db)
+	}
 
-	pvzService := pvz.NewPvzService(pvzRepo)
-	receptionService := reception.NewReceptionService(receptionRepo)
-	productService := product.NewProductService(productRepo, receptionRepo)
+	pvzService := pvz.NewPvzService(repos.TxManager, repos.Pvz, repos.City, repos.Assignment)
+	receptionService := reception.NewReceptionService(repos.TxManager, repos.Reception, repos.Pvz)
+	productService := product.NewProductService(repos.TxManager, repos.Product, repos.Reception, repos.ProductType)
 
 	pvzHandler := handlers.NewPvzHandler(pvzService)
 	receptionHandler := handlers.NewReceptionHandler(receptionService)
@@ -53,7 +54,7 @@ func TestCreatePvzAndReception(t *testing.T) {
 	defer ts.Close()
 
 	pvzData := map[string]interface{}{
-		"city": dto.Москва,
+		"city": "Москва",
 	}
 	pvzJSON, err := json.Marshal(pvzData)
 	if err != nil {
@@ -99,7 +100,7 @@ func TestCreatePvzAndReception(t *testing.T) {
 	for i := 0; i < 50; i++ {
 		productData := map[string]interface{}{
 			"pvzId": pvzInfo.Id,
-			"type":  dto.ProductTypeОдежда,
+			"type":  "одежда",
 		}
 		productJSON, err := json.Marshal(productData)
 		if err != nil {
@@ -134,3 +135,51 @@ func TestCreatePvzAndReception(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, resp.StatusCode, "Expected status code to be 200 for closing reception")
 }
+
+func TestConcurrentReceptionsForOnePvz(t *testing.T) {
+	const workers = 10
+
+	router := setupTestRouter()
+
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	pvzJSON, err := json.Marshal(map[string]interface{}{"city": "Казань"})
+	require.NoError(t, err)
+	resp, err := http.Post(ts.URL+"/pvz", "application/json", bytes.NewBuffer(pvzJSON))
+	require.NoError(t, err)
+	var pvzInfo dto.PVZ
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&pvzInfo))
+	require.NoError(t, resp.Body.Close())
+
+	receptionJSON, err := json.Marshal(map[string]interface{}{"pvzId": pvzInfo.Id})
+	require.NoError(t, err)
+
+	statuses := make(chan int, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := http.Post(ts.URL+"/receptions", "application/json", bytes.NewReader(receptionJSON))
+			if err != nil {
+				statuses <- 0
+				return
+			}
+			_ = resp.Body.Close()
+			statuses <- resp.StatusCode
+		}()
+	}
+	wg.Wait()
+	close(statuses)
+
+	var created int
+	for status := range statuses {
+		if status == http.StatusCreated {
+			created++
+			continue
+		}
+		assert.Equal(t, http.StatusBadRequest, status)
+	}
+	assert.Equal(t, 1, created, "Expected exactly one open reception")
+}
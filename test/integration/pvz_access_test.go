@@ -0,0 +1,236 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/itisalisas/avito-backend/internal/generated/dto"
+	"github.com/itisalisas/avito-backend/internal/handlers"
+	"github.com/itisalisas/avito-backend/internal/jwtkeys"
+	"github.com/itisalisas/avito-backend/internal/middleware"
+	"github.com/itisalisas/avito-backend/internal/models"
+	"github.com/itisalisas/avito-backend/internal/passwordhash"
+	"github.com/itisalisas/avito-backend/internal/service/assignment"
+	"github.com/itisalisas/avito-backend/internal/service/auth"
+	"github.com/itisalisas/avito-backend/internal/service/product"
+	"github.com/itisalisas/avito-backend/internal/service/pvz"
+	"github.com/itisalisas/avito-backend/internal/service/rbac"
+	"github.com/itisalisas/avito-backend/internal/service/reception"
+	"github.com/itisalisas/avito-backend/internal/service/serviceaccount"
+	"github.com/itisalisas/avito-backend/internal/service/user"
+	"github.com/itisalisas/avito-backend/internal/storage"
+	"github.com/itisalisas/avito-backend/internal/storage/memory"
+)
+
+// securedRouter mounts the PVZ routes behind the same authentication,
+// permission and PVZ access middlewares as cmd/main.go.
+type securedRouter struct {
+	*chi.Mux
+	auth           *auth.Service
+	assignment     *assignment.Service
+	serviceAccount *serviceaccount.Service
+}
+
+func setupSecuredRouter(t *testing.T) *securedRouter {
+	repos := memory.NewRepositories()
+	if db := storage.DBTestSetup(); db != nil {
+		repos = storage.NewRepositories(db)
+	}
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	keys, err := jwtkeys.NewManager(private)
+	require.NoError(t, err)
+	hasher := passwordhash.New(passwordhash.Bcrypt{Cost: bcrypt.MinCost})
+
+	authService := auth.NewAuthService(repos.TxManager, repos.User, repos.Token, repos.Invite, repos.Pvz,
+		repos.Assignment, repos.LoginAttempt, repos.TwoFactor, repos.Identity, keys, nil, hasher, auth.Config{DummyLogin: true})
+	assignmentService := assignment.NewAssignmentService(repos.TxManager, repos.Assignment, repos.User, repos.Pvz, repos.Reception)
+	userService := user.NewUserService(repos.TxManager, repos.User, repos.Token)
+	serviceAccountService := serviceaccount.NewServiceAccountService(repos.TxManager, repos.ServiceAccount)
+	rbacService := rbac.NewRBACService(repos.TxManager, repos.Role)
+
+	pvzHandler := handlers.NewPvzHandler(pvz.NewPvzService(repos.TxManager, repos.Pvz, repos.City, repos.Assignment))
+	receptionHandler := handlers.NewReceptionHandler(reception.NewReceptionService(repos.TxManager, repos.Reception, repos.Pvz))
+	productHandler := handlers.NewProductHandler(product.NewProductService(repos.TxManager, repos.Product, repos.Reception, repos.ProductType))
+
+	checkAuth := middleware.CheckAuth(keys.Keyfunc, authService, userService, serviceAccountService, rbacService)
+	fromPath := middleware.CheckPvzAccess(assignmentService, middleware.PvzFromPath)
+	fromBody := middleware.CheckPvzAccess(assignmentService, middleware.PvzFromBody)
+
+	r := chi.NewRouter()
+	r.With(checkAuth, middleware.RequirePermission(models.PermPvzCreate)).HandleFunc("POST /pvz", pvzHandler.AddPvz)
+	r.With(checkAuth, middleware.RequirePermission(models.PermPvzUpdate), fromPath).HandleFunc("PATCH /pvz/{pvzId}", pvzHandler.UpdatePvz)
+	r.With(checkAuth, middleware.RequirePermission(models.PermPvzDecommission), fromPath).HandleFunc("POST /pvz/{pvzId}/decommission", pvzHandler.DecommissionPvz)
+	r.With(checkAuth, middleware.RequirePermission(models.PermReceptionCreate), fromBody).HandleFunc("POST /receptions", receptionHandler.AddReception)
+	r.With(checkAuth, middleware.RequirePermission(models.PermProductCreate), fromBody).HandleFunc("POST /products", productHandler.AddProduct)
+	r.With(checkAuth, middleware.RequirePermission(models.PermProductDelete), fromPath).HandleFunc("POST /pvz/{pvzId}/delete_last_product", productHandler.DeleteLastProduct)
+	r.With(checkAuth, middleware.RequirePermission(models.PermReceptionClose), fromPath).HandleFunc("POST /pvz/{pvzId}/close_last_reception", receptionHandler.CloseLastReception)
+
+	return &securedRouter{Mux: r, auth: authService, assignment: assignmentService, serviceAccount: serviceAccountService}
+}
+
+func (r *securedRouter) dummyToken(t *testing.T, role dto.PostDummyLoginJSONBodyRole) string {
+	token, err := r.auth.DummyLogin(dto.PostDummyLoginJSONRequestBody{Role: role})
+	require.NoError(t, err)
+	return *token
+}
+
+// employeeToken registers an employee, assigns them to the PVZs given and
+// logs them in.
+func (r *securedRouter) employeeToken(t *testing.T, pvzIds ...openapi_types.UUID) string {
+	ctx := context.Background()
+	email := openapi_types.Email(uuid.NewString() + "@example.com")
+
+	registered, err := r.auth.Register(ctx, dto.PostRegisterJSONRequestBody{Email: email, Password: "password123", Role: dto.Employee})
+	require.NoError(t, err)
+	for _, pvzId := range pvzIds {
+		_, err := r.assignment.AssignPvz(ctx, *registered.Id, pvzId)
+		require.NoError(t, err)
+	}
+
+	result, err := r.auth.Login(ctx, dto.PostLoginJSONRequestBody{Email: email, Password: "password123"}, "")
+	require.NoError(t, err)
+	return result.Tokens.AccessToken
+}
+
+// apiKey creates a service account with the role, limited to the PVZs
+// given, and returns a key for it.
+func (r *securedRouter) apiKey(t *testing.T, role dto.UserRole, pvzIds ...openapi_types.UUID) string {
+	ctx := context.Background()
+	account, err := r.serviceAccount.CreateServiceAccount(ctx, dto.PostServiceAccountsJSONRequestBody{
+		Name:   "scanner",
+		Role:   string(role),
+		PvzIds: &pvzIds,
+	})
+	require.NoError(t, err)
+
+	key, err := r.serviceAccount.CreateAPIKey(ctx, account.Id, dto.PostServiceAccountsServiceAccountIdApiKeysJSONRequestBody{Name: "key"})
+	require.NoError(t, err)
+	return *key.Key
+}
+
+func post(t *testing.T, url, token string, body any) *http.Response {
+	return send(t, http.MethodPost, url, "Authorization", "Bearer "+token, body)
+}
+
+// send makes a request authenticated by the header given.
+func send(t *testing.T, method, url, header, credentials string, body any) *http.Response {
+	payload, err := json.Marshal(body)
+	require.NoError(t, err)
+	req, err := http.NewRequest(method, url, bytes.NewReader(payload))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(header, credentials)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func TestPvzAccess(t *testing.T) {
+	router := setupSecuredRouter(t)
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	moderator := router.dummyToken(t, dto.PostDummyLoginJSONBodyRoleModerator)
+	resp := post(t, ts.URL+"/pvz", moderator, map[string]any{"city": "Москва"})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created dto.PVZ
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+	pvzId := *created.Id
+
+	tests := []struct {
+		name       string
+		token      string
+		wantStatus int
+	}{
+		{
+			name:       "assigned employee",
+			token:      router.employeeToken(t, pvzId),
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "unassigned employee",
+			token:      router.employeeToken(t),
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			// Dummy tokens stand for no user, so they cannot be assigned.
+			name:       "dummy employee",
+			token:      router.dummyToken(t, dto.PostDummyLoginJSONBodyRoleEmployee),
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := post(t, ts.URL+"/receptions", tt.token, map[string]any{"pvzId": pvzId})
+			assert.Equal(t, tt.wantStatus, resp.StatusCode, "open reception")
+
+			resp = post(t, ts.URL+"/products", tt.token, map[string]any{"pvzId": pvzId, "type": "одежда"})
+			assert.Equal(t, tt.wantStatus, resp.StatusCode, "add product")
+
+			// The assigned employee closes the reception it opened, so the
+			// PVZ is free again for the cases after it.
+			wantStatus := tt.wantStatus
+			if wantStatus == http.StatusCreated {
+				wantStatus = http.StatusOK
+			}
+			resp = post(t, fmt.Sprintf("%s/pvz/%s/delete_last_product", ts.URL, pvzId), tt.token, nil)
+			assert.Equal(t, wantStatus, resp.StatusCode, "delete last product")
+
+			resp = post(t, fmt.Sprintf("%s/pvz/%s/close_last_reception", ts.URL, pvzId), tt.token, nil)
+			assert.Equal(t, wantStatus, resp.StatusCode, "close last reception")
+		})
+	}
+}
+
+func TestPvzAccess_ScopedModerator(t *testing.T) {
+	router := setupSecuredRouter(t)
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	moderator := router.dummyToken(t, dto.PostDummyLoginJSONBodyRoleModerator)
+	var pvzIds []openapi_types.UUID
+	for range 2 {
+		resp := post(t, ts.URL+"/pvz", moderator, map[string]any{"city": "Москва"})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var created dto.PVZ
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+		pvzIds = append(pvzIds, *created.Id)
+	}
+	inScope, outOfScope := pvzIds[0], pvzIds[1]
+	key := router.apiKey(t, dto.UserRoleModerator, inScope)
+
+	tests := []struct {
+		pvzId      openapi_types.UUID
+		wantStatus int
+	}{
+		{pvzId: outOfScope, wantStatus: http.StatusForbidden},
+		{pvzId: inScope, wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		resp := send(t, http.MethodPatch, fmt.Sprintf("%s/pvz/%s", ts.URL, tt.pvzId), middleware.APIKeyHeader, key,
+			map[string]any{"name": "ПВЗ на Тверской"})
+		assert.Equal(t, tt.wantStatus, resp.StatusCode, "update %s", tt.pvzId)
+
+		resp = send(t, http.MethodPost, fmt.Sprintf("%s/pvz/%s/decommission", ts.URL, tt.pvzId), middleware.APIKeyHeader, key, nil)
+		assert.Equal(t, tt.wantStatus, resp.StatusCode, "decommission %s", tt.pvzId)
+	}
+}